@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"pdrest/internal/config"
+	"pdrest/internal/data"
+	"pdrest/internal/database"
+	"pdrest/internal/domain"
+	"pdrest/internal/interfaces/services"
+)
+
+const achievementsUsage = `usage:
+  server achievements import [-file extra/achievements_first.csv] [-dry-run]
+  server achievements export [-file achievements.csv]`
+
+// runAchievementsCommand runs "achievements import|export" against the configured database and returns
+// the process exit code.
+func runAchievementsCommand(cfg *config.Config, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, achievementsUsage)
+		return 2
+	}
+
+	flags := flag.NewFlagSet("achievements "+args[0], flag.ContinueOnError)
+	file := flags.String("file", "", "CSV file to read (import, default extra/achievements_first.csv) or write (export, default stdout)")
+	dryRun := flags.Bool("dry-run", false, "import: only print the diff against the database")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	db, err := database.New(cfg.GetDatabaseURL())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to PostgreSQL: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	achievementService := services.NewAchievementService(
+		data.NewPostgresAchievementRepository(db.Pool),
+		data.NewPostgresPrizeRepository(db.Pool),
+		data.NewPostgresPrizeValueRepository(db.Pool),
+		nil, // the catalog doesn't pay rewards
+		nil,
+	)
+	ctx := context.Background()
+
+	switch args[0] {
+	case "import":
+		path := *file
+		if path == "" {
+			path = "extra/achievements_first.csv"
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to open %s: %v\n", path, err)
+			return 1
+		}
+		defer f.Close()
+
+		result, err := achievementService.ImportAchievementCatalog(ctx, f, *dryRun)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		printAchievementCatalogDiff(os.Stdout, result)
+		return 0
+	case "export":
+		var out io.Writer = os.Stdout
+		if *file != "" {
+			f, err := os.Create(*file)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "failed to create %s: %v\n", *file, err)
+				return 1
+			}
+			defer f.Close()
+			out = f
+		}
+		if err := achievementService.ExportAchievementCatalog(ctx, out); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	default:
+		fmt.Fprintln(os.Stderr, achievementsUsage)
+		return 2
+	}
+}
+
+func printAchievementCatalogDiff(w io.Writer, result *domain.AchievementCatalogImportResult) {
+	for _, change := range result.Changes {
+		marker := " "
+		switch change.Action {
+		case domain.AchievementCatalogCreate:
+			marker = "+"
+		case domain.AchievementCatalogUpdate:
+			marker = "~"
+		}
+		fmt.Fprintf(w, "%s %s (line %d)\n", marker, change.AchievementID, change.Line)
+		for _, field := range change.Changes {
+			fmt.Fprintf(w, "    %s\n", field)
+		}
+		for _, warning := range change.Warnings {
+			fmt.Fprintf(w, "    warning: %s\n", warning)
+		}
+	}
+	if len(result.NotInFile) > 0 {
+		fmt.Fprintf(w, "not in file (left as they are): %s\n", strings.Join(result.NotInFile, ", "))
+	}
+
+	verb := "applied"
+	if result.DryRun {
+		verb = "dry run, nothing saved"
+	}
+	fmt.Fprintf(w, "%d to create, %d to update, %d unchanged (%s)\n", result.Created, result.Updated, result.Unchanged, verb)
+}
@@ -8,10 +8,12 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // quest periods use users' time zones; the runtime image has no zoneinfo
 
 	"pdrest/internal/config"
 	"pdrest/internal/data"
 	"pdrest/internal/database"
+	"pdrest/internal/domain"
 	"pdrest/internal/interfaces/http"
 	"pdrest/internal/interfaces/services"
 
@@ -24,6 +26,11 @@ func main() {
 	// Load configuration
 	cfg := config.Load()
 
+	// "server achievements import|export" manages the achievement catalog instead of serving the API
+	if len(os.Args) > 1 && os.Args[1] == "achievements" {
+		os.Exit(runAchievementsCommand(cfg, os.Args[2:]))
+	}
+
 	// Create Echo instance
 	e := echo.New()
 
@@ -53,10 +60,24 @@ func main() {
 	var userService *services.UserService
 	var ratingService *services.RatingService
 	var eventService *services.EventService
+	var eventAdminService *services.EventAdminService
+	var eventTemplateService *services.EventTemplateService
 	var rouletteService *services.RouletteService
 	var betService *services.BetService
 	var betScheduler *services.BetScheduler
 	var achievementService *services.AchievementService
+	var seasonService *services.SeasonService
+	var leagueService *services.LeagueService
+	var skillService *services.SkillService
+	var referralService *services.ReferralService
+	var squadService *services.SquadService
+	var duelService *services.DuelService
+	var inventoryService *services.InventoryService
+	var profileService *services.ProfileService
+	var xpService *services.XPService
+	var questService *services.QuestService
+	var loginStreakService *services.LoginStreakService
+	var backgroundJobs []*services.PeriodicJob
 	authService := services.NewAuthService(cfg.JWT.SecretKey, cfg.JWT.AccessTokenTTL, cfg.JWT.RefreshTokenTTL)
 
 	// Create Google auth service
@@ -101,13 +122,26 @@ func main() {
 		log.Printf("Warning: Failed to connect to PostgreSQL: %v", err)
 		log.Println("Falling back to in-memory repository")
 		repo = data.NewInMemoryUserRepository()
-		userService = services.NewUserService(repo)
+		userService = services.NewUserService(repo, nil)
 		ratingService = nil
 		// Event, roulette, bet, and achievement services require database - will return error if accessed
 		eventService = nil
+		eventAdminService = nil
+		eventTemplateService = nil
 		rouletteService = nil
 		betService = nil
 		achievementService = nil
+		seasonService = nil
+		leagueService = nil
+		skillService = nil
+		referralService = nil
+		squadService = nil
+		duelService = nil
+		inventoryService = nil
+		profileService = nil
+		xpService = nil
+		questService = nil
+		loginStreakService = nil
 	} else {
 		defer db.Close()
 		log.Println("Successfully connected to PostgreSQL database")
@@ -121,22 +155,138 @@ func main() {
 		achievementRepo := data.NewPostgresAchievementRepository(db.Pool)
 		prizeRepo := data.NewPostgresPrizeRepository(db.Pool)
 		prizeValueRepo := data.NewPostgresPrizeValueRepository(db.Pool)
+		seasonRepo := data.NewPostgresSeasonRepository(db.Pool)
+		leagueRepo := data.NewPostgresLeagueRepository(db.Pool)
+		skillRepo := data.NewPostgresSkillRatingRepository(db.Pool)
+		referralRepo := data.NewPostgresReferralRepository(db.Pool)
+		eventTemplateRepo := data.NewPostgresEventTemplateRepository(db.Pool)
+		squadRepo := data.NewPostgresSquadRepository(db.Pool)
+		duelRepo := data.NewPostgresDuelRepository(db.Pool)
+		rewardRepo := data.NewPostgresRewardRepository(db.Pool)
+		inventoryRepo := data.NewPostgresInventoryRepository(db.Pool)
+		profileRepo := data.NewPostgresProfileRepository(db.Pool)
+		xpRepo := data.NewPostgresXPRepository(db.Pool)
+		questRepo := data.NewPostgresQuestRepository(db.Pool)
+		loginStreakRepo := data.NewPostgresLoginStreakRepository(db.Pool)
 
 		repo = postgresRepo
 
+		// Domain events (bet settled/claimed, event joined, referral activated, user logged in) drive achievement progress and XP
+		eventBus := services.NewDomainEventBus()
+
 		// Create services
-		userService = services.NewUserService(repo)
+		userService = services.NewUserService(repo, eventBus)
 		ratingService = services.NewRatingService(ratingRepo)
-		eventService = services.NewEventService(eventRepo, prizeRepo, prizeValueRepo, achievementRepo, ratingRepo)
-		rouletteService = services.NewRouletteService(rouletteRepo, repo, prizeRepo, prizeValueRepo, eventRepo, ratingRepo)
+		rouletteService = services.NewRouletteService(rouletteRepo, repo, prizeRepo, prizeValueRepo, eventRepo, ratingRepo, rewardRepo, xpRepo)
 		priceProvider := services.NewPriceProvider(cfg.Pyth.HermesURL) // Pyth Hermes latest_price_feeds
-		betScheduler = services.NewBetScheduler(betRepo, priceProvider)
-		betService = services.NewBetService(betRepo, priceProvider, betScheduler, ratingRepo)
-		achievementService = services.NewAchievementService(achievementRepo, prizeRepo, prizeValueRepo, ratingRepo, betRepo)
+		skillService = services.NewSkillService(skillRepo, betRepo)
+		eventService = services.NewEventService(eventRepo, prizeRepo, prizeValueRepo, achievementRepo, time.Duration(cfg.Events.SnapshotIntervalMinutes)*time.Minute, eventBus)
+		squadService = services.NewSquadService(squadRepo, eventRepo, eventService, cfg.Events.SquadMaxSize)
+		eventLifecycleService := services.NewEventLifecycleService(eventRepo)
+		eventAdminService = services.NewEventAdminService(eventRepo, eventLifecycleService)
+		betScheduler = services.NewBetScheduler(betRepo, priceProvider, skillService)
+		referralService = services.NewReferralService(referralRepo, ratingRepo, int64(cfg.Referral.SignupBonus), cfg.Referral.GetLevelPercents(), cfg.Referral.ActiveDays)
+		betService = services.NewBetService(betRepo, priceProvider, betScheduler, ratingRepo, inventoryRepo, xpRepo, skillService, referralService, eventBus)
+		// Prize values pay points, extra roulette spins, badges or inventory items (boosts, shields, spins)
+		// through their reward type's handler
+		inventoryService = services.NewInventoryService(inventoryRepo)
+		profileService = services.NewProfileService(profileRepo, repo, rewardRepo, achievementRepo, leagueRepo, xpRepo, skillService)
+		rewardService := services.NewRewardService(
+			services.NewPointsRewardHandler(ratingRepo),
+			services.NewSpinsRewardHandler(rouletteRepo, rewardRepo),
+			services.NewBoostRewardHandler(inventoryRepo),
+			services.NewBadgeRewardHandler(rewardRepo),
+			services.NewItemRewardHandler(inventoryRepo, rouletteRepo),
+		)
+		achievementService = services.NewAchievementService(achievementRepo, prizeRepo, prizeValueRepo, rewardService, eventBus)
+		betScheduler.OnSettled(betService.PublishBetSettled)
+		eventBus.Subscribe(achievementService.OnDomainEvent,
+			domain.DomainEventBetSettled,
+			domain.DomainEventBetClaimed,
+			domain.DomainEventEventJoined,
+			domain.DomainEventReferralActivated,
+			domain.DomainEventUserLoggedIn,
+		)
+		eventBus.Subscribe(achievementService.NotifyAchievementCompleted, domain.DomainEventAchievementCompleted)
+
+		// XP: settled bets, wins, daily logins, joined events and completed achievements grant XP by XP_* weights;
+		// reaching a level queues a notification
+		xpService = services.NewXPService(xpRepo, domain.XPWeights{
+			BetPlaced:            cfg.XP.BetPlaced,
+			BetWon:               cfg.XP.BetWon,
+			DailyLogin:           cfg.XP.DailyLogin,
+			EventJoined:          cfg.XP.EventJoined,
+			AchievementCompleted: cfg.XP.AchievementCompleted,
+		}, eventBus)
+		eventBus.Subscribe(xpService.OnDomainEvent,
+			domain.DomainEventBetSettled,
+			domain.DomainEventUserLoggedIn,
+			domain.DomainEventEventJoined,
+			domain.DomainEventAchievementCompleted,
+		)
+		eventBus.Subscribe(xpService.NotifyLevelUp, domain.DomainEventLevelUp)
+
+		// Quests: users get QUEST_DAILY_COUNT daily and QUEST_WEEKLY_COUNT weekly quests in their time zone;
+		// settled bets and activated referrals move them
+		questService = services.NewQuestService(questRepo, prizeRepo, prizeValueRepo, rewardService, cfg.Quests.DailyCount, cfg.Quests.WeeklyCount)
+		eventBus.Subscribe(questService.OnDomainEvent,
+			domain.DomainEventBetSettled,
+			domain.DomainEventBetClaimed,
+			domain.DomainEventReferralActivated,
+		)
+
+		// Login streaks: daily check-ins pay the login reward calendar; streak freezes cover up to
+		// LOGIN_STREAK_MAX_FREEZE_DAYS missed days
+		loginStreakService = services.NewLoginStreakService(loginStreakRepo, questRepo, prizeRepo, prizeValueRepo, rewardService, cfg.Streaks.MaxFreezeDays)
+
+		// Duels: the lead bet's settlement settles the duel; the duel job refunds expired proposals and settles
+		// duels whose lead bet was not closed by the scheduler
+		duelService = services.NewDuelService(duelRepo, betRepo, priceProvider, betScheduler, skillService, time.Duration(cfg.Duel.ExpiryMinutes)*time.Minute)
+		betScheduler.OnSettled(duelService.OnBetSettled)
+		backgroundJobs = append(backgroundJobs, services.NewPeriodicJob("duels", time.Duration(cfg.Duel.CheckIntervalMinutes)*time.Minute, duelService.Resolve))
+
+		// Seasons: first season starts at SEASON_FIRST_START (or the current UTC month), then rolls over every SEASON_LENGTH_DAYS
+		seasonFirstStart := time.Now().UTC()
+		seasonFirstStart = time.Date(seasonFirstStart.Year(), seasonFirstStart.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if cfg.Season.FirstStart != "" {
+			if parsed, err := time.Parse(time.RFC3339, cfg.Season.FirstStart); err == nil {
+				seasonFirstStart = parsed.UTC()
+			} else {
+				log.Printf("Warning: invalid SEASON_FIRST_START %q: %v", cfg.Season.FirstStart, err)
+			}
+		}
+		seasonService = services.NewSeasonService(seasonRepo, prizeValueRepo, seasonFirstStart, time.Duration(cfg.Season.LengthDays)*24*time.Hour)
+		backgroundJobs = append(backgroundJobs, services.NewPeriodicJob("season rollover", time.Duration(cfg.Season.CheckIntervalMinutes)*time.Minute, seasonService.Rollover))
+
+		leagueService = services.NewLeagueService(leagueRepo, prizeValueRepo, cfg.League.CohortSize, cfg.League.PromoteCount, cfg.League.RelegateCount)
+		backgroundJobs = append(backgroundJobs, services.NewPeriodicJob("league rollover", time.Duration(cfg.League.CheckIntervalMinutes)*time.Minute, leagueService.Rollover))
+
+		// Recurring events: instances are created ahead of their start by every replica; creation is idempotent
+		eventTemplateService = services.NewEventTemplateService(eventTemplateRepo, eventAdminService)
+		backgroundJobs = append(backgroundJobs, services.NewPeriodicJob("event templates", time.Duration(cfg.Events.TemplateCheckIntervalMinutes)*time.Minute, eventTemplateService.Run))
+
+		// Event lifecycle: events start and end by their schedule; entering a state runs its hooks first.
+		// Before an event is finished competition standings are frozen into event_results and prizes released,
+		// and squad standings are frozen into squad_results with the members' prize shares paid out;
+		// ranks of running competitions are snapshotted for the leaderboard's rank change
+		eventLifecycleService.OnEnter(domain.EventStateActive, eventService.PromoteWaitlist)
+		eventLifecycleService.OnEnter(domain.EventStateActive, rouletteService.OpenEventRoulettes)
+		eventLifecycleService.OnEnter(domain.EventStateFinished, eventService.FreezeResults)
+		eventLifecycleService.OnEnter(domain.EventStateFinished, eventService.ReleasePrizes)
+		eventLifecycleService.OnEnter(domain.EventStateFinished, eventService.PayPrizePool)
+		eventLifecycleService.OnEnter(domain.EventStateFinished, squadService.FreezeResults)
+		eventLifecycleService.OnEnter(domain.EventStateFinished, rouletteService.CloseEventRoulettes)
+		eventLifecycleService.OnEnter(domain.EventStateArchived, rouletteService.CloseEventRoulettes)
+		backgroundJobs = append(backgroundJobs, services.NewPeriodicJob("event lifecycle", time.Duration(cfg.Events.FinalizeCheckIntervalMinutes)*time.Minute, eventLifecycleService.Advance))
+		backgroundJobs = append(backgroundJobs, services.NewPeriodicJob("leaderboard snapshots", time.Duration(cfg.Events.SnapshotIntervalMinutes)*time.Minute, eventService.SnapshotLeaderboards))
+
+		for _, job := range backgroundJobs {
+			job.Start()
+		}
 	}
 
 	// Register HTTP handlers (eventService, rouletteService, betService, and achievementService may be nil if database unavailable)
-	http.NewHTTPHandler(e, userService, ratingService, eventService, rouletteService, betService, achievementService, authService, googleAuthService, googleOAuthConfig, telegramAuthService, cfg.JWT.SecretKey, cfg.JWT.StrictMode)
+	http.NewHTTPHandler(e, userService, ratingService, eventService, eventAdminService, eventTemplateService, rouletteService, betService, achievementService, seasonService, leagueService, skillService, referralService, squadService, duelService, inventoryService, profileService, xpService, questService, loginStreakService, authService, googleAuthService, googleOAuthConfig, telegramAuthService, cfg.JWT.SecretKey, cfg.JWT.StrictMode)
 
 	// Start server in a goroutine
 	addr := cfg.GetAddress()
@@ -159,6 +309,9 @@ func main() {
 	if betScheduler != nil {
 		betScheduler.Shutdown()
 	}
+	for _, job := range backgroundJobs {
+		job.Shutdown()
+	}
 
 	// Gracefully shutdown Echo server
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -19,6 +19,70 @@ type Config struct {
 	Telegram TelegramConfig
 	Google   GoogleConfig
 	Pyth     PythConfig
+	Season   SeasonConfig
+	League   LeagueConfig
+	Referral ReferralConfig
+	Events   EventsConfig
+	Duel     DuelConfig
+	XP       XPConfig
+	Quests   QuestsConfig
+	Streaks  LoginStreaksConfig
+}
+
+// QuestsConfig holds how many quests users get per period.
+type QuestsConfig struct {
+	DailyCount  int
+	WeeklyCount int
+}
+
+// LoginStreaksConfig holds how many missed days in a row streak freezes can cover.
+type LoginStreaksConfig struct {
+	MaxFreezeDays int
+}
+
+// XPConfig holds the XP granted per source; the level curve and perks live in the levels table.
+type XPConfig struct {
+	BetPlaced            int // per settled bet
+	BetWon               int // per won bet, on top of BetPlaced
+	DailyLogin           int // first sign-in of a UTC day
+	EventJoined          int
+	AchievementCompleted int
+}
+
+// DuelConfig holds head-to-head duel settings.
+type DuelConfig struct {
+	ExpiryMinutes        int // how long a proposed duel can be accepted
+	CheckIntervalMinutes int // how often expired duels are refunded and missed settlements caught up
+}
+
+// EventsConfig holds event scheduler settings.
+type EventsConfig struct {
+	TemplateCheckIntervalMinutes int // how often recurring event templates are materialized
+	FinalizeCheckIntervalMinutes int // how often events are moved along their lifecycle (start, finalization)
+	SnapshotIntervalMinutes      int // how often running competition ranks are snapshotted for rank change
+	SquadMaxSize                 int // member cap of new squads
+}
+
+// ReferralConfig holds referral reward settings.
+type ReferralConfig struct {
+	SignupBonus   int    // points paid to the direct referrer on the invitee's first completed bet
+	LevelPercents string // comma-separated % of invitee winnings per level, e.g. "10,5,2"
+	ActiveDays    int    // invitees with a bet in this window count as active
+}
+
+// LeagueConfig holds weekly league settings.
+type LeagueConfig struct {
+	CohortSize           int
+	PromoteCount         int // top N of each cohort move up a tier
+	RelegateCount        int // bottom N of each cohort move down a tier
+	CheckIntervalMinutes int
+}
+
+// SeasonConfig holds season rollover settings.
+type SeasonConfig struct {
+	FirstStart           string // RFC3339 start of the first season; empty = start of the current UTC month
+	LengthDays           int
+	CheckIntervalMinutes int
 }
 
 // PythConfig holds Pyth Network Hermes price feed settings (see https://docs.pyth.network/price-feeds/core/api-reference).
@@ -137,6 +201,46 @@ func Load() *Config {
 		Pyth: PythConfig{
 			HermesURL: getEnv("PYTH_HERMES_URL", "https://hermes.pyth.network"),
 		},
+		Season: SeasonConfig{
+			FirstStart:           getEnv("SEASON_FIRST_START", ""),
+			LengthDays:           getEnvAsInt("SEASON_LENGTH_DAYS", 30),
+			CheckIntervalMinutes: getEnvAsInt("SEASON_CHECK_INTERVAL_MINUTES", 5),
+		},
+		League: LeagueConfig{
+			CohortSize:           getEnvAsInt("LEAGUE_COHORT_SIZE", 50),
+			PromoteCount:         getEnvAsInt("LEAGUE_PROMOTE_COUNT", 10),
+			RelegateCount:        getEnvAsInt("LEAGUE_RELEGATE_COUNT", 10),
+			CheckIntervalMinutes: getEnvAsInt("LEAGUE_CHECK_INTERVAL_MINUTES", 5),
+		},
+		Referral: ReferralConfig{
+			SignupBonus:   getEnvAsInt("REFERRAL_SIGNUP_BONUS", 100),
+			LevelPercents: getEnv("REFERRAL_LEVEL_PERCENTS", "10,5,2"),
+			ActiveDays:    getEnvAsInt("REFERRAL_ACTIVE_DAYS", 30),
+		},
+		Events: EventsConfig{
+			TemplateCheckIntervalMinutes: getEnvAsInt("EVENT_TEMPLATE_CHECK_INTERVAL_MINUTES", 10),
+			FinalizeCheckIntervalMinutes: getEnvAsInt("EVENT_FINALIZE_CHECK_INTERVAL_MINUTES", 1),
+			SnapshotIntervalMinutes:      getEnvAsInt("EVENT_LEADERBOARD_SNAPSHOT_INTERVAL_MINUTES", 60),
+			SquadMaxSize:                 getEnvAsInt("SQUAD_MAX_SIZE", 5),
+		},
+		Duel: DuelConfig{
+			ExpiryMinutes:        getEnvAsInt("DUEL_EXPIRY_MINUTES", 60),
+			CheckIntervalMinutes: getEnvAsInt("DUEL_CHECK_INTERVAL_MINUTES", 1),
+		},
+		XP: XPConfig{
+			BetPlaced:            getEnvAsInt("XP_BET_PLACED", 10),
+			BetWon:               getEnvAsInt("XP_BET_WON", 15),
+			DailyLogin:           getEnvAsInt("XP_DAILY_LOGIN", 20),
+			EventJoined:          getEnvAsInt("XP_EVENT_JOINED", 50),
+			AchievementCompleted: getEnvAsInt("XP_ACHIEVEMENT_COMPLETED", 100),
+		},
+		Quests: QuestsConfig{
+			DailyCount:  getEnvAsInt("QUEST_DAILY_COUNT", 3),
+			WeeklyCount: getEnvAsInt("QUEST_WEEKLY_COUNT", 2),
+		},
+		Streaks: LoginStreaksConfig{
+			MaxFreezeDays: getEnvAsInt("LOGIN_STREAK_MAX_FREEZE_DAYS", 2),
+		},
 	}
 }
 
@@ -173,6 +277,20 @@ func (c *WAFConfig) GetWhitelistedPaths() []string {
 	return paths
 }
 
+// GetLevelPercents returns the referral winnings share per level; invalid entries are skipped
+func (c *ReferralConfig) GetLevelPercents() []float64 {
+	percents := []float64{}
+	for _, part := range splitAndTrim(c.LevelPercents, ",") {
+		parsed, err := strconv.ParseFloat(part, 64)
+		if err != nil || parsed < 0 {
+			log.Printf("Warning: ignoring invalid REFERRAL_LEVEL_PERCENTS entry %q", part)
+			continue
+		}
+		percents = append(percents, parsed)
+	}
+	return percents
+}
+
 func splitAndTrim(s, sep string) []string {
 	parts := []string{}
 	for _, part := range strings.Split(s, sep) {
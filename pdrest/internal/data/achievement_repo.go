@@ -3,9 +3,11 @@ package data
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"pdrest/internal/domain"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -24,6 +26,16 @@ type AchievementRepository interface {
 	UpdateUserAchievementClaimStatus(ctx context.Context, userUUID string, achievementID string, claimed bool) error
 	UpdateUserAchievementNeedSteps(ctx context.Context, userUUID string, achievementID string, needSteps int) error
 	UpsertUserAchievementProgress(ctx context.Context, userUUID string, achievementID string, stepsGot int, needSteps int, claimed bool) error
+	GetAchievementRules(ctx context.Context) ([]domain.AchievementRule, error)
+	GetAchievementRule(ctx context.Context, achievementID string) (*domain.AchievementRule, error)
+	CountAchievementMetric(ctx context.Context, userUUID string, rule domain.AchievementRule) (int, error)
+	CountWinStreaks(ctx context.Context, userUUID string, rule domain.AchievementRule) (int, int, error)
+	SetUserAchievementRunLength(ctx context.Context, userUUID string, achievementID string, runLength int) error
+	ApplyAchievementProgress(ctx context.Context, userUUID string, eventKey string, deltas []domain.AchievementProgressDelta, completed []string) ([]string, error)
+	GetAchievementsCompletedByEvent(ctx context.Context, userUUID string, eventKey string) ([]string, error)
+	QueueNotification(ctx context.Context, notification *domain.Notification) error
+	GetAchievementCatalog(ctx context.Context) ([]domain.AchievementCatalogEntry, error)
+	SaveAchievementCatalog(ctx context.Context, entries []domain.AchievementCatalogEntry) error
 }
 
 // PostgresAchievementRepository implements AchievementRepository with PostgreSQL.
@@ -391,3 +403,506 @@ func (r *PostgresAchievementRepository) UpsertUserAchievementProgress(ctx contex
 	}
 	return nil
 }
+
+func (r *PostgresAchievementRepository) GetAchievementRules(ctx context.Context) ([]domain.AchievementRule, error) {
+	query := `
+		SELECT achievement_id, metric, threshold, window_days, filters
+		FROM achievement_rules
+		ORDER BY metric ASC, threshold ASC, achievement_id ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get achievement rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []domain.AchievementRule
+	for rows.Next() {
+		rule, err := scanAchievementRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating achievement rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+func (r *PostgresAchievementRepository) GetAchievementRule(ctx context.Context, achievementID string) (*domain.AchievementRule, error) {
+	query := `
+		SELECT achievement_id, metric, threshold, window_days, filters
+		FROM achievement_rules
+		WHERE achievement_id = $1
+	`
+
+	rule, err := scanAchievementRule(r.pool.QueryRow(ctx, query, achievementID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return rule, nil
+}
+
+func scanAchievementRule(row pgx.Row) (*domain.AchievementRule, error) {
+	var rule domain.AchievementRule
+	var filtersJSON []byte
+	if err := row.Scan(&rule.AchievementID, &rule.Metric, &rule.Threshold, &rule.WindowDays, &filtersJSON); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan achievement rule: %w", err)
+	}
+	if len(filtersJSON) > 0 {
+		if err := json.Unmarshal(filtersJSON, &rule.Filters); err != nil {
+			return nil, fmt.Errorf("failed to parse achievement rule filters: %w", err)
+		}
+	}
+	return &rule, nil
+}
+
+// CountAchievementMetric counts the rule's metric for the user over the rule's window and filters.
+// Empty filters and a zero window match everything.
+func (r *PostgresAchievementRepository) CountAchievementMetric(ctx context.Context, userUUID string, rule domain.AchievementRule) (int, error) {
+	f := rule.Filters
+	var query string
+	var args []interface{}
+
+	switch rule.Metric {
+	case domain.AchievementMetricWins, domain.AchievementMetricPairWins:
+		query = `
+			SELECT COUNT(*)
+			FROM bets
+			WHERE user_uuid = $1
+			  AND close_price IS NOT NULL
+			  AND (
+				(side = 'pump' AND close_price > open_price) OR
+				(side = 'dump' AND close_price < open_price)
+			  )
+			  AND ($2 = '' OR pair = $2)
+			  AND ($3 = '' OR side = $3)
+			  AND ($4 = 0 OR timeframe = $4)
+			  AND ($5 = 0 OR close_time >= (NOW() AT TIME ZONE 'UTC') - make_interval(days => $5))
+		`
+		args = []interface{}{userUUID, f.Pair, f.Side, f.Timeframe, rule.WindowDays}
+	case domain.AchievementMetricBetsPlaced:
+		query = `
+			SELECT COUNT(*)
+			FROM bets
+			WHERE user_uuid = $1
+			  AND ($2 = '' OR pair = $2)
+			  AND ($3 = '' OR side = $3)
+			  AND ($4 = 0 OR timeframe = $4)
+			  AND ($5 = 0 OR open_time >= (NOW() AT TIME ZONE 'UTC') - make_interval(days => $5))
+		`
+		args = []interface{}{userUUID, f.Pair, f.Side, f.Timeframe, rule.WindowDays}
+	case domain.AchievementMetricWinStreak:
+		best, _, err := r.CountWinStreaks(ctx, userUUID, rule)
+		return best, err
+	case domain.AchievementMetricReferrals:
+		query = `
+			SELECT COUNT(*)
+			FROM users
+			WHERE referrer_user_uuid = $1
+			  AND ($2 = 0 OR created_at >= (EXTRACT(EPOCH FROM NOW())::BIGINT - $2::BIGINT * 86400) * 1000)
+		`
+		args = []interface{}{userUUID, rule.WindowDays}
+	case domain.AchievementMetricLoginDays:
+		query = `
+			SELECT COUNT(*)
+			FROM user_login_days
+			WHERE user_uuid = $1
+			  AND ($2 = 0 OR login_day > (NOW() AT TIME ZONE 'UTC')::date - $2::INTEGER)
+		`
+		args = []interface{}{userUUID, rule.WindowDays}
+	case domain.AchievementMetricEventPlacements:
+		query = `
+			SELECT COUNT(*)
+			FROM event_results er
+			JOIN all_events e ON e.id = er.event_id
+			WHERE er.user_uuid = $1
+			  AND ($2 = 0 OR er.rank <= $2)
+			  AND ($3 = '' OR $3 = ANY(string_to_array(REPLACE(COALESCE(e.tags, ''), ' ', ''), ',')))
+			  AND ($4 = 0 OR er.created_at >= (EXTRACT(EPOCH FROM NOW())::BIGINT - $4::BIGINT * 86400) * 1000)
+		`
+		args = []interface{}{userUUID, f.MaxRank, strings.TrimSpace(f.EventTag), rule.WindowDays}
+	default:
+		return 0, fmt.Errorf("unsupported achievement metric %q", rule.Metric)
+	}
+
+	var count int
+	if err := r.pool.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count achievement metric %s: %w", rule.Metric, err)
+	}
+	return count, nil
+}
+
+// CountWinStreaks returns the user's longest run of consecutive winning bets and the run they are on now,
+// over the rule's window and bet filters.
+func (r *PostgresAchievementRepository) CountWinStreaks(ctx context.Context, userUUID string, rule domain.AchievementRule) (int, int, error) {
+	// Gaps and islands: consecutive wins share the same difference of row numbers.
+	query := `
+		WITH settled AS (
+			SELECT id, close_time,
+			       ((side = 'pump' AND close_price > open_price) OR
+			        (side = 'dump' AND close_price < open_price)) AS won
+			FROM bets
+			WHERE user_uuid = $1
+			  AND close_price IS NOT NULL
+			  AND ($2 = '' OR pair = $2)
+			  AND ($3 = '' OR side = $3)
+			  AND ($4 = 0 OR timeframe = $4)
+			  AND ($5 = 0 OR close_time >= (NOW() AT TIME ZONE 'UTC') - make_interval(days => $5))
+		),
+		runs AS (
+			SELECT id, close_time, won,
+			       ROW_NUMBER() OVER (ORDER BY close_time, id) -
+			       ROW_NUMBER() OVER (PARTITION BY won ORDER BY close_time, id) AS run
+			FROM settled
+		),
+		win_runs AS (
+			SELECT run, COUNT(*) AS run_length, MAX(close_time) AS ended_at
+			FROM runs
+			WHERE won
+			GROUP BY run
+		)
+		SELECT COALESCE(MAX(run_length), 0),
+		       COALESCE(MAX(run_length) FILTER (
+		           WHERE ended_at >= (SELECT MAX(close_time) FROM settled)
+		       ), 0)
+		FROM win_runs
+	`
+
+	f := rule.Filters
+	var best, current int
+	if err := r.pool.QueryRow(ctx, query, userUUID, f.Pair, f.Side, f.Timeframe, rule.WindowDays).Scan(&best, &current); err != nil {
+		return 0, 0, fmt.Errorf("failed to count win streaks: %w", err)
+	}
+	return best, current, nil
+}
+
+func (r *PostgresAchievementRepository) SetUserAchievementRunLength(ctx context.Context, userUUID string, achievementID string, runLength int) error {
+	query := `
+		UPDATE user_achievements
+		SET run_length = $3
+		WHERE user_uuid = $1 AND achievement_id = $2
+	`
+
+	if _, err := r.pool.Exec(ctx, query, userUUID, achievementID, runLength); err != nil {
+		return fmt.Errorf("failed to update run_length: %w", err)
+	}
+	return nil
+}
+
+// ApplyAchievementProgress applies an event's counter deltas once per user and event key, and records the
+// achievements the event completed: those completed by the deltas plus the given ones (completed by the
+// caller, e.g. by recounting windowed rules). An event key seen before applies no deltas.
+// Returns every achievement recorded as completed by this call.
+func (r *PostgresAchievementRepository) ApplyAchievementProgress(ctx context.Context, userUUID string, eventKey string, deltas []domain.AchievementProgressDelta, completed []string) ([]string, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin achievement progress transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	insertLogQuery := `
+		INSERT INTO achievement_event_log (user_uuid, event_key)
+		VALUES ($1, $2)
+		ON CONFLICT (user_uuid, event_key) DO NOTHING
+	`
+	tag, err := tx.Exec(ctx, insertLogQuery, userUUID, eventKey)
+	if err != nil {
+		err = fmt.Errorf("failed to record achievement event: %w", err)
+		return nil, err
+	}
+
+	completedIDs := append([]string{}, completed...)
+	if tag.RowsAffected() > 0 {
+		// Only unclaimed, uncompleted progress moves; the returned flag is whether this update completed it.
+		counterQuery := `
+			UPDATE user_achievements
+			SET steps_got = LEAST(steps_got + $3, COALESCE(NULLIF(need_steps, 0), $4))
+			WHERE user_uuid = $1 AND achievement_id = $2
+			  AND claimed_status = FALSE
+			  AND steps_got < COALESCE(NULLIF(need_steps, 0), $4)
+			RETURNING steps_got >= COALESCE(NULLIF(need_steps, 0), $4)
+		`
+		streakQuery := `
+			UPDATE user_achievements
+			SET run_length = CASE WHEN $5 THEN 0 ELSE run_length + $3 END,
+			    steps_got = CASE WHEN $5 THEN steps_got
+			                ELSE GREATEST(steps_got, LEAST(run_length + $3, COALESCE(NULLIF(need_steps, 0), $4))) END
+			WHERE user_uuid = $1 AND achievement_id = $2
+			  AND claimed_status = FALSE
+			  AND steps_got < COALESCE(NULLIF(need_steps, 0), $4)
+			RETURNING steps_got >= COALESCE(NULLIF(need_steps, 0), $4)
+		`
+		for _, delta := range deltas {
+			var done bool
+			if delta.Streak {
+				err = tx.QueryRow(ctx, streakQuery, userUUID, delta.AchievementID, delta.Increment, delta.NeedSteps, delta.ResetRun).Scan(&done)
+			} else {
+				err = tx.QueryRow(ctx, counterQuery, userUUID, delta.AchievementID, delta.Increment, delta.NeedSteps).Scan(&done)
+			}
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					err = nil
+					continue
+				}
+				err = fmt.Errorf("failed to advance achievement %s: %w", delta.AchievementID, err)
+				return nil, err
+			}
+			if done {
+				completedIDs = append(completedIDs, delta.AchievementID)
+			}
+		}
+	}
+
+	if len(completedIDs) > 0 {
+		updateLogQuery := `
+			UPDATE achievement_event_log
+			SET completed_achievements = completed_achievements || $3::TEXT[]
+			WHERE user_uuid = $1 AND event_key = $2
+		`
+		if _, err = tx.Exec(ctx, updateLogQuery, userUUID, eventKey, completedIDs); err != nil {
+			err = fmt.Errorf("failed to record completed achievements: %w", err)
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf("failed to commit achievement progress: %w", err)
+		return nil, err
+	}
+	return completedIDs, nil
+}
+
+func (r *PostgresAchievementRepository) GetAchievementsCompletedByEvent(ctx context.Context, userUUID string, eventKey string) ([]string, error) {
+	query := `
+		SELECT completed_achievements
+		FROM achievement_event_log
+		WHERE user_uuid = $1 AND event_key = $2
+	`
+
+	var completed []string
+	if err := r.pool.QueryRow(ctx, query, userUUID, eventKey).Scan(&completed); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get achievements completed by event: %w", err)
+	}
+	return completed, nil
+}
+
+func (r *PostgresAchievementRepository) QueueNotification(ctx context.Context, notification *domain.Notification) error {
+	query := `
+		INSERT INTO notifications (user_id, producer, message, created_at, updated_at)
+		VALUES ($1, $2, $3, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+	`
+	if _, err := r.pool.Exec(ctx, query, notification.UserUUID, notification.Producer, notification.Message); err != nil {
+		return fmt.Errorf("failed to queue notification: %w", err)
+	}
+	return nil
+}
+
+// GetAchievementCatalog returns the achievements managed by the catalog sheet (all but event placement
+// achievements, which belong to their events) with their rules and prizes.
+func (r *PostgresAchievementRepository) GetAchievementCatalog(ctx context.Context) ([]domain.AchievementCatalogEntry, error) {
+	query := `
+		SELECT a.id, a.badge, a.title, a.image_url, a.desc_text, COALESCE(a.tags, ''), a.prize_id, a.steps,
+		       COALESCE(a.step_desc, ''),
+		       r.metric, r.threshold, r.window_days, r.filters,
+		       pv.value, pv.label
+		FROM achievements a
+		LEFT JOIN achievement_rules r ON r.achievement_id = a.id
+		LEFT JOIN prize_values pv ON pv.id = a.prize_id
+		WHERE NOT ('event' = ANY(string_to_array(REPLACE(COALESCE(a.tags, ''), ' ', ''), ',')))
+		ORDER BY a.id ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get achievement catalog: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []domain.AchievementCatalogEntry
+	for rows.Next() {
+		var entry domain.AchievementCatalogEntry
+		var metric *string
+		var threshold, windowDays *int
+		var filtersJSON []byte
+		var prizeValue *int64
+		var prizeLabel *string
+		a := &entry.Achievement
+		if err := rows.Scan(
+			&a.ID, &a.Badge, &a.Title, &a.ImageURL, &a.Desc, &a.Tags, &a.PrizeID, &a.Steps, &a.StepDesc,
+			&metric, &threshold, &windowDays, &filtersJSON,
+			&prizeValue, &prizeLabel,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan achievement catalog entry: %w", err)
+		}
+		if metric != nil {
+			entry.Rule = domain.AchievementRule{AchievementID: a.ID, Metric: *metric, Threshold: *threshold, WindowDays: *windowDays}
+			if len(filtersJSON) > 0 {
+				if err := json.Unmarshal(filtersJSON, &entry.Rule.Filters); err != nil {
+					return nil, fmt.Errorf("failed to parse achievement rule filters: %w", err)
+				}
+			}
+		}
+		if prizeValue != nil && prizeLabel != nil {
+			entry.PrizeValue = *prizeValue
+			entry.Prize = *prizeLabel
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating achievement catalog: %w", err)
+	}
+
+	return entries, nil
+}
+
+// SaveAchievementCatalog upserts the entries' achievements and rules in one transaction. New achievements
+// are inserted as given; existing ones keep their texts and get the entry's image, tags and steps.
+// An entry with a prize links the achievement to that prize value, created on the event of its current
+// prize or, for achievements without one, on an achievements event named after the achievement.
+func (r *PostgresAchievementRepository) SaveAchievementCatalog(ctx context.Context, entries []domain.AchievementCatalogEntry) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin achievement catalog transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	upsertAchievementQuery := `
+		INSERT INTO achievements (id, badge, title, image_url, desc_text, tags, steps, step_desc, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+		ON CONFLICT (id) DO UPDATE SET
+			image_url = EXCLUDED.image_url,
+			tags = EXCLUDED.tags,
+			steps = EXCLUDED.steps
+	`
+	upsertRuleQuery := `
+		INSERT INTO achievement_rules (achievement_id, metric, threshold, window_days, filters)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (achievement_id) DO UPDATE SET
+			metric = EXCLUDED.metric,
+			threshold = EXCLUDED.threshold,
+			window_days = EXCLUDED.window_days,
+			filters = EXCLUDED.filters,
+			updated_at = EXTRACT(EPOCH FROM NOW())::BIGINT * 1000
+	`
+	for _, entry := range entries {
+		a := entry.Achievement
+		if _, err = tx.Exec(ctx, upsertAchievementQuery, a.ID, a.Badge, a.Title, a.ImageURL, a.Desc, a.Tags, a.Steps, a.StepDesc); err != nil {
+			err = fmt.Errorf("failed to save achievement %s: %w", a.ID, err)
+			return err
+		}
+
+		filtersJSON, marshalErr := json.Marshal(entry.Rule.Filters)
+		if marshalErr != nil {
+			err = fmt.Errorf("failed to marshal achievement rule filters: %w", marshalErr)
+			return err
+		}
+		if _, err = tx.Exec(ctx, upsertRuleQuery, a.ID, entry.Rule.Metric, entry.Rule.Threshold, entry.Rule.WindowDays, filtersJSON); err != nil {
+			err = fmt.Errorf("failed to save achievement rule %s: %w", a.ID, err)
+			return err
+		}
+
+		if entry.Prize != "" {
+			if err = linkAchievementPrize(ctx, tx, a, entry.PrizeValue, entry.Prize); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf("failed to commit achievement catalog: %w", err)
+		return err
+	}
+	return nil
+}
+
+// linkAchievementPrize points the achievement at the prize value (value, label), reusing a matching prize
+// value of the achievement's prize event.
+func linkAchievementPrize(ctx context.Context, tx pgx.Tx, achievement domain.Achievement, value int64, label string) error {
+	var eventID *string
+	var currentValue *int64
+	var currentLabel *string
+	currentQuery := `
+		SELECT pv.event_id, pv.value, pv.label
+		FROM achievements a
+		LEFT JOIN prize_values pv ON pv.id = a.prize_id
+		WHERE a.id = $1
+	`
+	if err := tx.QueryRow(ctx, currentQuery, achievement.ID).Scan(&eventID, &currentValue, &currentLabel); err != nil {
+		return fmt.Errorf("failed to get achievement prize: %w", err)
+	}
+	if currentValue != nil && currentLabel != nil && *currentValue == value && *currentLabel == label {
+		return nil
+	}
+
+	if eventID == nil {
+		// Like the global win achievements, each achievement's prizes hang off their own achievements event
+		id := achievement.ID
+		eventID = &id
+		var tags *string
+		err := tx.QueryRow(ctx, `SELECT tags FROM all_events WHERE id = $1`, id).Scan(&tags)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			insertEventQuery := `
+				INSERT INTO all_events (id, badge, title, desc_text, deadline, tags, reward, info, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, EXTRACT(EPOCH FROM (NOW() + INTERVAL '3650 days'))::BIGINT * 1000, 'achivements',
+				        jsonb_build_array(jsonb_build_object('place', 'any', 'value', $5::TEXT)), $6,
+				        EXTRACT(EPOCH FROM NOW())::BIGINT * 1000, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+			`
+			if _, err := tx.Exec(ctx, insertEventQuery, id, achievement.Badge, achievement.Title, achievement.Desc, label, "Achievement prize event: "+id); err != nil {
+				return fmt.Errorf("failed to create achievement prize event %s: %w", id, err)
+			}
+		case err != nil:
+			return fmt.Errorf("failed to get achievement prize event: %w", err)
+		case tags == nil || *tags != "achivements":
+			return fmt.Errorf("cannot link prize of achievement %s: event %s already exists and is not an achievements event", id, id)
+		}
+	}
+
+	var prizeValueID int
+	findPrizeQuery := `
+		SELECT id FROM prize_values
+		WHERE event_id = $1 AND value = $2 AND label = $3 AND reward_type = 'points'
+		ORDER BY id ASC
+		LIMIT 1
+	`
+	err := tx.QueryRow(ctx, findPrizeQuery, *eventID, value, label).Scan(&prizeValueID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		insertPrizeQuery := `
+			INSERT INTO prize_values (event_id, value, label, segment_id, created_at, updated_at)
+			VALUES ($1, $2, $3, NULL, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+			RETURNING id
+		`
+		err = tx.QueryRow(ctx, insertPrizeQuery, *eventID, value, label).Scan(&prizeValueID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save prize value for achievement %s: %w", achievement.ID, err)
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE achievements SET prize_id = $2 WHERE id = $1`, achievement.ID, prizeValueID); err != nil {
+		return fmt.Errorf("failed to link prize of achievement %s: %w", achievement.ID, err)
+	}
+	return nil
+}
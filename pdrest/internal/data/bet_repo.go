@@ -2,6 +2,7 @@ package data
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"pdrest/internal/domain"
 	"time"
@@ -13,6 +14,7 @@ import (
 type BetRepository interface {
 	CreateBet(ctx context.Context, bet *domain.Bet) error
 	GetBetByID(ctx context.Context, betID int, userUUID string) (*domain.Bet, error)
+	GetBetByIDAnyUser(ctx context.Context, betID int) (*domain.Bet, error)
 	UpdateBetClosePrice(ctx context.Context, betID int, closePrice float64, closeTime time.Time) error
 	UpdateBetClaimStatus(ctx context.Context, betID int, userUUID string, claimed bool) error
 	GetWinningBetsByUser(ctx context.Context, userUUID string) ([]domain.Bet, error)
@@ -74,7 +76,7 @@ func (r *PostgresBetRepository) CreateBet(ctx context.Context, bet *domain.Bet)
 
 func (r *PostgresBetRepository) GetBetByID(ctx context.Context, betID int, userUUID string) (*domain.Bet, error) {
 	query := `
-		SELECT id, user_uuid, side, sum, pair, timeframe, open_price, close_price, open_time, close_time, claimed_status, created_at, updated_at
+		SELECT id, user_uuid, side, sum, pair, timeframe, open_price, close_price, open_time, close_time, claimed_status, created_at, updated_at, duel_id, modifiers
 		FROM bets
 		WHERE id = $1 AND user_uuid = $2
 	`
@@ -82,6 +84,7 @@ func (r *PostgresBetRepository) GetBetByID(ctx context.Context, betID int, userU
 	var bet domain.Bet
 	var closePrice *float64
 	var closeTime *time.Time
+	var modifiersJSON []byte
 
 	err := r.pool.QueryRow(ctx, query, betID, userUUID).Scan(
 		&bet.ID,
@@ -97,6 +100,8 @@ func (r *PostgresBetRepository) GetBetByID(ctx context.Context, betID int, userU
 		&bet.Claimed,
 		&bet.CreatedAt,
 		&bet.UpdatedAt,
+		&bet.DuelID,
+		&modifiersJSON,
 	)
 
 	if err != nil {
@@ -105,6 +110,66 @@ func (r *PostgresBetRepository) GetBetByID(ctx context.Context, betID int, userU
 		}
 		return nil, fmt.Errorf("failed to get bet: %w", err)
 	}
+	if len(modifiersJSON) > 0 {
+		if err := json.Unmarshal(modifiersJSON, &bet.Modifiers); err != nil {
+			return nil, fmt.Errorf("failed to parse bet modifiers: %w", err)
+		}
+	}
+
+	bet.ClosePrice = closePrice
+	bet.OpenTime = normalizeBetTimestamp(bet.OpenTime)
+	if closeTime != nil {
+		normalized := normalizeBetTimestamp(*closeTime)
+		bet.CloseTime = &normalized
+	} else {
+		bet.CloseTime = nil
+	}
+
+	return &bet, nil
+}
+
+// GetBetByIDAnyUser loads a bet without an ownership check (for background settlement).
+func (r *PostgresBetRepository) GetBetByIDAnyUser(ctx context.Context, betID int) (*domain.Bet, error) {
+	query := `
+		SELECT id, user_uuid, side, sum, pair, timeframe, open_price, close_price, open_time, close_time, claimed_status, created_at, updated_at, duel_id, modifiers
+		FROM bets
+		WHERE id = $1
+	`
+
+	var bet domain.Bet
+	var closePrice *float64
+	var closeTime *time.Time
+	var modifiersJSON []byte
+
+	err := r.pool.QueryRow(ctx, query, betID).Scan(
+		&bet.ID,
+		&bet.UserID,
+		&bet.Side,
+		&bet.Sum,
+		&bet.Pair,
+		&bet.Timeframe,
+		&bet.OpenPrice,
+		&closePrice,
+		&bet.OpenTime,
+		&closeTime,
+		&bet.Claimed,
+		&bet.CreatedAt,
+		&bet.UpdatedAt,
+		&bet.DuelID,
+		&modifiersJSON,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get bet: %w", err)
+	}
+	if len(modifiersJSON) > 0 {
+		if err := json.Unmarshal(modifiersJSON, &bet.Modifiers); err != nil {
+			return nil, fmt.Errorf("failed to parse bet modifiers: %w", err)
+		}
+	}
 
 	bet.ClosePrice = closePrice
 	bet.OpenTime = normalizeBetTimestamp(bet.OpenTime)
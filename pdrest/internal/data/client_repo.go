@@ -3,6 +3,7 @@ package data
 import (
 	"context"
 	"pdrest/internal/domain"
+	"time"
 )
 
 type UserRepository interface {
@@ -24,7 +25,9 @@ type UserRepository interface {
 	ApplyReferralCode(ctx context.Context, userUUID string, referralCode string) error
 	SetReferrerByInviterUUID(ctx context.Context, userUUID string, inviterUUID string) error
 	SetReferrerByInviterTGID(ctx context.Context, userUUID string, inviterTGID int64) error
+	GetReferrerUUID(ctx context.Context, userUUID string) (string, error)
 	UpdateUserLanguage(ctx context.Context, userUUID string, language string) error
+	UpdateLastLogin(ctx context.Context, userUUID string) error
 }
 
 type InMemoryUserRepository struct {
@@ -130,7 +133,18 @@ func (r *InMemoryUserRepository) SetReferrerByInviterTGID(ctx context.Context, u
 	return nil
 }
 
+func (r *InMemoryUserRepository) GetReferrerUUID(ctx context.Context, userUUID string) (string, error) {
+	// In-memory repository doesn't track referrers
+	return "", nil
+}
+
 func (r *InMemoryUserRepository) UpdateUserLanguage(ctx context.Context, userUUID string, language string) error {
 	// In-memory repository doesn't support user updates
 	return nil
 }
+
+func (r *InMemoryUserRepository) UpdateLastLogin(ctx context.Context, userUUID string) error {
+	now := time.Now().UnixMilli()
+	r.storage[userUUID] = &now
+	return nil
+}
@@ -0,0 +1,445 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"pdrest/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DuelRepository provides access to duels and their escrowed stakes.
+type DuelRepository interface {
+	AreFriends(ctx context.Context, userUUID string, otherUUID string) (bool, error)
+	CreateDuel(ctx context.Context, duel *domain.Duel, notification *domain.Notification) error
+	GetDuel(ctx context.Context, duelID int) (*domain.Duel, error)
+	GetDuelByInviteCode(ctx context.Context, inviteCode string) (*domain.Duel, error)
+	GetDuelByBetID(ctx context.Context, betID int) (*domain.Duel, error)
+	GetUserDuels(ctx context.Context, userUUID string, limit, offset int) ([]domain.Duel, error)
+	AcceptDuel(ctx context.Context, duelID int, opponentUUID string, openPrice float64, openTime time.Time, nowMs int64) (bool, error)
+	EndPendingDuel(ctx context.Context, duelID int, status string, notification *domain.Notification) (bool, error)
+	SettleDuel(ctx context.Context, duelID int, closePrice float64, closeTime time.Time, winnerUUID string, notifications []domain.Notification, nowMs int64) (bool, error)
+	GetDuelsToResolve(ctx context.Context, nowMs int64, closedBefore time.Time) ([]domain.Duel, error)
+}
+
+type PostgresDuelRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresDuelRepository(pool *pgxpool.Pool) *PostgresDuelRepository {
+	return &PostgresDuelRepository{pool: pool}
+}
+
+// AreFriends reports whether one of the users invited the other.
+func (r *PostgresDuelRepository) AreFriends(ctx context.Context, userUUID string, otherUUID string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM users
+			WHERE (user_uuid = $1 AND referrer_user_uuid = $2)
+			   OR (user_uuid = $2 AND referrer_user_uuid = $1)
+		)
+	`
+
+	var friends bool
+	if err := r.pool.QueryRow(ctx, query, userUUID, otherUUID).Scan(&friends); err != nil {
+		return false, fmt.Errorf("failed to check friendship: %w", err)
+	}
+	return friends, nil
+}
+
+// CreateDuel escrows the challenger's stake and stores the duel, queuing the notification for the
+// challenged friend if any. Fails if the challenger has fewer points than the stake.
+func (r *PostgresDuelRepository) CreateDuel(ctx context.Context, duel *domain.Duel, notification *domain.Notification) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin duel transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	paid, debitErr := debitRatingPoints(ctx, tx, duel.ChallengerUUID, duel.Stake, fmt.Sprintf("Duel stake: %s", duel.Pair), domain.RatingSourceDuel)
+	if debitErr != nil {
+		err = debitErr
+		return err
+	}
+	if !paid {
+		err = errors.New("not enough points for the stake")
+		return err
+	}
+
+	queryInsert := `
+		INSERT INTO duels (challenger_uuid, opponent_uuid, invite_code, pair, timeframe, stake, challenger_side, status, expires_at, created_at, updated_at)
+		VALUES ($1, NULLIF($2, '')::UUID, $3, $4, $5, $6, $7, $8, $9, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+		ON CONFLICT (invite_code) DO NOTHING
+		RETURNING id, created_at
+	`
+	if err = tx.QueryRow(ctx, queryInsert,
+		duel.ChallengerUUID,
+		duel.OpponentUUID,
+		duel.InviteCode,
+		duel.Pair,
+		duel.Timeframe,
+		duel.Stake,
+		duel.ChallengerSide,
+		domain.DuelPending,
+		duel.ExpiresAt,
+	).Scan(&duel.ID, &duel.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = errors.New("invite code already exists")
+			return err
+		}
+		return fmt.Errorf("failed to create duel: %w", err)
+	}
+	duel.Status = domain.DuelPending
+
+	if notification != nil {
+		if err = insertNotification(ctx, tx, notification); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit duel transaction: %w", err)
+	}
+	return nil
+}
+
+const duelSelect = `
+	SELECT d.id, d.challenger_uuid::text, COALESCE(d.opponent_uuid::text, ''), d.invite_code, d.pair, d.timeframe, d.stake,
+		d.challenger_side, d.status, d.challenger_bet_id, d.opponent_bet_id, d.open_price::DOUBLE PRECISION, d.close_price::DOUBLE PRECISION,
+		d.open_time, COALESCE(d.winner_uuid::text, ''), d.expires_at, d.accepted_at, d.settled_at, d.created_at,
+		c.google_name, c.telegram_username, c.telegram_first_name, c.telegram_last_name,
+		c.display_name, COALESCE(c.hide_name, FALSE),
+		o.google_name, o.telegram_username, o.telegram_first_name, o.telegram_last_name,
+		o.display_name, COALESCE(o.hide_name, FALSE)
+	FROM duels d
+	JOIN users c ON c.user_uuid = d.challenger_uuid
+	LEFT JOIN users o ON o.user_uuid = d.opponent_uuid
+`
+
+// GetDuel retrieves a duel by ID. Returns nil if it does not exist.
+func (r *PostgresDuelRepository) GetDuel(ctx context.Context, duelID int) (*domain.Duel, error) {
+	duel, err := scanDuel(r.pool.QueryRow(ctx, duelSelect+`WHERE d.id = $1`, duelID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get duel: %w", err)
+	}
+	return duel, nil
+}
+
+// GetDuelByInviteCode retrieves the duel with the invite code. Returns nil if there is none.
+func (r *PostgresDuelRepository) GetDuelByInviteCode(ctx context.Context, inviteCode string) (*domain.Duel, error) {
+	duel, err := scanDuel(r.pool.QueryRow(ctx, duelSelect+`WHERE d.invite_code = $1`, inviteCode))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get duel by invite code: %w", err)
+	}
+	return duel, nil
+}
+
+// GetDuelByBetID retrieves the duel the bet is a position of. Returns nil for ordinary bets.
+func (r *PostgresDuelRepository) GetDuelByBetID(ctx context.Context, betID int) (*domain.Duel, error) {
+	duel, err := scanDuel(r.pool.QueryRow(ctx, duelSelect+`WHERE d.challenger_bet_id = $1 OR d.opponent_bet_id = $1`, betID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get duel by bet: %w", err)
+	}
+	return duel, nil
+}
+
+// GetUserDuels lists the duels the user proposed or was challenged to, newest first.
+func (r *PostgresDuelRepository) GetUserDuels(ctx context.Context, userUUID string, limit, offset int) ([]domain.Duel, error) {
+	query := duelSelect + `
+		WHERE d.challenger_uuid = $1 OR d.opponent_uuid = $1
+		ORDER BY d.created_at DESC, d.id DESC
+		LIMIT $2 OFFSET $3
+	`
+	return r.queryDuels(ctx, query, userUUID, limit, offset)
+}
+
+// AcceptDuel escrows the opponent's stake and opens both positions as bets at the given price and time.
+// Returns false if the duel is no longer pending, has expired or is meant for someone else.
+func (r *PostgresDuelRepository) AcceptDuel(ctx context.Context, duelID int, opponentUUID string, openPrice float64, openTime time.Time, nowMs int64) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin accept duel transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	var challengerUUID, pair, challengerSide string
+	var timeframe int
+	var stake int64
+	queryLock := `
+		SELECT challenger_uuid::text, pair, timeframe, stake, challenger_side
+		FROM duels
+		WHERE id = $1
+		  AND status = 'pending'
+		  AND expires_at > $3
+		  AND challenger_uuid <> $2
+		  AND (opponent_uuid IS NULL OR opponent_uuid = $2)
+		FOR UPDATE
+	`
+	if scanErr := tx.QueryRow(ctx, queryLock, duelID, opponentUUID, nowMs).Scan(&challengerUUID, &pair, &timeframe, &stake, &challengerSide); scanErr != nil {
+		if errors.Is(scanErr, pgx.ErrNoRows) {
+			_ = tx.Rollback(ctx)
+			return false, nil
+		}
+		err = scanErr
+		return false, fmt.Errorf("failed to lock duel: %w", err)
+	}
+
+	paid, debitErr := debitRatingPoints(ctx, tx, opponentUUID, stake, fmt.Sprintf("Duel stake: %s", pair), domain.RatingSourceDuel)
+	if debitErr != nil {
+		err = debitErr
+		return false, err
+	}
+	if !paid {
+		err = errors.New("not enough points for the stake")
+		return false, err
+	}
+
+	duel := domain.Duel{ChallengerSide: challengerSide}
+	queryBet := `
+		INSERT INTO bets (user_uuid, side, sum, pair, timeframe, open_price, open_time, duel_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+	var challengerBetID, opponentBetID int
+	if err = tx.QueryRow(ctx, queryBet, challengerUUID, challengerSide, stake, pair, timeframe, openPrice, openTime, duelID).Scan(&challengerBetID); err != nil {
+		return false, fmt.Errorf("failed to open challenger position: %w", err)
+	}
+	if err = tx.QueryRow(ctx, queryBet, opponentUUID, duel.OpponentSide(), stake, pair, timeframe, openPrice, openTime, duelID).Scan(&opponentBetID); err != nil {
+		return false, fmt.Errorf("failed to open opponent position: %w", err)
+	}
+
+	queryAccept := `
+		UPDATE duels
+		SET status = 'active', opponent_uuid = $2, challenger_bet_id = $3, opponent_bet_id = $4,
+		    open_price = $5, open_time = $6, accepted_at = $7, updated_at = $7
+		WHERE id = $1
+	`
+	if _, err = tx.Exec(ctx, queryAccept, duelID, opponentUUID, challengerBetID, opponentBetID, openPrice, openTime, nowMs); err != nil {
+		return false, fmt.Errorf("failed to accept duel: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit accept duel transaction: %w", err)
+	}
+	return true, nil
+}
+
+// EndPendingDuel declines, cancels or expires a pending duel and refunds the challenger's stake.
+// Returns false if the duel is no longer pending.
+func (r *PostgresDuelRepository) EndPendingDuel(ctx context.Context, duelID int, status string, notification *domain.Notification) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin duel transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	var challengerUUID, pair string
+	var stake int64
+	queryEnd := `
+		UPDATE duels
+		SET status = $2, updated_at = EXTRACT(EPOCH FROM NOW())::BIGINT * 1000
+		WHERE id = $1 AND status = 'pending'
+		RETURNING challenger_uuid::text, pair, stake
+	`
+	if scanErr := tx.QueryRow(ctx, queryEnd, duelID, status).Scan(&challengerUUID, &pair, &stake); scanErr != nil {
+		if errors.Is(scanErr, pgx.ErrNoRows) {
+			_ = tx.Rollback(ctx)
+			return false, nil
+		}
+		err = scanErr
+		return false, fmt.Errorf("failed to end duel: %w", err)
+	}
+
+	if err = creditRatingPoints(ctx, tx, challengerUUID, stake, fmt.Sprintf("Duel stake refund: %s", pair), domain.RatingSourceDuel); err != nil {
+		return false, err
+	}
+	if notification != nil {
+		if err = insertNotification(ctx, tx, notification); err != nil {
+			return false, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit duel transaction: %w", err)
+	}
+	return true, nil
+}
+
+// SettleDuel closes the duel's positions at the close price and pays the pot: both stakes to the winner,
+// or each stake back on a draw (empty winnerUUID). Returns false if the duel was already settled.
+func (r *PostgresDuelRepository) SettleDuel(ctx context.Context, duelID int, closePrice float64, closeTime time.Time, winnerUUID string, notifications []domain.Notification, nowMs int64) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin settle duel transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	var challengerUUID, opponentUUID, pair string
+	var stake int64
+	querySettle := `
+		UPDATE duels
+		SET status = 'settled', close_price = $2, winner_uuid = NULLIF($3, '')::UUID, settled_at = $4, updated_at = $4
+		WHERE id = $1 AND status = 'active'
+		RETURNING challenger_uuid::text, opponent_uuid::text, pair, stake
+	`
+	if scanErr := tx.QueryRow(ctx, querySettle, duelID, closePrice, winnerUUID, nowMs).Scan(&challengerUUID, &opponentUUID, &pair, &stake); scanErr != nil {
+		if errors.Is(scanErr, pgx.ErrNoRows) {
+			_ = tx.Rollback(ctx)
+			return false, nil
+		}
+		err = scanErr
+		return false, fmt.Errorf("failed to settle duel: %w", err)
+	}
+
+	queryBets := `
+		UPDATE bets
+		SET close_price = $2, close_time = $3, updated_at = EXTRACT(EPOCH FROM NOW())::BIGINT * 1000
+		WHERE duel_id = $1 AND close_price IS NULL
+	`
+	if _, err = tx.Exec(ctx, queryBets, duelID, closePrice, closeTime); err != nil {
+		return false, fmt.Errorf("failed to close duel positions: %w", err)
+	}
+
+	if winnerUUID != "" {
+		err = creditRatingPoints(ctx, tx, winnerUUID, 2*stake, fmt.Sprintf("Duel won: %s", pair), domain.RatingSourceDuel)
+	} else {
+		for _, userUUID := range []string{challengerUUID, opponentUUID} {
+			if err = creditRatingPoints(ctx, tx, userUUID, stake, fmt.Sprintf("Duel draw refund: %s", pair), domain.RatingSourceDuel); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for i := range notifications {
+		if err = insertNotification(ctx, tx, &notifications[i]); err != nil {
+			return false, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit settle duel transaction: %w", err)
+	}
+	return true, nil
+}
+
+// GetDuelsToResolve returns pending duels that expired by nowMs and active duels whose positions were due
+// to close before closedBefore but were not settled (e.g. the server restarted while they were open).
+func (r *PostgresDuelRepository) GetDuelsToResolve(ctx context.Context, nowMs int64, closedBefore time.Time) ([]domain.Duel, error) {
+	query := duelSelect + `
+		WHERE (d.status = 'pending' AND d.expires_at <= $1)
+		   OR (d.status = 'active' AND d.open_time + make_interval(secs => d.timeframe) <= $2)
+		ORDER BY d.id ASC
+	`
+	return r.queryDuels(ctx, query, nowMs, closedBefore.UTC())
+}
+
+func (r *PostgresDuelRepository) queryDuels(ctx context.Context, query string, args ...interface{}) ([]domain.Duel, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get duels: %w", err)
+	}
+	defer rows.Close()
+
+	var duels []domain.Duel
+	for rows.Next() {
+		duel, err := scanDuel(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan duel: %w", err)
+		}
+		duels = append(duels, *duel)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating duels: %w", err)
+	}
+	return duels, nil
+}
+
+func scanDuel(row pgx.Row) (*domain.Duel, error) {
+	var duel domain.Duel
+	var openTime *time.Time
+	var cGoogle, cUsername, cFirst, cLast, cChosen sql.NullString
+	var oGoogle, oUsername, oFirst, oLast, oChosen sql.NullString
+	var cHidden, oHidden bool
+	if err := row.Scan(
+		&duel.ID,
+		&duel.ChallengerUUID,
+		&duel.OpponentUUID,
+		&duel.InviteCode,
+		&duel.Pair,
+		&duel.Timeframe,
+		&duel.Stake,
+		&duel.ChallengerSide,
+		&duel.Status,
+		&duel.ChallengerBetID,
+		&duel.OpponentBetID,
+		&duel.OpenPrice,
+		&duel.ClosePrice,
+		&openTime,
+		&duel.WinnerUUID,
+		&duel.ExpiresAt,
+		&duel.AcceptedAt,
+		&duel.SettledAt,
+		&duel.CreatedAt,
+		&cGoogle, &cUsername, &cFirst, &cLast,
+		&cChosen, &cHidden,
+		&oGoogle, &oUsername, &oFirst, &oLast,
+		&oChosen, &oHidden,
+	); err != nil {
+		return nil, err
+	}
+	duel.ChallengerName = buildRatingName(cChosen, cHidden, cGoogle, cUsername, cFirst, cLast)
+	if duel.OpponentUUID != "" {
+		duel.OpponentName = buildRatingName(oChosen, oHidden, oGoogle, oUsername, oFirst, oLast)
+	}
+	if openTime != nil {
+		normalized := normalizeBetTimestamp(*openTime)
+		duel.OpenTime = &normalized
+	}
+	return &duel, nil
+}
+
+// insertNotification queues a notification for delivery inside tx.
+func insertNotification(ctx context.Context, tx pgx.Tx, notification *domain.Notification) error {
+	query := `
+		INSERT INTO notifications (user_id, producer, message, created_at, updated_at)
+		VALUES ($1, $2, $3, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+	`
+	if _, err := tx.Exec(ctx, query, notification.UserUUID, notification.Producer, notification.Message); err != nil {
+		return fmt.Errorf("failed to queue notification: %w", err)
+	}
+	return nil
+}
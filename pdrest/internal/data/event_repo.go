@@ -2,7 +2,9 @@ package data
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -24,6 +26,30 @@ type EventRepository interface {
 	UpdateUserEventPrizeStatusIfUnknown(ctx context.Context, userUUID string, eventID string, hasPrise *bool, prizeValueID *int) (bool, error)
 	UpdateUserEventPrizeTakenStatusIfNotTaken(ctx context.Context, userUUID string, eventID string, taken bool) (bool, error)
 	HasUserEvent(ctx context.Context, userUUID string, eventID string) (bool, error)
+	GetUserEventStatus(ctx context.Context, userUUID string, eventID string) (string, error)
+	GetUserEligibility(ctx context.Context, userUUID string, achievementIDs []string) (*domain.UserEligibility, error)
+	JoinEvent(ctx context.Context, userUUID string, eventID string, maxParticipants int, entryFee int64) (*domain.JoinEventResult, error)
+	PromoteEventWaitlist(ctx context.Context, eventID string, maxParticipants int, entryFee int64) ([]string, error)
+	LeaveEvent(ctx context.Context, userUUID string, eventID string, status string) (bool, error)
+	GetEventEntryFees(ctx context.Context, eventID string) (map[string]int64, error)
+	PayEventPrizePool(ctx context.Context, eventID string, payouts []domain.PrizePoolPayout, nowMs int64) (bool, error)
+	GetAdminEvents(ctx context.Context, includeArchived bool) ([]domain.AdminEvent, error)
+	GetAdminEvent(ctx context.Context, id string) (*domain.AdminEvent, error)
+	CreateAdminEvent(ctx context.Context, event *domain.AdminEvent) error
+	UpdateAdminEvent(ctx context.Context, event *domain.AdminEvent, replacePrizes bool) error
+	GetEventStandings(ctx context.Context, eventID string, startMs, endMs int64, rules domain.EventScoringRules, userUUID string, limit, offset int) ([]domain.BetPrizeLeaderboardEntry, error)
+	GetUserEventStanding(ctx context.Context, eventID string, startMs, endMs int64, rules domain.EventScoringRules, userUUID string) (*domain.BetPrizeLeaderboardEntry, error)
+	GetRunningCompetitions(ctx context.Context) ([]domain.Event, error)
+	GetEventsToAdvance(ctx context.Context, nowMs int64) ([]domain.Event, error)
+	TransitionEventState(ctx context.Context, eventID string, from, to domain.EventState, nowMs int64) (bool, error)
+	GetEventStateTransitions(ctx context.Context, eventID string) ([]domain.EventStateTransition, error)
+	ReleaseEventPrizes(ctx context.Context, eventID string) (int64, error)
+	SaveEventResults(ctx context.Context, eventID string, results []domain.EventResult, notifications []domain.Notification, nowMs int64) (bool, error)
+	IsEventFinalized(ctx context.Context, eventID string) (bool, error)
+	GetUserEventResult(ctx context.Context, eventID string, userUUID string) (*domain.EventResult, error)
+	GetEventResults(ctx context.Context, eventID string, limit, offset int) ([]domain.EventResult, error)
+	SaveEventRankSnapshot(ctx context.Context, eventID string, entries []domain.BetPrizeLeaderboardEntry, nowMs int64, minAgeMs int64) (bool, error)
+	GetEventRankSnapshot(ctx context.Context, eventID string, userUUIDs []string) (map[string]int, *int64, error)
 }
 
 type PostgresEventRepository struct {
@@ -34,12 +60,13 @@ func NewPostgresEventRepository(pool *pgxpool.Pool) *PostgresEventRepository {
 	return &PostgresEventRepository{pool: pool}
 }
 
-// GetAllEvents retrieves all events from the database, optionally filtered by tag
+// GetAllEvents retrieves scheduled and active events, optionally filtered by tag
 func (r *PostgresEventRepository) GetAllEvents(ctx context.Context, tag string) ([]domain.Event, error) {
 	query := `
-		SELECT id, badge, title, desc_text, start_time, deadline, tags, reward, info
+		SELECT id, badge, title, desc_text, start_time, deadline, tags, reward, info, scoring, eligibility, prize_pool, state
 		FROM all_events
 		WHERE ($1 = '' OR tags ILIKE '%' || $1 || '%')
+		  AND state IN ('scheduled', 'active')
 		ORDER BY deadline ASC
 	`
 
@@ -53,6 +80,8 @@ func (r *PostgresEventRepository) GetAllEvents(ctx context.Context, tag string)
 	for rows.Next() {
 		var event domain.Event
 		var rewardJSON []byte
+		var scoringJSON []byte
+		var eligibilityJSON []byte
 		var startMs int64
 		var deadlineMs int64
 
@@ -66,6 +95,10 @@ func (r *PostgresEventRepository) GetAllEvents(ctx context.Context, tag string)
 			&event.Tags,
 			&rewardJSON,
 			&event.Info,
+			&scoringJSON,
+			&eligibilityJSON,
+			&event.PrizePool,
+			&event.State,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan event: %w", err)
@@ -79,6 +112,12 @@ func (r *PostgresEventRepository) GetAllEvents(ctx context.Context, tag string)
 		if err := json.Unmarshal(rewardJSON, &event.Reward); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal reward: %w", err)
 		}
+		if event.Scoring, err = parseScoringRules(scoringJSON); err != nil {
+			return nil, err
+		}
+		if event.Eligibility, err = parseEventEligibility(eligibilityJSON); err != nil {
+			return nil, err
+		}
 
 		events = append(events, event)
 	}
@@ -93,13 +132,15 @@ func (r *PostgresEventRepository) GetAllEvents(ctx context.Context, tag string)
 // GetEventByID retrieves a single event by ID
 func (r *PostgresEventRepository) GetEventByID(ctx context.Context, id string) (*domain.Event, error) {
 	query := `
-		SELECT id, badge, title, desc_text, start_time, deadline, tags, reward, info
+		SELECT id, badge, title, desc_text, start_time, deadline, tags, reward, info, scoring, eligibility, prize_pool, state
 		FROM all_events
 		WHERE id = $1
 	`
 
 	var event domain.Event
 	var rewardJSON []byte
+	var scoringJSON []byte
+	var eligibilityJSON []byte
 	var startMs int64
 	var deadlineMs int64
 
@@ -113,6 +154,10 @@ func (r *PostgresEventRepository) GetEventByID(ctx context.Context, id string) (
 		&event.Tags,
 		&rewardJSON,
 		&event.Info,
+		&scoringJSON,
+		&eligibilityJSON,
+		&event.PrizePool,
+		&event.State,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -129,6 +174,12 @@ func (r *PostgresEventRepository) GetEventByID(ctx context.Context, id string) (
 	if err := json.Unmarshal(rewardJSON, &event.Reward); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal reward: %w", err)
 	}
+	if event.Scoring, err = parseScoringRules(scoringJSON); err != nil {
+		return nil, err
+	}
+	if event.Eligibility, err = parseEventEligibility(eligibilityJSON); err != nil {
+		return nil, err
+	}
 
 	return &event, nil
 }
@@ -265,16 +316,18 @@ func (r *PostgresEventRepository) AddUserEvent(ctx context.Context, userUUID str
 func (r *PostgresEventRepository) GetUserEventsWithAvailable(ctx context.Context, userUUID string, tag string, nowMs int64) ([]domain.UserEventEntry, error) {
 	query := `
 		WITH user_events_cte AS (
-			SELECT e.id, e.badge, e.title, e.desc_text, e.start_time, e.deadline, e.tags, e.reward, e.info,
+			SELECT e.id, e.badge, e.title, e.desc_text, e.start_time, e.deadline, e.tags, e.reward, e.info, e.state,
 			       ue.status, ue.created_at AS joined_at, ue.has_prise_status, ue.prize_taken_status,
-			       pv.label AS prize_desc
+			       CASE WHEN er.prize_value_id = ue.prize_value_id THEN COALESCE(er.prize_label, pv.label) ELSE pv.label END AS prize_desc
 			FROM user_events ue
 			JOIN all_events e ON e.id = ue.event_id
 			LEFT JOIN prize_values pv ON pv.id = ue.prize_value_id
+			LEFT JOIN event_results er ON er.event_id = ue.event_id AND er.user_uuid = ue.user_uuid
 			WHERE ue.user_uuid = $1
+			  AND e.state <> 'draft'
 		),
 		available_events AS (
-			SELECT e.id, e.badge, e.title, e.desc_text, e.start_time, e.deadline, e.tags, e.reward, e.info,
+			SELECT e.id, e.badge, e.title, e.desc_text, e.start_time, e.deadline, e.tags, e.reward, e.info, e.state,
 			       'available' AS status, NULL::BIGINT AS joined_at, NULL::BOOL AS has_prise_status, FALSE AS prize_taken_status,
 			       NULL::TEXT AS prize_desc
 			FROM all_events e
@@ -282,6 +335,7 @@ func (r *PostgresEventRepository) GetUserEventsWithAvailable(ctx context.Context
 			WHERE ue.event_id IS NULL
 			  AND e.tags ILIKE '%' || $2 || '%'
 			  AND e.deadline > $3
+			  AND e.state IN ('scheduled', 'active')
 		)
 		SELECT * FROM user_events_cte
 		UNION ALL
@@ -313,6 +367,7 @@ func (r *PostgresEventRepository) GetUserEventsWithAvailable(ctx context.Context
 			&event.Tags,
 			&rewardJSON,
 			&event.Info,
+			&event.State,
 			&event.Status,
 			&joinedAtMs,
 			&event.HasPrise,
@@ -403,7 +458,7 @@ func (r *PostgresEventRepository) HasUserEvent(ctx context.Context, userUUID str
 		SELECT EXISTS (
 			SELECT 1
 			FROM user_events
-			WHERE user_uuid = $1 AND event_id = $2
+			WHERE user_uuid = $1 AND event_id = $2 AND status = 'joined'
 		)
 	`
 
@@ -414,3 +469,1383 @@ func (r *PostgresEventRepository) HasUserEvent(ctx context.Context, userUUID str
 
 	return exists, nil
 }
+
+// GetUserEventStatus returns the user's status in the event, or "" if the user never joined it.
+func (r *PostgresEventRepository) GetUserEventStatus(ctx context.Context, userUUID string, eventID string) (string, error) {
+	query := `SELECT COALESCE(status, '') FROM user_events WHERE user_uuid = $1 AND event_id = $2`
+
+	var status string
+	if err := r.pool.QueryRow(ctx, query, userUUID, eventID).Scan(&status); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get user event status: %w", err)
+	}
+	return status, nil
+}
+
+// GetUserEligibility returns the user's total rating points, linked auth providers and which of the
+// given achievements the user has completed.
+func (r *PostgresEventRepository) GetUserEligibility(ctx context.Context, userUUID string, achievementIDs []string) (*domain.UserEligibility, error) {
+	query := `
+		SELECT
+			COALESCE((SELECT SUM(points) FROM rating WHERE user_uuid = u.user_uuid), 0)::BIGINT,
+			COALESCE((
+				SELECT MAX(l.level) FROM levels l
+				WHERE l.xp_required <= COALESCE((SELECT xp FROM user_xp WHERE user_uuid = u.user_uuid), 0)
+			), 1),
+			u.google_id IS NOT NULL,
+			u.telegram_id IS NOT NULL,
+			COALESCE((
+				SELECT array_agg(ua.achievement_id)
+				FROM user_achievements ua
+				WHERE ua.user_uuid = u.user_uuid
+				  AND ua.achievement_id = ANY($2::TEXT[])
+				  AND ua.steps_got >= ua.need_steps
+			), '{}')
+		FROM users u
+		WHERE u.user_uuid = $1
+	`
+
+	if achievementIDs == nil {
+		achievementIDs = []string{}
+	}
+	var eligibility domain.UserEligibility
+	var google, telegram bool
+	if err := r.pool.QueryRow(ctx, query, userUUID, achievementIDs).Scan(
+		&eligibility.TotalPoints,
+		&eligibility.Level,
+		&google,
+		&telegram,
+		&eligibility.CompletedAchievements,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user eligibility: %w", err)
+	}
+	if google {
+		eligibility.AuthProviders = append(eligibility.AuthProviders, "google")
+	}
+	if telegram {
+		eligibility.AuthProviders = append(eligibility.AuthProviders, "telegram")
+	}
+	return &eligibility, nil
+}
+
+// JoinEvent adds the user to the event: joined while the event has free places and nobody is waiting,
+// waitlisted otherwise. A joined user pays the entry fee into the event's prize pool. A user who already
+// joined or waits gets their current status back with Status "already_exists".
+func (r *PostgresEventRepository) JoinEvent(ctx context.Context, userUUID string, eventID string, maxParticipants int, entryFee int64) (*domain.JoinEventResult, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin join event transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	// Locking the event serializes joins, so the participant cap and waitlist order hold.
+	if _, err = tx.Exec(ctx, `SELECT 1 FROM all_events WHERE id = $1 FOR UPDATE`, eventID); err != nil {
+		return nil, fmt.Errorf("failed to lock event: %w", err)
+	}
+
+	var current string
+	var feePaid int64
+	queryCurrent := `SELECT status, entry_fee_paid FROM user_events WHERE user_uuid = $1 AND event_id = $2`
+	if scanErr := tx.QueryRow(ctx, queryCurrent, userUUID, eventID).Scan(&current, &feePaid); scanErr != nil && !errors.Is(scanErr, pgx.ErrNoRows) {
+		err = scanErr
+		return nil, fmt.Errorf("failed to get user event: %w", err)
+	}
+	if current != "" && current != domain.UserEventDropped {
+		result := &domain.JoinEventResult{Status: "already_exists", UserStatus: current, EntryFeePaid: feePaid}
+		if current == domain.UserEventWaitlisted {
+			if result.WaitlistPlace, err = waitlistPlace(ctx, tx, userUUID, eventID); err != nil {
+				return nil, err
+			}
+		}
+		_ = tx.Rollback(ctx)
+		return result, nil
+	}
+
+	var joined, waiting int
+	queryCount := `
+		SELECT COUNT(*) FILTER (WHERE status = 'joined'), COUNT(*) FILTER (WHERE status = 'waitlisted')
+		FROM user_events
+		WHERE event_id = $1
+	`
+	if err = tx.QueryRow(ctx, queryCount, eventID).Scan(&joined, &waiting); err != nil {
+		return nil, fmt.Errorf("failed to count event participants: %w", err)
+	}
+
+	result := &domain.JoinEventResult{Status: "created", UserStatus: domain.UserEventJoined}
+	if maxParticipants > 0 && (joined >= maxParticipants || waiting > 0) {
+		result.UserStatus = domain.UserEventWaitlisted
+		place := waiting + 1
+		result.WaitlistPlace = &place
+	} else if entryFee > 0 {
+		paid, chargeErr := chargeEntryFee(ctx, tx, userUUID, eventID, entryFee)
+		if chargeErr != nil {
+			err = chargeErr
+			return nil, err
+		}
+		if !paid {
+			err = errors.New("not enough points for the entry fee")
+			return nil, err
+		}
+		result.EntryFeePaid = entryFee
+	}
+
+	// A user dropped from the waitlist joins again at the end of the queue.
+	queryInsert := `
+		INSERT INTO user_events (user_uuid, event_id, status, entry_fee_paid, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+		ON CONFLICT (user_uuid, event_id) DO UPDATE
+		SET status = EXCLUDED.status,
+		    entry_fee_paid = EXCLUDED.entry_fee_paid,
+		    created_at = EXCLUDED.created_at,
+		    updated_at = EXCLUDED.updated_at
+	`
+	if _, err = tx.Exec(ctx, queryInsert, userUUID, eventID, result.UserStatus, result.EntryFeePaid); err != nil {
+		return nil, fmt.Errorf("failed to insert user event: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit join event transaction: %w", err)
+	}
+	return result, nil
+}
+
+// PromoteEventWaitlist moves waitlisted users into free places in join order (all of them if the event
+// has no cap), charging their entry fee. Users who cannot pay the fee any more are dropped from the
+// waitlist. Returns the promoted users.
+func (r *PostgresEventRepository) PromoteEventWaitlist(ctx context.Context, eventID string, maxParticipants int, entryFee int64) ([]string, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin waitlist transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	if _, err = tx.Exec(ctx, `SELECT 1 FROM all_events WHERE id = $1 FOR UPDATE`, eventID); err != nil {
+		return nil, fmt.Errorf("failed to lock event: %w", err)
+	}
+
+	var joined int
+	queryCount := `SELECT COUNT(*) FROM user_events WHERE event_id = $1 AND status = 'joined'`
+	if err = tx.QueryRow(ctx, queryCount, eventID).Scan(&joined); err != nil {
+		return nil, fmt.Errorf("failed to count event participants: %w", err)
+	}
+
+	queryNext := `
+		SELECT id, user_uuid::text
+		FROM user_events
+		WHERE event_id = $1 AND status = 'waitlisted'
+		ORDER BY created_at ASC, id ASC
+		LIMIT 1
+	`
+	queryUpdate := `
+		UPDATE user_events
+		SET status = $2, entry_fee_paid = $3, updated_at = EXTRACT(EPOCH FROM NOW())::BIGINT * 1000
+		WHERE id = $1
+	`
+	var promoted []string
+	for maxParticipants <= 0 || joined < maxParticipants {
+		var id int
+		var userUUID string
+		if scanErr := tx.QueryRow(ctx, queryNext, eventID).Scan(&id, &userUUID); scanErr != nil {
+			if errors.Is(scanErr, pgx.ErrNoRows) {
+				break
+			}
+			err = scanErr
+			return nil, fmt.Errorf("failed to get next waitlisted user: %w", err)
+		}
+
+		status, feePaid := domain.UserEventJoined, int64(0)
+		if entryFee > 0 {
+			paid, chargeErr := chargeEntryFee(ctx, tx, userUUID, eventID, entryFee)
+			if chargeErr != nil {
+				err = chargeErr
+				return nil, err
+			}
+			if paid {
+				feePaid = entryFee
+			} else {
+				status = domain.UserEventDropped
+			}
+		}
+		if _, err = tx.Exec(ctx, queryUpdate, id, status, feePaid); err != nil {
+			return nil, fmt.Errorf("failed to update waitlisted user: %w", err)
+		}
+		if status == domain.UserEventJoined {
+			joined++
+			promoted = append(promoted, userUUID)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit waitlist transaction: %w", err)
+	}
+	return promoted, nil
+}
+
+// LeaveEvent removes the user from the event if their status is still the given one, refunding the entry
+// fee they paid out of the prize pool. Returns false if the user had no such status.
+func (r *PostgresEventRepository) LeaveEvent(ctx context.Context, userUUID string, eventID string, status string) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin leave event transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	if _, err = tx.Exec(ctx, `SELECT 1 FROM all_events WHERE id = $1 FOR UPDATE`, eventID); err != nil {
+		return false, fmt.Errorf("failed to lock event: %w", err)
+	}
+
+	var feePaid int64
+	queryDelete := `
+		DELETE FROM user_events
+		WHERE user_uuid = $1 AND event_id = $2 AND status = $3
+		RETURNING entry_fee_paid
+	`
+	if scanErr := tx.QueryRow(ctx, queryDelete, userUUID, eventID, status).Scan(&feePaid); scanErr != nil {
+		if errors.Is(scanErr, pgx.ErrNoRows) {
+			_ = tx.Rollback(ctx)
+			return false, nil
+		}
+		err = scanErr
+		return false, fmt.Errorf("failed to delete user event: %w", err)
+	}
+
+	if feePaid > 0 {
+		if err = creditRatingPoints(ctx, tx, userUUID, feePaid, fmt.Sprintf("Entry fee refund: %s", eventID), domain.RatingSourceEntryFee); err != nil {
+			return false, err
+		}
+		queryPool := `
+			UPDATE all_events
+			SET prize_pool = prize_pool - $2, updated_at = EXTRACT(EPOCH FROM NOW())::BIGINT * 1000
+			WHERE id = $1
+		`
+		if _, err = tx.Exec(ctx, queryPool, eventID, feePaid); err != nil {
+			return false, fmt.Errorf("failed to update prize pool: %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit leave event transaction: %w", err)
+	}
+	return true, nil
+}
+
+// GetEventEntryFees returns the entry fee each participant paid, for participants who paid one.
+func (r *PostgresEventRepository) GetEventEntryFees(ctx context.Context, eventID string) (map[string]int64, error) {
+	query := `
+		SELECT user_uuid::text, entry_fee_paid
+		FROM user_events
+		WHERE event_id = $1 AND status = 'joined' AND entry_fee_paid > 0
+	`
+
+	rows, err := r.pool.Query(ctx, query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event entry fees: %w", err)
+	}
+	defer rows.Close()
+
+	fees := make(map[string]int64)
+	for rows.Next() {
+		var userUUID string
+		var fee int64
+		if err := rows.Scan(&userUUID, &fee); err != nil {
+			return nil, fmt.Errorf("failed to scan event entry fee: %w", err)
+		}
+		fees[userUUID] = fee
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating event entry fees: %w", err)
+	}
+	return fees, nil
+}
+
+// PayEventPrizePool marks the event's prize pool paid and credits the payouts as rating points.
+// Returns false if the pool was already paid (e.g. by another replica).
+func (r *PostgresEventRepository) PayEventPrizePool(ctx context.Context, eventID string, payouts []domain.PrizePoolPayout, nowMs int64) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin prize pool transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	queryPaid := `
+		UPDATE all_events
+		SET pool_paid_at = $2, updated_at = $2
+		WHERE id = $1 AND pool_paid_at IS NULL
+	`
+	tag, execErr := tx.Exec(ctx, queryPaid, eventID, nowMs)
+	if execErr != nil {
+		err = execErr
+		return false, fmt.Errorf("failed to mark prize pool paid: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		_ = tx.Rollback(ctx)
+		return false, nil
+	}
+
+	queryRating := `
+		INSERT INTO rating (user_uuid, points, description, source, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	for _, payout := range payouts {
+		if _, err = tx.Exec(ctx, queryRating, payout.UserUUID, payout.Points, payout.Description, string(domain.RatingSourcePrizePool), nowMs); err != nil {
+			return false, fmt.Errorf("failed to pay prize pool: %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit prize pool transaction: %w", err)
+	}
+	return true, nil
+}
+
+// chargeEntryFee debits the entry fee from the user's rating points into the event's prize pool.
+// Returns false without charging if the user has fewer points than the fee.
+func chargeEntryFee(ctx context.Context, tx pgx.Tx, userUUID string, eventID string, fee int64) (bool, error) {
+	paid, err := debitRatingPoints(ctx, tx, userUUID, fee, fmt.Sprintf("Entry fee: %s", eventID), domain.RatingSourceEntryFee)
+	if err != nil || !paid {
+		return false, err
+	}
+
+	queryPool := `
+		UPDATE all_events
+		SET prize_pool = prize_pool + $2, updated_at = EXTRACT(EPOCH FROM NOW())::BIGINT * 1000
+		WHERE id = $1
+	`
+	if _, err := tx.Exec(ctx, queryPool, eventID, fee); err != nil {
+		return false, fmt.Errorf("failed to update prize pool: %w", err)
+	}
+	return true, nil
+}
+
+// waitlistPlace returns the user's 1-based place on the event's waitlist.
+func waitlistPlace(ctx context.Context, tx pgx.Tx, userUUID string, eventID string) (*int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM user_events w
+		JOIN user_events me ON me.event_id = w.event_id AND me.user_uuid = $1
+		WHERE w.event_id = $2
+		  AND w.status = 'waitlisted'
+		  AND (w.created_at, w.id) <= (me.created_at, me.id)
+	`
+
+	var place int
+	if err := tx.QueryRow(ctx, query, userUUID, eventID).Scan(&place); err != nil {
+		return nil, fmt.Errorf("failed to get waitlist place: %w", err)
+	}
+	return &place, nil
+}
+
+// GetAdminEvents lists events for the admin API, newest deadline first (without prize values).
+func (r *PostgresEventRepository) GetAdminEvents(ctx context.Context, includeArchived bool) ([]domain.AdminEvent, error) {
+	query := `
+		SELECT id, badge, title, desc_text, start_time, deadline, tags, reward, info, scoring, eligibility, prize_pool, state, archived_at, state_changed_at
+		FROM all_events
+		WHERE ($1 OR archived_at IS NULL)
+		ORDER BY deadline DESC, id ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, includeArchived)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query admin events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []domain.AdminEvent
+	for rows.Next() {
+		event, err := scanAdminEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, *event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating admin events: %w", err)
+	}
+
+	return events, nil
+}
+
+// GetAdminEvent returns the event with its prize values (in id order) and their linked achievements.
+func (r *PostgresEventRepository) GetAdminEvent(ctx context.Context, id string) (*domain.AdminEvent, error) {
+	query := `
+		SELECT id, badge, title, desc_text, start_time, deadline, tags, reward, info, scoring, eligibility, prize_pool, state, archived_at, state_changed_at
+		FROM all_events
+		WHERE id = $1
+	`
+
+	event, err := scanAdminEvent(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	queryPrizes := `
+		SELECT pv.id, pv.value, pv.label, pv.segment_id, pv.weight, pv.total_budget, pv.daily_budget,
+		       a.id, a.badge, a.title, a.image_url, a.desc_text, a.tags, a.steps, a.step_desc
+		FROM prize_values pv
+		LEFT JOIN LATERAL (
+			SELECT id, badge, title, image_url, desc_text, tags, steps, step_desc
+			FROM achievements
+			WHERE prize_id = pv.id
+			ORDER BY id ASC
+			LIMIT 1
+		) a ON TRUE
+		WHERE pv.event_id = $1 AND pv.segment_id IS DISTINCT FROM 'tie_split'
+		ORDER BY pv.id ASC
+	`
+	rows, err := r.pool.Query(ctx, queryPrizes, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query event prize values: %w", err)
+	}
+	defer rows.Close()
+
+	event.PrizeValues = make([]domain.AdminEventPrize, 0)
+	for rows.Next() {
+		var prize domain.AdminEventPrize
+		var achievementID, badge, title, imageURL, desc, tags, stepDesc *string
+		var steps *int
+		if err := rows.Scan(
+			&prize.ID,
+			&prize.Value,
+			&prize.Label,
+			&prize.SegmentID,
+			&prize.Weight,
+			&prize.TotalBudget,
+			&prize.DailyBudget,
+			&achievementID,
+			&badge,
+			&title,
+			&imageURL,
+			&desc,
+			&tags,
+			&steps,
+			&stepDesc,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan event prize value: %w", err)
+		}
+		if achievementID != nil {
+			prizeID := prize.ID
+			prize.Achievement = &domain.Achievement{
+				ID:       *achievementID,
+				Badge:    derefString(badge),
+				Title:    derefString(title),
+				ImageURL: derefString(imageURL),
+				Desc:     derefString(desc),
+				Tags:     derefString(tags),
+				StepDesc: derefString(stepDesc),
+				PrizeID:  &prizeID,
+			}
+			if steps != nil {
+				prize.Achievement.Steps = *steps
+			}
+		}
+		event.PrizeValues = append(event.PrizeValues, prize)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating event prize values: %w", err)
+	}
+
+	return event, nil
+}
+
+// CreateAdminEvent inserts the event, its prize values and linked achievements in one transaction.
+func (r *PostgresEventRepository) CreateAdminEvent(ctx context.Context, event *domain.AdminEvent) error {
+	rewardJSON, err := json.Marshal(event.Reward)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reward: %w", err)
+	}
+	scoringJSON, err := scoringRulesJSON(event.Scoring)
+	if err != nil {
+		return err
+	}
+	eligibilityJSON, err := eventEligibilityJSON(event.Eligibility)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin event transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	query := `
+		INSERT INTO all_events (id, badge, title, desc_text, start_time, deadline, tags, reward, info, scoring, eligibility, state, state_changed_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+		ON CONFLICT (id) DO NOTHING
+	`
+	tag, execErr := tx.Exec(ctx, query,
+		event.ID,
+		event.Badge,
+		event.Title,
+		event.Desc,
+		event.StartTime.UTC().UnixMilli(),
+		event.Deadline.UTC().UnixMilli(),
+		event.Tags,
+		rewardJSON,
+		event.Info,
+		scoringJSON,
+		eligibilityJSON,
+		event.State,
+	)
+	if execErr != nil {
+		err = execErr
+		return fmt.Errorf("failed to create event: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		err = fmt.Errorf("event with id %s already exists", event.ID)
+		return err
+	}
+
+	if err = insertEventPrizes(ctx, tx, event.ID, event.PrizeValues); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit event transaction: %w", err)
+	}
+	return nil
+}
+
+// UpdateAdminEvent updates the event; with replacePrizes its prize values and linked
+// achievements are replaced by event.PrizeValues.
+func (r *PostgresEventRepository) UpdateAdminEvent(ctx context.Context, event *domain.AdminEvent, replacePrizes bool) error {
+	rewardJSON, err := json.Marshal(event.Reward)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reward: %w", err)
+	}
+	scoringJSON, err := scoringRulesJSON(event.Scoring)
+	if err != nil {
+		return err
+	}
+	eligibilityJSON, err := eventEligibilityJSON(event.Eligibility)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin event transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	query := `
+		UPDATE all_events
+		SET badge = $2,
+		    title = $3,
+		    desc_text = $4,
+		    start_time = $5,
+		    deadline = $6,
+		    tags = $7,
+		    reward = $8,
+		    info = $9,
+		    scoring = $10,
+		    eligibility = $11,
+		    updated_at = EXTRACT(EPOCH FROM NOW())::BIGINT * 1000
+		WHERE id = $1
+	`
+	tag, execErr := tx.Exec(ctx, query,
+		event.ID,
+		event.Badge,
+		event.Title,
+		event.Desc,
+		event.StartTime.UTC().UnixMilli(),
+		event.Deadline.UTC().UnixMilli(),
+		event.Tags,
+		rewardJSON,
+		event.Info,
+		scoringJSON,
+		eligibilityJSON,
+	)
+	if execErr != nil {
+		err = execErr
+		return fmt.Errorf("failed to update event: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		err = fmt.Errorf("event with id %s not found", event.ID)
+		return err
+	}
+
+	if replacePrizes {
+		queryDeleteAchievements := `
+			DELETE FROM achievements
+			WHERE prize_id IN (SELECT id FROM prize_values WHERE event_id = $1)
+		`
+		if _, err = tx.Exec(ctx, queryDeleteAchievements, event.ID); err != nil {
+			return fmt.Errorf("failed to delete event achievements: %w", err)
+		}
+		if _, err = tx.Exec(ctx, `DELETE FROM prize_values WHERE event_id = $1`, event.ID); err != nil {
+			return fmt.Errorf("failed to delete event prize values: %w", err)
+		}
+		if err = insertEventPrizes(ctx, tx, event.ID, event.PrizeValues); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit event transaction: %w", err)
+	}
+	return nil
+}
+
+// GetEventStandings is the competition scoring engine shared by the leaderboard, user progress and the
+// finalizer. It counts settled bets opened at or after startMs and closed by endMs that match the rules,
+// scores them with the rules' metric and ranks users by score, then by who reached it first (tied users
+// share a rank when the rules share or split ties).
+// With userUUID set only that user's bets are scored (rank 1) and minBets is not applied. A limit <= 0 returns all users.
+func (r *PostgresEventRepository) GetEventStandings(ctx context.Context, eventID string, startMs, endMs int64, rules domain.EventScoringRules, userUUID string, limit, offset int) ([]domain.BetPrizeLeaderboardEntry, error) {
+	minBets := rules.MinBets
+	if userUUID != "" {
+		minBets = 0
+	}
+	return r.queryEventStandings(ctx, eventID, startMs, endMs, rules, minBets, userUUID, "", limit, offset)
+}
+
+// GetUserEventStanding returns the user's entry ranked among all users, or nil if the user is not ranked.
+func (r *PostgresEventRepository) GetUserEventStanding(ctx context.Context, eventID string, startMs, endMs int64, rules domain.EventScoringRules, userUUID string) (*domain.BetPrizeLeaderboardEntry, error) {
+	entries, err := r.queryEventStandings(ctx, eventID, startMs, endMs, rules, rules.MinBets, "", userUUID, 1, 0)
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return &entries[0], nil
+}
+
+// queryEventStandings scores the bets of betsUser (all users if empty) and returns ranked entries,
+// only rankedUser's if set.
+func (r *PostgresEventRepository) queryEventStandings(ctx context.Context, eventID string, startMs, endMs int64, rules domain.EventScoringRules, minBets int, betsUser string, rankedUser string, limit, offset int) ([]domain.BetPrizeLeaderboardEntry, error) {
+	query := `
+		WITH counted AS (
+			SELECT
+				b.user_uuid,
+				b.sum,
+				b.close_time,
+				((b.side = 'pump' AND b.close_price > b.open_price) OR (b.side = 'dump' AND b.close_price < b.open_price)) AS won,
+				COALESCE(sru.rating_after - sru.rating_before, 0) AS skill_gain
+			FROM bets b
+			LEFT JOIN user_events ue ON ue.user_uuid = b.user_uuid AND ue.event_id = $1 AND ue.status = 'joined'
+			LEFT JOIN skill_rating_updates sru ON sru.bet_id = b.id
+			WHERE b.close_price IS NOT NULL
+			  AND b.open_time >= $2
+			  AND b.close_time <= $3
+			  AND ($4 = false OR ue.user_uuid IS NOT NULL)
+			  AND ($5 = false OR b.open_time >= to_timestamp(ue.created_at / 1000.0) AT TIME ZONE 'UTC')
+			  AND (cardinality($6::TEXT[]) = 0 OR b.pair = ANY($6::TEXT[]))
+			  AND (cardinality($7::INT[]) = 0 OR b.timeframe = ANY($7::INT[]))
+			  AND ($8 = '' OR b.user_uuid::text = $8)
+		),
+		scored AS (
+			SELECT
+				user_uuid,
+				COUNT(*)::INT AS bet_count,
+				COUNT(*) FILTER (WHERE won)::INT AS win_count,
+				COUNT(*) FILTER (WHERE NOT won)::INT AS loss_count,
+				COALESCE(SUM(CASE WHEN won THEN ROUND(sum) ELSE -ROUND(sum) END), 0)::BIGINT AS net_points,
+				COALESCE(SUM(sum), 0)::DOUBLE PRECISION AS volume,
+				COALESCE(SUM(skill_gain), 0)::DOUBLE PRECISION AS skill_gain,
+				(EXTRACT(EPOCH FROM MAX(close_time)) * 1000)::BIGINT AS last_scored_at
+			FROM counted
+			GROUP BY user_uuid
+			HAVING COUNT(*) >= $9
+		),
+		metric AS (
+			SELECT
+				user_uuid,
+				win_count,
+				loss_count,
+				net_points,
+				COALESCE(CASE $10::TEXT
+					WHEN 'roi' THEN net_points * 100.0 / NULLIF(volume, 0)
+					WHEN 'win_rate' THEN win_count * 100.0 / bet_count
+					WHEN 'skill' THEN skill_gain
+					ELSE net_points
+				END, 0)::DOUBLE PRECISION AS score,
+				bet_count,
+				last_scored_at
+			FROM scored
+		),
+		ranked AS (
+			SELECT
+				metric.*,
+				ROW_NUMBER() OVER (ORDER BY score DESC, last_scored_at ASC, user_uuid::text ASC)::INT AS position,
+				CASE WHEN $14 IN ('shared', 'split')
+					THEN RANK() OVER (ORDER BY score DESC)
+					ELSE ROW_NUMBER() OVER (ORDER BY score DESC, last_scored_at ASC, user_uuid::text ASC)
+				END::INT AS rank
+			FROM metric
+		)
+		SELECT
+			ranked.rank,
+			ranked.user_uuid::text,
+			ranked.win_count,
+			ranked.loss_count,
+			ranked.net_points,
+			ranked.score,
+			ranked.bet_count,
+			ranked.last_scored_at,
+			u.google_name,
+			u.telegram_username,
+			u.telegram_first_name,
+			u.telegram_last_name,
+			u.display_name,
+			COALESCE(u.hide_name, FALSE)
+		FROM ranked
+		LEFT JOIN users u ON u.user_uuid = ranked.user_uuid
+		WHERE ($11 = '' OR ranked.user_uuid::text = $11)
+		ORDER BY ranked.position ASC
+		LIMIT NULLIF($12, 0) OFFSET $13
+	`
+
+	pairs, timeframes := scoringFilters(rules)
+	rows, err := r.pool.Query(ctx, query,
+		eventID,
+		time.UnixMilli(startMs).UTC(),
+		time.UnixMilli(endMs).UTC(),
+		rules.ParticipantsOnly || rules.BetsAfterJoin,
+		rules.BetsAfterJoin,
+		pairs,
+		timeframes,
+		betsUser,
+		minBets,
+		string(rules.Metric),
+		rankedUser,
+		limit,
+		offset,
+		string(rules.Ties),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event standings: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []domain.BetPrizeLeaderboardEntry
+	for rows.Next() {
+		var entry domain.BetPrizeLeaderboardEntry
+		var googleName, telegramUsername, telegramFirstName, telegramLastName, chosenName sql.NullString
+		var hideName bool
+		if err := rows.Scan(
+			&entry.Rank,
+			&entry.UserUUID,
+			&entry.WinCount,
+			&entry.LossCount,
+			&entry.NetPoints,
+			&entry.Score,
+			&entry.BetCount,
+			&entry.LastScoredAt,
+			&googleName,
+			&telegramUsername,
+			&telegramFirstName,
+			&telegramLastName,
+			&chosenName,
+			&hideName,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan event standing: %w", err)
+		}
+		entry.UserName = buildRatingName(chosenName, hideName, googleName, telegramUsername, telegramFirstName, telegramLastName)
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating event standings: %w", err)
+	}
+
+	return entries, nil
+}
+
+// GetRunningCompetitions returns active competitions and those whose standings are not frozen yet.
+func (r *PostgresEventRepository) GetRunningCompetitions(ctx context.Context) ([]domain.Event, error) {
+	query := `
+		SELECT id, badge, title, desc_text, start_time, deadline, tags, reward, info, scoring, eligibility, prize_pool, state
+		FROM all_events
+		WHERE tags ILIKE '%competition%'
+		  AND state IN ('active', 'finalizing')
+		  AND finalized_at IS NULL
+		ORDER BY deadline ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query running competitions: %w", err)
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
+// GetEventsToAdvance returns events due for their next lifecycle step: scheduled events that have started,
+// active events past their deadline and finalizing events.
+func (r *PostgresEventRepository) GetEventsToAdvance(ctx context.Context, nowMs int64) ([]domain.Event, error) {
+	query := `
+		SELECT id, badge, title, desc_text, start_time, deadline, tags, reward, info, scoring, eligibility, prize_pool, state
+		FROM all_events
+		WHERE (state = 'scheduled' AND start_time <= $1)
+		   OR (state = 'active' AND deadline <= $1)
+		   OR state = 'finalizing'
+		ORDER BY deadline ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, nowMs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events to advance: %w", err)
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
+// TransitionEventState moves the event from one state to another and records the transition. Returns false
+// if the event is no longer in the from state (e.g. another replica moved it first).
+func (r *PostgresEventRepository) TransitionEventState(ctx context.Context, eventID string, from, to domain.EventState, nowMs int64) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin event state transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	queryState := `
+		UPDATE all_events
+		SET state = $3,
+		    state_changed_at = $4,
+		    archived_at = CASE WHEN $3 = 'archived' THEN $4 ELSE archived_at END,
+		    updated_at = $4
+		WHERE id = $1 AND state = $2
+	`
+	tag, execErr := tx.Exec(ctx, queryState, eventID, from, to, nowMs)
+	if execErr != nil {
+		err = execErr
+		return false, fmt.Errorf("failed to update event state: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		_ = tx.Rollback(ctx)
+		return false, nil
+	}
+
+	queryTransition := `
+		INSERT INTO event_state_transitions (event_id, from_state, to_state, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	if _, err = tx.Exec(ctx, queryTransition, eventID, from, to, nowMs); err != nil {
+		return false, fmt.Errorf("failed to record event state transition: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit event state transaction: %w", err)
+	}
+	return true, nil
+}
+
+// GetEventStateTransitions returns the event's state changes, oldest first.
+func (r *PostgresEventRepository) GetEventStateTransitions(ctx context.Context, eventID string) ([]domain.EventStateTransition, error) {
+	query := `
+		SELECT from_state, to_state, created_at
+		FROM event_state_transitions
+		WHERE event_id = $1
+		ORDER BY created_at ASC, id ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event state transitions: %w", err)
+	}
+	defer rows.Close()
+
+	var transitions []domain.EventStateTransition
+	for rows.Next() {
+		var transition domain.EventStateTransition
+		if err := rows.Scan(&transition.From, &transition.To, &transition.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan event state transition: %w", err)
+		}
+		transitions = append(transitions, transition)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating event state transitions: %w", err)
+	}
+
+	return transitions, nil
+}
+
+// ReleaseEventPrizes sets the prize status of every participant whose status is still unknown from the
+// frozen results: ranked winners get their prize value, everyone else no prize. Returns the rows updated.
+func (r *PostgresEventRepository) ReleaseEventPrizes(ctx context.Context, eventID string) (int64, error) {
+	query := `
+		UPDATE user_events ue
+		SET has_prise_status = er.prize_value_id IS NOT NULL,
+		    prize_value_id = er.prize_value_id,
+		    updated_at = EXTRACT(EPOCH FROM NOW())::BIGINT * 1000
+		FROM user_events target
+		LEFT JOIN event_results er ON er.event_id = target.event_id AND er.user_uuid = target.user_uuid
+		WHERE ue.id = target.id
+		  AND target.event_id = $1
+		  AND ue.has_prise_status IS NULL
+	`
+
+	result, err := r.pool.Exec(ctx, query, eventID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to release event prizes: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// SaveEventResults marks the event finalized and stores its final standings and winner notifications.
+// Returns false if the event was already finalized (e.g. by another replica).
+func (r *PostgresEventRepository) SaveEventResults(ctx context.Context, eventID string, results []domain.EventResult, notifications []domain.Notification, nowMs int64) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin event results transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	queryFinalize := `
+		UPDATE all_events
+		SET finalized_at = $2, updated_at = $2
+		WHERE id = $1 AND finalized_at IS NULL
+	`
+	tag, execErr := tx.Exec(ctx, queryFinalize, eventID, nowMs)
+	if execErr != nil {
+		err = execErr
+		return false, fmt.Errorf("failed to finalize event: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		_ = tx.Rollback(ctx)
+		return false, nil
+	}
+
+	queryResult := `
+		INSERT INTO event_results (event_id, user_uuid, rank, points, score, win_count, loss_count, last_scored_at, prize_value_id, prize_label, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), $11)
+	`
+	for _, result := range results {
+		if _, err = tx.Exec(ctx, queryResult,
+			eventID,
+			result.UserUUID,
+			result.Rank,
+			result.Points,
+			result.Score,
+			result.WinCount,
+			result.LossCount,
+			result.LastScoredAt,
+			result.PrizeValueID,
+			result.PrizeLabel,
+			nowMs,
+		); err != nil {
+			return false, fmt.Errorf("failed to insert event result: %w", err)
+		}
+	}
+
+	queryNotification := `
+		INSERT INTO notifications (user_id, producer, message, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+	`
+	for _, notification := range notifications {
+		if _, err = tx.Exec(ctx, queryNotification, notification.UserUUID, notification.Producer, notification.Message, nowMs); err != nil {
+			return false, fmt.Errorf("failed to queue notification: %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit event results transaction: %w", err)
+	}
+	return true, nil
+}
+
+// IsEventFinalized reports whether the event's final standings are frozen.
+func (r *PostgresEventRepository) IsEventFinalized(ctx context.Context, eventID string) (bool, error) {
+	query := `SELECT finalized_at IS NOT NULL FROM all_events WHERE id = $1`
+
+	var finalized bool
+	if err := r.pool.QueryRow(ctx, query, eventID).Scan(&finalized); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check event finalization: %w", err)
+	}
+	return finalized, nil
+}
+
+// GetUserEventResult returns the user's frozen result, or nil if the user was not ranked.
+func (r *PostgresEventRepository) GetUserEventResult(ctx context.Context, eventID string, userUUID string) (*domain.EventResult, error) {
+	query := `
+		SELECT er.event_id, er.user_uuid::text, er.rank, er.points, er.score, er.win_count, er.loss_count, er.last_scored_at, er.prize_value_id, er.prize_label,
+			u.google_name, u.telegram_username, u.telegram_first_name, u.telegram_last_name, u.display_name, COALESCE(u.hide_name, FALSE)
+		FROM event_results er
+		LEFT JOIN users u ON u.user_uuid = er.user_uuid
+		WHERE er.event_id = $1 AND er.user_uuid = $2
+	`
+
+	result, err := scanEventResult(r.pool.QueryRow(ctx, query, eventID, userUUID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get event result: %w", err)
+	}
+	return result, nil
+}
+
+// GetEventResults returns frozen standings ordered by rank.
+func (r *PostgresEventRepository) GetEventResults(ctx context.Context, eventID string, limit, offset int) ([]domain.EventResult, error) {
+	query := `
+		SELECT er.event_id, er.user_uuid::text, er.rank, er.points, er.score, er.win_count, er.loss_count, er.last_scored_at, er.prize_value_id, er.prize_label,
+			u.google_name, u.telegram_username, u.telegram_first_name, u.telegram_last_name, u.display_name, COALESCE(u.hide_name, FALSE)
+		FROM event_results er
+		LEFT JOIN users u ON u.user_uuid = er.user_uuid
+		WHERE er.event_id = $1
+		ORDER BY er.rank ASC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, eventID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event results: %w", err)
+	}
+	defer rows.Close()
+
+	var results []domain.EventResult
+	for rows.Next() {
+		result, err := scanEventResult(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event result: %w", err)
+		}
+		results = append(results, *result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating event results: %w", err)
+	}
+
+	return results, nil
+}
+
+// SaveEventRankSnapshot replaces the event's rank snapshot with the given standings. Returns false without
+// writing if another snapshot was taken less than minAgeMs ago (e.g. by another replica).
+func (r *PostgresEventRepository) SaveEventRankSnapshot(ctx context.Context, eventID string, entries []domain.BetPrizeLeaderboardEntry, nowMs int64, minAgeMs int64) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin rank snapshot transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	queryClaim := `
+		UPDATE all_events
+		SET rank_snapshot_at = $2
+		WHERE id = $1 AND (rank_snapshot_at IS NULL OR rank_snapshot_at <= $2 - $3)
+	`
+	tag, execErr := tx.Exec(ctx, queryClaim, eventID, nowMs, minAgeMs)
+	if execErr != nil {
+		err = execErr
+		return false, fmt.Errorf("failed to claim rank snapshot: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		_ = tx.Rollback(ctx)
+		return false, nil
+	}
+
+	if _, err = tx.Exec(ctx, `DELETE FROM event_rank_snapshots WHERE event_id = $1`, eventID); err != nil {
+		return false, fmt.Errorf("failed to clear rank snapshot: %w", err)
+	}
+
+	queryInsert := `
+		INSERT INTO event_rank_snapshots (event_id, user_uuid, rank, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	for _, entry := range entries {
+		if _, err = tx.Exec(ctx, queryInsert, eventID, entry.UserUUID, entry.Rank, nowMs); err != nil {
+			return false, fmt.Errorf("failed to insert rank snapshot: %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit rank snapshot transaction: %w", err)
+	}
+	return true, nil
+}
+
+// GetEventRankSnapshot returns the snapshot ranks of the given users and when the snapshot was taken
+// (nil if none was taken yet).
+func (r *PostgresEventRepository) GetEventRankSnapshot(ctx context.Context, eventID string, userUUIDs []string) (map[string]int, *int64, error) {
+	var snapshotAt *int64
+	if err := r.pool.QueryRow(ctx, `SELECT rank_snapshot_at FROM all_events WHERE id = $1`, eventID).Scan(&snapshotAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to get rank snapshot time: %w", err)
+	}
+
+	ranks := make(map[string]int)
+	if snapshotAt == nil || len(userUUIDs) == 0 {
+		return ranks, snapshotAt, nil
+	}
+
+	query := `
+		SELECT user_uuid::text, rank
+		FROM event_rank_snapshots
+		WHERE event_id = $1 AND user_uuid::text = ANY($2::TEXT[])
+	`
+	rows, err := r.pool.Query(ctx, query, eventID, userUUIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get rank snapshot: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userUUID string
+		var rank int
+		if err := rows.Scan(&userUUID, &rank); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan rank snapshot: %w", err)
+		}
+		ranks[userUUID] = rank
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating rank snapshot: %w", err)
+	}
+
+	return ranks, snapshotAt, nil
+}
+
+func scanEventResult(row pgx.Row) (*domain.EventResult, error) {
+	var result domain.EventResult
+	var prizeLabel, googleName, telegramUsername, telegramFirstName, telegramLastName, chosenName sql.NullString
+	var hideName bool
+	if err := row.Scan(
+		&result.EventID,
+		&result.UserUUID,
+		&result.Rank,
+		&result.Points,
+		&result.Score,
+		&result.WinCount,
+		&result.LossCount,
+		&result.LastScoredAt,
+		&result.PrizeValueID,
+		&prizeLabel,
+		&googleName,
+		&telegramUsername,
+		&telegramFirstName,
+		&telegramLastName,
+		&chosenName,
+		&hideName,
+	); err != nil {
+		return nil, err
+	}
+	result.UserName = buildRatingName(chosenName, hideName, googleName, telegramUsername, telegramFirstName, telegramLastName)
+	result.PrizeLabel = prizeLabel.String
+	return &result, nil
+}
+
+func scanEvents(rows pgx.Rows) ([]domain.Event, error) {
+	var events []domain.Event
+	for rows.Next() {
+		var event domain.Event
+		var rewardJSON, scoringJSON, eligibilityJSON []byte
+		var startMs, deadlineMs int64
+		if err := rows.Scan(&event.ID, &event.Badge, &event.Title, &event.Desc, &startMs, &deadlineMs, &event.Tags, &rewardJSON, &event.Info, &scoringJSON, &eligibilityJSON, &event.PrizePool, &event.State); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		event.StartTime = time.UnixMilli(startMs).UTC()
+		event.Deadline = time.UnixMilli(deadlineMs).UTC()
+		if err := json.Unmarshal(rewardJSON, &event.Reward); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal reward: %w", err)
+		}
+		scoring, err := parseScoringRules(scoringJSON)
+		if err != nil {
+			return nil, err
+		}
+		event.Scoring = scoring
+		if event.Eligibility, err = parseEventEligibility(eligibilityJSON); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating events: %w", err)
+	}
+
+	return events, nil
+}
+
+func insertEventPrizes(ctx context.Context, tx pgx.Tx, eventID string, prizes []domain.AdminEventPrize) error {
+	queryPrize := `
+		INSERT INTO prize_values (event_id, value, label, segment_id, weight, total_budget, daily_budget, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, COALESCE($5, 1), $6, $7, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+		RETURNING id
+	`
+	queryAchievement := `
+		INSERT INTO achievements (id, badge, title, image_url, desc_text, tags, prize_id, steps, step_desc, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	for i := range prizes {
+		prize := &prizes[i]
+		if err := tx.QueryRow(ctx, queryPrize, eventID, prize.Value, prize.Label, prize.SegmentID,
+			prize.Weight, prize.TotalBudget, prize.DailyBudget).Scan(&prize.ID); err != nil {
+			return fmt.Errorf("failed to create prize value: %w", err)
+		}
+
+		achievement := prize.Achievement
+		if achievement == nil {
+			continue
+		}
+		achievement.PrizeID = &prize.ID
+		tag, err := tx.Exec(ctx, queryAchievement,
+			achievement.ID,
+			achievement.Badge,
+			achievement.Title,
+			achievement.ImageURL,
+			achievement.Desc,
+			achievement.Tags,
+			achievement.PrizeID,
+			achievement.Steps,
+			achievement.StepDesc,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create achievement: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("achievement with id %s already exists", achievement.ID)
+		}
+	}
+
+	return nil
+}
+
+func scanAdminEvent(row pgx.Row) (*domain.AdminEvent, error) {
+	var event domain.AdminEvent
+	var rewardJSON []byte
+	var scoringJSON []byte
+	var eligibilityJSON []byte
+	var startMs int64
+	var deadlineMs int64
+
+	if err := row.Scan(
+		&event.ID,
+		&event.Badge,
+		&event.Title,
+		&event.Desc,
+		&startMs,
+		&deadlineMs,
+		&event.Tags,
+		&rewardJSON,
+		&event.Info,
+		&scoringJSON,
+		&eligibilityJSON,
+		&event.PrizePool,
+		&event.State,
+		&event.ArchivedAt,
+		&event.StateChangedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan event: %w", err)
+	}
+
+	event.StartTime = time.Unix(0, startMs*int64(time.Millisecond)).UTC()
+	event.Deadline = time.Unix(0, deadlineMs*int64(time.Millisecond)).UTC()
+
+	if err := json.Unmarshal(rewardJSON, &event.Reward); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal reward: %w", err)
+	}
+	scoring, err := parseScoringRules(scoringJSON)
+	if err != nil {
+		return nil, err
+	}
+	event.Scoring = scoring
+	if event.Eligibility, err = parseEventEligibility(eligibilityJSON); err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+// scoringRulesJSON encodes scoring rules for the scoring JSONB column; nil rules are stored as NULL.
+func scoringRulesJSON(rules *domain.EventScoringRules) ([]byte, error) {
+	if rules == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal scoring rules: %w", err)
+	}
+	return data, nil
+}
+
+func parseScoringRules(data []byte) (*domain.EventScoringRules, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	var rules domain.EventScoringRules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal scoring rules: %w", err)
+	}
+	return &rules, nil
+}
+
+// eventEligibilityJSON encodes eligibility rules for the eligibility JSONB column; nil rules are stored as NULL.
+func eventEligibilityJSON(eligibility *domain.EventEligibility) ([]byte, error) {
+	if eligibility == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(eligibility)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal eligibility rules: %w", err)
+	}
+	return data, nil
+}
+
+func parseEventEligibility(data []byte) (*domain.EventEligibility, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	var eligibility domain.EventEligibility
+	if err := json.Unmarshal(data, &eligibility); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal eligibility rules: %w", err)
+	}
+	return &eligibility, nil
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
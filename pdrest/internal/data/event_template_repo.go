@@ -0,0 +1,214 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"pdrest/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// EventTemplateRepository provides access to recurring event templates.
+type EventTemplateRepository interface {
+	GetEventTemplates(ctx context.Context, activeOnly bool) ([]domain.EventTemplate, error)
+	GetEventTemplate(ctx context.Context, id string) (*domain.EventTemplate, error)
+	UpsertEventTemplate(ctx context.Context, template *domain.EventTemplate) error
+	SetLastInstanceStart(ctx context.Context, id string, startMs int64) error
+}
+
+// PostgresEventTemplateRepository implements EventTemplateRepository with PostgreSQL.
+type PostgresEventTemplateRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresEventTemplateRepository(pool *pgxpool.Pool) *PostgresEventTemplateRepository {
+	return &PostgresEventTemplateRepository{pool: pool}
+}
+
+const eventTemplateColumns = `
+	id, badge, title, desc_text, COALESCE(tags, ''), reward, COALESCE(info, ''), prize_values, scoring, eligibility,
+	frequency, weekday, start_minute, duration_minutes, lead_minutes, active, last_instance_start
+`
+
+func scanEventTemplate(row pgx.Row) (*domain.EventTemplate, error) {
+	var template domain.EventTemplate
+	var rewardJSON, prizesJSON, scoringJSON, eligibilityJSON []byte
+	var frequency string
+	var lastInstanceStart *int64
+	if err := row.Scan(
+		&template.ID,
+		&template.Badge,
+		&template.Title,
+		&template.Desc,
+		&template.Tags,
+		&rewardJSON,
+		&template.Info,
+		&prizesJSON,
+		&scoringJSON,
+		&eligibilityJSON,
+		&frequency,
+		&template.Weekday,
+		&template.StartMinute,
+		&template.DurationMinutes,
+		&template.LeadMinutes,
+		&template.Active,
+		&lastInstanceStart,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(rewardJSON, &template.Reward); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal reward: %w", err)
+	}
+	if err := json.Unmarshal(prizesJSON, &template.PrizeValues); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal prize values: %w", err)
+	}
+	scoring, err := parseScoringRules(scoringJSON)
+	if err != nil {
+		return nil, err
+	}
+	template.Scoring = scoring
+	eligibility, err := parseEventEligibility(eligibilityJSON)
+	if err != nil {
+		return nil, err
+	}
+	template.Eligibility = eligibility
+	template.Frequency = domain.EventTemplateFrequency(frequency)
+	if lastInstanceStart != nil {
+		t := time.UnixMilli(*lastInstanceStart).UTC()
+		template.LastInstanceStart = &t
+	}
+	return &template, nil
+}
+
+func (r *PostgresEventTemplateRepository) GetEventTemplates(ctx context.Context, activeOnly bool) ([]domain.EventTemplate, error) {
+	query := `
+		SELECT ` + eventTemplateColumns + `
+		FROM event_templates
+		WHERE ($1 = false OR active = true)
+		ORDER BY id ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, activeOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query event templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []domain.EventTemplate
+	for rows.Next() {
+		template, err := scanEventTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event template: %w", err)
+		}
+		templates = append(templates, *template)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating event templates: %w", err)
+	}
+	return templates, nil
+}
+
+func (r *PostgresEventTemplateRepository) GetEventTemplate(ctx context.Context, id string) (*domain.EventTemplate, error) {
+	query := `
+		SELECT ` + eventTemplateColumns + `
+		FROM event_templates
+		WHERE id = $1
+	`
+
+	template, err := scanEventTemplate(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get event template: %w", err)
+	}
+	return template, nil
+}
+
+// UpsertEventTemplate creates or replaces a template. Already materialized instances are not changed.
+func (r *PostgresEventTemplateRepository) UpsertEventTemplate(ctx context.Context, template *domain.EventTemplate) error {
+	rewardJSON, err := json.Marshal(template.Reward)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reward: %w", err)
+	}
+	prizesJSON, err := json.Marshal(template.PrizeValues)
+	if err != nil {
+		return fmt.Errorf("failed to marshal prize values: %w", err)
+	}
+	scoringJSON, err := scoringRulesJSON(template.Scoring)
+	if err != nil {
+		return err
+	}
+	eligibilityJSON, err := eventEligibilityJSON(template.Eligibility)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO event_templates (
+			id, badge, title, desc_text, tags, reward, info, prize_values, scoring, eligibility,
+			frequency, weekday, start_minute, duration_minutes, lead_minutes, active, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+		ON CONFLICT (id) DO UPDATE SET
+			badge = EXCLUDED.badge,
+			title = EXCLUDED.title,
+			desc_text = EXCLUDED.desc_text,
+			tags = EXCLUDED.tags,
+			reward = EXCLUDED.reward,
+			info = EXCLUDED.info,
+			prize_values = EXCLUDED.prize_values,
+			scoring = EXCLUDED.scoring,
+			eligibility = EXCLUDED.eligibility,
+			frequency = EXCLUDED.frequency,
+			weekday = EXCLUDED.weekday,
+			start_minute = EXCLUDED.start_minute,
+			duration_minutes = EXCLUDED.duration_minutes,
+			lead_minutes = EXCLUDED.lead_minutes,
+			active = EXCLUDED.active,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err = r.pool.Exec(ctx, query,
+		template.ID,
+		template.Badge,
+		template.Title,
+		template.Desc,
+		template.Tags,
+		rewardJSON,
+		template.Info,
+		prizesJSON,
+		scoringJSON,
+		eligibilityJSON,
+		string(template.Frequency),
+		template.Weekday,
+		template.StartMinute,
+		template.DurationMinutes,
+		template.LeadMinutes,
+		template.Active,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert event template: %w", err)
+	}
+	return nil
+}
+
+// SetLastInstanceStart records the latest materialized instance; it never moves backwards.
+func (r *PostgresEventTemplateRepository) SetLastInstanceStart(ctx context.Context, id string, startMs int64) error {
+	query := `
+		UPDATE event_templates
+		SET last_instance_start = GREATEST(COALESCE(last_instance_start, 0), $2),
+		    updated_at = EXTRACT(EPOCH FROM NOW())::BIGINT * 1000
+		WHERE id = $1
+	`
+
+	if _, err := r.pool.Exec(ctx, query, id, startMs); err != nil {
+		return fmt.Errorf("failed to update event template: %w", err)
+	}
+	return nil
+}
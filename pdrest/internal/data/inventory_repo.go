@@ -0,0 +1,283 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"pdrest/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// InventoryRepository provides access to users' boosts and consumables.
+type InventoryRepository interface {
+	AddInventoryItem(ctx context.Context, item *domain.InventoryItem) error
+	GetUserInventory(ctx context.Context, userUUID string, nowMs int64) ([]domain.InventoryItem, error)
+	ActivateInventoryItem(ctx context.Context, userUUID string, itemID int, nowMs int64) (*domain.ActivateInventoryItemResponse, error)
+	CreateBetWithModifiers(ctx context.Context, bet *domain.Bet, nowMs int64) error
+}
+
+// PostgresInventoryRepository implements InventoryRepository with PostgreSQL.
+type PostgresInventoryRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresInventoryRepository(pool *pgxpool.Pool) *PostgresInventoryRepository {
+	return &PostgresInventoryRepository{pool: pool}
+}
+
+const inventoryItemColumns = `id, user_uuid, item_type, quantity, expires_at, activated_at, metadata, got_prize_id, created_at`
+
+// usableInventoryItem matches items with uses left that haven't expired at $2
+const usableInventoryItem = `(quantity IS NULL OR quantity > 0) AND (expires_at IS NULL OR expires_at > $2)`
+
+func scanInventoryItem(row pgx.Row) (*domain.InventoryItem, error) {
+	var item domain.InventoryItem
+	var metadataJSON []byte
+	if err := row.Scan(
+		&item.ID,
+		&item.UserUUID,
+		&item.ItemType,
+		&item.Quantity,
+		&item.ExpiresAt,
+		&item.ActivatedAt,
+		&metadataJSON,
+		&item.GotPrizeID,
+		&item.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if len(metadataJSON) > 0 {
+		if err := json.Unmarshal(metadataJSON, &item.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to parse metadata of inventory item %d: %w", item.ID, err)
+		}
+	}
+	item.Status = domain.InventoryStatusAvailable
+	if item.ActivatedAt != nil {
+		item.Status = domain.InventoryStatusActive
+	}
+	return &item, nil
+}
+
+func (r *PostgresInventoryRepository) AddInventoryItem(ctx context.Context, item *domain.InventoryItem) error {
+	metadataJSON, err := json.Marshal(item.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to encode inventory item metadata: %w", err)
+	}
+	query := `
+		INSERT INTO user_inventory (user_uuid, item_type, quantity, expires_at, activated_at, metadata, got_prize_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+		RETURNING id, created_at
+	`
+	if err := r.pool.QueryRow(ctx, query, item.UserUUID, item.ItemType, item.Quantity, item.ExpiresAt, item.ActivatedAt, metadataJSON, item.GotPrizeID).Scan(&item.ID, &item.CreatedAt); err != nil {
+		return fmt.Errorf("failed to add inventory item: %w", err)
+	}
+	item.Status = domain.InventoryStatusAvailable
+	if item.ActivatedAt != nil {
+		item.Status = domain.InventoryStatusActive
+	}
+	return nil
+}
+
+// GetUserInventory returns the user's items that have uses left and haven't expired, active ones first.
+func (r *PostgresInventoryRepository) GetUserInventory(ctx context.Context, userUUID string, nowMs int64) ([]domain.InventoryItem, error) {
+	query := `
+		SELECT ` + inventoryItemColumns + `
+		FROM user_inventory
+		WHERE user_uuid = $1 AND ` + usableInventoryItem + `
+		ORDER BY activated_at IS NULL, created_at DESC, id DESC
+	`
+	rows, err := r.pool.Query(ctx, query, userUUID, nowMs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query inventory: %w", err)
+	}
+	defer rows.Close()
+
+	items := []domain.InventoryItem{}
+	for rows.Next() {
+		item, err := scanInventoryItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan inventory item: %w", err)
+		}
+		items = append(items, *item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating inventory: %w", err)
+	}
+	return items, nil
+}
+
+// ActivateInventoryItem activates the user's item. Extra spins are added to their roulette and the item is used
+// up; other items start applying to the user's bets, one active item per type.
+func (r *PostgresInventoryRepository) ActivateInventoryItem(ctx context.Context, userUUID string, itemID int, nowMs int64) (*domain.ActivateInventoryItemResponse, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin inventory transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	query := `SELECT ` + inventoryItemColumns + ` FROM user_inventory WHERE id = $1 AND user_uuid = $2 FOR UPDATE`
+	item, err := scanInventoryItem(tx.QueryRow(ctx, query, itemID, userUUID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = errors.New("inventory item not found")
+		return nil, err
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to get inventory item: %w", err)
+		return nil, err
+	}
+	switch {
+	case item.ActivatedAt != nil:
+		err = errors.New("inventory item is already active")
+		return nil, err
+	case item.ExpiresAt != nil && *item.ExpiresAt <= nowMs:
+		err = errors.New("inventory item has expired")
+		return nil, err
+	case item.Quantity != nil && *item.Quantity == 0:
+		err = errors.New("inventory item is already used up")
+		return nil, err
+	case item.ItemType == domain.InventoryItemStreakFreeze:
+		err = errors.New("streak freezes are used automatically when a login streak day is missed")
+		return nil, err
+	}
+
+	response := &domain.ActivateInventoryItemResponse{}
+	if item.ItemType == domain.InventoryItemExtraSpin {
+		spins := 1
+		if item.Quantity != nil {
+			spins = *item.Quantity
+		}
+		addSpinsQuery := `
+			INSERT INTO user_roulette_spins (user_uuid, roulette_config_id, extra_spins, updated_at)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (user_uuid, roulette_config_id) DO UPDATE
+			SET extra_spins = user_roulette_spins.extra_spins + EXCLUDED.extra_spins,
+			    updated_at = EXCLUDED.updated_at
+		`
+		if _, err = tx.Exec(ctx, addSpinsQuery, userUUID, item.Metadata.RouletteConfigID, spins, nowMs); err != nil {
+			err = fmt.Errorf("failed to add roulette spins: %w", err)
+			return nil, err
+		}
+		zero := 0
+		item.Quantity = &zero
+		response.RouletteConfigID = item.Metadata.RouletteConfigID
+		response.SpinsAdded = spins
+	} else {
+		var activeExists bool
+		activeQuery := `
+			SELECT EXISTS (
+				SELECT 1 FROM user_inventory
+				WHERE user_uuid = $1 AND item_type = $3 AND activated_at IS NOT NULL AND ` + usableInventoryItem + `
+			)
+		`
+		if err = tx.QueryRow(ctx, activeQuery, userUUID, nowMs, item.ItemType).Scan(&activeExists); err != nil {
+			err = fmt.Errorf("failed to check active inventory items: %w", err)
+			return nil, err
+		}
+		if activeExists {
+			err = fmt.Errorf("another %s is already active", item.ItemType)
+			return nil, err
+		}
+		if item.Metadata.DurationHours > 0 {
+			expiresAt := nowMs + int64(item.Metadata.DurationHours)*3600*1000
+			if item.ExpiresAt == nil || expiresAt < *item.ExpiresAt {
+				item.ExpiresAt = &expiresAt
+			}
+		}
+	}
+	item.ActivatedAt = &nowMs
+	item.Status = domain.InventoryStatusActive
+
+	updateQuery := `
+		UPDATE user_inventory
+		SET activated_at = $2, quantity = $3, expires_at = $4, updated_at = $2
+		WHERE id = $1
+	`
+	if _, err = tx.Exec(ctx, updateQuery, item.ID, nowMs, item.Quantity, item.ExpiresAt); err != nil {
+		err = fmt.Errorf("failed to activate inventory item: %w", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf("failed to commit inventory item activation: %w", err)
+		return nil, err
+	}
+	response.Item = *item
+	return response, nil
+}
+
+// CreateBetWithModifiers opens the bet with the user's active items applied, in one transaction: the highest
+// payout boost and a loss shield, each using up one of its uses. The modifiers are recorded on the bet.
+func (r *PostgresInventoryRepository) CreateBetWithModifiers(ctx context.Context, bet *domain.Bet, nowMs int64) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin bet transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	activeQuery := `
+		SELECT ` + inventoryItemColumns + `
+		FROM user_inventory
+		WHERE user_uuid = $1 AND item_type = $3 AND activated_at IS NOT NULL AND ` + usableInventoryItem + `
+		ORDER BY COALESCE((metadata->>'multiplier')::FLOAT8, 0) DESC, activated_at ASC, id ASC
+		LIMIT 1
+		FOR UPDATE
+	`
+	modifiers := []domain.BetModifier{}
+	for _, itemType := range []string{domain.InventoryItemPayoutBoost, domain.InventoryItemLossShield} {
+		var item *domain.InventoryItem
+		item, err = scanInventoryItem(tx.QueryRow(ctx, activeQuery, bet.UserID, nowMs, itemType))
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = nil
+			continue
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to get active %s: %w", itemType, err)
+			return err
+		}
+		if item.Quantity != nil {
+			if _, err = tx.Exec(ctx, `UPDATE user_inventory SET quantity = quantity - 1, updated_at = $2 WHERE id = $1`, item.ID, nowMs); err != nil {
+				err = fmt.Errorf("failed to use inventory item: %w", err)
+				return err
+			}
+		}
+		modifier := domain.BetModifier{ItemID: item.ID, Type: item.ItemType}
+		if itemType == domain.InventoryItemPayoutBoost {
+			modifier.Multiplier = item.Metadata.Multiplier
+		}
+		modifiers = append(modifiers, modifier)
+	}
+
+	modifiersJSON, err := json.Marshal(modifiers)
+	if err != nil {
+		err = fmt.Errorf("failed to encode bet modifiers: %w", err)
+		return err
+	}
+	betQuery := `
+		INSERT INTO bets (user_uuid, side, sum, pair, timeframe, open_price, open_time, modifiers)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at
+	`
+	if err = tx.QueryRow(ctx, betQuery, bet.UserID, bet.Side, bet.Sum, bet.Pair, bet.Timeframe, bet.OpenPrice, bet.OpenTime, modifiersJSON).
+		Scan(&bet.ID, &bet.CreatedAt, &bet.UpdatedAt); err != nil {
+		err = fmt.Errorf("failed to create bet: %w", err)
+		return err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf("failed to commit bet: %w", err)
+		return err
+	}
+	bet.Modifiers = modifiers
+	return nil
+}
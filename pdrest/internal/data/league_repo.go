@@ -0,0 +1,504 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"pdrest/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// LeagueRepository provides access to league tiers, weekly cohorts and results.
+type LeagueRepository interface {
+	GetUserTier(ctx context.Context, userUUID string) (domain.LeagueTier, error)
+	SetUserTier(ctx context.Context, userUUID string, tier domain.LeagueTier) error
+	EnsureWeek(ctx context.Context, weekStartMs, weekEndMs int64) error
+	GetEndedActiveWeeks(ctx context.Context, nowMs int64) ([]domain.LeagueWeek, error)
+	FinishWeek(ctx context.Context, weekStartMs int64, nowMs int64, promoteCount, relegateCount int) (bool, error)
+	GetUserCohortID(ctx context.Context, userUUID string, weekStartMs int64) (*int, error)
+	AssignUserToCohort(ctx context.Context, userUUID string, weekStartMs int64, cohortSize int) (int, error)
+	GetCohort(ctx context.Context, cohortID int) (*domain.LeagueCohort, error)
+	GetCohortStandings(ctx context.Context, cohortID int) ([]domain.LeagueCohortEntry, error)
+	GetUnappliedLeagueResults(ctx context.Context) ([]domain.LeagueUnappliedResult, error)
+	ApplyLeagueResult(ctx context.Context, result domain.LeagueUnappliedResult, newTier domain.LeagueTier, prize *domain.Prize, points int64, description string) (bool, error)
+}
+
+// PostgresLeagueRepository implements LeagueRepository with PostgreSQL.
+type PostgresLeagueRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresLeagueRepository(pool *pgxpool.Pool) *PostgresLeagueRepository {
+	return &PostgresLeagueRepository{pool: pool}
+}
+
+// GetUserTier returns the user's tier; users who never played start in bronze.
+func (r *PostgresLeagueRepository) GetUserTier(ctx context.Context, userUUID string) (domain.LeagueTier, error) {
+	query := `
+		SELECT tier
+		FROM league_members
+		WHERE user_uuid = $1
+	`
+
+	var tier string
+	if err := r.pool.QueryRow(ctx, query, userUUID).Scan(&tier); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.LeagueTierBronze, nil
+		}
+		return "", fmt.Errorf("failed to get league tier: %w", err)
+	}
+	return domain.LeagueTier(tier), nil
+}
+
+func (r *PostgresLeagueRepository) SetUserTier(ctx context.Context, userUUID string, tier domain.LeagueTier) error {
+	query := `
+		INSERT INTO league_members (user_uuid, tier, created_at, updated_at)
+		VALUES ($1, $2, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+		ON CONFLICT (user_uuid) DO UPDATE
+		SET tier = EXCLUDED.tier, updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err := r.pool.Exec(ctx, query, userUUID, string(tier)); err != nil {
+		return fmt.Errorf("failed to set league tier: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresLeagueRepository) EnsureWeek(ctx context.Context, weekStartMs, weekEndMs int64) error {
+	query := `
+		INSERT INTO league_weeks (week_start, week_end, status, created_at)
+		VALUES ($1, $2, 'active', EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+		ON CONFLICT (week_start) DO NOTHING
+	`
+
+	if _, err := r.pool.Exec(ctx, query, weekStartMs, weekEndMs); err != nil {
+		return fmt.Errorf("failed to ensure league week: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresLeagueRepository) GetEndedActiveWeeks(ctx context.Context, nowMs int64) ([]domain.LeagueWeek, error) {
+	query := `
+		SELECT week_start, week_end, status
+		FROM league_weeks
+		WHERE status = 'active' AND week_end <= $1
+		ORDER BY week_start ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, nowMs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ended league weeks: %w", err)
+	}
+	defer rows.Close()
+
+	var weeks []domain.LeagueWeek
+	for rows.Next() {
+		var week domain.LeagueWeek
+		var startMs, endMs int64
+		if err := rows.Scan(&startMs, &endMs, &week.Status); err != nil {
+			return nil, fmt.Errorf("failed to scan league week: %w", err)
+		}
+		week.WeekStart = time.UnixMilli(startMs).UTC()
+		week.WeekEnd = time.UnixMilli(endMs).UTC()
+		weeks = append(weeks, week)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating league weeks: %w", err)
+	}
+
+	return weeks, nil
+}
+
+// FinishWeek marks an ended week as finished and freezes points, ranks and outcomes per cohort: the top
+// promoteCount with points are promoted, the bottom relegateCount are relegated. The outcomes are applied by
+// ApplyLeagueResult. Returns false if another replica already finished it.
+func (r *PostgresLeagueRepository) FinishWeek(ctx context.Context, weekStartMs int64, nowMs int64, promoteCount, relegateCount int) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin league week transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	var weekEndMs int64
+	queryFinish := `
+		UPDATE league_weeks
+		SET status = 'finished', finalized_at = $2
+		WHERE week_start = $1 AND status = 'active' AND week_end <= $2
+		RETURNING week_end
+	`
+	if err = tx.QueryRow(ctx, queryFinish, weekStartMs, nowMs).Scan(&weekEndMs); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = nil
+			_ = tx.Rollback(ctx)
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to finish league week: %w", err)
+	}
+
+	querySnapshot := `
+		UPDATE league_cohort_members m
+		SET points = s.week_points,
+		    final_rank = s.final_rank,
+		    outcome = CASE
+		        WHEN s.final_rank <= $3 AND s.week_points > 0 AND s.tier <> 'diamond' THEN 'promoted'
+		        WHEN s.final_rank > s.cohort_size - $4 AND s.final_rank > $3 AND s.tier <> 'bronze' THEN 'relegated'
+		        ELSE 'stayed'
+		    END
+		FROM (
+			SELECT
+				lm.user_uuid,
+				lm.tier,
+				lm.week_points,
+				ROW_NUMBER() OVER (PARTITION BY lm.cohort_id ORDER BY lm.week_points DESC, lm.joined_at ASC, lm.user_uuid ASC) AS final_rank,
+				COUNT(*) OVER (PARTITION BY lm.cohort_id) AS cohort_size
+			FROM (
+				SELECT
+					cm.user_uuid,
+					cm.cohort_id,
+					cm.tier,
+					cm.joined_at,
+					COALESCE((
+						SELECT SUM(r.points)
+						FROM rating r
+						WHERE r.user_uuid = cm.user_uuid
+						  AND r.bet_id IS NOT NULL
+						  AND r.got_prize_id IS NULL
+						  AND r.created_at >= $1
+						  AND r.created_at < $2
+					), 0)::BIGINT AS week_points
+				FROM league_cohort_members cm
+				WHERE cm.week_start = $1
+			) lm
+		) s
+		WHERE m.week_start = $1 AND m.user_uuid = s.user_uuid
+	`
+	if _, err = tx.Exec(ctx, querySnapshot, weekStartMs, weekEndMs, promoteCount, relegateCount); err != nil {
+		return false, fmt.Errorf("failed to snapshot league standings: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit league week transaction: %w", err)
+	}
+	return true, nil
+}
+
+func (r *PostgresLeagueRepository) GetUserCohortID(ctx context.Context, userUUID string, weekStartMs int64) (*int, error) {
+	query := `
+		SELECT cohort_id
+		FROM league_cohort_members
+		WHERE user_uuid = $1 AND week_start = $2
+	`
+
+	var cohortID int
+	if err := r.pool.QueryRow(ctx, query, userUUID, weekStartMs).Scan(&cohortID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get league cohort: %w", err)
+	}
+	return &cohortID, nil
+}
+
+// AssignUserToCohort places the user in a non-full cohort of their tier for the week,
+// opening a new cohort when all are full. Assignment is serialized per week and tier.
+func (r *PostgresLeagueRepository) AssignUserToCohort(ctx context.Context, userUUID string, weekStartMs int64, cohortSize int) (int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin league assignment transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	var tier string
+	queryTier := `
+		INSERT INTO league_members (user_uuid, tier, created_at, updated_at)
+		VALUES ($1, 'bronze', EXTRACT(EPOCH FROM NOW())::BIGINT * 1000, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+		ON CONFLICT (user_uuid) DO UPDATE SET user_uuid = EXCLUDED.user_uuid
+		RETURNING tier
+	`
+	if err = tx.QueryRow(ctx, queryTier, userUUID).Scan(&tier); err != nil {
+		return 0, fmt.Errorf("failed to get league tier: %w", err)
+	}
+
+	if _, err = tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('league_cohort:' || $1::text || ':' || $2::text))`, weekStartMs, tier); err != nil {
+		return 0, fmt.Errorf("failed to lock league cohorts: %w", err)
+	}
+
+	var cohortID int
+	queryExisting := `
+		SELECT cohort_id
+		FROM league_cohort_members
+		WHERE user_uuid = $1 AND week_start = $2
+	`
+	err = tx.QueryRow(ctx, queryExisting, userUUID, weekStartMs).Scan(&cohortID)
+	if err == nil {
+		if err = tx.Commit(ctx); err != nil {
+			return 0, fmt.Errorf("failed to commit league assignment transaction: %w", err)
+		}
+		return cohortID, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("failed to get league cohort: %w", err)
+	}
+
+	queryOpen := `
+		SELECT c.id
+		FROM league_cohorts c
+		LEFT JOIN league_cohort_members m ON m.cohort_id = c.id
+		WHERE c.week_start = $1 AND c.tier = $2
+		GROUP BY c.id
+		HAVING COUNT(m.user_uuid) < $3
+		ORDER BY c.id ASC
+		LIMIT 1
+	`
+	err = tx.QueryRow(ctx, queryOpen, weekStartMs, tier, cohortSize).Scan(&cohortID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		queryCreate := `
+			INSERT INTO league_cohorts (week_start, tier, created_at)
+			VALUES ($1, $2, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+			RETURNING id
+		`
+		err = tx.QueryRow(ctx, queryCreate, weekStartMs, tier).Scan(&cohortID)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to find league cohort: %w", err)
+	}
+
+	queryJoin := `
+		INSERT INTO league_cohort_members (week_start, user_uuid, cohort_id, tier, joined_at)
+		VALUES ($1, $2, $3, $4, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+	`
+	if _, err = tx.Exec(ctx, queryJoin, weekStartMs, userUUID, cohortID, tier); err != nil {
+		return 0, fmt.Errorf("failed to join league cohort: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit league assignment transaction: %w", err)
+	}
+	return cohortID, nil
+}
+
+func (r *PostgresLeagueRepository) GetCohort(ctx context.Context, cohortID int) (*domain.LeagueCohort, error) {
+	query := `
+		SELECT c.id, c.week_start, w.week_end, c.tier
+		FROM league_cohorts c
+		JOIN league_weeks w ON w.week_start = c.week_start
+		WHERE c.id = $1
+	`
+
+	var cohort domain.LeagueCohort
+	var startMs, endMs int64
+	var tier string
+	if err := r.pool.QueryRow(ctx, query, cohortID).Scan(&cohort.ID, &startMs, &endMs, &tier); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get league cohort: %w", err)
+	}
+	cohort.WeekStart = time.UnixMilli(startMs).UTC()
+	cohort.WeekEnd = time.UnixMilli(endMs).UTC()
+	cohort.Tier = domain.LeagueTier(tier)
+	return &cohort, nil
+}
+
+// GetCohortStandings returns the cohort leaderboard: frozen results for finished weeks,
+// live bet points for the running week.
+func (r *PostgresLeagueRepository) GetCohortStandings(ctx context.Context, cohortID int) ([]domain.LeagueCohortEntry, error) {
+	query := `
+		SELECT
+			m.user_uuid::text,
+			COALESCE(m.points, (
+				SELECT COALESCE(SUM(r.points), 0)
+				FROM rating r
+				WHERE r.user_uuid = m.user_uuid
+				  AND r.bet_id IS NOT NULL
+				  AND r.got_prize_id IS NULL
+				  AND r.created_at >= w.week_start
+				  AND r.created_at < w.week_end
+			))::BIGINT AS week_points,
+			m.outcome,
+			u.google_name,
+			u.telegram_username,
+			u.telegram_first_name,
+			u.telegram_last_name,
+			u.display_name,
+			COALESCE(u.hide_name, FALSE)
+		FROM league_cohort_members m
+		JOIN league_weeks w ON w.week_start = m.week_start
+		LEFT JOIN users u ON u.user_uuid = m.user_uuid
+		WHERE m.cohort_id = $1
+		ORDER BY COALESCE(m.final_rank, 2147483647) ASC, week_points DESC, m.joined_at ASC, m.user_uuid ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, cohortID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get league cohort standings: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []domain.LeagueCohortEntry
+	for rows.Next() {
+		var entry domain.LeagueCohortEntry
+		var outcome sql.NullString
+		var googleName, telegramUsername, telegramFirstName, telegramLastName, chosenName sql.NullString
+		var hideName bool
+		if err := rows.Scan(&entry.UserUUID, &entry.Points, &outcome, &googleName, &telegramUsername, &telegramFirstName, &telegramLastName, &chosenName, &hideName); err != nil {
+			return nil, fmt.Errorf("failed to scan league cohort standing: %w", err)
+		}
+		entry.Rank = len(entries) + 1
+		entry.UserName = buildRatingName(chosenName, hideName, googleName, telegramUsername, telegramFirstName, telegramLastName)
+		if outcome.Valid {
+			entry.Outcome = outcome.String
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating league cohort standings: %w", err)
+	}
+
+	return entries, nil
+}
+
+// GetUnappliedLeagueResults returns the frozen results of finished weeks whose tier move and prize haven't been
+// applied yet, oldest week first, with the prize value of their place when it is due a league prize.
+func (r *PostgresLeagueRepository) GetUnappliedLeagueResults(ctx context.Context) ([]domain.LeagueUnappliedResult, error) {
+	query := `
+		SELECT m.week_start, m.user_uuid::text, m.tier, m.final_rank, m.points, m.outcome, rw.prize_value_id
+		FROM league_cohort_members m
+		JOIN league_weeks w ON w.week_start = m.week_start
+		LEFT JOIN LATERAL (
+			SELECT lr.prize_value_id
+			FROM league_rewards lr
+			WHERE lr.tier = m.tier AND m.points > 0 AND m.final_rank BETWEEN lr.place_from AND lr.place_to
+			ORDER BY lr.place_from ASC
+			LIMIT 1
+		) rw ON TRUE
+		WHERE w.status = 'finished' AND m.outcome IS NOT NULL AND m.applied_at IS NULL
+		ORDER BY m.week_start ASC, m.cohort_id ASC, m.final_rank ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unapplied league results: %w", err)
+	}
+	defer rows.Close()
+
+	var results []domain.LeagueUnappliedResult
+	for rows.Next() {
+		var result domain.LeagueUnappliedResult
+		var weekStartMs int64
+		var tier string
+		if err := rows.Scan(&weekStartMs, &result.UserUUID, &tier, &result.Rank, &result.Points, &result.Outcome, &result.PrizeValueID); err != nil {
+			return nil, fmt.Errorf("failed to scan unapplied league result: %w", err)
+		}
+		result.WeekStart = time.UnixMilli(weekStartMs).UTC()
+		result.Tier = domain.LeagueTier(tier)
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating unapplied league results: %w", err)
+	}
+
+	return results, nil
+}
+
+// ApplyLeagueResult moves the user to newTier, records the prize of the result with its points (when prize is
+// set) and marks the result applied in one transaction. Returns false if the result was already applied (e.g.
+// by another replica).
+func (r *PostgresLeagueRepository) ApplyLeagueResult(ctx context.Context, result domain.LeagueUnappliedResult, newTier domain.LeagueTier, prize *domain.Prize, points int64, description string) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin league result transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	weekStartMs := result.WeekStart.UnixMilli()
+	queryLock := `
+		SELECT 1
+		FROM league_cohort_members
+		WHERE week_start = $1 AND user_uuid = $2 AND applied_at IS NULL
+		FOR UPDATE
+	`
+	var locked int
+	if err = tx.QueryRow(ctx, queryLock, weekStartMs, result.UserUUID).Scan(&locked); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = nil
+			_ = tx.Rollback(ctx)
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to lock league result: %w", err)
+	}
+
+	if newTier != result.Tier {
+		queryTier := `
+			INSERT INTO league_members (user_uuid, tier, created_at, updated_at)
+			VALUES ($1, $2, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+			ON CONFLICT (user_uuid) DO UPDATE
+			SET tier = EXCLUDED.tier, updated_at = EXCLUDED.updated_at
+		`
+		if _, err = tx.Exec(ctx, queryTier, result.UserUUID, string(newTier)); err != nil {
+			return false, fmt.Errorf("failed to set league tier: %w", err)
+		}
+	}
+
+	var gotPrizeID *int
+	if prize != nil {
+		queryPrize := `
+			INSERT INTO got_prizes (event_id, user_uuid, prize_value_id, prize_value, prize_type, awarded_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id
+		`
+		if err = tx.QueryRow(ctx, queryPrize,
+			prize.EventID,
+			prize.UserID,
+			prize.PrizeValueID,
+			prize.PrizeValue,
+			prize.PrizeType,
+			prize.AwardedAt,
+			prize.CreatedAt,
+		).Scan(&prize.ID); err != nil {
+			return false, fmt.Errorf("failed to create league prize: %w", err)
+		}
+		gotPrizeID = &prize.ID
+
+		if points != 0 {
+			queryPoints := `
+				INSERT INTO rating (user_uuid, points, got_prize_id, description, created_at)
+				VALUES ($1, $2, $3, $4, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+			`
+			if _, err = tx.Exec(ctx, queryPoints, result.UserUUID, points, prize.ID, description); err != nil {
+				return false, fmt.Errorf("failed to add league prize points: %w", err)
+			}
+		}
+	}
+
+	queryResult := `
+		UPDATE league_cohort_members
+		SET got_prize_id = COALESCE($3, got_prize_id), applied_at = EXTRACT(EPOCH FROM NOW())::BIGINT * 1000
+		WHERE week_start = $1 AND user_uuid = $2
+	`
+	if _, err = tx.Exec(ctx, queryResult, weekStartMs, result.UserUUID, gotPrizeID); err != nil {
+		return false, fmt.Errorf("failed to apply league result: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit league result transaction: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,286 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"pdrest/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// LoginStreakRepository provides access to the login reward calendar, users' login streaks and check-ins.
+type LoginStreakRepository interface {
+	GetLoginRewardCalendar(ctx context.Context) ([]domain.LoginRewardDay, error)
+	GetLoginStreak(ctx context.Context, userUUID string) (*domain.LoginStreak, error)
+	GetLoginCheckIn(ctx context.Context, userUUID string, day string) (*domain.LoginCheckIn, error)
+	CountStreakFreezes(ctx context.Context, userUUID string, nowMs int64) (int, error)
+	CheckIn(ctx context.Context, checkIn *domain.LoginCheckIn, prize *domain.Prize, previousDay string, nowMs int64) (bool, error)
+	ClaimLoginCheckInReward(ctx context.Context, userUUID string, day string, nowMs int64) (bool, error)
+	ReleaseLoginCheckInReward(ctx context.Context, userUUID string, day string) error
+}
+
+// PostgresLoginStreakRepository implements LoginStreakRepository with PostgreSQL.
+type PostgresLoginStreakRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresLoginStreakRepository(pool *pgxpool.Pool) *PostgresLoginStreakRepository {
+	return &PostgresLoginStreakRepository{pool: pool}
+}
+
+// GetLoginRewardCalendar returns the calendar days in order with their prize values.
+func (r *PostgresLoginStreakRepository) GetLoginRewardCalendar(ctx context.Context) ([]domain.LoginRewardDay, error) {
+	query := `
+		SELECT c.day, c.prize_value_id, pv.label, pv.reward_type
+		FROM login_reward_calendar c
+		JOIN prize_values pv ON pv.id = c.prize_value_id
+		ORDER BY c.day
+	`
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get login reward calendar: %w", err)
+	}
+	defer rows.Close()
+
+	var calendar []domain.LoginRewardDay
+	for rows.Next() {
+		var day domain.LoginRewardDay
+		if err := rows.Scan(&day.Day, &day.PrizeValueID, &day.Reward, &day.RewardType); err != nil {
+			return nil, fmt.Errorf("failed to scan login reward day: %w", err)
+		}
+		calendar = append(calendar, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate login reward calendar: %w", err)
+	}
+	return calendar, nil
+}
+
+// GetLoginStreak returns the user's streak, nil before their first check-in.
+func (r *PostgresLoginStreakRepository) GetLoginStreak(ctx context.Context, userUUID string) (*domain.LoginStreak, error) {
+	query := `
+		SELECT user_uuid::TEXT, current_streak, best_streak, COALESCE(to_char(last_checkin_day, 'YYYY-MM-DD'), ''), freezes_used
+		FROM user_login_streaks
+		WHERE user_uuid = $1
+	`
+	var streak domain.LoginStreak
+	err := r.pool.QueryRow(ctx, query, userUUID).Scan(
+		&streak.UserUUID,
+		&streak.CurrentStreak,
+		&streak.BestStreak,
+		&streak.LastCheckinDay,
+		&streak.FreezesUsed,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get login streak: %w", err)
+	}
+	return &streak, nil
+}
+
+// GetLoginCheckIn returns the user's check-in of the day, nil when they didn't check in.
+func (r *PostgresLoginStreakRepository) GetLoginCheckIn(ctx context.Context, userUUID string, day string) (*domain.LoginCheckIn, error) {
+	query := `
+		SELECT user_uuid::TEXT, to_char(checkin_day, 'YYYY-MM-DD'), streak_day, calendar_day, prize_value_id,
+		       freezes_used, got_prize_id, rewarded_at, created_at
+		FROM login_checkins
+		WHERE user_uuid = $1 AND checkin_day = $2::DATE
+	`
+	var checkIn domain.LoginCheckIn
+	err := r.pool.QueryRow(ctx, query, userUUID, day).Scan(
+		&checkIn.UserUUID,
+		&checkIn.Day,
+		&checkIn.StreakDay,
+		&checkIn.CalendarDay,
+		&checkIn.PrizeValueID,
+		&checkIn.FreezesUsed,
+		&checkIn.GotPrizeID,
+		&checkIn.RewardedAt,
+		&checkIn.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get login check-in: %w", err)
+	}
+	return &checkIn, nil
+}
+
+// CountStreakFreezes returns the streak freezes the user has left.
+func (r *PostgresLoginStreakRepository) CountStreakFreezes(ctx context.Context, userUUID string, nowMs int64) (int, error) {
+	query := `
+		SELECT COALESCE(SUM(quantity), 0)
+		FROM user_inventory
+		WHERE user_uuid = $1 AND item_type = $3 AND ` + usableInventoryItem
+	var freezes int
+	if err := r.pool.QueryRow(ctx, query, userUUID, nowMs, domain.InventoryItemStreakFreeze).Scan(&freezes); err != nil {
+		return 0, fmt.Errorf("failed to count streak freezes: %w", err)
+	}
+	return freezes, nil
+}
+
+// CheckIn records the check-in with the prize of its reward, uses up its freezes and moves the user's streak to
+// its streak day. It returns false when the user's last check-in is no longer previousDay, i.e. a concurrent
+// request checked in first. The reward is not granted yet, see ClaimLoginCheckInReward.
+func (r *PostgresLoginStreakRepository) CheckIn(ctx context.Context, checkIn *domain.LoginCheckIn, prize *domain.Prize, previousDay string, nowMs int64) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin check-in transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	if _, err = tx.Exec(ctx, `INSERT INTO user_login_streaks (user_uuid) VALUES ($1) ON CONFLICT (user_uuid) DO NOTHING`, checkIn.UserUUID); err != nil {
+		err = fmt.Errorf("failed to create login streak: %w", err)
+		return false, err
+	}
+	var lastDay string
+	lockQuery := `SELECT COALESCE(to_char(last_checkin_day, 'YYYY-MM-DD'), '') FROM user_login_streaks WHERE user_uuid = $1 FOR UPDATE`
+	if err = tx.QueryRow(ctx, lockQuery, checkIn.UserUUID).Scan(&lastDay); err != nil {
+		err = fmt.Errorf("failed to lock login streak: %w", err)
+		return false, err
+	}
+	if lastDay != previousDay {
+		_ = tx.Rollback(ctx)
+		return false, nil
+	}
+
+	if checkIn.FreezesUsed > 0 {
+		if err = useStreakFreezes(ctx, tx, checkIn.UserUUID, checkIn.FreezesUsed, nowMs); err != nil {
+			return false, err
+		}
+	}
+
+	prizeQuery := `
+		INSERT INTO got_prizes (event_id, user_uuid, prize_value_id, prize_value, prize_type, awarded_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+	if err = tx.QueryRow(ctx, prizeQuery,
+		prize.EventID,
+		prize.UserID,
+		prize.PrizeValueID,
+		prize.PrizeValue,
+		prize.PrizeType,
+		prize.AwardedAt,
+		prize.CreatedAt,
+	).Scan(&prize.ID); err != nil {
+		err = fmt.Errorf("failed to create check-in prize: %w", err)
+		return false, err
+	}
+
+	insertQuery := `
+		INSERT INTO login_checkins (user_uuid, checkin_day, streak_day, calendar_day, prize_value_id, freezes_used, got_prize_id, created_at)
+		VALUES ($1, $2::DATE, $3, $4, $5, $6, $7, $8)
+	`
+	if _, err = tx.Exec(ctx, insertQuery, checkIn.UserUUID, checkIn.Day, checkIn.StreakDay, checkIn.CalendarDay,
+		checkIn.PrizeValueID, checkIn.FreezesUsed, prize.ID, nowMs); err != nil {
+		err = fmt.Errorf("failed to record check-in: %w", err)
+		return false, err
+	}
+
+	updateQuery := `
+		UPDATE user_login_streaks
+		SET current_streak = $2,
+		    best_streak = GREATEST(best_streak, $2),
+		    last_checkin_day = $3::DATE,
+		    freezes_used = freezes_used + $4,
+		    updated_at = $5
+		WHERE user_uuid = $1
+	`
+	if _, err = tx.Exec(ctx, updateQuery, checkIn.UserUUID, checkIn.StreakDay, checkIn.Day, checkIn.FreezesUsed, nowMs); err != nil {
+		err = fmt.Errorf("failed to update login streak: %w", err)
+		return false, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf("failed to commit check-in: %w", err)
+		return false, err
+	}
+	checkIn.GotPrizeID = &prize.ID
+	checkIn.CreatedAt = nowMs
+	return true, nil
+}
+
+// useStreakFreezes takes count freezes from the user's inventory, soonest-expiring first.
+func useStreakFreezes(ctx context.Context, tx pgx.Tx, userUUID string, count int, nowMs int64) error {
+	query := `
+		SELECT id, quantity
+		FROM user_inventory
+		WHERE user_uuid = $1 AND item_type = $3 AND quantity > 0 AND ` + usableInventoryItem + `
+		ORDER BY expires_at NULLS LAST, id
+		FOR UPDATE
+	`
+	rows, err := tx.Query(ctx, query, userUUID, nowMs, domain.InventoryItemStreakFreeze)
+	if err != nil {
+		return fmt.Errorf("failed to get streak freezes: %w", err)
+	}
+	type freezeItem struct {
+		id       int
+		quantity int
+	}
+	var items []freezeItem
+	for rows.Next() {
+		var item freezeItem
+		if err := rows.Scan(&item.id, &item.quantity); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan streak freeze: %w", err)
+		}
+		items = append(items, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate streak freezes: %w", err)
+	}
+
+	for _, item := range items {
+		if count == 0 {
+			break
+		}
+		used := item.quantity
+		if used > count {
+			used = count
+		}
+		if _, err := tx.Exec(ctx, `UPDATE user_inventory SET quantity = quantity - $2, updated_at = $3 WHERE id = $1`, item.id, used, nowMs); err != nil {
+			return fmt.Errorf("failed to use streak freeze: %w", err)
+		}
+		count -= used
+	}
+	if count > 0 {
+		return errors.New("not enough streak freezes")
+	}
+	return nil
+}
+
+// ClaimLoginCheckInReward marks the check-in's reward as granted before it is granted. It returns false when
+// the reward was already granted or a concurrent claim is granting it, so a check-in pays out at most once.
+func (r *PostgresLoginStreakRepository) ClaimLoginCheckInReward(ctx context.Context, userUUID string, day string, nowMs int64) (bool, error) {
+	query := `
+		UPDATE login_checkins
+		SET rewarded_at = $3
+		WHERE user_uuid = $1 AND checkin_day = $2::DATE AND rewarded_at IS NULL
+	`
+	tag, err := r.pool.Exec(ctx, query, userUUID, day, nowMs)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim check-in reward: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// ReleaseLoginCheckInReward takes back a claim whose grant failed, so the next claim of the day grants again.
+func (r *PostgresLoginStreakRepository) ReleaseLoginCheckInReward(ctx context.Context, userUUID string, day string) error {
+	query := `UPDATE login_checkins SET rewarded_at = NULL WHERE user_uuid = $1 AND checkin_day = $2::DATE`
+	if _, err := r.pool.Exec(ctx, query, userUUID, day); err != nil {
+		return fmt.Errorf("failed to release check-in reward: %w", err)
+	}
+	return nil
+}
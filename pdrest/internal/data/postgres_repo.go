@@ -49,10 +49,11 @@ func (r *PostgresUserRepository) GetProfile(uuid string) (*domain.UserProfile, e
 	var authProvider *string
 	var googleName *string
 	var telegramUsername *string
+	var displayName *string
 
-	query := `SELECT user_uuid, auth_provider, google_name, telegram_username FROM users WHERE user_uuid = $1`
+	query := `SELECT user_uuid, auth_provider, google_name, telegram_username, display_name FROM users WHERE user_uuid = $1`
 
-	err := r.pool.QueryRow(ctx, query, uuid).Scan(&result.UserID, &authProvider, &googleName, &telegramUsername)
+	err := r.pool.QueryRow(ctx, query, uuid).Scan(&result.UserID, &authProvider, &googleName, &telegramUsername, &displayName)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -60,8 +61,10 @@ func (r *PostgresUserRepository) GetProfile(uuid string) (*domain.UserProfile, e
 		return nil, fmt.Errorf("failed to get user profile: %w", err)
 	}
 
-	// Determine username based on auth_provider
-	if authProvider != nil {
+	// A chosen display name wins; otherwise determine username based on auth_provider
+	if displayName != nil && *displayName != "" {
+		result.Username = displayName
+	} else if authProvider != nil {
 		if *authProvider == "google" && googleName != nil {
 			result.Username = googleName
 		} else if *authProvider == "telegram" && telegramUsername != nil {
@@ -95,8 +98,15 @@ func (r *PostgresUserRepository) FindUserByMainRef(ctx context.Context, refCode
 		return nil, fmt.Errorf("ref_code is required")
 	}
 
+	// main_ref wins over campaign codes listed in add_refs
 	var result domain.User
-	query := `SELECT user_uuid, telegram_id FROM users WHERE main_ref = $1 LIMIT 1`
+	query := `
+		SELECT user_uuid, telegram_id
+		FROM users
+		WHERE main_ref = $1 OR add_refs @> ARRAY[$1]::TEXT[]
+		ORDER BY (main_ref IS NOT DISTINCT FROM $1) DESC
+		LIMIT 1
+	`
 	err := r.pool.QueryRow(ctx, query, normalized).Scan(&result.UserID, &result.TelegramID)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -405,14 +415,17 @@ func (r *PostgresUserRepository) ApplyReferralCode(ctx context.Context, userUUID
 		}
 	}()
 
+	// Resolve the code against main_ref first, then campaign codes in add_refs.
 	var referrerUUID string
+	var isMainRef bool
 	queryReferrer := `
-		SELECT user_uuid::text
+		SELECT user_uuid::text, (main_ref IS NOT DISTINCT FROM $1) AS is_main_ref
 		FROM users
-		WHERE main_ref = $1
+		WHERE main_ref = $1 OR add_refs @> ARRAY[$1]::TEXT[]
+		ORDER BY is_main_ref DESC
 		LIMIT 1
 	`
-	if err = tx.QueryRow(ctx, queryReferrer, normalizedCode).Scan(&referrerUUID); err != nil {
+	if err = tx.QueryRow(ctx, queryReferrer, normalizedCode).Scan(&referrerUUID, &isMainRef); err != nil {
 		if err == pgx.ErrNoRows {
 			return fmt.Errorf("referral code not found")
 		}
@@ -420,20 +433,44 @@ func (r *PostgresUserRepository) ApplyReferralCode(ctx context.Context, userUUID
 	}
 
 	if referrerUUID == userUUID {
-		return fmt.Errorf("cannot use own referral code")
+		err = fmt.Errorf("cannot use own referral code")
+		return err
 	}
 
 	querySetReferrer := `
 		UPDATE users
-		SET referrer_user_uuid = $2
+		SET referrer_user_uuid = $2, referral_code = $3
 		WHERE user_uuid = $1 AND referrer_user_uuid IS NULL
 	`
-	tag, execErr := tx.Exec(ctx, querySetReferrer, userUUID, referrerUUID)
+	tag, execErr := tx.Exec(ctx, querySetReferrer, userUUID, referrerUUID, normalizedCode)
 	if execErr != nil {
+		err = execErr
 		return fmt.Errorf("failed to set referrer: %w", execErr)
 	}
 	if tag.RowsAffected() == 0 {
-		return fmt.Errorf("referrer already set")
+		err = fmt.Errorf("referrer already set")
+		return err
+	}
+
+	if !isMainRef {
+		// Campaign codes honour expiry and usage caps.
+		queryUseCode := `
+			UPDATE referral_codes
+			SET uses = uses + 1
+			WHERE code = $1
+			  AND owner_uuid = $2
+			  AND (expires_at IS NULL OR expires_at > EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+			  AND (max_uses IS NULL OR uses < max_uses)
+		`
+		tag, execErr = tx.Exec(ctx, queryUseCode, normalizedCode, referrerUUID)
+		if execErr != nil {
+			err = execErr
+			return fmt.Errorf("failed to use referral code: %w", execErr)
+		}
+		if tag.RowsAffected() == 0 {
+			err = fmt.Errorf("referral code expired or exhausted")
+			return err
+		}
 	}
 
 	if err = tx.Commit(ctx); err != nil {
@@ -522,6 +559,22 @@ func (r *PostgresUserRepository) SetReferrerByInviterUUID(ctx context.Context, u
 	return nil
 }
 
+// GetReferrerUUID returns the user's referrer, or "" when the user has none.
+func (r *PostgresUserRepository) GetReferrerUUID(ctx context.Context, userUUID string) (string, error) {
+	var referrerUUID *string
+	query := `SELECT referrer_user_uuid::text FROM users WHERE user_uuid = $1`
+	if err := r.pool.QueryRow(ctx, query, userUUID).Scan(&referrerUUID); err != nil {
+		if err == pgx.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get referrer: %w", err)
+	}
+	if referrerUUID == nil {
+		return "", nil
+	}
+	return *referrerUUID, nil
+}
+
 func (r *PostgresUserRepository) UpdateUserLanguage(ctx context.Context, userUUID string, language string) error {
 	if userUUID == "" {
 		return fmt.Errorf("user_uuid is required")
@@ -542,6 +595,24 @@ func (r *PostgresUserRepository) UpdateUserLanguage(ctx context.Context, userUUI
 	return nil
 }
 
+// UpdateLastLogin stamps last_login_at for an existing user (token refresh, returning logins)
+func (r *PostgresUserRepository) UpdateLastLogin(ctx context.Context, userUUID string) error {
+	if userUUID == "" {
+		return fmt.Errorf("user_uuid is required")
+	}
+
+	query := `
+		UPDATE users
+		SET last_login_at = EXTRACT(EPOCH FROM NOW())::BIGINT * 1000
+		WHERE user_uuid = $1
+	`
+	_, err := r.pool.Exec(ctx, query, userUUID)
+	if err != nil {
+		return fmt.Errorf("failed to update last login: %w", err)
+	}
+	return nil
+}
+
 // CreateOrUpdateUserWithTelegramInfo creates or updates a user with Telegram OAuth information
 func (r *PostgresUserRepository) CreateOrUpdateUserWithTelegramInfo(ctx context.Context, userUUID string, telegramID int64, telegramUsername string, telegramFirstName string, telegramLastName string) error {
 	if userUUID == "" {
@@ -2,6 +2,7 @@ package data
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"pdrest/internal/domain"
 
@@ -14,6 +15,10 @@ type PrizeValueRepository interface {
 	GetPrizeValuesByEventID(ctx context.Context, eventID string) ([]domain.PrizeValue, error)
 	GetPrizeValueByID(ctx context.Context, id int) (*domain.PrizeValue, error)
 	GetPrizeValueByEventIDAndValue(ctx context.Context, eventID string, value int64) (*domain.PrizeValue, error)
+	GetPrizeValueUsage(ctx context.Context, prizeValueIDs []int, day string) (map[int]domain.PrizeValueUsage, error)
+	ReservePrizeValue(ctx context.Context, prizeValueID int, day string) (bool, error)
+	ReleasePrizeValue(ctx context.Context, prizeValueID int, day string) error
+	GetOrCreateTieSplitPrizeValue(ctx context.Context, eventID string, value int64, label string) (*domain.PrizeValue, error)
 }
 
 // PostgresPrizeValueRepository implements PrizeValueRepository with PostgreSQL
@@ -27,7 +32,8 @@ func NewPostgresPrizeValueRepository(pool *pgxpool.Pool) *PostgresPrizeValueRepo
 
 func (r *PostgresPrizeValueRepository) GetPrizeValuesByEventID(ctx context.Context, eventID string) ([]domain.PrizeValue, error) {
 	query := `
-		SELECT id, event_id, value, label, segment_id, created_at, updated_at
+		SELECT id, event_id, value, label, segment_id, created_at, updated_at, reward_type, reward_params,
+		       weight, total_budget, daily_budget
 		FROM prize_values
 		WHERE event_id = $1
 		ORDER BY id ASC
@@ -41,23 +47,11 @@ func (r *PostgresPrizeValueRepository) GetPrizeValuesByEventID(ctx context.Conte
 
 	var prizeValues []domain.PrizeValue
 	for rows.Next() {
-		var pv domain.PrizeValue
-		var segmentID *string
-
-		if err := rows.Scan(
-			&pv.ID,
-			&pv.EventID,
-			&pv.Value,
-			&pv.Label,
-			&segmentID,
-			&pv.CreatedAt,
-			&pv.UpdatedAt,
-		); err != nil {
+		pv, err := scanPrizeValue(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan prize value: %w", err)
 		}
-
-		pv.SegmentID = segmentID
-		prizeValues = append(prizeValues, pv)
+		prizeValues = append(prizeValues, *pv)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -69,62 +63,187 @@ func (r *PostgresPrizeValueRepository) GetPrizeValuesByEventID(ctx context.Conte
 
 func (r *PostgresPrizeValueRepository) GetPrizeValueByID(ctx context.Context, id int) (*domain.PrizeValue, error) {
 	query := `
-		SELECT id, event_id, value, label, segment_id, created_at, updated_at
+		SELECT id, event_id, value, label, segment_id, created_at, updated_at, reward_type, reward_params,
+		       weight, total_budget, daily_budget
 		FROM prize_values
 		WHERE id = $1
 	`
 
-	var pv domain.PrizeValue
-	var segmentID *string
-
-	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&pv.ID,
-		&pv.EventID,
-		&pv.Value,
-		&pv.Label,
-		&segmentID,
-		&pv.CreatedAt,
-		&pv.UpdatedAt,
-	)
-
+	pv, err := scanPrizeValue(r.pool.QueryRow(ctx, query, id))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
 		}
 		return nil, fmt.Errorf("failed to get prize value: %w", err)
 	}
-
-	pv.SegmentID = segmentID
-	return &pv, nil
+	return pv, nil
 }
 
 func (r *PostgresPrizeValueRepository) GetPrizeValueByEventIDAndValue(ctx context.Context, eventID string, value int64) (*domain.PrizeValue, error) {
 	query := `
-		SELECT id, event_id, value, label, segment_id, created_at, updated_at
+		SELECT id, event_id, value, label, segment_id, created_at, updated_at, reward_type, reward_params,
+		       weight, total_budget, daily_budget
 		FROM prize_values
 		WHERE event_id = $1 AND value = $2
 	`
 
-	var pv domain.PrizeValue
-	var segmentID *string
+	pv, err := scanPrizeValue(r.pool.QueryRow(ctx, query, eventID, value))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get prize value by event and value: %w", err)
+	}
+	return pv, nil
+}
+
+// GetPrizeValueUsage returns the roulette wins of the prize values overall and on the UTC day (YYYY-MM-DD).
+func (r *PostgresPrizeValueRepository) GetPrizeValueUsage(ctx context.Context, prizeValueIDs []int, day string) (map[int]domain.PrizeValueUsage, error) {
+	usage := make(map[int]domain.PrizeValueUsage)
+	if len(prizeValueIDs) == 0 {
+		return usage, nil
+	}
+	query := `
+		SELECT prize_value_id, COALESCE(SUM(used), 0), COALESCE(SUM(used) FILTER (WHERE usage_day = $2::DATE), 0)
+		FROM prize_value_usage
+		WHERE prize_value_id = ANY($1)
+		GROUP BY prize_value_id
+	`
+	rows, err := r.pool.Query(ctx, query, prizeValueIDs, day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prize value usage: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var u domain.PrizeValueUsage
+		if err := rows.Scan(&id, &u.Total, &u.Today); err != nil {
+			return nil, fmt.Errorf("failed to scan prize value usage: %w", err)
+		}
+		usage[id] = u
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating prize value usage: %w", err)
+	}
+	return usage, nil
+}
+
+// ReservePrizeValue counts a roulette win of the prize value on the UTC day. It returns false when the prize
+// value's total or daily budget is used up.
+func (r *PostgresPrizeValueRepository) ReservePrizeValue(ctx context.Context, prizeValueID int, day string) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin prize value reservation: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	var totalBudget, dailyBudget *int
+	err = tx.QueryRow(ctx, `SELECT total_budget, daily_budget FROM prize_values WHERE id = $1 FOR UPDATE`, prizeValueID).Scan(&totalBudget, &dailyBudget)
+	if err == pgx.ErrNoRows {
+		err = fmt.Errorf("prize value %d not found", prizeValueID)
+		return false, err
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to lock prize value: %w", err)
+		return false, err
+	}
+
+	var total, today int
+	usageQuery := `
+		SELECT COALESCE(SUM(used), 0), COALESCE(SUM(used) FILTER (WHERE usage_day = $2::DATE), 0)
+		FROM prize_value_usage
+		WHERE prize_value_id = $1
+	`
+	if err = tx.QueryRow(ctx, usageQuery, prizeValueID, day).Scan(&total, &today); err != nil {
+		err = fmt.Errorf("failed to get prize value usage: %w", err)
+		return false, err
+	}
+	if (totalBudget != nil && total >= *totalBudget) || (dailyBudget != nil && today >= *dailyBudget) {
+		_ = tx.Rollback(ctx)
+		return false, nil
+	}
+
+	reserveQuery := `
+		INSERT INTO prize_value_usage (prize_value_id, usage_day, used, updated_at)
+		VALUES ($1, $2::DATE, 1, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+		ON CONFLICT (prize_value_id, usage_day) DO UPDATE
+		SET used = prize_value_usage.used + 1,
+		    updated_at = EXCLUDED.updated_at
+	`
+	if _, err = tx.Exec(ctx, reserveQuery, prizeValueID, day); err != nil {
+		err = fmt.Errorf("failed to reserve prize value: %w", err)
+		return false, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf("failed to commit prize value reservation: %w", err)
+		return false, err
+	}
+	return true, nil
+}
+
+// ReleasePrizeValue gives back a win reserved on the UTC day, when the roulette was spun again.
+func (r *PostgresPrizeValueRepository) ReleasePrizeValue(ctx context.Context, prizeValueID int, day string) error {
+	query := `
+		UPDATE prize_value_usage
+		SET used = GREATEST(used - 1, 0),
+		    updated_at = EXTRACT(EPOCH FROM NOW())::BIGINT * 1000
+		WHERE prize_value_id = $1 AND usage_day = $2::DATE
+	`
+	if _, err := r.pool.Exec(ctx, query, prizeValueID, day); err != nil {
+		return fmt.Errorf("failed to release prize value: %w", err)
+	}
+	return nil
+}
+
+// GetOrCreateTieSplitPrizeValue returns the event's prize value paying value to each user of a split tie,
+// creating it on first use.
+func (r *PostgresPrizeValueRepository) GetOrCreateTieSplitPrizeValue(ctx context.Context, eventID string, value int64, label string) (*domain.PrizeValue, error) {
+	query := `
+		INSERT INTO prize_values (event_id, value, label, segment_id, created_at, updated_at)
+		VALUES ($1, $2, $3, 'tie_split', EXTRACT(EPOCH FROM NOW())::BIGINT * 1000, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+		ON CONFLICT (event_id, value) WHERE segment_id = 'tie_split'
+		DO UPDATE SET updated_at = EXCLUDED.updated_at
+		RETURNING id, event_id, value, label, segment_id, created_at, updated_at, reward_type, reward_params,
+		          weight, total_budget, daily_budget
+	`
 
-	err := r.pool.QueryRow(ctx, query, eventID, value).Scan(
+	pv, err := scanPrizeValue(r.pool.QueryRow(ctx, query, eventID, value, label))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save tie split prize value: %w", err)
+	}
+	return pv, nil
+}
+
+// scanPrizeValue scans a prize_values row selected with its reward columns
+func scanPrizeValue(row pgx.Row) (*domain.PrizeValue, error) {
+	var pv domain.PrizeValue
+	var paramsJSON []byte
+	if err := row.Scan(
 		&pv.ID,
 		&pv.EventID,
 		&pv.Value,
 		&pv.Label,
-		&segmentID,
+		&pv.SegmentID,
 		&pv.CreatedAt,
 		&pv.UpdatedAt,
-	)
-	if err != nil {
-		if err == pgx.ErrNoRows {
-			return nil, nil
+		&pv.RewardType,
+		&paramsJSON,
+		&pv.Weight,
+		&pv.TotalBudget,
+		&pv.DailyBudget,
+	); err != nil {
+		return nil, err
+	}
+	if len(paramsJSON) > 0 {
+		if err := json.Unmarshal(paramsJSON, &pv.RewardParams); err != nil {
+			return nil, fmt.Errorf("failed to parse reward params of prize value %d: %w", pv.ID, err)
 		}
-		return nil, fmt.Errorf("failed to get prize value by event and value: %w", err)
 	}
-
-	pv.SegmentID = segmentID
 	return &pv, nil
 }
 
@@ -146,3 +265,19 @@ func (r *InMemoryPrizeValueRepository) GetPrizeValueByID(ctx context.Context, id
 func (r *InMemoryPrizeValueRepository) GetPrizeValueByEventIDAndValue(ctx context.Context, eventID string, value int64) (*domain.PrizeValue, error) {
 	return nil, fmt.Errorf("prize value retrieval requires database connection")
 }
+
+func (r *InMemoryPrizeValueRepository) GetPrizeValueUsage(ctx context.Context, prizeValueIDs []int, day string) (map[int]domain.PrizeValueUsage, error) {
+	return map[int]domain.PrizeValueUsage{}, nil
+}
+
+func (r *InMemoryPrizeValueRepository) ReservePrizeValue(ctx context.Context, prizeValueID int, day string) (bool, error) {
+	return false, fmt.Errorf("prize value budgets require database connection")
+}
+
+func (r *InMemoryPrizeValueRepository) ReleasePrizeValue(ctx context.Context, prizeValueID int, day string) error {
+	return nil
+}
+
+func (r *InMemoryPrizeValueRepository) GetOrCreateTieSplitPrizeValue(ctx context.Context, eventID string, value int64, label string) (*domain.PrizeValue, error) {
+	return nil, fmt.Errorf("prize value creation requires database connection")
+}
@@ -0,0 +1,206 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"pdrest/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ProfileRepository provides access to profile settings, showcases and the stats shown on profiles.
+type ProfileRepository interface {
+	GetProfileSettings(ctx context.Context, userUUID string) (*domain.ProfileSettings, error)
+	UpdateProfileSettings(ctx context.Context, userUUID string, settings domain.ProfileSettings) error
+	GetProfileStats(ctx context.Context, userUUID string) (*domain.ProfileStats, error)
+	GetGlobalRank(ctx context.Context, userUUID string) (int, error)
+	GetShowcase(ctx context.Context, userUUID string) ([]domain.ShowcaseBadge, error)
+	SetShowcase(ctx context.Context, userUUID string, achievementIDs []string) error
+}
+
+// PostgresProfileRepository implements ProfileRepository with PostgreSQL.
+type PostgresProfileRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresProfileRepository(pool *pgxpool.Pool) *PostgresProfileRepository {
+	return &PostgresProfileRepository{pool: pool}
+}
+
+// GetProfileSettings returns the user's profile settings, nil when the user doesn't exist.
+func (r *PostgresProfileRepository) GetProfileSettings(ctx context.Context, userUUID string) (*domain.ProfileSettings, error) {
+	query := `SELECT display_name, hide_name, hide_stats FROM users WHERE user_uuid = $1`
+	var settings domain.ProfileSettings
+	err := r.pool.QueryRow(ctx, query, userUUID).Scan(&settings.DisplayName, &settings.HideName, &settings.HideStats)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile settings: %w", err)
+	}
+	return &settings, nil
+}
+
+func (r *PostgresProfileRepository) UpdateProfileSettings(ctx context.Context, userUUID string, settings domain.ProfileSettings) error {
+	query := `
+		UPDATE users
+		SET display_name = $2, hide_name = $3, hide_stats = $4
+		WHERE user_uuid = $1
+	`
+	tag, err := r.pool.Exec(ctx, query, userUUID, settings.DisplayName, settings.HideName, settings.HideStats)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return errors.New("display name is already taken")
+		}
+		return fmt.Errorf("failed to update profile settings: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+// GetProfileStats counts the user's settled bets, wins, points, joined events and claimed achievements.
+// BestWinStreak is left to the caller.
+func (r *PostgresProfileRepository) GetProfileStats(ctx context.Context, userUUID string) (*domain.ProfileStats, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM bets WHERE user_uuid = $1),
+			(SELECT COUNT(*) FROM bets
+			 WHERE user_uuid = $1 AND close_price IS NOT NULL
+			   AND ((side = 'pump' AND close_price > open_price) OR (side = 'dump' AND close_price < open_price))),
+			(SELECT COUNT(*) FROM bets WHERE user_uuid = $1 AND close_price IS NOT NULL),
+			(SELECT COALESCE(SUM(points), 0)::BIGINT FROM rating WHERE user_uuid = $1),
+			(SELECT COUNT(*) FROM user_events WHERE user_uuid = $1),
+			(SELECT COUNT(*) FROM user_achievements WHERE user_uuid = $1 AND claimed_status = TRUE)
+	`
+	var stats domain.ProfileStats
+	var settled int
+	if err := r.pool.QueryRow(ctx, query, userUUID).Scan(
+		&stats.BetsPlaced,
+		&stats.Wins,
+		&settled,
+		&stats.TotalPoints,
+		&stats.EventsJoined,
+		&stats.AchievementsClaimed,
+	); err != nil {
+		return nil, fmt.Errorf("failed to get profile stats: %w", err)
+	}
+	stats.Losses = settled - stats.Wins
+	if settled > 0 {
+		stats.WinRate = float64(stats.Wins) / float64(settled)
+	}
+	return &stats, nil
+}
+
+// GetGlobalRank returns the user's place in the global rating, ordered like GetGlobalRating; 0 when the user has
+// no rating rows.
+func (r *PostgresProfileRepository) GetGlobalRank(ctx context.Context, userUUID string) (int, error) {
+	query := `
+		WITH totals AS (
+			SELECT user_uuid, SUM(points)::BIGINT AS total_points
+			FROM rating
+			GROUP BY user_uuid
+		),
+		me AS (
+			SELECT total_points FROM totals WHERE user_uuid = $1
+		)
+		SELECT CASE WHEN NOT EXISTS (SELECT 1 FROM me) THEN 0 ELSE (
+			SELECT COUNT(*) + 1
+			FROM totals t, me
+			WHERE t.total_points > me.total_points
+			   OR (t.total_points = me.total_points AND t.user_uuid::text < $1::text)
+		) END
+	`
+	var rank int
+	if err := r.pool.QueryRow(ctx, query, userUUID).Scan(&rank); err != nil {
+		return 0, fmt.Errorf("failed to get global rank: %w", err)
+	}
+	return rank, nil
+}
+
+func (r *PostgresProfileRepository) GetShowcase(ctx context.Context, userUUID string) ([]domain.ShowcaseBadge, error) {
+	query := `
+		SELECT s.position, a.id, a.title, a.badge, a.image_url
+		FROM user_showcase_badges s
+		JOIN achievements a ON a.id = s.achievement_id
+		WHERE s.user_uuid = $1
+		ORDER BY s.position
+	`
+	rows, err := r.pool.Query(ctx, query, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get showcase: %w", err)
+	}
+	defer rows.Close()
+
+	showcase := []domain.ShowcaseBadge{}
+	for rows.Next() {
+		var badge domain.ShowcaseBadge
+		if err := rows.Scan(&badge.Position, &badge.AchievementID, &badge.Title, &badge.Badge, &badge.ImageURL); err != nil {
+			return nil, fmt.Errorf("failed to scan showcase badge: %w", err)
+		}
+		showcase = append(showcase, badge)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating showcase: %w", err)
+	}
+	return showcase, nil
+}
+
+// SetShowcase replaces the user's showcase with the given achievements, in order. Every achievement must be
+// claimed by the user.
+func (r *PostgresProfileRepository) SetShowcase(ctx context.Context, userUUID string, achievementIDs []string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	for _, achievementID := range achievementIDs {
+		var claimed bool
+		err = tx.QueryRow(ctx, `
+			SELECT EXISTS (
+				SELECT 1 FROM user_achievements
+				WHERE user_uuid = $1 AND achievement_id = $2 AND claimed_status = TRUE
+			)
+		`, userUUID, achievementID).Scan(&claimed)
+		if err != nil {
+			err = fmt.Errorf("failed to check achievement: %w", err)
+			return err
+		}
+		if !claimed {
+			err = fmt.Errorf("achievement %s is not claimed", achievementID)
+			return err
+		}
+	}
+
+	if _, err = tx.Exec(ctx, `DELETE FROM user_showcase_badges WHERE user_uuid = $1`, userUUID); err != nil {
+		err = fmt.Errorf("failed to clear showcase: %w", err)
+		return err
+	}
+	for i, achievementID := range achievementIDs {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO user_showcase_badges (user_uuid, position, achievement_id)
+			VALUES ($1, $2, $3)
+		`, userUUID, i+1, achievementID)
+		if err != nil {
+			err = fmt.Errorf("failed to add showcase badge: %w", err)
+			return err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf("failed to commit transaction: %w", err)
+		return err
+	}
+	return nil
+}
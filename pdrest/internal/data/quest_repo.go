@@ -0,0 +1,326 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"pdrest/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// QuestRepository provides access to quest templates, the quests given to users and their progress.
+type QuestRepository interface {
+	GetUserTimezone(ctx context.Context, userUUID string, nowMs int64) (*domain.UserTimezone, error)
+	SetUserTimezone(ctx context.Context, userUUID string, timezone string, fromMs, nowMs int64) error
+	GetActiveQuestTemplates(ctx context.Context) ([]domain.QuestTemplate, error)
+	AssignUserQuests(ctx context.Context, quests []domain.UserQuest) error
+	GetUserQuests(ctx context.Context, userUUID string, nowMs int64) ([]domain.UserQuest, error)
+	GetUserQuestByID(ctx context.Context, userUUID string, questID int) (*domain.UserQuest, error)
+	ApplyQuestProgress(ctx context.Context, eventKey string, deltas []domain.QuestProgressDelta, nowMs int64) ([]int, error)
+	ClaimUserQuest(ctx context.Context, userUUID string, questID int, nowMs int64) (bool, error)
+	SetUserQuestPrize(ctx context.Context, questID int, gotPrizeID int) error
+	UnclaimUserQuest(ctx context.Context, questID int) error
+}
+
+// PostgresQuestRepository implements QuestRepository with PostgreSQL.
+type PostgresQuestRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresQuestRepository(pool *pgxpool.Pool) *PostgresQuestRepository {
+	return &PostgresQuestRepository{pool: pool}
+}
+
+// GetUserTimezone returns the user's quest time zone at nowMs, with a pending change once it applies.
+// Returns nil if the user doesn't exist.
+func (r *PostgresQuestRepository) GetUserTimezone(ctx context.Context, userUUID string, nowMs int64) (*domain.UserTimezone, error) {
+	query := `
+		SELECT
+			CASE WHEN timezone_pending_from <= $2 THEN timezone_pending ELSE timezone END,
+			CASE WHEN timezone_pending_from > $2 THEN timezone_pending END,
+			CASE WHEN timezone_pending_from > $2 THEN timezone_pending_from END
+		FROM users
+		WHERE user_uuid = $1
+	`
+
+	var result domain.UserTimezone
+	var pending sql.NullString
+	var pendingFrom sql.NullInt64
+	err := r.pool.QueryRow(ctx, query, userUUID, nowMs).Scan(&result.Timezone, &pending, &pendingFrom)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user timezone: %w", err)
+	}
+	result.PendingTimezone = pending.String
+	result.PendingFrom = pendingFrom.Int64
+	return &result, nil
+}
+
+// SetUserTimezone requests the user's time zone from fromMs, replacing an earlier request; a pending zone that
+// applies by nowMs becomes the current one first.
+func (r *PostgresQuestRepository) SetUserTimezone(ctx context.Context, userUUID string, timezone string, fromMs, nowMs int64) error {
+	query := `
+		UPDATE users
+		SET timezone = CASE
+				WHEN $3 <= $4 THEN $2::VARCHAR
+				WHEN timezone_pending_from <= $4 THEN timezone_pending
+				ELSE timezone
+			END,
+			timezone_pending = CASE WHEN $3 <= $4 THEN NULL ELSE $2::VARCHAR END,
+			timezone_pending_from = CASE WHEN $3 <= $4 THEN NULL ELSE $3::BIGINT END
+		WHERE user_uuid = $1
+	`
+
+	tag, err := r.pool.Exec(ctx, query, userUUID, timezone, fromMs, nowMs)
+	if err != nil {
+		return fmt.Errorf("failed to set user timezone: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+func (r *PostgresQuestRepository) GetActiveQuestTemplates(ctx context.Context) ([]domain.QuestTemplate, error) {
+	query := `
+		SELECT id, period, title, desc_text, metric, threshold, filters, prize_value_id
+		FROM quest_templates
+		WHERE active = TRUE
+		ORDER BY id
+	`
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quest templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []domain.QuestTemplate
+	for rows.Next() {
+		var template domain.QuestTemplate
+		var filters []byte
+		if err := rows.Scan(&template.ID, &template.Period, &template.Title, &template.Desc, &template.Metric,
+			&template.Threshold, &filters, &template.PrizeValueID); err != nil {
+			return nil, fmt.Errorf("failed to scan quest template: %w", err)
+		}
+		if len(filters) > 0 {
+			if err := json.Unmarshal(filters, &template.Filters); err != nil {
+				return nil, fmt.Errorf("failed to decode filters of quest template %s: %w", template.ID, err)
+			}
+		}
+		templates = append(templates, template)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating quest templates: %w", err)
+	}
+	return templates, nil
+}
+
+// AssignUserQuests gives the quests to their users; quests a user already has for the period are skipped.
+func (r *PostgresQuestRepository) AssignUserQuests(ctx context.Context, quests []domain.UserQuest) error {
+	query := `
+		INSERT INTO user_quests (user_uuid, template_id, period_start, period_end, need_steps)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_uuid, template_id, period_start) DO NOTHING
+	`
+	for _, quest := range quests {
+		if _, err := r.pool.Exec(ctx, query, quest.UserUUID, quest.TemplateID, quest.PeriodStart, quest.ResetsAt, quest.NeedSteps); err != nil {
+			return fmt.Errorf("failed to assign quest %s: %w", quest.TemplateID, err)
+		}
+	}
+	return nil
+}
+
+const userQuestColumns = `
+	q.id, q.user_uuid, q.template_id, t.period, t.title, t.desc_text, t.metric, t.filters,
+	q.progress, q.need_steps, q.current_run, pv.label, t.prize_value_id, q.period_start, q.period_end, q.completed_at, q.claimed_at
+`
+
+const userQuestJoins = `
+	FROM user_quests q
+	JOIN quest_templates t ON t.id = q.template_id
+	JOIN prize_values pv ON pv.id = t.prize_value_id
+`
+
+func scanUserQuest(row pgx.Row) (*domain.UserQuest, error) {
+	var quest domain.UserQuest
+	var filters []byte
+	if err := row.Scan(
+		&quest.ID,
+		&quest.UserUUID,
+		&quest.TemplateID,
+		&quest.Period,
+		&quest.Title,
+		&quest.Desc,
+		&quest.Metric,
+		&filters,
+		&quest.Progress,
+		&quest.NeedSteps,
+		&quest.CurrentRun,
+		&quest.Reward,
+		&quest.PrizeValueID,
+		&quest.PeriodStart,
+		&quest.ResetsAt,
+		&quest.CompletedAt,
+		&quest.ClaimedAt,
+	); err != nil {
+		return nil, err
+	}
+	if len(filters) > 0 {
+		if err := json.Unmarshal(filters, &quest.Filters); err != nil {
+			return nil, fmt.Errorf("failed to decode quest filters: %w", err)
+		}
+	}
+	switch {
+	case quest.ClaimedAt != nil:
+		quest.Status = domain.QuestStatusClaimed
+	case quest.CompletedAt != nil:
+		quest.Status = domain.QuestStatusCompleted
+	default:
+		quest.Status = domain.QuestStatusActive
+	}
+	return &quest, nil
+}
+
+// GetUserQuests returns the user's quests of the periods running at nowMs, daily first.
+func (r *PostgresQuestRepository) GetUserQuests(ctx context.Context, userUUID string, nowMs int64) ([]domain.UserQuest, error) {
+	query := `SELECT ` + userQuestColumns + userQuestJoins + `
+		WHERE q.user_uuid = $1 AND q.period_start <= $2 AND q.period_end > $2
+		ORDER BY t.period = 'weekly', q.id
+	`
+	rows, err := r.pool.Query(ctx, query, userUUID, nowMs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user quests: %w", err)
+	}
+	defer rows.Close()
+
+	quests := []domain.UserQuest{}
+	for rows.Next() {
+		quest, err := scanUserQuest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user quest: %w", err)
+		}
+		quests = append(quests, *quest)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating user quests: %w", err)
+	}
+	return quests, nil
+}
+
+func (r *PostgresQuestRepository) GetUserQuestByID(ctx context.Context, userUUID string, questID int) (*domain.UserQuest, error) {
+	query := `SELECT ` + userQuestColumns + userQuestJoins + ` WHERE q.id = $1 AND q.user_uuid = $2`
+	quest, err := scanUserQuest(r.pool.QueryRow(ctx, query, questID, userUUID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user quest: %w", err)
+	}
+	return quest, nil
+}
+
+// ApplyQuestProgress moves the quests by the deltas of one domain event and returns the IDs of the quests it
+// completed. Each event moves a quest once; completed quests don't move.
+func (r *PostgresQuestRepository) ApplyQuestProgress(ctx context.Context, eventKey string, deltas []domain.QuestProgressDelta, nowMs int64) ([]int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin quest progress transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	insertEventQuery := `
+		INSERT INTO user_quest_events (user_quest_id, event_key)
+		VALUES ($1, $2)
+		ON CONFLICT (user_quest_id, event_key) DO NOTHING
+	`
+	progressQuery := `
+		UPDATE user_quests
+		SET current_run = CASE WHEN NOT $3 THEN current_run WHEN $4 THEN 0 ELSE current_run + $2 END,
+		    progress = CASE
+		        WHEN NOT $3 THEN LEAST(progress + $2, need_steps)
+		        WHEN $4 THEN progress
+		        ELSE GREATEST(progress, LEAST(current_run + $2, need_steps)) END,
+		    updated_at = $5
+		WHERE id = $1 AND completed_at IS NULL
+		RETURNING progress >= need_steps
+	`
+	completeQuery := `UPDATE user_quests SET completed_at = $2 WHERE id = $1`
+
+	var completed []int
+	for _, delta := range deltas {
+		var tag pgconn.CommandTag
+		tag, err = tx.Exec(ctx, insertEventQuery, delta.UserQuestID, eventKey)
+		if err != nil {
+			err = fmt.Errorf("failed to record quest event: %w", err)
+			return nil, err
+		}
+		if tag.RowsAffected() == 0 {
+			continue
+		}
+
+		var done bool
+		err = tx.QueryRow(ctx, progressQuery, delta.UserQuestID, delta.Increment, delta.Streak, delta.ResetRun, nowMs).Scan(&done)
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = nil
+			continue
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to advance quest %d: %w", delta.UserQuestID, err)
+			return nil, err
+		}
+		if done {
+			if _, err = tx.Exec(ctx, completeQuery, delta.UserQuestID, nowMs); err != nil {
+				err = fmt.Errorf("failed to complete quest %d: %w", delta.UserQuestID, err)
+				return nil, err
+			}
+			completed = append(completed, delta.UserQuestID)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf("failed to commit quest progress: %w", err)
+		return nil, err
+	}
+	return completed, nil
+}
+
+// ClaimUserQuest marks a completed, unclaimed quest of the user as claimed; false when it can't be claimed.
+func (r *PostgresQuestRepository) ClaimUserQuest(ctx context.Context, userUUID string, questID int, nowMs int64) (bool, error) {
+	query := `
+		UPDATE user_quests
+		SET claimed_at = $3, updated_at = $3
+		WHERE id = $1 AND user_uuid = $2 AND completed_at IS NOT NULL AND claimed_at IS NULL
+	`
+	tag, err := r.pool.Exec(ctx, query, questID, userUUID, nowMs)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim quest: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *PostgresQuestRepository) SetUserQuestPrize(ctx context.Context, questID int, gotPrizeID int) error {
+	if _, err := r.pool.Exec(ctx, `UPDATE user_quests SET got_prize_id = $2 WHERE id = $1`, questID, gotPrizeID); err != nil {
+		return fmt.Errorf("failed to link quest prize: %w", err)
+	}
+	return nil
+}
+
+// UnclaimUserQuest reverts a claim whose reward could not be granted.
+func (r *PostgresQuestRepository) UnclaimUserQuest(ctx context.Context, questID int) error {
+	if _, err := r.pool.Exec(ctx, `UPDATE user_quests SET claimed_at = NULL WHERE id = $1`, questID); err != nil {
+		return fmt.Errorf("failed to revert quest claim: %w", err)
+	}
+	return nil
+}
@@ -17,6 +17,7 @@ type RatingRepository interface {
 	GetGlobalRating(ctx context.Context, limit, offset int) ([]domain.GlobalRatingEntry, error)
 	GetFriendsRatings(ctx context.Context, userUUID string, limit, offset int) ([]domain.FriendRatingEntry, error)
 	AddPoints(ctx context.Context, userUUID string, points int64, gotPrizeID *int, betID *int, description string) error
+	AddReferralPoints(ctx context.Context, reward *domain.ReferralReward) (bool, error)
 	GetMaxCreatedAt(ctx context.Context, userUUID string) (*int64, error)
 	GetUserBetPointsInRange(ctx context.Context, userUUID string, startMs, endMs int64) (int64, error)
 	GetBetPointsLeaderboard(ctx context.Context, startMs, endMs int64, limit int) ([]domain.BetPrizeLeaderboardEntry, error)
@@ -63,10 +64,12 @@ func (r *PostgresRatingRepository) GetGlobalRating(ctx context.Context, limit, o
 			u.google_name,
 			u.telegram_username,
 			u.telegram_first_name,
-			u.telegram_last_name
+			u.telegram_last_name,
+			u.display_name,
+			COALESCE(u.hide_name, FALSE)
 		FROM rating r
 		LEFT JOIN users u ON u.user_uuid = r.user_uuid
-		GROUP BY r.user_uuid, u.google_name, u.telegram_username, u.telegram_first_name, u.telegram_last_name
+		GROUP BY r.user_uuid, u.google_name, u.telegram_username, u.telegram_first_name, u.telegram_last_name, u.display_name, u.hide_name
 		ORDER BY total_points DESC, user_uuid ASC
 		LIMIT $1 OFFSET $2
 	`
@@ -86,35 +89,14 @@ func (r *PostgresRatingRepository) GetGlobalRating(ctx context.Context, limit, o
 		var telegramUsername sql.NullString
 		var telegramFirstName sql.NullString
 		var telegramLastName sql.NullString
+		var chosenName sql.NullString
+		var hideName bool
 
-		if err := rows.Scan(&userUUID, &totalPoints, &googleName, &telegramUsername, &telegramFirstName, &telegramLastName); err != nil {
+		if err := rows.Scan(&userUUID, &totalPoints, &googleName, &telegramUsername, &telegramFirstName, &telegramLastName, &chosenName, &hideName); err != nil {
 			return nil, fmt.Errorf("failed to scan global rating entry: %w", err)
 		}
 
-		displayName := ""
-		if googleName.Valid && googleName.String != "" {
-			displayName = googleName.String
-		} else if telegramUsername.Valid && telegramUsername.String != "" {
-			displayName = telegramUsername.String
-		} else {
-			first := ""
-			last := ""
-			if telegramFirstName.Valid {
-				first = telegramFirstName.String
-			}
-			if telegramLastName.Valid {
-				last = telegramLastName.String
-			}
-			combined := strings.TrimSpace(strings.TrimSpace(first) + " " + strings.TrimSpace(last))
-			if combined != "" {
-				displayName = combined
-			}
-		}
-
-		if displayName == "" {
-			displayName = "Unknown"
-		}
-		entry.UserName = displayName
+		entry.UserName = buildRatingName(chosenName, hideName, googleName, telegramUsername, telegramFirstName, telegramLastName)
 		entry.Value = totalPoints
 		entries = append(entries, entry)
 	}
@@ -134,11 +116,13 @@ func (r *PostgresRatingRepository) GetFriendsRatings(ctx context.Context, userUU
 			u.google_name,
 			u.telegram_username,
 			u.telegram_first_name,
-			u.telegram_last_name
+			u.telegram_last_name,
+			u.display_name,
+			u.hide_name
 		FROM users u
 		LEFT JOIN rating r ON r.user_uuid = u.user_uuid
 		WHERE u.referrer_user_uuid = $1
-		GROUP BY u.user_uuid, u.google_name, u.telegram_username, u.telegram_first_name, u.telegram_last_name
+		GROUP BY u.user_uuid, u.google_name, u.telegram_username, u.telegram_first_name, u.telegram_last_name, u.display_name, u.hide_name
 		ORDER BY total_points DESC, friend_uuid ASC
 		LIMIT $2 OFFSET $3
 	`
@@ -158,35 +142,15 @@ func (r *PostgresRatingRepository) GetFriendsRatings(ctx context.Context, userUU
 		var telegramUsername sql.NullString
 		var telegramFirstName sql.NullString
 		var telegramLastName sql.NullString
+		var chosenName sql.NullString
+		var hideName bool
 
-		if err := rows.Scan(&friendUUID, &totalPoints, &googleName, &telegramUsername, &telegramFirstName, &telegramLastName); err != nil {
+		if err := rows.Scan(&friendUUID, &totalPoints, &googleName, &telegramUsername, &telegramFirstName, &telegramLastName, &chosenName, &hideName); err != nil {
 			return nil, fmt.Errorf("failed to scan friends rating entry: %w", err)
 		}
 
-		displayName := ""
-		if googleName.Valid && googleName.String != "" {
-			displayName = googleName.String
-		} else if telegramUsername.Valid && telegramUsername.String != "" {
-			displayName = telegramUsername.String
-		} else {
-			first := ""
-			last := ""
-			if telegramFirstName.Valid {
-				first = telegramFirstName.String
-			}
-			if telegramLastName.Valid {
-				last = telegramLastName.String
-			}
-			combined := strings.TrimSpace(strings.TrimSpace(first) + " " + strings.TrimSpace(last))
-			if combined != "" {
-				displayName = combined
-			}
-		}
-		if displayName == "" {
-			displayName = "Unknown"
-		}
-
-		entry.UserName = displayName
+		entry.UserUUID = friendUUID
+		entry.UserName = buildRatingName(chosenName, hideName, googleName, telegramUsername, telegramFirstName, telegramLastName)
 		entry.Value = totalPoints
 		entries = append(entries, entry)
 	}
@@ -216,6 +180,60 @@ func (r *PostgresRatingRepository) AddPoints(ctx context.Context, userUUID strin
 	return nil
 }
 
+// AddReferralPoints records a referral payout and credits it to the beneficiary's rating
+// under the referral source. Returns false if the same payout was already recorded.
+func (r *PostgresRatingRepository) AddReferralPoints(ctx context.Context, reward *domain.ReferralReward) (bool, error) {
+	if reward.Points <= 0 {
+		return false, nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin referral points transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	queryReward := `
+		INSERT INTO referral_rewards (beneficiary_uuid, invitee_uuid, level, reward_type, bet_id, points, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+		ON CONFLICT (invitee_uuid, reward_type, level, (COALESCE(bet_id, 0))) DO NOTHING
+	`
+	tag, execErr := tx.Exec(ctx, queryReward,
+		reward.BeneficiaryUUID,
+		reward.InviteeUUID,
+		reward.Level,
+		string(reward.RewardType),
+		reward.BetID,
+		reward.Points,
+	)
+	if execErr != nil {
+		err = execErr
+		return false, fmt.Errorf("failed to record referral reward: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		_ = tx.Rollback(ctx)
+		return false, nil
+	}
+
+	queryRating := `
+		INSERT INTO rating (user_uuid, points, description, source, created_at)
+		VALUES ($1, $2, $3, $4, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+	`
+	if _, execErr = tx.Exec(ctx, queryRating, reward.BeneficiaryUUID, reward.Points, reward.Description, string(domain.RatingSourceReferral)); execErr != nil {
+		err = execErr
+		return false, fmt.Errorf("failed to add referral points: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit referral points transaction: %w", err)
+	}
+	return true, nil
+}
+
 func (r *PostgresRatingRepository) GetMaxCreatedAt(ctx context.Context, userUUID string) (*int64, error) {
 	query := `
 		SELECT MAX(created_at)
@@ -254,19 +272,24 @@ func (r *PostgresRatingRepository) GetUserBetPointsInRange(ctx context.Context,
 	return total, nil
 }
 
+// GetBetPointsLeaderboard ranks users by net bet points claimed in the window. Equal points are
+// ranked by who reached them first. A limit <= 0 returns all users.
 func (r *PostgresRatingRepository) GetBetPointsLeaderboard(ctx context.Context, startMs, endMs int64, limit int) ([]domain.BetPrizeLeaderboardEntry, error) {
 	query := `
 		SELECT
 			user_uuid::text AS user_uuid,
-			COALESCE(SUM(points), 0)::BIGINT AS net_points
+			COALESCE(SUM(points), 0)::BIGINT AS net_points,
+			COUNT(*) FILTER (WHERE points > 0)::INT AS win_count,
+			COUNT(*) FILTER (WHERE points < 0)::INT AS loss_count,
+			MAX(created_at) AS last_scored_at
 		FROM rating
 		WHERE bet_id IS NOT NULL
 		  AND got_prize_id IS NULL
 		  AND created_at >= $1
 		  AND created_at < $2
 		GROUP BY user_uuid
-		ORDER BY net_points DESC, user_uuid ASC
-		LIMIT $3
+		ORDER BY net_points DESC, last_scored_at ASC, user_uuid ASC
+		LIMIT NULLIF($3, 0)
 	`
 
 	rows, err := r.pool.Query(ctx, query, startMs, endMs, limit)
@@ -278,11 +301,9 @@ func (r *PostgresRatingRepository) GetBetPointsLeaderboard(ctx context.Context,
 	var entries []domain.BetPrizeLeaderboardEntry
 	for rows.Next() {
 		var entry domain.BetPrizeLeaderboardEntry
-		if err := rows.Scan(&entry.UserUUID, &entry.NetPoints); err != nil {
+		if err := rows.Scan(&entry.UserUUID, &entry.NetPoints, &entry.WinCount, &entry.LossCount, &entry.LastScoredAt); err != nil {
 			return nil, fmt.Errorf("failed to scan bet points leaderboard: %w", err)
 		}
-		entry.WinCount = 0
-		entry.LossCount = 0
 		entries = append(entries, entry)
 	}
 	if err := rows.Err(); err != nil {
@@ -315,6 +336,10 @@ func (r *InMemoryRatingRepository) AddPoints(ctx context.Context, userUUID strin
 	return nil
 }
 
+func (r *InMemoryRatingRepository) AddReferralPoints(ctx context.Context, reward *domain.ReferralReward) (bool, error) {
+	return false, nil
+}
+
 func (r *InMemoryRatingRepository) GetMaxCreatedAt(ctx context.Context, userUUID string) (*int64, error) {
 	return nil, nil
 }
@@ -326,3 +351,74 @@ func (r *InMemoryRatingRepository) GetUserBetPointsInRange(ctx context.Context,
 func (r *InMemoryRatingRepository) GetBetPointsLeaderboard(ctx context.Context, startMs, endMs int64, limit int) ([]domain.BetPrizeLeaderboardEntry, error) {
 	return []domain.BetPrizeLeaderboardEntry{}, nil
 }
+
+// buildDisplayName picks the public name for a user: Google name, Telegram username,
+// Telegram first/last name, or "Unknown".
+func buildDisplayName(googleName, telegramUsername, telegramFirstName, telegramLastName sql.NullString) string {
+	if googleName.Valid && googleName.String != "" {
+		return googleName.String
+	}
+	if telegramUsername.Valid && telegramUsername.String != "" {
+		return telegramUsername.String
+	}
+	first := ""
+	last := ""
+	if telegramFirstName.Valid {
+		first = telegramFirstName.String
+	}
+	if telegramLastName.Valid {
+		last = telegramLastName.String
+	}
+	combined := strings.TrimSpace(strings.TrimSpace(first) + " " + strings.TrimSpace(last))
+	if combined != "" {
+		return combined
+	}
+	return "Unknown"
+}
+
+// buildRatingName is buildDisplayName respecting the user's profile settings: Anonymous when the user hides
+// their name, else the display name they chose.
+func buildRatingName(chosenName sql.NullString, hideName bool, googleName, telegramUsername, telegramFirstName, telegramLastName sql.NullString) string {
+	if hideName {
+		return domain.ProfileAnonymousName
+	}
+	if chosenName.Valid && chosenName.String != "" {
+		return chosenName.String
+	}
+	return buildDisplayName(googleName, telegramUsername, telegramFirstName, telegramLastName)
+}
+
+// debitRatingPoints debits points from the user's rating inside tx, e.g. an entry fee or a duel stake.
+// Returns false without debiting if the user has fewer points.
+func debitRatingPoints(ctx context.Context, tx pgx.Tx, userUUID string, points int64, description string, source domain.RatingSource) (bool, error) {
+	// Locking the user keeps concurrent debits from spending the same points twice.
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM users WHERE user_uuid = $1 FOR UPDATE`, userUUID); err != nil {
+		return false, fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	var balance int64
+	queryBalance := `SELECT COALESCE(SUM(points), 0)::BIGINT FROM rating WHERE user_uuid = $1`
+	if err := tx.QueryRow(ctx, queryBalance, userUUID).Scan(&balance); err != nil {
+		return false, fmt.Errorf("failed to get user points: %w", err)
+	}
+	if balance < points {
+		return false, nil
+	}
+
+	if err := creditRatingPoints(ctx, tx, userUUID, -points, description, source); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// creditRatingPoints adds a rating row for the user inside tx; negative points debit.
+func creditRatingPoints(ctx context.Context, tx pgx.Tx, userUUID string, points int64, description string, source domain.RatingSource) error {
+	query := `
+		INSERT INTO rating (user_uuid, points, description, source, created_at)
+		VALUES ($1, $2, $3, $4, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+	`
+	if _, err := tx.Exec(ctx, query, userUUID, points, description, string(source)); err != nil {
+		return fmt.Errorf("failed to add rating points: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"pdrest/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ReferralRepository provides access to the referral tree and referral earnings.
+type ReferralRepository interface {
+	GetReferrerChain(ctx context.Context, userUUID string, depth int) ([]string, error)
+	GetReferralStats(ctx context.Context, userUUID string, depth int, activeSince time.Time) (*domain.ReferralStatsResponse, error)
+	CreateReferralCode(ctx context.Context, code *domain.ReferralCode) error
+	CountReferralCodes(ctx context.Context, ownerUUID string) (int, error)
+	GetReferralCodeStats(ctx context.Context, ownerUUID string) ([]domain.ReferralCodeStats, error)
+}
+
+// PostgresReferralRepository implements ReferralRepository with PostgreSQL.
+type PostgresReferralRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresReferralRepository(pool *pgxpool.Pool) *PostgresReferralRepository {
+	return &PostgresReferralRepository{pool: pool}
+}
+
+// GetReferrerChain returns the user's referrers ordered by level: the direct referrer first,
+// then the referrer's referrer, up to depth levels.
+func (r *PostgresReferralRepository) GetReferrerChain(ctx context.Context, userUUID string, depth int) ([]string, error) {
+	query := `
+		WITH RECURSIVE chain AS (
+			SELECT u.referrer_user_uuid AS referrer_uuid, 1 AS level
+			FROM users u
+			WHERE u.user_uuid = $1 AND u.referrer_user_uuid IS NOT NULL
+			UNION ALL
+			SELECT u.referrer_user_uuid, c.level + 1
+			FROM chain c
+			JOIN users u ON u.user_uuid = c.referrer_uuid
+			WHERE u.referrer_user_uuid IS NOT NULL AND c.level < $2
+		)
+		SELECT referrer_uuid::text
+		FROM chain
+		ORDER BY level ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, userUUID, depth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get referrer chain: %w", err)
+	}
+	defer rows.Close()
+
+	var chain []string
+	for rows.Next() {
+		var referrerUUID string
+		if err := rows.Scan(&referrerUUID); err != nil {
+			return nil, fmt.Errorf("failed to scan referrer: %w", err)
+		}
+		chain = append(chain, referrerUUID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating referrer chain rows: %w", err)
+	}
+
+	return chain, nil
+}
+
+func (r *PostgresReferralRepository) GetReferralStats(ctx context.Context, userUUID string, depth int, activeSince time.Time) (*domain.ReferralStatsResponse, error) {
+	levels := make([]domain.ReferralLevelStats, depth)
+	for i := range levels {
+		levels[i].Level = i + 1
+	}
+
+	queryInvitees := `
+		WITH RECURSIVE tree AS (
+			SELECT user_uuid, 1 AS level
+			FROM users
+			WHERE referrer_user_uuid = $1
+			UNION ALL
+			SELECT u.user_uuid, t.level + 1
+			FROM tree t
+			JOIN users u ON u.referrer_user_uuid = t.user_uuid
+			WHERE t.level < $2 AND u.user_uuid <> $1
+		)
+		SELECT level, COUNT(DISTINCT user_uuid)::INT
+		FROM tree
+		GROUP BY level
+	`
+	rows, err := r.pool.Query(ctx, queryInvitees, userUUID, depth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get referral invitees: %w", err)
+	}
+	for rows.Next() {
+		var level, invitees int
+		if err := rows.Scan(&level, &invitees); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan referral invitees: %w", err)
+		}
+		if level >= 1 && level <= depth {
+			levels[level-1].Invitees = invitees
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating referral invitee rows: %w", err)
+	}
+
+	queryEarnings := `
+		SELECT level, COALESCE(SUM(points), 0)::BIGINT
+		FROM referral_rewards
+		WHERE beneficiary_uuid = $1
+		GROUP BY level
+	`
+	stats := &domain.ReferralStatsResponse{}
+	rows, err = r.pool.Query(ctx, queryEarnings, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get referral earnings: %w", err)
+	}
+	for rows.Next() {
+		var level int
+		var earnings int64
+		if err := rows.Scan(&level, &earnings); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan referral earnings: %w", err)
+		}
+		stats.LifetimeEarnings += earnings
+		if level >= 1 && level <= depth {
+			levels[level-1].Earnings = earnings
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating referral earnings rows: %w", err)
+	}
+
+	queryActive := `
+		SELECT COUNT(*)::INT
+		FROM users u
+		WHERE u.referrer_user_uuid = $1
+		  AND EXISTS (
+			SELECT 1 FROM bets b
+			WHERE b.user_uuid = u.user_uuid AND b.open_time >= $2
+		  )
+	`
+	if err := r.pool.QueryRow(ctx, queryActive, userUUID, activeSince.UTC()).Scan(&stats.ActiveInvitees); err != nil {
+		return nil, fmt.Errorf("failed to count active invitees: %w", err)
+	}
+
+	if depth > 0 {
+		stats.InviteCount = levels[0].Invitees
+	}
+	stats.Levels = levels
+	return stats, nil
+}
+
+// CreateReferralCode stores the code and appends it to the owner's users.add_refs.
+// Codes are unique across main_ref and all campaign codes.
+func (r *PostgresReferralRepository) CreateReferralCode(ctx context.Context, code *domain.ReferralCode) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin referral code transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	var ownerExists, takenByMainRef bool
+	queryCheck := `
+		SELECT
+			EXISTS (SELECT 1 FROM users WHERE user_uuid = $1),
+			EXISTS (SELECT 1 FROM users WHERE main_ref = $2)
+	`
+	if err = tx.QueryRow(ctx, queryCheck, code.OwnerUUID, code.Code).Scan(&ownerExists, &takenByMainRef); err != nil {
+		return fmt.Errorf("failed to check referral code: %w", err)
+	}
+	if !ownerExists {
+		err = errors.New("owner user not found")
+		return err
+	}
+	if takenByMainRef {
+		err = errors.New("referral code already exists")
+		return err
+	}
+
+	queryInsert := `
+		INSERT INTO referral_codes (code, owner_uuid, name, expires_at, max_uses, created_by_admin, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+		ON CONFLICT (code) DO NOTHING
+		RETURNING created_at
+	`
+	if err = tx.QueryRow(ctx, queryInsert, code.Code, code.OwnerUUID, code.Name, code.ExpiresAt, code.MaxUses, code.CreatedByAdmin).Scan(&code.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = errors.New("referral code already exists")
+			return err
+		}
+		return fmt.Errorf("failed to create referral code: %w", err)
+	}
+
+	queryAddRef := `
+		UPDATE users
+		SET add_refs = array_append(COALESCE(add_refs, ARRAY[]::TEXT[]), $2)
+		WHERE user_uuid = $1
+	`
+	if _, err = tx.Exec(ctx, queryAddRef, code.OwnerUUID, code.Code); err != nil {
+		return fmt.Errorf("failed to add referral code to user: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit referral code transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresReferralRepository) CountReferralCodes(ctx context.Context, ownerUUID string) (int, error) {
+	query := `SELECT COUNT(*)::INT FROM referral_codes WHERE owner_uuid = $1`
+
+	var count int
+	if err := r.pool.QueryRow(ctx, query, ownerUUID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count referral codes: %w", err)
+	}
+	return count, nil
+}
+
+// GetReferralCodeStats returns the owner's main_ref followed by campaign codes, with signups
+// attributed to each code and conversions (invitees who claimed at least one bet).
+func (r *PostgresReferralRepository) GetReferralCodeStats(ctx context.Context, ownerUUID string) ([]domain.ReferralCodeStats, error) {
+	query := `
+		SELECT code, name, expires_at, max_uses, created_by_admin, created_at, is_main, signups, conversions
+		FROM (
+			SELECT
+				o.main_ref AS code,
+				'' AS name,
+				NULL::BIGINT AS expires_at,
+				NULL::INTEGER AS max_uses,
+				FALSE AS created_by_admin,
+				COALESCE(o.created_at, 0) AS created_at,
+				TRUE AS is_main,
+				COUNT(u.user_uuid)::INT AS signups,
+				COUNT(u.user_uuid) FILTER (
+					WHERE EXISTS (SELECT 1 FROM bets b WHERE b.user_uuid = u.user_uuid AND b.claimed_status)
+				)::INT AS conversions
+			FROM users o
+			LEFT JOIN users u ON u.referrer_user_uuid = o.user_uuid AND u.referral_code = o.main_ref
+			WHERE o.user_uuid = $1 AND o.main_ref IS NOT NULL AND o.main_ref <> ''
+			GROUP BY o.main_ref, o.created_at
+
+			UNION ALL
+
+			SELECT
+				rc.code,
+				rc.name,
+				rc.expires_at,
+				rc.max_uses,
+				rc.created_by_admin,
+				COALESCE(rc.created_at, 0),
+				FALSE,
+				COUNT(u.user_uuid)::INT,
+				COUNT(u.user_uuid) FILTER (
+					WHERE EXISTS (SELECT 1 FROM bets b WHERE b.user_uuid = u.user_uuid AND b.claimed_status)
+				)::INT
+			FROM referral_codes rc
+			LEFT JOIN users u ON u.referrer_user_uuid = rc.owner_uuid AND u.referral_code = rc.code
+			WHERE rc.owner_uuid = $1
+			GROUP BY rc.code
+		) codes
+		ORDER BY is_main DESC, created_at ASC, code ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, ownerUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get referral code stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []domain.ReferralCodeStats
+	for rows.Next() {
+		var entry domain.ReferralCodeStats
+		if err := rows.Scan(
+			&entry.Code,
+			&entry.Name,
+			&entry.ExpiresAt,
+			&entry.MaxUses,
+			&entry.CreatedByAdmin,
+			&entry.CreatedAt,
+			&entry.IsMain,
+			&entry.Signups,
+			&entry.Conversions,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan referral code stats: %w", err)
+		}
+		entry.OwnerUUID = ownerUUID
+		stats = append(stats, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating referral code stats rows: %w", err)
+	}
+
+	return stats, nil
+}
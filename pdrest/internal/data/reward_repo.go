@@ -0,0 +1,100 @@
+package data
+
+import (
+	"context"
+	"fmt"
+
+	"pdrest/internal/domain"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RewardRepository stores what spins and badge rewards grant; boosts and other items go to the inventory.
+type RewardRepository interface {
+	AddRouletteSpins(ctx context.Context, userUUID string, rouletteConfigID int, spins int) (int, error)
+	GetRouletteExtraSpins(ctx context.Context, userUUID string, rouletteConfigID int) (int, error)
+	AwardBadge(ctx context.Context, userUUID string, badge *domain.UserBadge) (bool, error)
+	GetUserBadges(ctx context.Context, userUUID string) ([]domain.UserBadge, error)
+}
+
+// PostgresRewardRepository implements RewardRepository with PostgreSQL.
+type PostgresRewardRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresRewardRepository(pool *pgxpool.Pool) *PostgresRewardRepository {
+	return &PostgresRewardRepository{pool: pool}
+}
+
+// AddRouletteSpins adds extra spins on the roulette and returns the user's extra spins on it.
+func (r *PostgresRewardRepository) AddRouletteSpins(ctx context.Context, userUUID string, rouletteConfigID int, spins int) (int, error) {
+	query := `
+		INSERT INTO user_roulette_spins (user_uuid, roulette_config_id, extra_spins, updated_at)
+		VALUES ($1, $2, $3, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+		ON CONFLICT (user_uuid, roulette_config_id) DO UPDATE
+		SET extra_spins = user_roulette_spins.extra_spins + EXCLUDED.extra_spins,
+		    updated_at = EXCLUDED.updated_at
+		RETURNING extra_spins
+	`
+	var total int
+	if err := r.pool.QueryRow(ctx, query, userUUID, rouletteConfigID, spins).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to add roulette spins: %w", err)
+	}
+	return total, nil
+}
+
+// GetRouletteExtraSpins returns the extra spins the user was granted on the roulette, 0 when none.
+func (r *PostgresRewardRepository) GetRouletteExtraSpins(ctx context.Context, userUUID string, rouletteConfigID int) (int, error) {
+	query := `
+		SELECT COALESCE(SUM(extra_spins), 0)
+		FROM user_roulette_spins
+		WHERE user_uuid = $1 AND roulette_config_id = $2
+	`
+	var spins int
+	if err := r.pool.QueryRow(ctx, query, userUUID, rouletteConfigID).Scan(&spins); err != nil {
+		return 0, fmt.Errorf("failed to get roulette extra spins: %w", err)
+	}
+	return spins, nil
+}
+
+// AwardBadge adds the badge to the user's profile; it returns false when the user already had it.
+func (r *PostgresRewardRepository) AwardBadge(ctx context.Context, userUUID string, badge *domain.UserBadge) (bool, error) {
+	query := `
+		INSERT INTO user_badges (user_uuid, badge_id, title, image_url, got_prize_id, awarded_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+		ON CONFLICT (user_uuid, badge_id) DO NOTHING
+	`
+	tag, err := r.pool.Exec(ctx, query, userUUID, badge.BadgeID, badge.Title, badge.ImageURL, badge.GotPrizeID)
+	if err != nil {
+		return false, fmt.Errorf("failed to award badge: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetUserBadges returns the user's badges, newest first.
+func (r *PostgresRewardRepository) GetUserBadges(ctx context.Context, userUUID string) ([]domain.UserBadge, error) {
+	query := `
+		SELECT badge_id, title, COALESCE(image_url, ''), got_prize_id, awarded_at
+		FROM user_badges
+		WHERE user_uuid = $1
+		ORDER BY awarded_at DESC, badge_id ASC
+	`
+	rows, err := r.pool.Query(ctx, query, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user badges: %w", err)
+	}
+	defer rows.Close()
+
+	badges := []domain.UserBadge{}
+	for rows.Next() {
+		var badge domain.UserBadge
+		if err := rows.Scan(&badge.BadgeID, &badge.Title, &badge.ImageURL, &badge.GotPrizeID, &badge.AwardedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan user badge: %w", err)
+		}
+		badges = append(badges, badge)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating user badges: %w", err)
+	}
+	return badges, nil
+}
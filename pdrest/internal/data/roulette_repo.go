@@ -18,6 +18,7 @@ type RouletteRepository interface {
 	GetRouletteConfigByID(ctx context.Context, id int) (*domain.RouletteConfig, error)
 	CreateRouletteConfig(ctx context.Context, config *domain.RouletteConfig) error
 	UpdateRouletteConfig(ctx context.Context, config *domain.RouletteConfig) error
+	SetEventRoulettesActive(ctx context.Context, eventID string, active bool) (int64, error)
 
 	// Preauth token methods
 	CreatePreauthToken(ctx context.Context, token *domain.RoulettePreauthToken) error
@@ -47,7 +48,7 @@ func NewPostgresRouletteRepository(pool *pgxpool.Pool) *PostgresRouletteReposito
 // GetRouletteConfigByType retrieves active roulette config by type and event_id
 func (r *PostgresRouletteRepository) GetRouletteConfigByType(ctx context.Context, rouletteType domain.RouletteType, eventID string) (*domain.RouletteConfig, error) {
 	query := `
-		SELECT id, roulette_type, event_id, max_spins, is_active, created_at, updated_at
+		SELECT id, roulette_type, event_id, max_spins, is_active, guarantees, created_at, updated_at
 		FROM roulette_config
 		WHERE roulette_type = $1 AND event_id = $2 AND is_active = TRUE
 		ORDER BY id DESC
@@ -55,6 +56,7 @@ func (r *PostgresRouletteRepository) GetRouletteConfigByType(ctx context.Context
 	`
 
 	var config domain.RouletteConfig
+	var guaranteesJSON []byte
 
 	err := r.pool.QueryRow(ctx, query, string(rouletteType), eventID).Scan(
 		&config.ID,
@@ -62,6 +64,7 @@ func (r *PostgresRouletteRepository) GetRouletteConfigByType(ctx context.Context
 		&config.EventID,
 		&config.MaxSpins,
 		&config.IsActive,
+		&guaranteesJSON,
 		&config.CreatedAt,
 		&config.UpdatedAt,
 	)
@@ -71,6 +74,9 @@ func (r *PostgresRouletteRepository) GetRouletteConfigByType(ctx context.Context
 		}
 		return nil, fmt.Errorf("failed to get roulette config: %w", err)
 	}
+	if err := json.Unmarshal(guaranteesJSON, &config.Guarantees); err != nil {
+		return nil, fmt.Errorf("failed to parse guarantees of roulette config %d: %w", config.ID, err)
+	}
 
 	return &config, nil
 }
@@ -78,12 +84,13 @@ func (r *PostgresRouletteRepository) GetRouletteConfigByType(ctx context.Context
 // GetRouletteConfigByID retrieves roulette config by ID
 func (r *PostgresRouletteRepository) GetRouletteConfigByID(ctx context.Context, id int) (*domain.RouletteConfig, error) {
 	query := `
-		SELECT id, roulette_type, event_id, max_spins, is_active, created_at, updated_at
+		SELECT id, roulette_type, event_id, max_spins, is_active, guarantees, created_at, updated_at
 		FROM roulette_config
 		WHERE id = $1
 	`
 
 	var config domain.RouletteConfig
+	var guaranteesJSON []byte
 
 	err := r.pool.QueryRow(ctx, query, id).Scan(
 		&config.ID,
@@ -91,6 +98,7 @@ func (r *PostgresRouletteRepository) GetRouletteConfigByID(ctx context.Context,
 		&config.EventID,
 		&config.MaxSpins,
 		&config.IsActive,
+		&guaranteesJSON,
 		&config.CreatedAt,
 		&config.UpdatedAt,
 	)
@@ -100,6 +108,9 @@ func (r *PostgresRouletteRepository) GetRouletteConfigByID(ctx context.Context,
 		}
 		return nil, fmt.Errorf("failed to get roulette config: %w", err)
 	}
+	if err := json.Unmarshal(guaranteesJSON, &config.Guarantees); err != nil {
+		return nil, fmt.Errorf("failed to parse guarantees of roulette config %d: %w", config.ID, err)
+	}
 
 	return &config, nil
 }
@@ -109,16 +120,21 @@ func (r *PostgresRouletteRepository) CreateRouletteConfig(ctx context.Context, c
 	nowMs := time.Now().UTC().UnixMilli()
 
 	query := `
-		INSERT INTO roulette_config (roulette_type, event_id, max_spins, is_active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO roulette_config (roulette_type, event_id, max_spins, is_active, guarantees, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id
 	`
 
-	err := r.pool.QueryRow(ctx, query,
+	guaranteesJSON, err := marshalRouletteGuarantees(config.Guarantees)
+	if err != nil {
+		return err
+	}
+	err = r.pool.QueryRow(ctx, query,
 		string(config.Type),
 		config.EventID,
 		config.MaxSpins,
 		config.IsActive,
+		guaranteesJSON,
 		nowMs,
 		nowMs,
 	).Scan(&config.ID)
@@ -141,16 +157,22 @@ func (r *PostgresRouletteRepository) UpdateRouletteConfig(ctx context.Context, c
 		    event_id = $3,
 		    max_spins = $4,
 		    is_active = $5,
-		    updated_at = $6
+		    guarantees = $6,
+		    updated_at = $7
 		WHERE id = $1
 	`
 
+	guaranteesJSON, err := marshalRouletteGuarantees(config.Guarantees)
+	if err != nil {
+		return err
+	}
 	result, err := r.pool.Exec(ctx, query,
 		config.ID,
 		string(config.Type),
 		config.EventID,
 		config.MaxSpins,
 		config.IsActive,
+		guaranteesJSON,
 		nowMs,
 	)
 	if err != nil {
@@ -165,6 +187,36 @@ func (r *PostgresRouletteRepository) UpdateRouletteConfig(ctx context.Context, c
 	return nil
 }
 
+// marshalRouletteGuarantees encodes guarantees for the JSONB column, which is never NULL
+func marshalRouletteGuarantees(guarantees []domain.RouletteGuarantee) ([]byte, error) {
+	if guarantees == nil {
+		guarantees = []domain.RouletteGuarantee{}
+	}
+	guaranteesJSON, err := json.Marshal(guarantees)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal roulette guarantees: %w", err)
+	}
+	return guaranteesJSON, nil
+}
+
+// SetEventRoulettesActive opens or closes the during_event roulettes of an event. Returns the configs changed.
+func (r *PostgresRouletteRepository) SetEventRoulettesActive(ctx context.Context, eventID string, active bool) (int64, error) {
+	query := `
+		UPDATE roulette_config
+		SET is_active = $2,
+		    updated_at = $3
+		WHERE event_id = $1
+		  AND roulette_type = 'during_event'
+		  AND is_active <> $2
+	`
+
+	result, err := r.pool.Exec(ctx, query, eventID, active, time.Now().UTC().UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("failed to update event roulettes: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
 // CreatePreauthToken creates a new preauth token
 func (r *PostgresRouletteRepository) CreatePreauthToken(ctx context.Context, token *domain.RoulettePreauthToken) error {
 	nowMs := time.Now().UTC().UnixMilli()
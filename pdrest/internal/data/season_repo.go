@@ -0,0 +1,429 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"pdrest/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SeasonRepository provides access to seasons and archived season standings.
+type SeasonRepository interface {
+	GetActiveSeason(ctx context.Context) (*domain.Season, error)
+	GetSeasonByID(ctx context.Context, id int) (*domain.Season, error)
+	GetFinishedSeasons(ctx context.Context, limit, offset int) ([]domain.Season, error)
+	CreateSeason(ctx context.Context, season *domain.Season) (bool, error)
+	GetSeasonStandings(ctx context.Context, startMs, endMs int64, limit, offset int) ([]domain.SeasonStandingEntry, error)
+	FinishSeason(ctx context.Context, seasonID int, nowMs int64) (bool, error)
+	GetSeasonResults(ctx context.Context, seasonID int, limit, offset int) ([]domain.SeasonStandingEntry, error)
+	GetUnpaidSeasonResults(ctx context.Context) ([]domain.SeasonUnpaidResult, error)
+	AwardSeasonResultPrize(ctx context.Context, seasonID int, prize *domain.Prize, points int64, description string) (bool, error)
+}
+
+// PostgresSeasonRepository implements SeasonRepository with PostgreSQL.
+type PostgresSeasonRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresSeasonRepository(pool *pgxpool.Pool) *PostgresSeasonRepository {
+	return &PostgresSeasonRepository{pool: pool}
+}
+
+// seasonPointsFilter excludes season-end prizes so they don't count towards the next season.
+const seasonPointsFilter = `
+	r.created_at >= $1
+	AND r.created_at < $2
+	AND NOT EXISTS (
+		SELECT 1 FROM got_prizes gp
+		WHERE gp.id = r.got_prize_id AND gp.prize_type = 'season_reward'
+	)
+`
+
+func scanSeason(row pgx.Row) (*domain.Season, error) {
+	var season domain.Season
+	var startMs, endMs int64
+	var status string
+	var finalizedAt *int64
+	if err := row.Scan(&season.ID, &season.Title, &startMs, &endMs, &status, &finalizedAt); err != nil {
+		return nil, err
+	}
+	season.StartTime = time.UnixMilli(startMs).UTC()
+	season.EndTime = time.UnixMilli(endMs).UTC()
+	season.Status = domain.SeasonStatus(status)
+	if finalizedAt != nil {
+		t := time.UnixMilli(*finalizedAt).UTC()
+		season.FinalizedAt = &t
+	}
+	return &season, nil
+}
+
+func (r *PostgresSeasonRepository) GetActiveSeason(ctx context.Context) (*domain.Season, error) {
+	query := `
+		SELECT id, title, start_time, end_time, status, finalized_at
+		FROM seasons
+		WHERE status = 'active'
+		ORDER BY start_time ASC
+		LIMIT 1
+	`
+
+	season, err := scanSeason(r.pool.QueryRow(ctx, query))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get active season: %w", err)
+	}
+	return season, nil
+}
+
+func (r *PostgresSeasonRepository) GetSeasonByID(ctx context.Context, id int) (*domain.Season, error) {
+	query := `
+		SELECT id, title, start_time, end_time, status, finalized_at
+		FROM seasons
+		WHERE id = $1
+	`
+
+	season, err := scanSeason(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get season: %w", err)
+	}
+	return season, nil
+}
+
+func (r *PostgresSeasonRepository) GetFinishedSeasons(ctx context.Context, limit, offset int) ([]domain.Season, error) {
+	query := `
+		SELECT id, title, start_time, end_time, status, finalized_at
+		FROM seasons
+		WHERE status = 'finished'
+		ORDER BY start_time DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.pool.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get finished seasons: %w", err)
+	}
+	defer rows.Close()
+
+	var seasons []domain.Season
+	for rows.Next() {
+		season, err := scanSeason(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan season: %w", err)
+		}
+		seasons = append(seasons, *season)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating seasons: %w", err)
+	}
+
+	return seasons, nil
+}
+
+// CreateSeason inserts a season; returns false if a season with the same start already exists.
+func (r *PostgresSeasonRepository) CreateSeason(ctx context.Context, season *domain.Season) (bool, error) {
+	query := `
+		INSERT INTO seasons (title, start_time, end_time, status, created_at, updated_at)
+		VALUES ($1, $2, $3, 'active', EXTRACT(EPOCH FROM NOW())::BIGINT * 1000, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+		ON CONFLICT (start_time) DO NOTHING
+		RETURNING id
+	`
+
+	err := r.pool.QueryRow(
+		ctx,
+		query,
+		season.Title,
+		season.StartTime.UTC().UnixMilli(),
+		season.EndTime.UTC().UnixMilli(),
+	).Scan(&season.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create season: %w", err)
+	}
+	season.Status = domain.SeasonStatusActive
+
+	return true, nil
+}
+
+// GetSeasonStandings computes live standings from rating rows inside the season window.
+func (r *PostgresSeasonRepository) GetSeasonStandings(ctx context.Context, startMs, endMs int64, limit, offset int) ([]domain.SeasonStandingEntry, error) {
+	query := `
+		SELECT
+			r.user_uuid::text AS user_uuid,
+			COALESCE(SUM(r.points), 0)::BIGINT AS season_points,
+			u.google_name,
+			u.telegram_username,
+			u.telegram_first_name,
+			u.telegram_last_name,
+			u.display_name,
+			COALESCE(u.hide_name, FALSE)
+		FROM rating r
+		LEFT JOIN users u ON u.user_uuid = r.user_uuid
+		WHERE ` + seasonPointsFilter + `
+		GROUP BY r.user_uuid, u.google_name, u.telegram_username, u.telegram_first_name, u.telegram_last_name, u.display_name, u.hide_name
+		ORDER BY season_points DESC, MAX(r.created_at) ASC, user_uuid ASC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.pool.Query(ctx, query, startMs, endMs, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get season standings: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []domain.SeasonStandingEntry
+	for rows.Next() {
+		var entry domain.SeasonStandingEntry
+		var googleName, telegramUsername, telegramFirstName, telegramLastName, chosenName sql.NullString
+		var hideName bool
+		if err := rows.Scan(&entry.UserUUID, &entry.Value, &googleName, &telegramUsername, &telegramFirstName, &telegramLastName, &chosenName, &hideName); err != nil {
+			return nil, fmt.Errorf("failed to scan season standing: %w", err)
+		}
+		entry.Rank = offset + len(entries) + 1
+		entry.UserName = buildRatingName(chosenName, hideName, googleName, telegramUsername, telegramFirstName, telegramLastName)
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating season standings: %w", err)
+	}
+
+	return entries, nil
+}
+
+// FinishSeason marks an ended season as finished and snapshots its final standings.
+// Returns false if the season was already finished (e.g. by another replica) or has not ended yet.
+func (r *PostgresSeasonRepository) FinishSeason(ctx context.Context, seasonID int, nowMs int64) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin season transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	var startMs, endMs int64
+	queryFinish := `
+		UPDATE seasons
+		SET status = 'finished', finalized_at = $2, updated_at = $2
+		WHERE id = $1 AND status = 'active' AND end_time <= $2
+		RETURNING start_time, end_time
+	`
+	if err = tx.QueryRow(ctx, queryFinish, seasonID, nowMs).Scan(&startMs, &endMs); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = nil
+			_ = tx.Rollback(ctx)
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to finish season: %w", err)
+	}
+
+	querySnapshot := `
+		INSERT INTO season_results (season_id, user_uuid, rank, points, created_at)
+		SELECT
+			$3,
+			s.user_uuid,
+			ROW_NUMBER() OVER (ORDER BY s.season_points DESC, s.last_points_at ASC, s.user_uuid ASC),
+			s.season_points,
+			EXTRACT(EPOCH FROM NOW())::BIGINT * 1000
+		FROM (
+			SELECT
+				r.user_uuid,
+				COALESCE(SUM(r.points), 0)::BIGINT AS season_points,
+				MAX(r.created_at) AS last_points_at
+			FROM rating r
+			WHERE ` + seasonPointsFilter + `
+			GROUP BY r.user_uuid
+		) s
+		ON CONFLICT (season_id, user_uuid) DO NOTHING
+	`
+	if _, err = tx.Exec(ctx, querySnapshot, startMs, endMs, seasonID); err != nil {
+		return false, fmt.Errorf("failed to snapshot season standings: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit season transaction: %w", err)
+	}
+	return true, nil
+}
+
+func (r *PostgresSeasonRepository) GetSeasonResults(ctx context.Context, seasonID int, limit, offset int) ([]domain.SeasonStandingEntry, error) {
+	query := `
+		SELECT
+			sr.rank,
+			sr.user_uuid::text,
+			sr.points,
+			gp.prize_value,
+			u.google_name,
+			u.telegram_username,
+			u.telegram_first_name,
+			u.telegram_last_name,
+			u.display_name,
+			COALESCE(u.hide_name, FALSE)
+		FROM season_results sr
+		LEFT JOIN users u ON u.user_uuid = sr.user_uuid
+		LEFT JOIN got_prizes gp ON gp.id = sr.got_prize_id
+		WHERE sr.season_id = $1
+		ORDER BY sr.rank ASC
+		LIMIT $2 OFFSET $3
+	`
+
+	return r.querySeasonResults(ctx, query, seasonID, limit, offset)
+}
+
+func (r *PostgresSeasonRepository) querySeasonResults(ctx context.Context, query string, args ...interface{}) ([]domain.SeasonStandingEntry, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get season results: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []domain.SeasonStandingEntry
+	for rows.Next() {
+		var entry domain.SeasonStandingEntry
+		var googleName, telegramUsername, telegramFirstName, telegramLastName, chosenName sql.NullString
+		var hideName bool
+		if err := rows.Scan(
+			&entry.Rank,
+			&entry.UserUUID,
+			&entry.Value,
+			&entry.PrizeValue,
+			&googleName,
+			&telegramUsername,
+			&telegramFirstName,
+			&telegramLastName,
+			&chosenName,
+			&hideName,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan season result: %w", err)
+		}
+		entry.UserName = buildRatingName(chosenName, hideName, googleName, telegramUsername, telegramFirstName, telegramLastName)
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating season results: %w", err)
+	}
+
+	return entries, nil
+}
+
+// GetUnpaidSeasonResults returns the results of finished seasons that scored points, placed in a season reward's
+// range and have no prize yet, with the prize value of their place.
+func (r *PostgresSeasonRepository) GetUnpaidSeasonResults(ctx context.Context) ([]domain.SeasonUnpaidResult, error) {
+	query := `
+		SELECT DISTINCT ON (sr.season_id, sr.user_uuid)
+			sr.season_id,
+			s.title,
+			sr.user_uuid::text,
+			sr.rank,
+			rw.prize_value_id
+		FROM season_results sr
+		JOIN seasons s ON s.id = sr.season_id AND s.status = 'finished'
+		JOIN season_rewards rw ON sr.rank >= rw.place_from AND sr.rank <= rw.place_to
+		WHERE sr.got_prize_id IS NULL AND sr.points > 0
+		ORDER BY sr.season_id, sr.user_uuid, rw.place_from
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unpaid season results: %w", err)
+	}
+	defer rows.Close()
+
+	var results []domain.SeasonUnpaidResult
+	for rows.Next() {
+		var result domain.SeasonUnpaidResult
+		if err := rows.Scan(&result.SeasonID, &result.SeasonTitle, &result.UserUUID, &result.Rank, &result.PrizeValueID); err != nil {
+			return nil, fmt.Errorf("failed to scan unpaid season result: %w", err)
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating unpaid season results: %w", err)
+	}
+
+	return results, nil
+}
+
+// AwardSeasonResultPrize records the prize of a season result, credits its points and links it to the result in
+// one transaction. Returns false if the result already has a prize (e.g. paid by another replica).
+func (r *PostgresSeasonRepository) AwardSeasonResultPrize(ctx context.Context, seasonID int, prize *domain.Prize, points int64, description string) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin season prize transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	queryLock := `
+		SELECT 1
+		FROM season_results
+		WHERE season_id = $1 AND user_uuid = $2 AND got_prize_id IS NULL
+		FOR UPDATE
+	`
+	var locked int
+	if err = tx.QueryRow(ctx, queryLock, seasonID, prize.UserID).Scan(&locked); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = nil
+			_ = tx.Rollback(ctx)
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to lock season result: %w", err)
+	}
+
+	queryPrize := `
+		INSERT INTO got_prizes (event_id, user_uuid, prize_value_id, prize_value, prize_type, awarded_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+	if err = tx.QueryRow(ctx, queryPrize,
+		prize.EventID,
+		prize.UserID,
+		prize.PrizeValueID,
+		prize.PrizeValue,
+		prize.PrizeType,
+		prize.AwardedAt,
+		prize.CreatedAt,
+	).Scan(&prize.ID); err != nil {
+		return false, fmt.Errorf("failed to create season prize: %w", err)
+	}
+
+	if points != 0 {
+		queryPoints := `
+			INSERT INTO rating (user_uuid, points, got_prize_id, description, created_at)
+			VALUES ($1, $2, $3, $4, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+		`
+		if _, err = tx.Exec(ctx, queryPoints, prize.UserID, points, prize.ID, description); err != nil {
+			return false, fmt.Errorf("failed to add season prize points: %w", err)
+		}
+	}
+
+	queryResult := `
+		UPDATE season_results
+		SET got_prize_id = $3
+		WHERE season_id = $1 AND user_uuid = $2
+	`
+	if _, err = tx.Exec(ctx, queryResult, seasonID, prize.UserID, prize.ID); err != nil {
+		return false, fmt.Errorf("failed to set season result prize: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit season prize transaction: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,163 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"pdrest/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrSkillRatingConflict is returned when a concurrent settlement changed the rating first.
+var ErrSkillRatingConflict = errors.New("skill rating was updated concurrently")
+
+// SkillRatingRepository provides access to skill ratings.
+type SkillRatingRepository interface {
+	GetSkillRating(ctx context.Context, userUUID string) (*domain.SkillRating, error)
+	ApplySkillUpdate(ctx context.Context, update *domain.SkillRatingUpdate) (bool, error)
+	GetSkillLeaderboard(ctx context.Context, minGames, limit, offset int) ([]domain.SkillLeaderboardEntry, error)
+}
+
+// PostgresSkillRatingRepository implements SkillRatingRepository with PostgreSQL.
+type PostgresSkillRatingRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresSkillRatingRepository(pool *pgxpool.Pool) *PostgresSkillRatingRepository {
+	return &PostgresSkillRatingRepository{pool: pool}
+}
+
+func (r *PostgresSkillRatingRepository) GetSkillRating(ctx context.Context, userUUID string) (*domain.SkillRating, error) {
+	query := `
+		SELECT user_uuid::text, rating, deviation, games, updated_at
+		FROM skill_ratings
+		WHERE user_uuid = $1
+	`
+
+	var rating domain.SkillRating
+	if err := r.pool.QueryRow(ctx, query, userUUID).Scan(
+		&rating.UserUUID,
+		&rating.Rating,
+		&rating.Deviation,
+		&rating.Games,
+		&rating.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get skill rating: %w", err)
+	}
+	return &rating, nil
+}
+
+// ApplySkillUpdate records the update for the bet and stores the new rating.
+// Returns false if the bet was already applied; returns ErrSkillRatingConflict if
+// the rating changed since update.GamesBefore was read.
+func (r *PostgresSkillRatingRepository) ApplySkillUpdate(ctx context.Context, update *domain.SkillRatingUpdate) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin skill rating transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	queryLog := `
+		INSERT INTO skill_rating_updates (bet_id, user_uuid, rating_before, rating_after, deviation_before, deviation_after, score, weight, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+		ON CONFLICT (bet_id) DO NOTHING
+	`
+	tag, execErr := tx.Exec(ctx, queryLog,
+		update.BetID,
+		update.UserUUID,
+		update.RatingBefore,
+		update.RatingAfter,
+		update.DeviationBefore,
+		update.DeviationAfter,
+		update.Score,
+		update.Weight,
+	)
+	if execErr != nil {
+		err = execErr
+		return false, fmt.Errorf("failed to record skill rating update: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		_ = tx.Rollback(ctx)
+		return false, nil
+	}
+
+	queryRating := `
+		INSERT INTO skill_ratings (user_uuid, rating, deviation, games, updated_at)
+		VALUES ($1, $2, $3, $4 + 1, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+		ON CONFLICT (user_uuid) DO UPDATE
+		SET rating = EXCLUDED.rating,
+			deviation = EXCLUDED.deviation,
+			games = EXCLUDED.games,
+			updated_at = EXCLUDED.updated_at
+		WHERE skill_ratings.games = $4
+	`
+	tag, execErr = tx.Exec(ctx, queryRating, update.UserUUID, update.RatingAfter, update.DeviationAfter, update.GamesBefore)
+	if execErr != nil {
+		err = execErr
+		return false, fmt.Errorf("failed to store skill rating: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		err = ErrSkillRatingConflict
+		return false, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit skill rating transaction: %w", err)
+	}
+	return true, nil
+}
+
+func (r *PostgresSkillRatingRepository) GetSkillLeaderboard(ctx context.Context, minGames, limit, offset int) ([]domain.SkillLeaderboardEntry, error) {
+	query := `
+		SELECT
+			s.rating,
+			s.deviation,
+			s.games,
+			u.google_name,
+			u.telegram_username,
+			u.telegram_first_name,
+			u.telegram_last_name,
+			u.display_name,
+			COALESCE(u.hide_name, FALSE)
+		FROM skill_ratings s
+		LEFT JOIN users u ON u.user_uuid = s.user_uuid
+		WHERE s.games >= $1
+		ORDER BY s.rating DESC, s.deviation ASC, s.user_uuid ASC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, minGames, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get skill leaderboard: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []domain.SkillLeaderboardEntry
+	for rows.Next() {
+		var entry domain.SkillLeaderboardEntry
+		var googleName, telegramUsername, telegramFirstName, telegramLastName, chosenName sql.NullString
+		var hideName bool
+		if err := rows.Scan(&entry.Rating, &entry.Deviation, &entry.Games, &googleName, &telegramUsername, &telegramFirstName, &telegramLastName, &chosenName, &hideName); err != nil {
+			return nil, fmt.Errorf("failed to scan skill leaderboard entry: %w", err)
+		}
+		entry.Rank = offset + len(entries) + 1
+		entry.UserName = buildRatingName(chosenName, hideName, googleName, telegramUsername, telegramFirstName, telegramLastName)
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating skill leaderboard rows: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,598 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"pdrest/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SquadRepository provides access to squads, their members and frozen squad standings.
+type SquadRepository interface {
+	CreateSquad(ctx context.Context, squad *domain.Squad) error
+	GetSquad(ctx context.Context, squadID int) (*domain.Squad, error)
+	GetSquadByInviteCode(ctx context.Context, inviteCode string) (*domain.Squad, error)
+	GetUserSquad(ctx context.Context, eventID string, userUUID string) (*domain.Squad, error)
+	GetSquadMembers(ctx context.Context, squadID int, startMs, endMs int64, rules domain.EventScoringRules) ([]domain.SquadMember, error)
+	AddSquadMember(ctx context.Context, squadID int, userUUID string, nowMs int64) error
+	RemoveSquadMember(ctx context.Context, squadID int, userUUID string) (bool, error)
+	GetSquadStandings(ctx context.Context, eventID string, startMs, endMs int64, rules domain.EventScoringRules, squadID int, limit, offset int) ([]domain.SquadStanding, error)
+	SaveSquadResults(ctx context.Context, eventID string, results []domain.SquadStanding, prizes []domain.Prize, notifications []domain.Notification, nowMs int64) (bool, error)
+	AreSquadsFinalized(ctx context.Context, eventID string) (bool, error)
+	GetSquadResults(ctx context.Context, eventID string, squadID int, limit, offset int) ([]domain.SquadStanding, error)
+}
+
+// PostgresSquadRepository implements SquadRepository with PostgreSQL.
+type PostgresSquadRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresSquadRepository(pool *pgxpool.Pool) *PostgresSquadRepository {
+	return &PostgresSquadRepository{pool: pool}
+}
+
+// CreateSquad inserts the squad with its captain as the first member.
+func (r *PostgresSquadRepository) CreateSquad(ctx context.Context, squad *domain.Squad) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin squad transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	var nameTaken bool
+	queryCheck := `SELECT EXISTS (SELECT 1 FROM squads WHERE event_id = $1 AND LOWER(name) = LOWER($2))`
+	if err = tx.QueryRow(ctx, queryCheck, squad.EventID, squad.Name).Scan(&nameTaken); err != nil {
+		return fmt.Errorf("failed to check squad name: %w", err)
+	}
+	if nameTaken {
+		err = errors.New("squad name already exists in this event")
+		return err
+	}
+
+	queryInsert := `
+		INSERT INTO squads (event_id, name, invite_code, captain_uuid, max_size, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+		ON CONFLICT (invite_code) DO NOTHING
+		RETURNING id, created_at
+	`
+	if err = tx.QueryRow(ctx, queryInsert, squad.EventID, squad.Name, squad.InviteCode, squad.CaptainUUID, squad.MaxSize).Scan(&squad.ID, &squad.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = errors.New("invite code already exists")
+			return err
+		}
+		return fmt.Errorf("failed to create squad: %w", err)
+	}
+
+	queryMember := `
+		INSERT INTO squad_members (squad_id, event_id, user_uuid, joined_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (event_id, user_uuid) DO NOTHING
+	`
+	tag, execErr := tx.Exec(ctx, queryMember, squad.ID, squad.EventID, squad.CaptainUUID, squad.CreatedAt)
+	if execErr != nil {
+		err = execErr
+		return fmt.Errorf("failed to add squad captain: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		err = errors.New("user is already in a squad of this event")
+		return err
+	}
+	squad.MemberCount = 1
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit squad transaction: %w", err)
+	}
+	return nil
+}
+
+const squadSelect = `
+	SELECT s.id, s.event_id, s.name, s.invite_code, s.captain_uuid::text, s.max_size,
+		(SELECT COUNT(*) FROM squad_members sm WHERE sm.squad_id = s.id)::INT,
+		s.created_at
+	FROM squads s
+`
+
+// GetSquad retrieves a squad by ID. Returns nil if it does not exist.
+func (r *PostgresSquadRepository) GetSquad(ctx context.Context, squadID int) (*domain.Squad, error) {
+	squad, err := scanSquad(r.pool.QueryRow(ctx, squadSelect+`WHERE s.id = $1`, squadID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get squad: %w", err)
+	}
+	return squad, nil
+}
+
+// GetSquadByInviteCode retrieves the squad with the invite code. Returns nil if there is none.
+func (r *PostgresSquadRepository) GetSquadByInviteCode(ctx context.Context, inviteCode string) (*domain.Squad, error) {
+	squad, err := scanSquad(r.pool.QueryRow(ctx, squadSelect+`WHERE s.invite_code = $1`, inviteCode))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get squad by invite code: %w", err)
+	}
+	return squad, nil
+}
+
+// GetUserSquad retrieves the user's squad in the event. Returns nil if the user is in none.
+func (r *PostgresSquadRepository) GetUserSquad(ctx context.Context, eventID string, userUUID string) (*domain.Squad, error) {
+	query := squadSelect + `
+		JOIN squad_members me ON me.squad_id = s.id
+		WHERE me.event_id = $1 AND me.user_uuid = $2
+	`
+	squad, err := scanSquad(r.pool.QueryRow(ctx, query, eventID, userUUID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user squad: %w", err)
+	}
+	return squad, nil
+}
+
+// GetSquadMembers lists the squad's members, oldest first, with the bet points each added to the squad:
+// settled bets opened after joining the squad within startMs..endMs that match the event's pair and timeframe filters.
+func (r *PostgresSquadRepository) GetSquadMembers(ctx context.Context, squadID int, startMs, endMs int64, rules domain.EventScoringRules) ([]domain.SquadMember, error) {
+	query := `
+		SELECT
+			sm.user_uuid::text,
+			sm.joined_at,
+			sm.user_uuid = s.captain_uuid,
+			COALESCE(SUM(CASE
+				WHEN (b.side = 'pump' AND b.close_price > b.open_price) OR (b.side = 'dump' AND b.close_price < b.open_price) THEN ROUND(b.sum)
+				ELSE -ROUND(b.sum)
+			END), 0)::BIGINT,
+			u.google_name,
+			u.telegram_username,
+			u.telegram_first_name,
+			u.telegram_last_name,
+			u.display_name,
+			COALESCE(u.hide_name, FALSE)
+		FROM squad_members sm
+		JOIN squads s ON s.id = sm.squad_id
+		LEFT JOIN users u ON u.user_uuid = sm.user_uuid
+		LEFT JOIN bets b ON b.user_uuid = sm.user_uuid
+			AND b.close_price IS NOT NULL
+			AND b.open_time >= GREATEST($2::TIMESTAMP, to_timestamp(sm.joined_at / 1000.0) AT TIME ZONE 'UTC')
+			AND b.close_time <= $3
+			AND (cardinality($4::TEXT[]) = 0 OR b.pair = ANY($4::TEXT[]))
+			AND (cardinality($5::INT[]) = 0 OR b.timeframe = ANY($5::INT[]))
+		WHERE sm.squad_id = $1
+		GROUP BY sm.user_uuid, sm.joined_at, s.captain_uuid, u.google_name, u.telegram_username, u.telegram_first_name, u.telegram_last_name, u.display_name, u.hide_name
+		ORDER BY sm.joined_at ASC, sm.user_uuid::text ASC
+	`
+
+	pairs, timeframes := scoringFilters(rules)
+	rows, err := r.pool.Query(ctx, query, squadID, time.UnixMilli(startMs).UTC(), time.UnixMilli(endMs).UTC(), pairs, timeframes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get squad members: %w", err)
+	}
+	defer rows.Close()
+
+	var members []domain.SquadMember
+	for rows.Next() {
+		var member domain.SquadMember
+		var googleName, telegramUsername, telegramFirstName, telegramLastName, chosenName sql.NullString
+		var hideName bool
+		if err := rows.Scan(
+			&member.UserUUID,
+			&member.JoinedAt,
+			&member.Captain,
+			&member.NetPoints,
+			&googleName,
+			&telegramUsername,
+			&telegramFirstName,
+			&telegramLastName,
+			&chosenName,
+			&hideName,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan squad member: %w", err)
+		}
+		member.UserName = buildRatingName(chosenName, hideName, googleName, telegramUsername, telegramFirstName, telegramLastName)
+		members = append(members, member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating squad members: %w", err)
+	}
+
+	return members, nil
+}
+
+// AddSquadMember adds the user to the squad unless it is full or the user already is in a squad of the event.
+func (r *PostgresSquadRepository) AddSquadMember(ctx context.Context, squadID int, userUUID string, nowMs int64) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin squad transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	// Locking the squad row serializes joins, so concurrent joins cannot exceed max_size.
+	var eventID string
+	var maxSize, memberCount int
+	queryLock := `
+		SELECT s.event_id, s.max_size, (SELECT COUNT(*) FROM squad_members sm WHERE sm.squad_id = s.id)::INT
+		FROM squads s
+		WHERE s.id = $1
+		FOR UPDATE
+	`
+	if err = tx.QueryRow(ctx, queryLock, squadID).Scan(&eventID, &maxSize, &memberCount); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = errors.New("squad not found")
+			return err
+		}
+		return fmt.Errorf("failed to lock squad: %w", err)
+	}
+	if memberCount >= maxSize {
+		err = fmt.Errorf("squad is full (%d members)", maxSize)
+		return err
+	}
+
+	queryMember := `
+		INSERT INTO squad_members (squad_id, event_id, user_uuid, joined_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (event_id, user_uuid) DO NOTHING
+	`
+	tag, execErr := tx.Exec(ctx, queryMember, squadID, eventID, userUUID, nowMs)
+	if execErr != nil {
+		err = execErr
+		return fmt.Errorf("failed to add squad member: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		err = errors.New("user is already in a squad of this event")
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit squad transaction: %w", err)
+	}
+	return nil
+}
+
+// RemoveSquadMember removes the user from the squad. A leaving captain hands over to the longest-standing
+// member; the squad is deleted when its last member leaves. Returns false if the user was not a member.
+func (r *PostgresSquadRepository) RemoveSquadMember(ctx context.Context, squadID int, userUUID string) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin squad transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	var captainUUID string
+	queryLock := `SELECT captain_uuid::text FROM squads WHERE id = $1 FOR UPDATE`
+	if err = tx.QueryRow(ctx, queryLock, squadID).Scan(&captainUUID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			_ = tx.Rollback(ctx)
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to lock squad: %w", err)
+	}
+
+	tag, execErr := tx.Exec(ctx, `DELETE FROM squad_members WHERE squad_id = $1 AND user_uuid = $2`, squadID, userUUID)
+	if execErr != nil {
+		err = execErr
+		return false, fmt.Errorf("failed to remove squad member: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		_ = tx.Rollback(ctx)
+		return false, nil
+	}
+
+	var nextCaptain sql.NullString
+	queryNext := `
+		SELECT user_uuid::text
+		FROM squad_members
+		WHERE squad_id = $1
+		ORDER BY joined_at ASC, user_uuid::text ASC
+		LIMIT 1
+	`
+	if scanErr := tx.QueryRow(ctx, queryNext, squadID).Scan(&nextCaptain); scanErr != nil && !errors.Is(scanErr, pgx.ErrNoRows) {
+		err = scanErr
+		return false, fmt.Errorf("failed to get next squad captain: %w", err)
+	}
+
+	switch {
+	case !nextCaptain.Valid:
+		if _, err = tx.Exec(ctx, `DELETE FROM squads WHERE id = $1`, squadID); err != nil {
+			return false, fmt.Errorf("failed to delete empty squad: %w", err)
+		}
+	case captainUUID == userUUID:
+		queryCaptain := `
+			UPDATE squads
+			SET captain_uuid = $2, updated_at = EXTRACT(EPOCH FROM NOW())::BIGINT * 1000
+			WHERE id = $1
+		`
+		if _, err = tx.Exec(ctx, queryCaptain, squadID, nextCaptain.String); err != nil {
+			return false, fmt.Errorf("failed to hand over squad captain: %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit squad transaction: %w", err)
+	}
+	return true, nil
+}
+
+// GetSquadStandings ranks the event's squads by the summed bet points of their members (see GetSquadMembers
+// for which bets count). Squads without counted bets are not ranked. A squadID > 0 returns only that squad's
+// standing; a limit <= 0 returns all ranked squads.
+func (r *PostgresSquadRepository) GetSquadStandings(ctx context.Context, eventID string, startMs, endMs int64, rules domain.EventScoringRules, squadID int, limit, offset int) ([]domain.SquadStanding, error) {
+	query := `
+		WITH counted AS (
+			SELECT
+				sm.squad_id,
+				b.sum,
+				b.close_time,
+				((b.side = 'pump' AND b.close_price > b.open_price) OR (b.side = 'dump' AND b.close_price < b.open_price)) AS won
+			FROM squad_members sm
+			JOIN bets b ON b.user_uuid = sm.user_uuid
+			WHERE sm.event_id = $1
+			  AND b.close_price IS NOT NULL
+			  AND b.open_time >= GREATEST($2::TIMESTAMP, to_timestamp(sm.joined_at / 1000.0) AT TIME ZONE 'UTC')
+			  AND b.close_time <= $3
+			  AND (cardinality($4::TEXT[]) = 0 OR b.pair = ANY($4::TEXT[]))
+			  AND (cardinality($5::INT[]) = 0 OR b.timeframe = ANY($5::INT[]))
+		),
+		scored AS (
+			SELECT
+				squad_id,
+				COUNT(*) FILTER (WHERE won)::INT AS win_count,
+				COUNT(*) FILTER (WHERE NOT won)::INT AS loss_count,
+				COALESCE(SUM(CASE WHEN won THEN ROUND(sum) ELSE -ROUND(sum) END), 0)::BIGINT AS net_points,
+				(EXTRACT(EPOCH FROM MAX(close_time)) * 1000)::BIGINT AS last_scored_at
+			FROM counted
+			GROUP BY squad_id
+		),
+		ranked AS (
+			SELECT
+				scored.*,
+				ROW_NUMBER() OVER (ORDER BY net_points DESC, last_scored_at ASC, squad_id ASC)::INT AS position,
+				CASE WHEN $6 IN ('shared', 'split')
+					THEN RANK() OVER (ORDER BY net_points DESC)
+					ELSE ROW_NUMBER() OVER (ORDER BY net_points DESC, last_scored_at ASC, squad_id ASC)
+				END::INT AS rank
+			FROM scored
+		)
+		SELECT
+			ranked.rank,
+			s.id,
+			s.name,
+			(SELECT COUNT(*) FROM squad_members sm WHERE sm.squad_id = s.id)::INT,
+			ranked.net_points,
+			ranked.win_count,
+			ranked.loss_count,
+			ranked.last_scored_at
+		FROM ranked
+		JOIN squads s ON s.id = ranked.squad_id
+		WHERE ($7 = 0 OR s.id = $7)
+		ORDER BY ranked.position ASC
+		LIMIT NULLIF($8, 0) OFFSET $9
+	`
+
+	pairs, timeframes := scoringFilters(rules)
+	rows, err := r.pool.Query(ctx, query,
+		eventID,
+		time.UnixMilli(startMs).UTC(),
+		time.UnixMilli(endMs).UTC(),
+		pairs,
+		timeframes,
+		string(rules.Ties),
+		squadID,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get squad standings: %w", err)
+	}
+	defer rows.Close()
+
+	var standings []domain.SquadStanding
+	for rows.Next() {
+		var standing domain.SquadStanding
+		if err := rows.Scan(
+			&standing.Rank,
+			&standing.SquadID,
+			&standing.Name,
+			&standing.MemberCount,
+			&standing.NetPoints,
+			&standing.WinCount,
+			&standing.LossCount,
+			&standing.LastScoredAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan squad standing: %w", err)
+		}
+		standings = append(standings, standing)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating squad standings: %w", err)
+	}
+
+	return standings, nil
+}
+
+// SaveSquadResults marks the event's squad standings finalized, stores them and pays the members' prize
+// shares into got_prizes with their notifications. Returns false if the squads were already finalized
+// (e.g. by another replica), in which case nothing is paid again.
+func (r *PostgresSquadRepository) SaveSquadResults(ctx context.Context, eventID string, results []domain.SquadStanding, prizes []domain.Prize, notifications []domain.Notification, nowMs int64) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin squad results transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	queryFinalize := `
+		UPDATE all_events
+		SET squads_finalized_at = $2, updated_at = $2
+		WHERE id = $1 AND squads_finalized_at IS NULL
+	`
+	tag, execErr := tx.Exec(ctx, queryFinalize, eventID, nowMs)
+	if execErr != nil {
+		err = execErr
+		return false, fmt.Errorf("failed to finalize squads: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		_ = tx.Rollback(ctx)
+		return false, nil
+	}
+
+	queryResult := `
+		INSERT INTO squad_results (event_id, squad_id, name, rank, points, win_count, loss_count, member_count, prize_value_id, prize_label, member_prize_label, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), NULLIF($11, ''), $12)
+	`
+	for _, result := range results {
+		if _, err = tx.Exec(ctx, queryResult,
+			eventID,
+			result.SquadID,
+			result.Name,
+			result.Rank,
+			result.NetPoints,
+			result.WinCount,
+			result.LossCount,
+			result.MemberCount,
+			result.PrizeValueID,
+			result.PrizeLabel,
+			result.MemberPrizeLabel,
+			nowMs,
+		); err != nil {
+			return false, fmt.Errorf("failed to insert squad result: %w", err)
+		}
+	}
+
+	queryPrize := `
+		INSERT INTO got_prizes (event_id, user_uuid, prize_value_id, prize_value, prize_type, awarded_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+	`
+	for _, prize := range prizes {
+		if _, err = tx.Exec(ctx, queryPrize, eventID, prize.UserID, prize.PrizeValueID, prize.PrizeValue, prize.PrizeType, nowMs); err != nil {
+			return false, fmt.Errorf("failed to insert squad prize: %w", err)
+		}
+	}
+
+	queryNotification := `
+		INSERT INTO notifications (user_id, producer, message, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+	`
+	for _, notification := range notifications {
+		if _, err = tx.Exec(ctx, queryNotification, notification.UserUUID, notification.Producer, notification.Message, nowMs); err != nil {
+			return false, fmt.Errorf("failed to queue notification: %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit squad results transaction: %w", err)
+	}
+	return true, nil
+}
+
+// AreSquadsFinalized reports whether the event's squad standings are frozen.
+func (r *PostgresSquadRepository) AreSquadsFinalized(ctx context.Context, eventID string) (bool, error) {
+	query := `SELECT squads_finalized_at IS NOT NULL FROM all_events WHERE id = $1`
+
+	var finalized bool
+	if err := r.pool.QueryRow(ctx, query, eventID).Scan(&finalized); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check squad finalization: %w", err)
+	}
+	return finalized, nil
+}
+
+// GetSquadResults returns frozen squad standings ordered by rank. A squadID > 0 returns only that squad's result.
+func (r *PostgresSquadRepository) GetSquadResults(ctx context.Context, eventID string, squadID int, limit, offset int) ([]domain.SquadStanding, error) {
+	query := `
+		SELECT rank, squad_id, name, member_count, points, win_count, loss_count, prize_value_id, prize_label, member_prize_label
+		FROM squad_results
+		WHERE event_id = $1
+		  AND ($2 = 0 OR squad_id = $2)
+		ORDER BY rank ASC, squad_id ASC
+		LIMIT NULLIF($3, 0) OFFSET $4
+	`
+
+	rows, err := r.pool.Query(ctx, query, eventID, squadID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get squad results: %w", err)
+	}
+	defer rows.Close()
+
+	var results []domain.SquadStanding
+	for rows.Next() {
+		var result domain.SquadStanding
+		var prizeLabel, memberPrizeLabel sql.NullString
+		if err := rows.Scan(
+			&result.Rank,
+			&result.SquadID,
+			&result.Name,
+			&result.MemberCount,
+			&result.NetPoints,
+			&result.WinCount,
+			&result.LossCount,
+			&result.PrizeValueID,
+			&prizeLabel,
+			&memberPrizeLabel,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan squad result: %w", err)
+		}
+		result.PrizeLabel = prizeLabel.String
+		result.MemberPrizeLabel = memberPrizeLabel.String
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating squad results: %w", err)
+	}
+
+	return results, nil
+}
+
+func scanSquad(row pgx.Row) (*domain.Squad, error) {
+	var squad domain.Squad
+	if err := row.Scan(
+		&squad.ID,
+		&squad.EventID,
+		&squad.Name,
+		&squad.InviteCode,
+		&squad.CaptainUUID,
+		&squad.MaxSize,
+		&squad.MemberCount,
+		&squad.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &squad, nil
+}
+
+// scoringFilters returns the event's pair and timeframe filters as non-nil query arrays.
+func scoringFilters(rules domain.EventScoringRules) ([]string, []int) {
+	pairs := rules.Pairs
+	if pairs == nil {
+		pairs = []string{}
+	}
+	timeframes := rules.Timeframes
+	if timeframes == nil {
+		timeframes = []int{}
+	}
+	return pairs, timeframes
+}
@@ -0,0 +1,189 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"pdrest/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// XPRepository provides access to the XP ledger, user XP totals and the level curve.
+type XPRepository interface {
+	GetLevels(ctx context.Context) ([]domain.Level, error)
+	GetUserLevel(ctx context.Context, userUUID string) (*domain.UserLevel, error)
+	GrantXP(ctx context.Context, grant domain.XPGrant) (*domain.XPGrantResult, error)
+	GetXPHistory(ctx context.Context, userUUID string, limit, offset int) ([]domain.XPLedgerEntry, error)
+	QueueNotification(ctx context.Context, notification *domain.Notification) error
+}
+
+// PostgresXPRepository implements XPRepository with PostgreSQL.
+type PostgresXPRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresXPRepository(pool *pgxpool.Pool) *PostgresXPRepository {
+	return &PostgresXPRepository{pool: pool}
+}
+
+// levelForXP is the level reached with the XP in $N; users below the curve are level 1.
+func levelForXP(param string) string {
+	return `COALESCE((SELECT MAX(level) FROM levels WHERE xp_required <= ` + param + `), 1)`
+}
+
+func (r *PostgresXPRepository) GetLevels(ctx context.Context) ([]domain.Level, error) {
+	query := `SELECT level, xp_required, title, max_stake, extra_spins FROM levels ORDER BY level`
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get levels: %w", err)
+	}
+	defer rows.Close()
+
+	levels := []domain.Level{}
+	for rows.Next() {
+		var level domain.Level
+		if err := rows.Scan(&level.Level, &level.XPRequired, &level.Title, &level.Perks.MaxStake, &level.Perks.ExtraSpins); err != nil {
+			return nil, fmt.Errorf("failed to scan level: %w", err)
+		}
+		levels = append(levels, level)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating levels: %w", err)
+	}
+	return levels, nil
+}
+
+// GetUserLevel returns the user's XP, level and perks; users without XP are at the first level.
+func (r *PostgresXPRepository) GetUserLevel(ctx context.Context, userUUID string) (*domain.UserLevel, error) {
+	query := `
+		WITH me AS (
+			SELECT COALESCE((SELECT xp FROM user_xp WHERE user_uuid = $1), 0) AS xp
+		)
+		SELECT me.xp, l.level, l.title, l.xp_required, l.max_stake, COALESCE(l.extra_spins, 0),
+		       (SELECT MIN(xp_required) FROM levels WHERE xp_required > me.xp)
+		FROM me
+		LEFT JOIN levels l ON l.level = (SELECT MAX(level) FROM levels WHERE xp_required <= me.xp)
+	`
+	var result domain.UserLevel
+	var level *int
+	var title *string
+	var levelXP *int64
+	if err := r.pool.QueryRow(ctx, query, userUUID).Scan(
+		&result.XP,
+		&level,
+		&title,
+		&levelXP,
+		&result.Perks.MaxStake,
+		&result.Perks.ExtraSpins,
+		&result.NextLevelXP,
+	); err != nil {
+		return nil, fmt.Errorf("failed to get user level: %w", err)
+	}
+	result.Level = 1
+	if level != nil {
+		result.Level = *level
+		result.Title = *title
+		result.LevelXP = *levelXP
+	}
+	return &result, nil
+}
+
+// GrantXP records the grant in the ledger and adds it to the user's total. A source that was already granted
+// is not granted again.
+func (r *PostgresXPRepository) GrantXP(ctx context.Context, grant domain.XPGrant) (*domain.XPGrantResult, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin xp transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	var ledgerID int64
+	err = tx.QueryRow(ctx, `
+		INSERT INTO xp_ledger (user_uuid, source, source_ref, xp)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_uuid, source, source_ref) DO NOTHING
+		RETURNING id
+	`, grant.UserUUID, grant.Source, grant.SourceRef, grant.XP).Scan(&ledgerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Already granted; the deferred rollback ends the transaction
+		return &domain.XPGrantResult{Granted: false}, nil
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to record xp: %w", err)
+		return nil, err
+	}
+
+	result := &domain.XPGrantResult{Granted: true}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO user_xp (user_uuid, xp, updated_at)
+		VALUES ($1, $2, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+		ON CONFLICT (user_uuid) DO UPDATE SET
+			xp = user_xp.xp + EXCLUDED.xp,
+			updated_at = EXCLUDED.updated_at
+		RETURNING xp
+	`, grant.UserUUID, grant.XP).Scan(&result.XP)
+	if err != nil {
+		err = fmt.Errorf("failed to add xp: %w", err)
+		return nil, err
+	}
+
+	err = tx.QueryRow(ctx, `SELECT `+levelForXP("$1")+`, `+levelForXP("$2"),
+		result.XP-int64(grant.XP), result.XP).Scan(&result.OldLevel, &result.NewLevel)
+	if err != nil {
+		err = fmt.Errorf("failed to get levels: %w", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf("failed to commit xp transaction: %w", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetXPHistory returns the user's XP grants, newest first.
+func (r *PostgresXPRepository) GetXPHistory(ctx context.Context, userUUID string, limit, offset int) ([]domain.XPLedgerEntry, error) {
+	query := `
+		SELECT source, source_ref, xp, created_at
+		FROM xp_ledger
+		WHERE user_uuid = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.pool.Query(ctx, query, userUUID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get xp history: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []domain.XPLedgerEntry{}
+	for rows.Next() {
+		var entry domain.XPLedgerEntry
+		if err := rows.Scan(&entry.Source, &entry.SourceRef, &entry.XP, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan xp entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating xp history: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *PostgresXPRepository) QueueNotification(ctx context.Context, notification *domain.Notification) error {
+	query := `
+		INSERT INTO notifications (user_id, producer, message, created_at, updated_at)
+		VALUES ($1, $2, $3, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000)
+	`
+	if _, err := r.pool.Exec(ctx, query, notification.UserUUID, notification.Producer, notification.Message); err != nil {
+		return fmt.Errorf("failed to queue notification: %w", err)
+	}
+	return nil
+}
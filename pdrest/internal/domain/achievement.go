@@ -69,3 +69,86 @@ type UserAchievementsResponse struct {
 type UserAchievementResponse struct {
 	Achievement UserAchievementEntry `json:"achievement"`
 }
+
+// Achievement rule metrics
+const (
+	AchievementMetricWins            = "wins"             // winning bets
+	AchievementMetricBetsPlaced      = "bets_placed"      // opened bets
+	AchievementMetricWinStreak       = "win_streak"       // longest run of consecutive winning bets
+	AchievementMetricPairWins        = "pair_wins"        // winning bets on filters.pair
+	AchievementMetricReferrals       = "referrals"        // users who joined with the user as referrer
+	AchievementMetricLoginDays       = "login_days"       // distinct UTC days with a login
+	AchievementMetricEventPlacements = "event_placements" // finished events ranked within filters.maxRank
+)
+
+// AchievementRuleFilters narrow what a rule's metric counts. Fields that don't apply to the metric are ignored.
+type AchievementRuleFilters struct {
+	Pair      string `json:"pair,omitempty"`      // bets on this pair only
+	Side      string `json:"side,omitempty"`      // "pump" or "dump" bets only
+	Timeframe int    `json:"timeframe,omitempty"` // bets with this timeframe (seconds) only
+	MaxRank   int    `json:"maxRank,omitempty"`   // event placements at this rank or better; default: any rank
+	EventTag  string `json:"eventTag,omitempty"`  // events with this tag only
+}
+
+// AchievementRule declares how an achievement is earned: the achievement completes once the metric,
+// counted over the last WindowDays days (0 = all time), reaches Threshold.
+type AchievementRule struct {
+	AchievementID string                 `json:"achievementId"`
+	Metric        string                 `json:"metric"`
+	Threshold     int                    `json:"threshold"`
+	WindowDays    int                    `json:"windowDays,omitempty"`
+	Filters       AchievementRuleFilters `json:"filters"`
+}
+
+// AchievementProgressDelta moves a user's counter for one rule-based achievement.
+type AchievementProgressDelta struct {
+	AchievementID string
+	NeedSteps     int
+	Increment     int  // added to the progress; for streaks, to the current run
+	Streak        bool // progress is the best run reached by the current run
+	ResetRun      bool // streaks: the current run ends (a lost bet)
+}
+
+// AchievementCatalogEntry is a row of the design team's achievement sheet (extra/achievements_first.csv):
+// the achievement, the rule parsed from its conditions and its prize.
+type AchievementCatalogEntry struct {
+	Achievement Achievement     `json:"achievement"`
+	Rule        AchievementRule `json:"rule"`
+	Conditions  string          `json:"conditions"`           // as written in the sheet, e.g. "10 successful bets"
+	Prize       string          `json:"prize,omitempty"`      // prize label, e.g. "10 USDT"; empty keeps the current prize
+	PrizeValue  int64           `json:"prizeValue,omitempty"` // points paid, parsed from Prize
+	Line        int             `json:"line,omitempty"`       // CSV line the entry was read from
+}
+
+// Achievement catalog import actions
+const (
+	AchievementCatalogCreate    = "create"
+	AchievementCatalogUpdate    = "update"
+	AchievementCatalogUnchanged = "unchanged"
+)
+
+// AchievementCatalogChange is what an import does to one achievement
+type AchievementCatalogChange struct {
+	AchievementID string   `json:"achievementId"`
+	Line          int      `json:"line"`
+	Action        string   `json:"action"`             // create, update or unchanged
+	Changes       []string `json:"changes,omitempty"`  // changed fields, "field: old -> new"
+	Warnings      []string `json:"warnings,omitempty"` // e.g. a new achievement without a prize
+}
+
+// AchievementCatalogImportResult is the diff of a catalog file against the database
+type AchievementCatalogImportResult struct {
+	DryRun    bool                       `json:"dryRun"`
+	Created   int                        `json:"created"`
+	Updated   int                        `json:"updated"`
+	Unchanged int                        `json:"unchanged"`
+	Changes   []AchievementCatalogChange `json:"changes"`
+	NotInFile []string                   `json:"notInFile,omitempty"` // catalog achievements the file doesn't list; left as they are
+}
+
+// AchievementClaimResult is the prize recorded for a claimed achievement and the reward it granted
+type AchievementClaimResult struct {
+	AchievementID string         `json:"achievementId"`
+	Prize         *Prize         `json:"prize"`
+	Reward        *GrantedReward `json:"reward"`
+}
@@ -15,6 +15,8 @@ type Bet struct {
 	CloseTime  *time.Time `json:"closeTime,omitempty"`
 	Claimed    bool       `json:"claimedStatus"`
 	PrizeStatus string    `json:"prizeStatus,omitempty"`
+	DuelID     *int       `json:"duelId,omitempty"` // set for the positions of a duel
+	Modifiers  []BetModifier `json:"modifiers,omitempty"` // inventory items applied when the bet was opened
 	CreatedAt  int64      `json:"created_at,omitempty"`
 	UpdatedAt  int64      `json:"updated_at,omitempty"`
 }
@@ -41,6 +43,7 @@ type BetStatusResponse struct {
 	ClosePrice *float64 `json:"closePrice,omitempty"`
 	OpenTime   time.Time `json:"openTime"`
 	Claimed    bool      `json:"claimedStatus"`
+	Modifiers  []BetModifier `json:"modifiers,omitempty"`
 }
 
 // type BetShareResultResponse struct {
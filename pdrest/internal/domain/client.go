@@ -6,8 +6,9 @@ type UserLastLogin struct {
 }
 
 type UserProfile struct {
-	UserID   string  `json:"userID"`
-	Username *string `json:"username,omitempty"`
+	UserID      string       `json:"userID"`
+	Username    *string      `json:"username,omitempty"`
+	SkillRating *SkillRating `json:"skillRating,omitempty"`
 }
 
 type User struct {
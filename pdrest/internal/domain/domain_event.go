@@ -0,0 +1,24 @@
+package domain
+
+// Domain event types published on the internal event bus
+const (
+	DomainEventBetSettled           = "bet_settled"           // a bet got its close price
+	DomainEventBetClaimed           = "bet_claimed"           // the owner claimed a closed bet
+	DomainEventEventJoined          = "event_joined"          // a user took a place in an event
+	DomainEventReferralActivated    = "referral_activated"    // a new user was attributed to their referrer
+	DomainEventUserLoggedIn         = "user_logged_in"        // a user signed in (session, Google or Telegram)
+	DomainEventAchievementCompleted = "achievement_completed" // a user's achievement progress reached its steps
+	DomainEventLevelUp              = "level_up"              // a user's XP reached a new level
+)
+
+// DomainEvent is something that happened to a user. Only the fields of its type are set.
+type DomainEvent struct {
+	Type          string `json:"type"`
+	UserUUID      string `json:"userUUID"`                // the user the event happened to
+	Bet           *Bet   `json:"bet,omitempty"`           // BetSettled, BetClaimed
+	EventID       string `json:"eventId,omitempty"`       // EventJoined
+	InviteeUUID   string `json:"inviteeUUID,omitempty"`   // ReferralActivated: the referred user
+	AchievementID string `json:"achievementId,omitempty"` // AchievementCompleted
+	Level         int    `json:"level,omitempty"`         // LevelUp: the level reached
+	OccurredAt    int64  `json:"occurredAt"`              // Unix ms
+}
@@ -0,0 +1,61 @@
+package domain
+
+import "time"
+
+// Duel statuses
+const (
+	DuelPending   = "pending"   // proposed, waiting for the opponent
+	DuelActive    = "active"    // accepted, both positions are open
+	DuelSettled   = "settled"   // the positions closed and the pot was paid
+	DuelDeclined  = "declined"  // the opponent said no
+	DuelCancelled = "cancelled" // the challenger withdrew before it was accepted
+	DuelExpired   = "expired"   // nobody accepted it in time
+)
+
+// Duel is a head-to-head bet between two users: both positions open at the same server price on
+// opposite sides and the winner takes both stakes.
+type Duel struct {
+	ID              int        `json:"id"`
+	ChallengerUUID  string     `json:"challengerUuid"`
+	ChallengerName  string     `json:"challengerName"`
+	OpponentUUID    string     `json:"opponentUuid,omitempty"` // empty for link duels until accepted
+	OpponentName    string     `json:"opponentName,omitempty"`
+	InviteCode      string     `json:"inviteCode,omitempty"` // shown to the challenger only
+	Pair            string     `json:"pair"`
+	Timeframe       int        `json:"timeframe"` // in seconds
+	Stake           int64      `json:"stake"`     // points each side puts into the pot
+	ChallengerSide  string     `json:"challengerSide"`
+	Status          string     `json:"status"`
+	ChallengerBetID *int       `json:"challengerBetId,omitempty"`
+	OpponentBetID   *int       `json:"opponentBetId,omitempty"`
+	OpenPrice       *float64   `json:"openPrice,omitempty"`
+	ClosePrice      *float64   `json:"closePrice,omitempty"`
+	OpenTime        *time.Time `json:"openTime,omitempty"`
+	WinnerUUID      string     `json:"winnerUuid,omitempty"` // empty for a draw
+	ExpiresAt       int64      `json:"expiresAt"`
+	AcceptedAt      *int64     `json:"acceptedAt,omitempty"`
+	SettledAt       *int64     `json:"settledAt,omitempty"`
+	CreatedAt       int64      `json:"createdAt"`
+}
+
+// OpponentSide is the side the opponent's position takes.
+func (d *Duel) OpponentSide() string {
+	if d.ChallengerSide == "pump" {
+		return "dump"
+	}
+	return "pump"
+}
+
+// CreateDuelRequest proposes a duel to a friend, or to whoever opens its link when OpponentUUID is empty
+type CreateDuelRequest struct {
+	OpponentUUID string `json:"opponentUuid"`
+	Pair         string `json:"pair"`
+	Timeframe    int    `json:"timeframe"`
+	Stake        int64  `json:"stake"`
+	Side         string `json:"side"` // the challenger's side: "pump" or "dump"
+}
+
+// AcceptDuelRequest accepts a duel by the invite code from its link
+type AcceptDuelRequest struct {
+	InviteCode string `json:"inviteCode"`
+}
@@ -3,20 +3,36 @@ package domain
 import "time"
 
 type Event struct {
-	ID       string    `json:"id"`
-	Badge    string    `json:"badge"`
-	Title    string    `json:"title"`
-	Desc     string    `json:"desc"`
-	StartTime time.Time `json:"startTime"`
-	Deadline time.Time `json:"deadline"`
-	Tags     string    `json:"tags"`
-	Reward   []Reward  `json:"reward"`
-	Info     string    `json:"info"`
+	ID          string             `json:"id"`
+	Badge       string             `json:"badge"`
+	Title       string             `json:"title"`
+	Desc        string             `json:"desc"`
+	StartTime   time.Time          `json:"startTime"`
+	Deadline    time.Time          `json:"deadline"`
+	Tags        string             `json:"tags"`
+	Reward      []Reward           `json:"reward"`
+	Info        string             `json:"info"`
+	Scoring     *EventScoringRules `json:"scoring,omitempty"`
+	Eligibility *EventEligibility  `json:"eligibility,omitempty"`
+	PrizePool   int64              `json:"prizePool,omitempty"` // entry fees collected, in rating points
+	State       EventState         `json:"state,omitempty"`
 }
 
+// Reward is a reward tier: the ranks it covers and what each of them wins.
 type Reward struct {
-	Place string `json:"place"`
-	Value string `json:"value"`
+	Place         string  `json:"place"`                   // "1", "4-10" or "any"; kept in sync with the rank range
+	Value         string  `json:"value"`                   // display label, e.g. "50 USDT"
+	RankFrom      int     `json:"rankFrom,omitempty"`      // first rank of the tier; 0 for "any"
+	RankTo        int     `json:"rankTo,omitempty"`        // last rank of the tier (inclusive)
+	Amount        float64 `json:"amount,omitempty"`        // amount won per rank
+	Currency      string  `json:"currency,omitempty"`      // e.g. "USDT"
+	ImageURL      string  `json:"imageUrl,omitempty"`      // tier artwork
+	AchievementID string  `json:"achievementId,omitempty"` // achievement granted with the tier; its prize value pays the tier
+}
+
+// Covers reports whether the tier rewards the given rank.
+func (r Reward) Covers(rank int) bool {
+	return r.RankFrom > 0 && rank >= r.RankFrom && rank <= r.RankTo
 }
 
 type EventsResponse struct {
@@ -33,6 +49,7 @@ type UserEventEntry struct {
 	Tags             string     `json:"tags"`
 	Reward           []Reward   `json:"reward"`
 	Info             string     `json:"info"`
+	State            EventState `json:"state,omitempty"`
 	Status           string     `json:"status"`
 	JoinedAt         *time.Time `json:"joinedAt,omitempty"`
 	HasPrise         *bool      `json:"hasPriseStatus,omitempty"`
@@ -45,19 +62,28 @@ type UserEventsResponse struct {
 }
 
 type BetPrizeLeaderboardEntry struct {
-	UserUUID  string `json:"userUUID"`
-	WinCount  int    `json:"winCount"`
-	LossCount int    `json:"lossCount"`
-	NetPoints int64  `json:"netPoints"`
+	Rank         int     `json:"rank"`
+	UserUUID     string  `json:"userUUID"`
+	UserName     string  `json:"userName"`
+	WinCount     int     `json:"winCount"`
+	LossCount    int     `json:"lossCount"`
+	NetPoints    int64   `json:"netPoints"`
+	Score        float64 `json:"score"` // value of the event's scoring metric
+	BetCount     int     `json:"betCount"`
+	LastScoredAt int64   `json:"lastScoredAt"` // Unix ms of the last counted bet (tie-break)
 }
 
 type EventProgressResponse struct {
-	EventID         string `json:"eventId"`
-	Participating   bool   `json:"participating"`
-	CollectedPoints int64  `json:"collectedPoints"`
+	EventID         string             `json:"eventId"`
+	Participating   bool               `json:"participating"`
+	CollectedPoints int64              `json:"collectedPoints"`
+	Metric          EventScoringMetric `json:"metric,omitempty"`
+	Score           float64            `json:"score"`
+	BetCount        int                `json:"betCount"`
+	MinBets         int                `json:"minBets"`
 }
 
 type EventLeaderResponse struct {
 	LeaderImage string `json:"leader_image,omitempty"`
 	Points      int64  `json:"points"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// AdminEvent is an event together with its prize values and linked achievements,
+// as created and edited through the admin API.
+type AdminEvent struct {
+	Event
+	ArchivedAt     *int64                 `json:"archivedAt,omitempty"`
+	StateChangedAt *int64                 `json:"stateChangedAt,omitempty"`
+	Transitions    []EventStateTransition `json:"transitions,omitempty"`
+	PrizeValues    []AdminEventPrize      `json:"prizeValues,omitempty"`
+}
+
+// AdminEventPrize is a prize value of an event. For competitions prize values are listed
+// in place order (1st place first) and must not increase in value.
+type AdminEventPrize struct {
+	ID          int          `json:"id,omitempty"`
+	Value       int64        `json:"value"`
+	Label       string       `json:"label"`
+	SegmentID   *string      `json:"segmentId,omitempty"`
+	Weight      *int         `json:"weight,omitempty"`      // relative roulette weight, nil = 1
+	TotalBudget *int         `json:"totalBudget,omitempty"` // max roulette wins overall, nil = unlimited
+	DailyBudget *int         `json:"dailyBudget,omitempty"` // max roulette wins per UTC day, nil = unlimited
+	Achievement *Achievement `json:"achievement,omitempty"`
+}
+
+// ScheduleEventRequest moves an event's start and deadline.
+type ScheduleEventRequest struct {
+	StartTime time.Time `json:"startTime"`
+	Deadline  time.Time `json:"deadline"`
+}
+
+// CloneEventRequest copies an event with its prize values and achievements under a new ID.
+type CloneEventRequest struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title,omitempty"`
+	StartTime time.Time `json:"startTime"`
+	Deadline  time.Time `json:"deadline"`
+}
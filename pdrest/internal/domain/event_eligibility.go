@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// User event statuses
+const (
+	UserEventJoined     = "joined"     // takes part in the event
+	UserEventWaitlisted = "waitlisted" // waits for a free place in a full event
+	UserEventDropped    = "dropped"    // left the waitlist because the entry fee could not be paid
+)
+
+// EventEligibility are the rules a user must meet to join an event, and its optional entry fee.
+// Every rule is optional; an event without rules can be joined by anyone until its deadline.
+type EventEligibility struct {
+	JoinOpensAt          *time.Time `json:"joinOpensAt,omitempty"`          // joining opens; default: as soon as published
+	JoinClosesAt         *time.Time `json:"joinClosesAt,omitempty"`         // joining closes; default: the deadline
+	MaxParticipants      int        `json:"maxParticipants,omitempty"`      // further users join the waitlist
+	MinTotalPoints       int64      `json:"minTotalPoints,omitempty"`       // rating points the user must have
+	MinLevel             int        `json:"minLevel,omitempty"`             // XP level the user must have reached
+	RequiredAchievements []string   `json:"requiredAchievements,omitempty"` // achievement IDs the user must have completed
+	AuthProviders        []string   `json:"authProviders,omitempty"`        // "google", "telegram": the user must have linked one of them
+	EntryFee             int64      `json:"entryFee,omitempty"`             // rating points debited on joining into the prize pool
+	PoolShares           []float64  `json:"poolShares,omitempty"`           // % of the prize pool paid per rank; default [100]
+}
+
+// UserEligibility is what a user brings to the eligibility rules of an event
+type UserEligibility struct {
+	TotalPoints           int64
+	Level                 int
+	AuthProviders         []string
+	CompletedAchievements []string
+}
+
+// JoinEventResult is the outcome of joining an event
+type JoinEventResult struct {
+	Status        string `json:"status"`                  // created or already_exists
+	UserStatus    string `json:"userStatus"`              // joined or waitlisted
+	EntryFeePaid  int64  `json:"entryFeePaid,omitempty"`  // points debited into the prize pool
+	WaitlistPlace *int   `json:"waitlistPlace,omitempty"` // 1-based place on the waitlist
+}
+
+// PrizePoolPayout is the share of an event's prize pool paid to a user
+type PrizePoolPayout struct {
+	UserUUID    string
+	Points      int64
+	Description string
+}
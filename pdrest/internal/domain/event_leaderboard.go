@@ -0,0 +1,23 @@
+package domain
+
+// EventLeaderboardEntry is a single row of a competition leaderboard
+type EventLeaderboardEntry struct {
+	Rank       int     `json:"rank"`
+	UserUUID   string  `json:"-"`
+	UserName   string  `json:"userName"`
+	NetPoints  int64   `json:"netPoints"`
+	Score      float64 `json:"score"` // value of the event's scoring metric
+	WinCount   int     `json:"winCount"`
+	LossCount  int     `json:"lossCount"`
+	RankChange *int    `json:"rankChange"` // places gained since the last snapshot; null if not ranked then
+}
+
+// EventLeaderboardResponse is a page of a competition leaderboard plus the caller's own entry
+type EventLeaderboardResponse struct {
+	EventID    string                  `json:"eventId"`
+	Metric     EventScoringMetric      `json:"metric"`
+	Final      bool                    `json:"final"` // true once the standings are frozen
+	SnapshotAt *int64                  `json:"snapshotAt,omitempty"`
+	Entries    []EventLeaderboardEntry `json:"entries"`
+	Me         *EventLeaderboardEntry  `json:"me,omitempty"`
+}
@@ -0,0 +1,16 @@
+package domain
+
+// EventResult is a user's frozen final standing in a finished competition.
+type EventResult struct {
+	EventID      string  `json:"eventId"`
+	UserUUID     string  `json:"userUUID"`
+	UserName     string  `json:"userName"`
+	Rank         int     `json:"rank"`
+	Points       int64   `json:"points"`
+	Score        float64 `json:"score"` // value of the event's scoring metric the rank is based on
+	WinCount     int     `json:"winCount"`
+	LossCount    int     `json:"lossCount"`
+	LastScoredAt int64   `json:"lastScoredAt"` // Unix ms; equal scores rank by who reached them first
+	PrizeValueID *int    `json:"prizeValueId,omitempty"`
+	PrizeLabel   string  `json:"prizeLabel,omitempty"` // what the rank won, e.g. "40 USDT" for a split tie
+}
@@ -0,0 +1,32 @@
+package domain
+
+// EventScoringMetric is the value competition standings are ranked by.
+type EventScoringMetric string
+
+const (
+	ScoringNetPoints EventScoringMetric = "net_points" // won bet sums minus lost bet sums
+	ScoringROI       EventScoringMetric = "roi"        // net points as % of the total bet sum
+	ScoringWinRate   EventScoringMetric = "win_rate"   // won bets as % of counted bets
+	ScoringSkill     EventScoringMetric = "skill"      // skill rating gained from counted bets
+)
+
+// RewardTieMode decides how users with equal scores share reward tiers.
+type RewardTieMode string
+
+const (
+	RewardTiesEarliest RewardTieMode = "earliest" // the user who reached the score first ranks higher
+	RewardTiesShared   RewardTieMode = "shared"   // tied users share the best rank and each wins its tier
+	RewardTiesSplit    RewardTieMode = "split"    // tied users share the best rank and split the tiers of the ranks they cover
+)
+
+// EventScoringRules decide which bets count towards a competition and how they are scored.
+// Only settled bets opened and closed inside the event window are counted.
+type EventScoringRules struct {
+	ParticipantsOnly bool               `json:"participantsOnly"`     // only users who joined the event
+	BetsAfterJoin    bool               `json:"betsAfterJoin"`        // only bets opened after joining (implies participantsOnly)
+	Pairs            []string           `json:"pairs,omitempty"`      // empty = all pairs
+	Timeframes       []int              `json:"timeframes,omitempty"` // seconds; empty = all timeframes
+	MinBets          int                `json:"minBets"`              // users with fewer counted bets are not ranked
+	Metric           EventScoringMetric `json:"metric"`
+	Ties             RewardTieMode      `json:"ties,omitempty"` // empty = earliest
+}
@@ -0,0 +1,53 @@
+package domain
+
+// EventState is a step of the event lifecycle
+type EventState string
+
+const (
+	EventStateDraft      EventState = "draft"      // being prepared, not visible to users
+	EventStateScheduled  EventState = "scheduled"  // published, not started yet
+	EventStateActive     EventState = "active"     // between start time and deadline
+	EventStateFinalizing EventState = "finalizing" // deadline passed, results are being frozen
+	EventStateFinished   EventState = "finished"   // results frozen, prizes released
+	EventStateArchived   EventState = "archived"   // hidden from all lists
+)
+
+// eventStateTransitions lists the states each state may move to
+var eventStateTransitions = map[EventState][]EventState{
+	EventStateDraft:      {EventStateScheduled, EventStateArchived},
+	EventStateScheduled:  {EventStateDraft, EventStateActive, EventStateArchived},
+	EventStateActive:     {EventStateFinalizing},
+	EventStateFinalizing: {EventStateFinished},
+	EventStateFinished:   {EventStateArchived},
+}
+
+// CanTransitionTo reports whether an event may move from s to next
+func (s EventState) CanTransitionTo(next EventState) bool {
+	for _, allowed := range eventStateTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValid reports whether s is a known state
+func (s EventState) IsValid() bool {
+	switch s {
+	case EventStateDraft, EventStateScheduled, EventStateActive, EventStateFinalizing, EventStateFinished, EventStateArchived:
+		return true
+	}
+	return false
+}
+
+// EventStateTransition is a recorded state change of an event
+type EventStateTransition struct {
+	From      EventState `json:"from"`
+	To        EventState `json:"to"`
+	CreatedAt int64      `json:"createdAt"`
+}
+
+// EventStateRequest moves an event to another state through the admin API
+type EventStateRequest struct {
+	State EventState `json:"state"`
+}
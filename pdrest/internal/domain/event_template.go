@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// EventTemplateFrequency is how often a template produces an event instance.
+type EventTemplateFrequency string
+
+const (
+	EventTemplateDaily  EventTemplateFrequency = "daily"
+	EventTemplateWeekly EventTemplateFrequency = "weekly"
+)
+
+// EventTemplate describes a recurring event. Instances are created ahead of their start
+// with the ID "<template id>_<DD_MM_YYYY>" of the instance start date (UTC).
+type EventTemplate struct {
+	ID          string                 `json:"id"`
+	Badge       string                 `json:"badge"`
+	Title       string                 `json:"title"`
+	Desc        string                 `json:"desc"`
+	Tags        string                 `json:"tags"`
+	Reward      []Reward               `json:"reward"`
+	Info        string                 `json:"info"`
+	PrizeValues []AdminEventPrize      `json:"prizeValues"`
+	Scoring     *EventScoringRules     `json:"scoring,omitempty"`
+	Eligibility *EventEligibility      `json:"eligibility,omitempty"` // join window times are not copied to instances
+	Frequency   EventTemplateFrequency `json:"frequency"`
+	// Weekday of the instance start for weekly templates (0 = Sunday ... 6 = Saturday).
+	Weekday           int        `json:"weekday"`
+	StartMinute       int        `json:"startMinute"` // minutes after 00:00 UTC
+	DurationMinutes   int        `json:"durationMinutes"`
+	LeadMinutes       int        `json:"leadMinutes"` // how long before its start an instance is created
+	Active            bool       `json:"active"`
+	LastInstanceStart *time.Time `json:"lastInstanceStart,omitempty"`
+}
+
+// MaterializeTemplatesResponse lists the event instances created by a materialization run.
+type MaterializeTemplatesResponse struct {
+	Created []string `json:"created"`
+}
@@ -0,0 +1,56 @@
+package domain
+
+// Inventory item types
+const (
+	InventoryItemPayoutBoost  = "payout_boost"  // winning bets pay Multiplier times their points
+	InventoryItemLossShield   = "loss_shield"   // losing bets cost no points
+	InventoryItemExtraSpin    = "extra_spin"    // activation adds Quantity spins on RouletteConfigID
+	InventoryItemStreakFreeze = "streak_freeze" // covers a missed day of the login streak; used automatically
+)
+
+// Inventory item statuses, derived from the item's fields
+const (
+	InventoryStatusAvailable = "available"
+	InventoryStatusActive    = "active"
+)
+
+// InventoryItemMetadata holds the type-specific settings of an item
+type InventoryItemMetadata struct {
+	Multiplier       float64 `json:"multiplier,omitempty"`
+	RouletteConfigID int     `json:"rouletteConfigId,omitempty"`
+	DurationHours    int     `json:"durationHours,omitempty"` // how long the item works once activated
+}
+
+// InventoryItem is a boost or consumable owned by a user. Quantity is the uses left, nil for an item that
+// works until it expires.
+type InventoryItem struct {
+	ID          int                   `json:"id"`
+	UserUUID    string                `json:"-"`
+	ItemType    string                `json:"itemType"`
+	Quantity    *int                  `json:"quantity,omitempty"`
+	ExpiresAt   *int64                `json:"expiresAt,omitempty"`
+	ActivatedAt *int64                `json:"activatedAt,omitempty"`
+	Metadata    InventoryItemMetadata `json:"metadata"`
+	GotPrizeID  *int                  `json:"gotPrizeId,omitempty"`
+	Status      string                `json:"status"`
+	CreatedAt   int64                 `json:"createdAt"`
+}
+
+// InventoryResponse lists a user's usable items
+type InventoryResponse struct {
+	Items []InventoryItem `json:"items"`
+}
+
+// ActivateInventoryItemResponse is an activated item; for extra spins, the spins added to the roulette
+type ActivateInventoryItemResponse struct {
+	Item             InventoryItem `json:"item"`
+	RouletteConfigID int           `json:"rouletteConfigId,omitempty"`
+	SpinsAdded       int           `json:"spinsAdded,omitempty"`
+}
+
+// BetModifier is an inventory item applied to a bet when it was opened
+type BetModifier struct {
+	ItemID     int     `json:"itemId"`
+	Type       string  `json:"type"`
+	Multiplier float64 `json:"multiplier,omitempty"`
+}
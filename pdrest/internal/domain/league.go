@@ -0,0 +1,104 @@
+package domain
+
+import "time"
+
+// LeagueTier represents a league level
+type LeagueTier string
+
+const (
+	LeagueTierBronze   LeagueTier = "bronze"
+	LeagueTierSilver   LeagueTier = "silver"
+	LeagueTierGold     LeagueTier = "gold"
+	LeagueTierPlatinum LeagueTier = "platinum"
+	LeagueTierDiamond  LeagueTier = "diamond"
+)
+
+// LeagueTiers lists tiers from lowest to highest
+var LeagueTiers = []LeagueTier{
+	LeagueTierBronze,
+	LeagueTierSilver,
+	LeagueTierGold,
+	LeagueTierPlatinum,
+	LeagueTierDiamond,
+}
+
+// Promote returns the next tier up (diamond stays diamond)
+func (t LeagueTier) Promote() LeagueTier {
+	for i, tier := range LeagueTiers {
+		if tier == t && i+1 < len(LeagueTiers) {
+			return LeagueTiers[i+1]
+		}
+	}
+	return t
+}
+
+// Relegate returns the next tier down (bronze stays bronze)
+func (t LeagueTier) Relegate() LeagueTier {
+	for i, tier := range LeagueTiers {
+		if tier == t && i > 0 {
+			return LeagueTiers[i-1]
+		}
+	}
+	return t
+}
+
+// League outcomes written at weekly rollover
+const (
+	LeagueOutcomePromoted  = "promoted"
+	LeagueOutcomeRelegated = "relegated"
+	LeagueOutcomeStayed    = "stayed"
+)
+
+// LeagueEventID is the all_events row holding league prize values
+const LeagueEventID = "league"
+
+// LeagueWeek is a weekly league round
+type LeagueWeek struct {
+	WeekStart time.Time `json:"weekStart"`
+	WeekEnd   time.Time `json:"weekEnd"`
+	Status    string    `json:"status"`
+}
+
+// LeagueCohort is a group of users of the same tier competing in a week
+type LeagueCohort struct {
+	ID        int        `json:"id"`
+	WeekStart time.Time  `json:"weekStart"`
+	WeekEnd   time.Time  `json:"weekEnd"`
+	Tier      LeagueTier `json:"tier"`
+}
+
+// LeagueUnappliedResult is a frozen cohort result of a finished week whose tier move and prize haven't been
+// applied yet. PrizeValueID is set when the place is due a league prize.
+type LeagueUnappliedResult struct {
+	WeekStart    time.Time
+	UserUUID     string
+	Tier         LeagueTier
+	Rank         int
+	Points       int64
+	Outcome      string
+	PrizeValueID *int
+}
+
+// LeagueCohortEntry is a single row of a cohort leaderboard
+type LeagueCohortEntry struct {
+	Rank     int    `json:"rank"`
+	UserUUID string `json:"-"`
+	UserName string `json:"userName"`
+	Points   int64  `json:"points"`
+	Outcome  string `json:"outcome,omitempty"`
+}
+
+// LeagueCohortResponse is a cohort leaderboard
+type LeagueCohortResponse struct {
+	Cohort  LeagueCohort        `json:"cohort"`
+	Entries []LeagueCohortEntry `json:"entries"`
+}
+
+// UserLeagueResponse describes the caller's league and current cohort standings
+type UserLeagueResponse struct {
+	Tier    LeagueTier          `json:"tier"`
+	Rank    int                 `json:"rank"`
+	Points  int64               `json:"points"`
+	Cohort  LeagueCohort        `json:"cohort"`
+	Entries []LeagueCohortEntry `json:"entries"`
+}
@@ -0,0 +1,55 @@
+package domain
+
+// LoginRewardDay is a day of the login reward calendar; the calendar starts over after its last day
+type LoginRewardDay struct {
+	Day          int    `json:"day"`
+	PrizeValueID int    `json:"prizeValueId"`
+	Reward       string `json:"reward"` // prize value label
+	RewardType   string `json:"rewardType"`
+}
+
+// LoginStreak is a user's run of consecutive daily check-ins. Days are YYYY-MM-DD in the user's time zone.
+type LoginStreak struct {
+	UserUUID       string
+	CurrentStreak  int
+	BestStreak     int
+	LastCheckinDay string // "" before the first check-in
+	FreezesUsed    int
+}
+
+// LoginCheckIn is a day the user checked in and the calendar reward claimed for it
+type LoginCheckIn struct {
+	UserUUID     string `json:"-"`
+	Day          string `json:"day"`
+	StreakDay    int    `json:"streakDay"`
+	CalendarDay  int    `json:"calendarDay"`
+	PrizeValueID int    `json:"prizeValueId"`
+	FreezesUsed  int    `json:"freezesUsed"`
+	GotPrizeID   *int   `json:"gotPrizeId,omitempty"`
+	RewardedAt   *int64 `json:"rewardedAt,omitempty"` // nil until the reward was granted
+	CreatedAt    int64  `json:"createdAt"`
+}
+
+// LoginStreakResponse is a user's login streak as of today
+type LoginStreakResponse struct {
+	Timezone         string           `json:"timezone"`
+	Today            string           `json:"today"`
+	CurrentStreak    int              `json:"currentStreak"` // 0 once a missed day can't be covered by freezes
+	BestStreak       int              `json:"bestStreak"`
+	ClaimedToday     bool             `json:"claimedToday"`
+	NextStreakDay    int              `json:"nextStreakDay"` // streak day of the next check-in
+	NextReward       *LoginRewardDay  `json:"nextReward,omitempty"`
+	MissedDays       int              `json:"missedDays"` // days since the last check-in the next check-in uses freezes for
+	FreezesAvailable int              `json:"freezesAvailable"`
+	ResetsAt         int64            `json:"resetsAt"` // next midnight in the user's time zone
+	Calendar         []LoginRewardDay `json:"calendar"`
+}
+
+// LoginStreakClaimResult is today's check-in and the reward granted for it
+type LoginStreakClaimResult struct {
+	CheckIn       LoginCheckIn   `json:"checkIn"`
+	CurrentStreak int            `json:"currentStreak"`
+	BestStreak    int            `json:"bestStreak"`
+	Prize         *Prize         `json:"prize"`
+	Reward        *GrantedReward `json:"reward"`
+}
@@ -0,0 +1,17 @@
+package domain
+
+// Notification producers
+const (
+	NotificationProducerEventFinalizer = "event_finalizer"
+	NotificationProducerSquadFinalizer = "squad_finalizer"
+	NotificationProducerDuels          = "duels"
+	NotificationProducerAchievements   = "achievements"
+	NotificationProducerLevels         = "levels"
+)
+
+// Notification is a message queued for delivery to a user (notifications table, status CREATED).
+type Notification struct {
+	UserUUID string `json:"userUUID"`
+	Producer string `json:"producer"`
+	Message  string `json:"message"`
+}
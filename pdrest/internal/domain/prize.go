@@ -7,6 +7,11 @@ const (
 	PrizeTypeRouletteOnStart     PrizeType = "roulette_on_start"
 	PrizeTypeRouletteDuringEvent PrizeType = "roulette_during_event"
 	PrizeTypeEventReward         PrizeType = "event_reward"
+	PrizeTypeSeasonReward        PrizeType = "season_reward"
+	PrizeTypeLeagueReward        PrizeType = "league_reward"
+	PrizeTypeSquadReward         PrizeType = "squad_reward"
+	PrizeTypeQuestReward         PrizeType = "quest_reward"
+	PrizeTypeLoginReward         PrizeType = "login_reward"
 )
 
 // Prize represents a prize awarded to a user
@@ -9,4 +9,25 @@ type PrizeValue struct {
 	SegmentID *string `json:"segment_id,omitempty"` // Optional segment ID for roulette wheel
 	CreatedAt int64   `json:"created_at"`
 	UpdatedAt int64   `json:"updated_at"`
+
+	RewardType   string       `json:"reward_type"`   // points (Value), spins, boost or badge
+	RewardParams RewardParams `json:"reward_params"` // parameters of non-points rewards
+
+	Weight      int  `json:"weight"`                 // relative roulette weight
+	TotalBudget *int `json:"total_budget,omitempty"` // max roulette wins overall, nil = unlimited
+	DailyBudget *int `json:"daily_budget,omitempty"` // max roulette wins per UTC day, nil = unlimited
+}
+
+// PrizeSegmentTieSplit marks the prize values created for the share of users who split tied reward tiers
+const PrizeSegmentTieSplit = "tie_split"
+
+// Budgeted reports whether the prize value has a roulette budget cap
+func (pv *PrizeValue) Budgeted() bool {
+	return pv.TotalBudget != nil || pv.DailyBudget != nil
+}
+
+// PrizeValueUsage is how often a budgeted prize value was won by roulette spins
+type PrizeValueUsage struct {
+	Total int // all time
+	Today int // current UTC day
 }
@@ -0,0 +1,67 @@
+package domain
+
+// Profile limits
+const (
+	ProfileShowcaseSize      = 3
+	ProfileDisplayNameMinLen = 3
+	ProfileDisplayNameMaxLen = 24
+	ProfileAnonymousName     = "Anonymous"
+)
+
+// ProfileSettings are the profile fields a user controls
+type ProfileSettings struct {
+	DisplayName *string `json:"displayName,omitempty"`
+	HideName    bool    `json:"hideName"`
+	HideStats   bool    `json:"hideStats"`
+}
+
+// UpdateProfileSettingsRequest changes the given settings; an empty displayName clears it
+type UpdateProfileSettingsRequest struct {
+	DisplayName *string `json:"displayName"`
+	HideName    *bool   `json:"hideName"`
+	HideStats   *bool   `json:"hideStats"`
+}
+
+// SetShowcaseRequest sets the claimed achievements shown on the profile, in order
+type SetShowcaseRequest struct {
+	AchievementIDs []string `json:"achievementIds"`
+}
+
+// ShowcaseBadge is a claimed achievement shown on a profile
+type ShowcaseBadge struct {
+	Position      int    `json:"position"`
+	AchievementID string `json:"achievementId"`
+	Title         string `json:"title"`
+	Badge         string `json:"badge"`
+	ImageURL      string `json:"imageUrl"`
+}
+
+// ProfileStats are a user's lifetime stats
+type ProfileStats struct {
+	BetsPlaced          int     `json:"betsPlaced"`
+	Wins                int     `json:"wins"`
+	Losses              int     `json:"losses"`
+	WinRate             float64 `json:"winRate"` // wins / settled bets, 0..1
+	BestWinStreak       int     `json:"bestWinStreak"`
+	TotalPoints         int64   `json:"totalPoints"`
+	EventsJoined        int     `json:"eventsJoined"`
+	AchievementsClaimed int     `json:"achievementsClaimed"`
+}
+
+// PublicProfile is a user's profile as other users see it; NameHidden and StatsHidden tell the viewer which
+// parts the user hides. The owner sees everything and their Settings.
+type PublicProfile struct {
+	UserID      string           `json:"userID"`
+	Username    *string          `json:"username,omitempty"`
+	NameHidden  bool             `json:"nameHidden,omitempty"`
+	Showcase    []ShowcaseBadge  `json:"showcase"`
+	Badges      []UserBadge      `json:"badges"`
+	StatsHidden bool             `json:"statsHidden,omitempty"`
+	Stats       *ProfileStats    `json:"stats,omitempty"`
+	Rank        int              `json:"rank,omitempty"` // place in the global rating
+	Level       int              `json:"level,omitempty"`
+	XP          int64            `json:"xp,omitempty"`
+	League      LeagueTier       `json:"league,omitempty"`
+	SkillRating *SkillRating     `json:"skillRating,omitempty"`
+	Settings    *ProfileSettings `json:"settings,omitempty"`
+}
@@ -0,0 +1,83 @@
+package domain
+
+// Quest periods
+const (
+	QuestPeriodDaily  = "daily"  // resets at midnight in the user's time zone
+	QuestPeriodWeekly = "weekly" // resets at Monday midnight in the user's time zone
+)
+
+// Quest statuses, derived from the quest's progress and claim
+const (
+	QuestStatusActive    = "active"
+	QuestStatusCompleted = "completed" // can be claimed
+	QuestStatusClaimed   = "claimed"
+)
+
+// QuestTemplate is a quest users can be given. Metric is one of the achievement metrics bets_placed, wins,
+// win_streak and referrals, counted within the quest's period.
+type QuestTemplate struct {
+	ID           string                 `json:"id"`
+	Period       string                 `json:"period"`
+	Title        string                 `json:"title"`
+	Desc         string                 `json:"desc"`
+	Metric       string                 `json:"metric"`
+	Threshold    int                    `json:"threshold"`
+	Filters      AchievementRuleFilters `json:"filters"`
+	PrizeValueID int                    `json:"prizeValueId"`
+}
+
+// UserQuest is a quest given to a user for one period
+type UserQuest struct {
+	ID           int                    `json:"id"`
+	UserUUID     string                 `json:"-"`
+	TemplateID   string                 `json:"templateId"`
+	Period       string                 `json:"period"`
+	Title        string                 `json:"title"`
+	Desc         string                 `json:"desc"`
+	Metric       string                 `json:"metric"`
+	Filters      AchievementRuleFilters `json:"-"`
+	Progress     int                    `json:"progress"`
+	NeedSteps    int                    `json:"needSteps"`
+	CurrentRun   int                    `json:"-"`
+	Status       string                 `json:"status"`
+	Reward       string                 `json:"reward"` // prize value label
+	PrizeValueID int                    `json:"-"`
+	PeriodStart  int64                  `json:"periodStart"`
+	ResetsAt     int64                  `json:"resetsAt"` // end of the period
+	CompletedAt  *int64                 `json:"completedAt,omitempty"`
+	ClaimedAt    *int64                 `json:"claimedAt,omitempty"`
+}
+
+// UserTimezone is the time zone a user's days and weeks run in and a requested change, which applies from the
+// next daily reset of the current zone
+type UserTimezone struct {
+	Timezone        string `json:"timezone"`
+	PendingTimezone string `json:"pendingTimezone,omitempty"`
+	PendingFrom     int64  `json:"pendingFrom,omitempty"`
+}
+
+// QuestsResponse lists a user's quests of the current periods
+type QuestsResponse struct {
+	UserTimezone
+	Quests []UserQuest `json:"quests"`
+}
+
+// QuestProgressDelta moves a user quest's progress; for streaks, Increment is added to the current run
+type QuestProgressDelta struct {
+	UserQuestID int
+	Increment   int
+	Streak      bool
+	ResetRun    bool
+}
+
+// QuestClaimResult is a claimed quest and the reward granted for it
+type QuestClaimResult struct {
+	Quest  UserQuest      `json:"quest"`
+	Prize  *Prize         `json:"prize"`
+	Reward *GrantedReward `json:"reward"`
+}
+
+// SetTimezoneRequest sets the IANA time zone quests reset in, e.g. "Europe/Berlin"
+type SetTimezoneRequest struct {
+	Timezone string `json:"timezone"`
+}
@@ -8,6 +8,10 @@ const (
 	RatingSourceBetBonus     RatingSource = "bet_bonus"
 	RatingSourcePromoBonus   RatingSource = "promo_bonus"
 	RatingSourceServiceBonus RatingSource = "servivce_bonus"
+	RatingSourceReferral     RatingSource = "referral"
+	RatingSourceEntryFee     RatingSource = "entry_fee"  // event entry fees and their refunds
+	RatingSourcePrizePool    RatingSource = "prize_pool" // event prize pool payouts
+	RatingSourceDuel         RatingSource = "duel"       // duel stakes, pots and refunds
 )
 
 // RatingTotals aggregates USDT points (1 USDT = 1 point) per source for a user.
@@ -38,6 +42,7 @@ type GlobalRatingEntry struct {
 
 // FriendRatingEntry represents aggregated points for a referred friend.
 type FriendRatingEntry struct {
+	UserUUID string `json:"userUuid"` // e.g. to challenge the friend to a duel
 	UserName string `json:"userName"`
 	Value    int64  `json:"value"`
 }
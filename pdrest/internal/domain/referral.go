@@ -0,0 +1,63 @@
+package domain
+
+// ReferralRewardType distinguishes referral payouts.
+type ReferralRewardType string
+
+const (
+	ReferralRewardSignup   ReferralRewardType = "signup"
+	ReferralRewardWinnings ReferralRewardType = "winnings"
+)
+
+// ReferralReward is a single payout to a referrer for an invitee's activity.
+type ReferralReward struct {
+	BeneficiaryUUID string
+	InviteeUUID     string
+	Level           int
+	RewardType      ReferralRewardType
+	BetID           *int
+	Points          int64
+	Description     string
+}
+
+// ReferralLevelStats aggregates invitees and earnings for one referral level.
+type ReferralLevelStats struct {
+	Level    int   `json:"level"`
+	Invitees int   `json:"invitees"`
+	Earnings int64 `json:"earnings"`
+}
+
+// ReferralStatsResponse summarises a user's referrals.
+type ReferralStatsResponse struct {
+	InviteCount      int                  `json:"inviteCount"`
+	ActiveInvitees   int                  `json:"activeInvitees"`
+	LifetimeEarnings int64                `json:"lifetimeEarnings"`
+	Levels           []ReferralLevelStats `json:"levels"`
+}
+
+// ReferralCode is an extra named referral code (e.g. per channel or influencer) owned by a user.
+type ReferralCode struct {
+	Code           string `json:"code"`
+	OwnerUUID      string `json:"-"`
+	Name           string `json:"name"`
+	ExpiresAt      *int64 `json:"expiresAt,omitempty"`
+	MaxUses        *int   `json:"maxUses,omitempty"`
+	CreatedByAdmin bool   `json:"createdByAdmin"`
+	CreatedAt      int64  `json:"createdAt"`
+}
+
+// ReferralCodeStats reports signups and conversions (invitees who claimed a bet) per code.
+type ReferralCodeStats struct {
+	ReferralCode
+	IsMain      bool `json:"isMain"`
+	Signups     int  `json:"signups"`
+	Conversions int  `json:"conversions"`
+}
+
+// CreateReferralCodeRequest creates a campaign code; an empty code is generated.
+type CreateReferralCodeRequest struct {
+	OwnerUUID string `json:"ownerUUID,omitempty"` // admin only
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	ExpiresAt *int64 `json:"expiresAt,omitempty"`
+	MaxUses   *int   `json:"maxUses,omitempty"`
+}
@@ -0,0 +1,50 @@
+package domain
+
+// Reward types of a prize value
+const (
+	RewardTypePoints = "points"
+	RewardTypeSpins  = "spins"
+	RewardTypeBoost  = "boost"
+	RewardTypeBadge  = "badge"
+	RewardTypeItem   = "item"
+)
+
+// RewardParams configures a prize value's reward; which fields apply depends on the reward type
+type RewardParams struct {
+	RouletteConfigID int     `json:"rouletteConfigId,omitempty"` // spins: roulette the spins are added to
+	Spins            int     `json:"spins,omitempty"`            // spins: number of extra spins
+	Multiplier       float64 `json:"multiplier,omitempty"`       // boost: payout multiplier, e.g. 2 for double points
+	Bets             int     `json:"bets,omitempty"`             // boost: number of bets the boost applies to
+	DurationHours    int     `json:"durationHours,omitempty"`    // boost, item: how long it lasts once activated
+	ItemType         string  `json:"itemType,omitempty"`         // item: inventory item type
+	Quantity         int     `json:"quantity,omitempty"`         // item: uses (bets or spins)
+	BadgeID          string  `json:"badgeId,omitempty"`          // badge
+	Title            string  `json:"title,omitempty"`            // badge: title shown on the profile
+	ImageURL         string  `json:"imageUrl,omitempty"`         // badge
+}
+
+// GrantedReward describes what claiming a prize gave the user
+type GrantedReward struct {
+	Type             string  `json:"type"`
+	Description      string  `json:"description"`
+	Points           int64   `json:"points,omitempty"`
+	RouletteConfigID int     `json:"rouletteConfigId,omitempty"`
+	Spins            int     `json:"spins,omitempty"`
+	Multiplier       float64 `json:"multiplier,omitempty"`
+	ItemID           int     `json:"itemId,omitempty"` // boost, item: the inventory item granted
+	ItemType         string  `json:"itemType,omitempty"`
+	Quantity         int     `json:"quantity,omitempty"`
+	BadgeID          string  `json:"badgeId,omitempty"`
+	Title            string  `json:"title,omitempty"`
+	ImageURL         string  `json:"imageUrl,omitempty"`
+	AlreadyOwned     bool    `json:"alreadyOwned,omitempty"` // badge the user already had
+}
+
+// UserBadge is a badge or title shown on a user's profile
+type UserBadge struct {
+	BadgeID    string `json:"badgeId"`
+	Title      string `json:"title"`
+	ImageURL   string `json:"imageUrl,omitempty"`
+	GotPrizeID *int   `json:"gotPrizeId,omitempty"`
+	AwardedAt  int64  `json:"awardedAt"`
+}
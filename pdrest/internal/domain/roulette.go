@@ -10,13 +10,31 @@ const (
 
 // RouletteConfig represents the configuration for a roulette
 type RouletteConfig struct {
-	ID        int          `json:"id"`
-	Type      RouletteType `json:"type"`
-	EventID   string       `json:"event_id"` // Required foreign key to all_events (startup for on_start, specific event for during_event)
-	MaxSpins  int          `json:"max_spins"`
-	IsActive  bool         `json:"is_active"`
-	CreatedAt int64        `json:"created_at"`
-	UpdatedAt int64        `json:"updated_at"`
+	ID         int                 `json:"id"`
+	Type       RouletteType        `json:"type"`
+	EventID    string              `json:"event_id"` // Required foreign key to all_events (startup for on_start, specific event for during_event)
+	MaxSpins   int                 `json:"max_spins"`
+	IsActive   bool                `json:"is_active"`
+	Guarantees []RouletteGuarantee `json:"guarantees,omitempty"`
+	CreatedAt  int64               `json:"created_at"`
+	UpdatedAt  int64               `json:"updated_at"`
+}
+
+// RouletteGuarantee makes spin number Spin of a roulette land a prize worth at least MinValue, unless one of
+// the roulette's earlier spins already did
+type RouletteGuarantee struct {
+	Spin     int   `json:"spin"`
+	MinValue int64 `json:"minValue"`
+}
+
+// RouletteConfigRequest creates or replaces a roulette config through the admin API. IsActive defaults to true
+// on create and is kept on update when omitted; guarantees are replaced only when present.
+type RouletteConfigRequest struct {
+	Type       RouletteType        `json:"type"`
+	EventID    string              `json:"eventId"`
+	MaxSpins   int                 `json:"maxSpins"`
+	IsActive   *bool               `json:"isActive,omitempty"`
+	Guarantees []RouletteGuarantee `json:"guarantees,omitempty"`
 }
 
 // RoulettePreauthToken represents a preauth token for roulette
@@ -90,3 +108,47 @@ type GetRouletteStatusResponse struct {
 	PrizeTaken     bool            `json:"prize_taken"`
 	PreauthToken   string          `json:"preauth_token,omitempty"`
 }
+
+// RouletteSimulationPrize is a prize value of a simulated configuration; nil budgets are unlimited
+type RouletteSimulationPrize struct {
+	PrizeValueID int    `json:"prizeValueId,omitempty"`
+	Label        string `json:"label"`
+	Value        int64  `json:"value"`
+	Weight       int    `json:"weight"`
+	TotalBudget  *int   `json:"totalBudget,omitempty"`
+	DailyBudget  *int   `json:"dailyBudget,omitempty"`
+}
+
+// RouletteSimulationRequest simulates spins of a roulette config, or of the given prizes and guarantees when
+// set. Spins are grouped into roulettes of MaxSpins spins and into days of SpinsPerDay spins for daily budgets.
+type RouletteSimulationRequest struct {
+	RouletteConfigID int                       `json:"rouletteConfigId,omitempty"`
+	Prizes           []RouletteSimulationPrize `json:"prizes,omitempty"`
+	Guarantees       []RouletteGuarantee       `json:"guarantees,omitempty"`
+	MaxSpins         int                       `json:"maxSpins,omitempty"`
+	Spins            int                       `json:"spins,omitempty"`
+	SpinsPerDay      int                       `json:"spinsPerDay,omitempty"`
+}
+
+// RouletteSimulationPrizeResult is how often a prize was landed in a simulation
+type RouletteSimulationPrizeResult struct {
+	RouletteSimulationPrize
+	Probability     float64 `json:"probability"` // weight share, ignoring budgets and guarantees
+	Hits            int     `json:"hits"`
+	HitRate         float64 `json:"hitRate"`
+	ExhaustedAtSpin int     `json:"exhaustedAtSpin,omitempty"` // spin the total budget ran out at
+}
+
+// RouletteSimulationResult is the expected payout of a roulette configuration
+type RouletteSimulationResult struct {
+	RouletteConfigID       int                             `json:"rouletteConfigId,omitempty"`
+	Spins                  int                             `json:"spins"`
+	MaxSpins               int                             `json:"maxSpins"`
+	SpinsPerDay            int                             `json:"spinsPerDay"`
+	Guarantees             []RouletteGuarantee             `json:"guarantees"`
+	ExpectedPayoutPerSpin  float64                         `json:"expectedPayoutPerSpin"` // by weights alone
+	SimulatedPayoutPerSpin float64                         `json:"simulatedPayoutPerSpin"`
+	NoPrizeSpins           int                             `json:"noPrizeSpins"` // spins with every prize exhausted
+	TotalPayout            int64                           `json:"totalPayout"`
+	Prizes                 []RouletteSimulationPrizeResult `json:"prizes"`
+}
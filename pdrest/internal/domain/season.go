@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+// SeasonStatus represents the status of a season
+type SeasonStatus string
+
+const (
+	SeasonStatusActive   SeasonStatus = "active"
+	SeasonStatusFinished SeasonStatus = "finished"
+)
+
+// SeasonEventID is the all_events row holding season prize values
+const SeasonEventID = "season"
+
+// Season is a time window in which season points are counted from zero
+type Season struct {
+	ID          int          `json:"id"`
+	Title       string       `json:"title"`
+	StartTime   time.Time    `json:"startTime"`
+	EndTime     time.Time    `json:"endTime"`
+	Status      SeasonStatus `json:"status"`
+	FinalizedAt *time.Time   `json:"finalizedAt,omitempty"`
+}
+
+// SeasonUnpaidResult is an archived result of a finished season whose place is due a season-end prize
+// that hasn't been paid yet
+type SeasonUnpaidResult struct {
+	SeasonID     int
+	SeasonTitle  string
+	UserUUID     string
+	Rank         int
+	PrizeValueID int
+}
+
+// SeasonStandingEntry is a single row of season standings (live or archived)
+type SeasonStandingEntry struct {
+	Rank       int     `json:"rank"`
+	UserUUID   string  `json:"-"`
+	UserName   string  `json:"userName"`
+	Value      int64   `json:"value"`
+	PrizeValue *string `json:"prizeValue,omitempty"`
+}
+
+// CurrentSeasonResponse describes the running season and its live standings
+type CurrentSeasonResponse struct {
+	Season    Season                `json:"season"`
+	Standings []SeasonStandingEntry `json:"standings"`
+}
+
+// SeasonResultsResponse describes archived standings of a finished season
+type SeasonResultsResponse struct {
+	Season  Season                `json:"season"`
+	Results []SeasonStandingEntry `json:"results"`
+}
@@ -0,0 +1,38 @@
+package domain
+
+// Skill rating defaults for users without settled bets
+const (
+	SkillRatingDefault    = 1500.0
+	SkillDeviationDefault = 350.0
+)
+
+// SkillRating is a user's Glicko-style prediction skill rating
+type SkillRating struct {
+	UserUUID  string  `json:"-"`
+	Rating    float64 `json:"rating"`
+	Deviation float64 `json:"deviation"`
+	Games     int     `json:"games"`
+	UpdatedAt int64   `json:"updatedAt,omitempty"`
+}
+
+// SkillRatingUpdate is the rating change caused by one settled bet
+type SkillRatingUpdate struct {
+	BetID           int
+	UserUUID        string
+	GamesBefore     int
+	RatingBefore    float64
+	RatingAfter     float64
+	DeviationBefore float64
+	DeviationAfter  float64
+	Score           float64
+	Weight          float64
+}
+
+// SkillLeaderboardEntry is a single row of the skill rating leaderboard
+type SkillLeaderboardEntry struct {
+	Rank      int     `json:"rank"`
+	UserName  string  `json:"userName"`
+	Rating    float64 `json:"rating"`
+	Deviation float64 `json:"deviation"`
+	Games     int     `json:"games"`
+}
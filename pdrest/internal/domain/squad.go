@@ -0,0 +1,62 @@
+package domain
+
+// Squad is a team of users competing together in an event tagged "squad"
+type Squad struct {
+	ID          int           `json:"id"`
+	EventID     string        `json:"eventId"`
+	Name        string        `json:"name"`
+	InviteCode  string        `json:"inviteCode,omitempty"` // shown to members only
+	CaptainUUID string        `json:"-"`
+	MaxSize     int           `json:"maxSize"`
+	MemberCount int           `json:"memberCount"`
+	Members     []SquadMember `json:"members,omitempty"` // shown to members only
+	CreatedAt   int64         `json:"createdAt"`
+}
+
+// SquadMember is a member of a squad with the bet points they added to the squad's score
+type SquadMember struct {
+	UserUUID  string `json:"userUuid,omitempty"` // shown to members only
+	UserName  string `json:"userName"`
+	Captain   bool   `json:"captain"`
+	NetPoints int64  `json:"netPoints"`
+	JoinedAt  int64  `json:"joinedAt"`
+}
+
+// SquadStanding is a squad's row of a squad leaderboard, live or frozen
+type SquadStanding struct {
+	Rank             int    `json:"rank"`
+	SquadID          int    `json:"squadId"`
+	Name             string `json:"name"`
+	MemberCount      int    `json:"memberCount"`
+	NetPoints        int64  `json:"netPoints"` // sum of the members' bet points
+	WinCount         int    `json:"winCount"`
+	LossCount        int    `json:"lossCount"`
+	LastScoredAt     int64  `json:"-"` // Unix ms; equal points rank by who reached them first
+	PrizeValueID     *int   `json:"-"`
+	PrizeLabel       string `json:"prizeLabel,omitempty"`       // what the squad won, e.g. "50 USDT"
+	MemberPrizeLabel string `json:"memberPrizeLabel,omitempty"` // each member's share, e.g. "10 USDT"
+}
+
+// SquadLeaderboardResponse is a page of an event's squad leaderboard plus the caller's squad
+type SquadLeaderboardResponse struct {
+	EventID string          `json:"eventId"`
+	Final   bool            `json:"final"` // true once the standings are frozen
+	Entries []SquadStanding `json:"entries"`
+	Me      *SquadStanding  `json:"me,omitempty"`
+}
+
+// CreateSquadRequest creates a squad in an event; the caller becomes its captain
+type CreateSquadRequest struct {
+	EventID string `json:"eventId"`
+	Name    string `json:"name"`
+}
+
+// JoinSquadRequest joins the squad with the invite code
+type JoinSquadRequest struct {
+	InviteCode string `json:"inviteCode"`
+}
+
+// RemoveSquadMemberRequest removes a member from the captain's squad
+type RemoveSquadMemberRequest struct {
+	UserUUID string `json:"userUuid"`
+}
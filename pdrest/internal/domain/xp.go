@@ -0,0 +1,73 @@
+package domain
+
+// XP sources
+const (
+	XPSourceBetPlaced            = "bet_placed"            // a bet of the user settled
+	XPSourceBetWon               = "bet_won"               // a bet of the user settled as a win
+	XPSourceDailyLogin           = "daily_login"           // the user's first sign-in of a UTC day
+	XPSourceEventJoined          = "event_joined"          // the user took a place in an event
+	XPSourceAchievementCompleted = "achievement_completed" // the user completed an achievement
+)
+
+// XPWeights is the XP granted per source; a source with weight 0 grants nothing
+type XPWeights struct {
+	BetPlaced            int
+	BetWon               int
+	DailyLogin           int
+	EventJoined          int
+	AchievementCompleted int
+}
+
+// Level is a step of the level curve and the perks it unlocks
+type Level struct {
+	Level      int        `json:"level"`
+	XPRequired int64      `json:"xpRequired"` // total XP needed to reach the level
+	Title      string     `json:"title"`
+	Perks      LevelPerks `json:"perks"`
+}
+
+// LevelPerks are the perks of a level
+type LevelPerks struct {
+	MaxStake   *float64 `json:"maxStake,omitempty"` // max bet sum in USDT; nil = no limit
+	ExtraSpins int      `json:"extraSpins"`         // added to every roulette's spin limit
+}
+
+// UserLevel is a user's XP and level
+type UserLevel struct {
+	XP          int64      `json:"xp"`
+	Level       int        `json:"level"`
+	Title       string     `json:"title"`
+	LevelXP     int64      `json:"levelXp"`               // XP the current level starts at
+	NextLevelXP *int64     `json:"nextLevelXp,omitempty"` // XP the next level starts at; nil at the top level
+	Perks       LevelPerks `json:"perks"`
+}
+
+// XPGrant is XP granted to a user for one source; SourceRef identifies the source (bet ID, UTC date, event ID,
+// achievement ID) so it is granted once
+type XPGrant struct {
+	UserUUID  string
+	Source    string
+	SourceRef string
+	XP        int
+}
+
+// XPGrantResult is the outcome of a grant; Granted is false when the source was already granted
+type XPGrantResult struct {
+	Granted  bool
+	XP       int64 // total XP after the grant
+	OldLevel int
+	NewLevel int
+}
+
+// XPLedgerEntry is a row of a user's XP history
+type XPLedgerEntry struct {
+	Source    string `json:"source"`
+	SourceRef string `json:"sourceRef"`
+	XP        int    `json:"xp"`
+	CreatedAt int64  `json:"createdAt"`
+}
+
+// XPHistoryResponse is a page of a user's XP history
+type XPHistoryResponse struct {
+	Entries []XPLedgerEntry `json:"entries"`
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// maxAchievementCatalogSize caps an uploaded achievement sheet
+const maxAchievementCatalogSize = 1 << 20
+
+// AdminImportAchievements imports the achievement sheet CSV, sent as the request body or as the multipart
+// field "file" (requires X-ADMIN-TOKEN). With ?dryRun=true only the diff against the database is returned.
+func (h *HTTPHandler) AdminImportAchievements(c echo.Context) error {
+	if ok, err := h.requireAchievementAdmin(c); !ok {
+		return err
+	}
+
+	var body io.Reader = c.Request().Body
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "file is required"})
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid file"})
+		}
+		defer file.Close()
+		body = file
+	}
+	data, err := io.ReadAll(io.LimitReader(body, maxAchievementCatalogSize+1))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if len(data) > maxAchievementCatalogSize {
+		return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": "achievement catalog is too large"})
+	}
+
+	dryRun := strings.EqualFold(c.QueryParam("dryRun"), "true")
+	result, err := h.achievementService.ImportAchievementCatalog(c.Request().Context(), bytes.NewReader(data), dryRun)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	if !dryRun {
+		log.Printf("admin/achievements: imported catalog, %d created, %d updated", result.Created, result.Updated)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// AdminExportAchievements returns the achievement catalog as the achievement sheet CSV (requires X-ADMIN-TOKEN)
+func (h *HTTPHandler) AdminExportAchievements(c echo.Context) error {
+	if ok, err := h.requireAchievementAdmin(c); !ok {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := h.achievementService.ExportAchievementCatalog(c.Request().Context(), &buf); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="achievements.csv"`)
+	return c.Blob(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+func (h *HTTPHandler) requireAchievementAdmin(c echo.Context) (bool, error) {
+	if h.achievementService == nil {
+		return false, c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for achievements"})
+	}
+	if err := requireAdminToken(c); err != nil {
+		log.Printf("admin/achievements: %v", err)
+		return false, c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+	}
+	return true, nil
+}
@@ -0,0 +1,215 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"pdrest/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CreateDuel proposes a duel to a friend or, without an opponent, by invite link
+func (h *HTTPHandler) CreateDuel(c echo.Context) error {
+	if h.duelService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for duels"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	var req domain.CreateDuelRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	duel, err := h.duelService.CreateDuel(c.Request().Context(), userUUID, &req)
+	if err != nil {
+		return c.JSON(duelErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, duel)
+}
+
+// UserDuels lists the caller's duels, newest first
+func (h *HTTPHandler) UserDuels(c echo.Context) error {
+	if h.duelService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for duels"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	limit, offset := parsePagination(c)
+	duels, err := h.duelService.GetUserDuels(c.Request().Context(), userUUID, limit, offset)
+	if err != nil {
+		return c.JSON(duelErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"duels": duels})
+}
+
+// Duel returns one of the caller's duels
+func (h *HTTPHandler) Duel(c echo.Context) error {
+	if h.duelService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for duels"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	duelID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || duelID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid duel id"})
+	}
+
+	duel, err := h.duelService.GetDuel(c.Request().Context(), userUUID, duelID)
+	if err != nil {
+		return c.JSON(duelErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, duel)
+}
+
+// DuelInvite returns the duel behind an invite link so its terms can be shown before accepting
+func (h *HTTPHandler) DuelInvite(c echo.Context) error {
+	if h.duelService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for duels"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	duel, err := h.duelService.GetDuelByInviteCode(c.Request().Context(), userUUID, c.Param("code"))
+	if err != nil {
+		return c.JSON(duelErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, duel)
+}
+
+// AcceptDuel accepts a duel the caller was challenged to
+func (h *HTTPHandler) AcceptDuel(c echo.Context) error {
+	if h.duelService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for duels"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	duelID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || duelID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid duel id"})
+	}
+
+	duel, err := h.duelService.AcceptDuel(c.Request().Context(), userUUID, duelID)
+	if err != nil {
+		return c.JSON(duelErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, duel)
+}
+
+// AcceptDuelByInvite accepts the duel behind an invite link
+func (h *HTTPHandler) AcceptDuelByInvite(c echo.Context) error {
+	if h.duelService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for duels"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	var req domain.AcceptDuelRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	duel, err := h.duelService.AcceptDuelByInviteCode(c.Request().Context(), userUUID, req.InviteCode)
+	if err != nil {
+		return c.JSON(duelErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, duel)
+}
+
+// DeclineDuel turns down a duel the caller was challenged to
+func (h *HTTPHandler) DeclineDuel(c echo.Context) error {
+	if h.duelService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for duels"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	duelID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || duelID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid duel id"})
+	}
+
+	if err := h.duelService.DeclineDuel(c.Request().Context(), userUUID, duelID); err != nil {
+		return c.JSON(duelErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"status": domain.DuelDeclined})
+}
+
+// CancelDuel withdraws a duel the caller proposed
+func (h *HTTPHandler) CancelDuel(c echo.Context) error {
+	if h.duelService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for duels"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	duelID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || duelID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid duel id"})
+	}
+
+	if err := h.duelService.CancelDuel(c.Request().Context(), userUUID, duelID); err != nil {
+		return c.JSON(duelErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"status": domain.DuelCancelled})
+}
+
+func duelErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "only the"),
+		strings.Contains(err.Error(), "meant for another user"),
+		strings.Contains(err.Error(), "must be a friend"),
+		strings.Contains(err.Error(), "not enough points"):
+		return http.StatusForbidden
+	case strings.Contains(err.Error(), "already"),
+		strings.Contains(err.Error(), "no longer pending"),
+		strings.Contains(err.Error(), "expired"):
+		return http.StatusConflict
+	case strings.Contains(err.Error(), "required"),
+		strings.Contains(err.Error(), "must"),
+		strings.Contains(err.Error(), "not supported"),
+		strings.Contains(err.Error(), "cannot"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,199 @@
+package http
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"pdrest/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AdminEvents lists events (requires X-ADMIN-TOKEN); archived events are included with ?archived=true
+func (h *HTTPHandler) AdminEvents(c echo.Context) error {
+	if ok, err := h.requireEventAdmin(c); !ok {
+		return err
+	}
+
+	includeArchived := strings.EqualFold(c.QueryParam("archived"), "true")
+	events, err := h.eventAdminService.GetEvents(c.Request().Context(), includeArchived)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"events": events,
+	})
+}
+
+// AdminEvent returns an event with its prize values and achievements (requires X-ADMIN-TOKEN)
+func (h *HTTPHandler) AdminEvent(c echo.Context) error {
+	if ok, err := h.requireEventAdmin(c); !ok {
+		return err
+	}
+
+	event, err := h.eventAdminService.GetEvent(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return c.JSON(adminEventErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, event)
+}
+
+// AdminCreateEvent creates an event with prize values and achievements (requires X-ADMIN-TOKEN)
+func (h *HTTPHandler) AdminCreateEvent(c echo.Context) error {
+	if ok, err := h.requireEventAdmin(c); !ok {
+		return err
+	}
+
+	var req domain.AdminEvent
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	event, err := h.eventAdminService.CreateEvent(c.Request().Context(), &req)
+	if err != nil {
+		return c.JSON(adminEventErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	log.Printf("admin/events: created event %s", event.ID)
+	return c.JSON(http.StatusOK, event)
+}
+
+// AdminUpdateEvent replaces an event; prize values are replaced only if present in the body (requires X-ADMIN-TOKEN)
+func (h *HTTPHandler) AdminUpdateEvent(c echo.Context) error {
+	if ok, err := h.requireEventAdmin(c); !ok {
+		return err
+	}
+
+	var req domain.AdminEvent
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	event, err := h.eventAdminService.UpdateEvent(c.Request().Context(), c.Param("id"), &req)
+	if err != nil {
+		return c.JSON(adminEventErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	log.Printf("admin/events: updated event %s", event.ID)
+	return c.JSON(http.StatusOK, event)
+}
+
+// AdminScheduleEvent moves an event's start and deadline (requires X-ADMIN-TOKEN)
+func (h *HTTPHandler) AdminScheduleEvent(c echo.Context) error {
+	if ok, err := h.requireEventAdmin(c); !ok {
+		return err
+	}
+
+	var req domain.ScheduleEventRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	event, err := h.eventAdminService.ScheduleEvent(c.Request().Context(), c.Param("id"), &req)
+	if err != nil {
+		return c.JSON(adminEventErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	log.Printf("admin/events: scheduled event %s (%s - %s)", event.ID, event.StartTime.Format("2006-01-02T15:04:05Z"), event.Deadline.Format("2006-01-02T15:04:05Z"))
+	return c.JSON(http.StatusOK, event)
+}
+
+// AdminArchiveEvent moves an event to the archived state (requires X-ADMIN-TOKEN)
+func (h *HTTPHandler) AdminArchiveEvent(c echo.Context) error {
+	if ok, err := h.requireEventAdmin(c); !ok {
+		return err
+	}
+
+	eventID := c.Param("id")
+	archived, err := h.eventAdminService.ArchiveEvent(c.Request().Context(), eventID)
+	if err != nil {
+		return c.JSON(adminEventErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	status := "archived"
+	if !archived {
+		status = "already_archived"
+	}
+	log.Printf("admin/events: archive event %s: %s", eventID, status)
+	return c.JSON(http.StatusOK, map[string]string{"status": status})
+}
+
+// AdminSetEventState publishes, unpublishes or archives an event (requires X-ADMIN-TOKEN)
+func (h *HTTPHandler) AdminSetEventState(c echo.Context) error {
+	if ok, err := h.requireEventAdmin(c); !ok {
+		return err
+	}
+
+	var req domain.EventStateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	eventID := c.Param("id")
+	changed, err := h.eventAdminService.SetEventState(c.Request().Context(), eventID, req.State)
+	if err != nil {
+		return c.JSON(adminEventErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	event, err := h.eventAdminService.GetEvent(c.Request().Context(), eventID)
+	if err != nil {
+		return c.JSON(adminEventErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	log.Printf("admin/events: set event %s state to %s (changed: %t)", eventID, req.State, changed)
+	return c.JSON(http.StatusOK, event)
+}
+
+// AdminCloneEvent copies an event with its prize values and achievements under a new ID (requires X-ADMIN-TOKEN)
+func (h *HTTPHandler) AdminCloneEvent(c echo.Context) error {
+	if ok, err := h.requireEventAdmin(c); !ok {
+		return err
+	}
+
+	var req domain.CloneEventRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	event, err := h.eventAdminService.CloneEvent(c.Request().Context(), c.Param("id"), &req)
+	if err != nil {
+		return c.JSON(adminEventErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	log.Printf("admin/events: cloned event %s into %s", c.Param("id"), event.ID)
+	return c.JSON(http.StatusOK, event)
+}
+
+// requireEventAdmin writes an error response and returns false if the request cannot proceed
+func (h *HTTPHandler) requireEventAdmin(c echo.Context) (bool, error) {
+	if h.eventAdminService == nil {
+		return false, c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for event management"})
+	}
+	if err := requireAdminToken(c); err != nil {
+		log.Printf("admin/events: %v", err)
+		return false, c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+	}
+	return true, nil
+}
+
+func adminEventErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "already exists"),
+		strings.Contains(err.Error(), "changed concurrently"):
+		return http.StatusConflict
+	case strings.Contains(err.Error(), "required"),
+		strings.Contains(err.Error(), "must"),
+		strings.Contains(err.Error(), "invalid"),
+		strings.Contains(err.Error(), "cannot"),
+		strings.Contains(err.Error(), "needs"),
+		strings.Contains(err.Error(), "reward for place"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
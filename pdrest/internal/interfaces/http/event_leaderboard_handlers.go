@@ -0,0 +1,45 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// EventLeaderboard returns a page of a competition leaderboard and the caller's own entry
+func (h *HTTPHandler) EventLeaderboard(c echo.Context) error {
+	if h.eventService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for events"})
+	}
+
+	// Get user UUID from context (set by JWT middleware)
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	eventID := strings.TrimSpace(c.Param("id"))
+	if eventID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "event id is required"})
+	}
+
+	limit, offset := parsePagination(c)
+
+	ctx := context.Background()
+	response, err := h.eventService.GetEventLeaderboard(ctx, eventID, userUUID, limit, offset)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		}
+		if strings.Contains(err.Error(), "required") ||
+			strings.Contains(err.Error(), "not started") ||
+			strings.Contains(err.Error(), "not a competition") {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"pdrest/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AdminEventTemplates lists recurring event templates (requires X-ADMIN-TOKEN)
+func (h *HTTPHandler) AdminEventTemplates(c echo.Context) error {
+	if ok, err := h.requireEventTemplateAdmin(c); !ok {
+		return err
+	}
+
+	templates, err := h.eventTemplateService.GetTemplates(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"templates": templates,
+	})
+}
+
+// AdminSaveEventTemplate creates or replaces a recurring event template (requires X-ADMIN-TOKEN)
+func (h *HTTPHandler) AdminSaveEventTemplate(c echo.Context) error {
+	if ok, err := h.requireEventTemplateAdmin(c); !ok {
+		return err
+	}
+
+	var req domain.EventTemplate
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	template, err := h.eventTemplateService.SaveTemplate(c.Request().Context(), c.Param("id"), &req)
+	if err != nil {
+		return c.JSON(adminEventErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	log.Printf("admin/event_templates: saved template %s", template.ID)
+	return c.JSON(http.StatusOK, template)
+}
+
+// AdminMaterializeEventTemplates creates due template instances now instead of waiting for the scheduler (requires X-ADMIN-TOKEN)
+func (h *HTTPHandler) AdminMaterializeEventTemplates(c echo.Context) error {
+	if ok, err := h.requireEventTemplateAdmin(c); !ok {
+		return err
+	}
+
+	created, err := h.eventTemplateService.Materialize(c.Request().Context(), time.Now().UTC())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	log.Printf("admin/event_templates: materialized %d events", len(created))
+	return c.JSON(http.StatusOK, domain.MaterializeTemplatesResponse{Created: created})
+}
+
+// requireEventTemplateAdmin writes an error response and returns false if the request cannot proceed
+func (h *HTTPHandler) requireEventTemplateAdmin(c echo.Context) (bool, error) {
+	if h.eventTemplateService == nil {
+		return false, c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for event templates"})
+	}
+	if err := requireAdminToken(c); err != nil {
+		log.Printf("admin/event_templates: %v", err)
+		return false, c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+	}
+	return true, nil
+}
@@ -24,34 +24,60 @@ import (
 )
 
 type HTTPHandler struct {
-	userService         *services.UserService
-	ratingService       *services.RatingService
-	eventService        *services.EventService
-	rouletteService     *services.RouletteService
-	betService          *services.BetService
-	achievementService  *services.AchievementService
-	authService         *services.AuthService
-	googleAuthService   *services.GoogleAuthService
-	googleOAuthConfig   *oauth2.Config
-	telegramAuthService *services.TelegramAuthService
-	jwtSecretKey        string
-	jwtStrictMode       bool
+	userService          *services.UserService
+	ratingService        *services.RatingService
+	eventService         *services.EventService
+	eventAdminService    *services.EventAdminService
+	eventTemplateService *services.EventTemplateService
+	rouletteService      *services.RouletteService
+	betService           *services.BetService
+	achievementService   *services.AchievementService
+	seasonService        *services.SeasonService
+	leagueService        *services.LeagueService
+	skillService         *services.SkillService
+	referralService      *services.ReferralService
+	squadService         *services.SquadService
+	duelService          *services.DuelService
+	inventoryService     *services.InventoryService
+	profileService       *services.ProfileService
+	xpService            *services.XPService
+	questService         *services.QuestService
+	loginStreakService   *services.LoginStreakService
+	authService          *services.AuthService
+	googleAuthService    *services.GoogleAuthService
+	googleOAuthConfig    *oauth2.Config
+	telegramAuthService  *services.TelegramAuthService
+	jwtSecretKey         string
+	jwtStrictMode        bool
 }
 
-func NewHTTPHandler(e *echo.Echo, userService *services.UserService, ratingService *services.RatingService, eventService *services.EventService, rouletteService *services.RouletteService, betService *services.BetService, achievementService *services.AchievementService, authService *services.AuthService, googleAuthService *services.GoogleAuthService, googleOAuthConfig *oauth2.Config, telegramAuthService *services.TelegramAuthService, jwtSecretKey string, jwtStrictMode bool) {
+func NewHTTPHandler(e *echo.Echo, userService *services.UserService, ratingService *services.RatingService, eventService *services.EventService, eventAdminService *services.EventAdminService, eventTemplateService *services.EventTemplateService, rouletteService *services.RouletteService, betService *services.BetService, achievementService *services.AchievementService, seasonService *services.SeasonService, leagueService *services.LeagueService, skillService *services.SkillService, referralService *services.ReferralService, squadService *services.SquadService, duelService *services.DuelService, inventoryService *services.InventoryService, profileService *services.ProfileService, xpService *services.XPService, questService *services.QuestService, loginStreakService *services.LoginStreakService, authService *services.AuthService, googleAuthService *services.GoogleAuthService, googleOAuthConfig *oauth2.Config, telegramAuthService *services.TelegramAuthService, jwtSecretKey string, jwtStrictMode bool) {
 	h := &HTTPHandler{
-		userService:         userService,
-		ratingService:       ratingService,
-		eventService:        eventService,
-		rouletteService:     rouletteService,
-		betService:          betService,
-		achievementService:  achievementService,
-		authService:         authService,
-		googleAuthService:   googleAuthService,
-		googleOAuthConfig:   googleOAuthConfig,
-		telegramAuthService: telegramAuthService,
-		jwtSecretKey:        jwtSecretKey,
-		jwtStrictMode:       jwtStrictMode,
+		userService:          userService,
+		ratingService:        ratingService,
+		eventService:         eventService,
+		eventAdminService:    eventAdminService,
+		eventTemplateService: eventTemplateService,
+		rouletteService:      rouletteService,
+		betService:           betService,
+		achievementService:   achievementService,
+		seasonService:        seasonService,
+		leagueService:        leagueService,
+		skillService:         skillService,
+		referralService:      referralService,
+		squadService:         squadService,
+		duelService:          duelService,
+		inventoryService:     inventoryService,
+		profileService:       profileService,
+		xpService:            xpService,
+		questService:         questService,
+		loginStreakService:   loginStreakService,
+		authService:          authService,
+		googleAuthService:    googleAuthService,
+		googleOAuthConfig:    googleOAuthConfig,
+		telegramAuthService:  telegramAuthService,
+		jwtSecretKey:         jwtSecretKey,
+		jwtStrictMode:        jwtStrictMode,
 	}
 
 	api := e.Group("/api")
@@ -62,8 +88,63 @@ func NewHTTPHandler(e *echo.Echo, userService *services.UserService, ratingServi
 	api.GET("/rate/:address", h.GetRateByAddress)
 	api.GET("/available_events", h.AvailableEvents)
 	api.GET("/globalrating", h.GlobalRating)
+	api.GET("/skillrating", h.SkillRating)
+	api.GET("/levels", h.Levels)
 	api.GET("/getidbysession", h.GetUserIDBySession)
 	api.POST("/admin/register_user", h.AdminRegisterUser)
+	api.POST("/admin/seasons", h.AdminCreateSeason)
+	api.GET("/admin/referral_codes", h.AdminReferralCodeStats)
+	api.GET("/admin/events", h.AdminEvents)
+	api.POST("/admin/events", h.AdminCreateEvent)
+	api.GET("/admin/events/:id", h.AdminEvent)
+	api.PUT("/admin/events/:id", h.AdminUpdateEvent)
+	api.POST("/admin/events/:id/schedule", h.AdminScheduleEvent)
+	api.POST("/admin/events/:id/archive", h.AdminArchiveEvent)
+	api.POST("/admin/events/:id/state", h.AdminSetEventState)
+	api.POST("/admin/events/:id/clone", h.AdminCloneEvent)
+	api.GET("/admin/event_templates", h.AdminEventTemplates)
+	api.PUT("/admin/event_templates/:id", h.AdminSaveEventTemplate)
+	api.POST("/admin/event_templates/materialize", h.AdminMaterializeEventTemplates)
+	api.POST("/admin/referral_codes", h.AdminCreateReferralCode)
+	api.POST("/admin/achievements/import", h.AdminImportAchievements)
+	api.GET("/admin/achievements/export", h.AdminExportAchievements)
+	api.POST("/admin/roulette/simulate", h.AdminSimulateRoulette)
+	api.POST("/admin/roulette/configs", h.AdminCreateRouletteConfig)
+	api.PUT("/admin/roulette/configs/:id", h.AdminUpdateRouletteConfig)
+
+	// Season endpoints
+	seasons := api.Group("/seasons")
+	seasons.GET("", h.FinishedSeasons)
+	seasons.GET("/current", h.CurrentSeason)
+	seasons.GET("/:id/results", h.SeasonResults)
+
+	// Event endpoints (protected by JWT)
+	events := api.Group("/events")
+	events.Use(JWTMiddleware(jwtSecretKey, jwtStrictMode))
+	events.GET("/:id/leaderboard", h.EventLeaderboard)
+	events.GET("/:id/squads", h.EventSquadLeaderboard)
+	events.GET("/:id/squad", h.EventSquad)
+
+	// Squad endpoints (protected by JWT)
+	squads := api.Group("/squads")
+	squads.Use(JWTMiddleware(jwtSecretKey, jwtStrictMode))
+	squads.POST("", h.CreateSquad)
+	squads.POST("/join", h.JoinSquad)
+	squads.GET("/:id", h.Squad)
+	squads.POST("/:id/leave", h.LeaveSquad)
+	squads.POST("/:id/remove_member", h.RemoveSquadMember)
+
+	// Duel endpoints (protected by JWT)
+	duels := api.Group("/duels")
+	duels.Use(JWTMiddleware(jwtSecretKey, jwtStrictMode))
+	duels.POST("", h.CreateDuel)
+	duels.GET("", h.UserDuels)
+	duels.GET("/invite/:code", h.DuelInvite)
+	duels.POST("/accept", h.AcceptDuelByInvite)
+	duels.GET("/:id", h.Duel)
+	duels.POST("/:id/accept", h.AcceptDuel)
+	duels.POST("/:id/decline", h.DeclineDuel)
+	duels.POST("/:id/cancel", h.CancelDuel)
 
 	// Documentation endpoints
 	api.GET("/docs", h.GetAPIDocumentation)
@@ -90,10 +171,23 @@ func NewHTTPHandler(e *echo.Echo, userService *services.UserService, ratingServi
 	user.Use(JWTMiddleware(jwtSecretKey, jwtStrictMode))
 	user.GET("/last_login/:uuid", h.UserLastLogin)
 	user.GET("/profile/:uuid", h.UserProfile)
+	user.GET("/profile_settings", h.UserProfileSettings)
+	user.PUT("/profile_settings", h.UpdateUserProfileSettings)
+	user.PUT("/profile/showcase", h.SetProfileShowcase)
+	user.GET("/level", h.UserLevel)
+	user.GET("/xp_history", h.UserXPHistory)
+	user.GET("/quests", h.UserQuests)
+	user.POST("/quests/:id/claim", h.ClaimQuest)
+	user.PUT("/timezone", h.SetUserTimezone)
+	user.GET("/login_streak", h.UserLoginStreak)
+	user.POST("/login_streak/claim", h.ClaimLoginStreak)
 	user.GET("/assets", h.UserAssets)
 	user.POST("/assets", h.UserAssets)
 	user.GET("/ya_referral_link", h.UserReferralLink)
 	user.GET("/friends_ratings", h.UserFriendsRatings)
+	user.GET("/referrals", h.UserReferrals)
+	user.GET("/referral_codes", h.UserReferralCodes)
+	user.POST("/referral_codes", h.CreateUserReferralCode)
 	user.GET("/achievements", h.UserAchievements)
 	user.GET("/achievement", h.UserAchievementByID)
 	user.GET("/all_achivements", h.AllAchievements)
@@ -101,6 +195,7 @@ func NewHTTPHandler(e *echo.Echo, userService *services.UserService, ratingServi
 	user.POST("/claim_achievement_prize", h.ClaimAchievement)
 	user.POST("/update_achivement_satus", h.UpdateAchievementStatus)
 	user.POST("/take_part_on_event", h.TakePartOnEvent)
+	user.POST("/leave_event", h.LeaveEvent)
 	user.POST("/update_prise_status", h.UpdateUserEventPrizeStatus)
 	user.POST("/event_progress", h.UserEventProgress)
 	user.POST("/best_in_event", h.BestInEvent)
@@ -110,6 +205,10 @@ func NewHTTPHandler(e *echo.Echo, userService *services.UserService, ratingServi
 	// user.GET("/shareresult", h.ShareResult)
 	user.POST("/claim_bet", h.ClaimBet)
 	user.GET("/unfinished_bets/:uuid", h.UnfinishedBets)
+	user.GET("/league", h.UserLeague)
+	user.GET("/league/cohort/:id", h.LeagueCohortLeaderboard)
+	user.GET("/inventory", h.UserInventory)
+	user.POST("/inventory/:id/activate", h.ActivateInventoryItem)
 
 	// Roulette endpoints
 	roulette := api.Group("/roulette")
@@ -281,11 +380,28 @@ func (h *HTTPHandler) UserProfile(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "uuid is required"})
 	}
 
+	if h.profileService != nil {
+		viewerUUID, _ := c.Get("user_uuid").(string)
+		profile, err := h.profileService.GetPublicProfile(c.Request().Context(), viewerUUID, uuid)
+		if err != nil {
+			return c.JSON(profileErrorStatus(err), map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusOK, profile)
+	}
+
 	result, err := h.userService.GetProfile(uuid)
 	if err != nil {
 		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
 	}
 
+	if h.skillService != nil {
+		skill, err := h.skillService.GetUserSkillRating(c.Request().Context(), result.UserID)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+		result.SkillRating = skill
+	}
+
 	return c.JSON(http.StatusOK, result)
 }
 
@@ -559,6 +675,11 @@ func (h *HTTPHandler) GlobalRating(c echo.Context) error {
 		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for global rating"})
 	}
 
+	// scope=season returns the current season standings instead of lifetime totals
+	if c.QueryParam("scope") == "season" {
+		return h.CurrentSeasonRating(c)
+	}
+
 	// Parse pagination parameters
 	limit := 50 // Default limit
 	if limitStr := c.QueryParam("limit"); limitStr != "" {
@@ -582,6 +703,22 @@ func (h *HTTPHandler) GlobalRating(c echo.Context) error {
 	return c.JSON(http.StatusOK, entries)
 }
 
+// SkillRating returns the skill rating leaderboard (users with at least 10 settled bets)
+func (h *HTTPHandler) SkillRating(c echo.Context) error {
+	if h.skillService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for skill rating"})
+	}
+
+	limit, offset := parsePagination(c)
+
+	entries, err := h.skillService.GetSkillLeaderboard(c.Request().Context(), limit, offset)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, entries)
+}
+
 func (h *HTTPHandler) OpenBet(c echo.Context) error {
 	if h.betService == nil {
 		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for bets"})
@@ -607,6 +744,13 @@ func (h *HTTPHandler) OpenBet(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
+	// Betting users compete in this week's league cohort
+	if h.leagueService != nil {
+		if _, err := h.leagueService.AssignUser(ctx, userUUID); err != nil {
+			log.Printf("openbet: failed to assign league cohort for %s: %v", userUUID, err)
+		}
+	}
+
 	return c.JSON(http.StatusOK, response)
 }
 
@@ -704,7 +848,7 @@ func (h *HTTPHandler) ClaimBet(c echo.Context) error {
 	}
 
 	ctx := context.Background()
-	won, err := h.betService.ClaimBet(ctx, req.BetID, userUUID)
+	_, err := h.betService.ClaimBet(ctx, req.BetID, userUUID)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
@@ -716,8 +860,9 @@ func (h *HTTPHandler) ClaimBet(c echo.Context) error {
 	}
 
 	newAchievementIDs := []string{}
-	if won && h.achievementService != nil {
-		ids, err := h.achievementService.UpdateWinAchievementsOnBet(ctx, userUUID)
+	if h.achievementService != nil {
+		// Progress was advanced by the bet's settled/claimed events; report what the bet completed.
+		ids, err := h.achievementService.GetBetAchievements(ctx, userUUID, req.BetID)
 		if err != nil {
 			// Bet claim is already completed successfully; achievement sync must not break response.
 			log.Printf("claim_bet: achievement update failed user_uuid=%s bet_id=%d: %v", userUUID, req.BetID, err)
@@ -824,6 +969,9 @@ func (h *HTTPHandler) RefreshToken(c echo.Context) error {
 	if profile == nil {
 		return c.JSON(http.StatusNotFound, map[string]string{"error": "user not found"})
 	}
+	if err := h.userService.RecordLogin(c.Request().Context(), req.UserID); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
 
 	tokenPair, err := h.authService.GenerateTokenPair(req.UserID)
 	if err != nil {
@@ -952,6 +1100,9 @@ func (h *HTTPHandler) VerifyGoogleToken(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user not found"})
 	}
+	if err := h.userService.RecordLogin(c.Request().Context(), user.UserID); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
 
 	// Generate JWT token pair for the user
 	tokenPair, err := h.authService.GenerateTokenPair(user.UserID)
@@ -1322,6 +1473,9 @@ func (h *HTTPHandler) TelegramLogin(c echo.Context) error {
 				_ = err
 			}
 		}
+		if err := h.userService.RecordLogin(ctx, existingUser.UserID); err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
 
 		tokenPair, err := h.authService.GenerateTokenPair(existingUser.UserID)
 		if err != nil {
@@ -1612,9 +1766,10 @@ func (h *HTTPHandler) AdminRegisterUser(c echo.Context) error {
 
 	if req.InviterDeeplinkRefcode != nil && strings.TrimSpace(*req.InviterDeeplinkRefcode) != "" {
 		refCode := strings.TrimSpace(*req.InviterDeeplinkRefcode)
+		// Resolves main_ref and campaign codes (add_refs); ApplyReferralCode records per-code attribution and usage caps.
 		inviterUser, findErr := h.userService.FindUserByMainRef(ctx, refCode)
 		if findErr == nil && inviterUser != nil {
-			if setErr := h.userService.SetReferrerByInviterUUID(ctx, newUserID, inviterUser.UserID); setErr != nil && !strings.Contains(setErr.Error(), "referrer already set") {
+			if setErr := h.userService.ApplyReferralCode(ctx, newUserID, refCode); setErr != nil && !strings.Contains(setErr.Error(), "referrer already set") {
 				log.Printf("admin/register_user: failed to set referrer by main_ref (new_user=%s inviter=%s): %v", newUserID, inviterUser.UserID, setErr)
 				return c.JSON(http.StatusBadRequest, map[string]string{"error": setErr.Error()})
 			}
@@ -1782,7 +1937,7 @@ func (h *HTTPHandler) ClaimAchievement(c echo.Context) error {
 	}
 
 	ctx := context.Background()
-	prize, err := h.achievementService.ClaimAchievement(ctx, userUUID, req.AchievementID)
+	result, err := h.achievementService.ClaimAchievement(ctx, userUUID, req.AchievementID)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
@@ -1795,8 +1950,9 @@ func (h *HTTPHandler) ClaimAchievement(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"status":        "claimed",
-		"prize_value":   prize.PrizeValue,
+		"prize_value":   result.Prize.PrizeValue,
 		"achievementId": req.AchievementID,
+		"reward":        result.Reward,
 	})
 }
 
@@ -1860,20 +2016,77 @@ func (h *HTTPHandler) TakePartOnEvent(c echo.Context) error {
 	}
 
 	ctx := context.Background()
-	status, err := h.eventService.TakePartOnEvent(ctx, userUUID, req.EventID)
+	result, err := h.eventService.TakePartOnEvent(ctx, userUUID, req.EventID)
 	if err != nil {
-		if strings.Contains(err.Error(), "required") || strings.Contains(err.Error(), "not found") {
-			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
-		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return c.JSON(joinEventErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	response := map[string]interface{}{
+		"status":     result.Status,
+		"eventId":    req.EventID,
+		"userStatus": result.UserStatus,
+	}
+	if result.EntryFeePaid > 0 {
+		response["entryFeePaid"] = result.EntryFeePaid
+	}
+	if result.WaitlistPlace != nil {
+		response["waitlistPlace"] = *result.WaitlistPlace
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// LeaveEvent takes the user out of an event they joined (before it starts) or wait for.
+func (h *HTTPHandler) LeaveEvent(c echo.Context) error {
+	if h.eventService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for events"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	var req struct {
+		EventID string `json:"eventId" query:"eventId"`
+	}
+	_ = c.Bind(&req)
+	if req.EventID == "" {
+		req.EventID = c.QueryParam("eventId")
+	}
+	if req.EventID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "eventId is required"})
+	}
+
+	if err := h.eventService.LeaveEvent(context.Background(), userUUID, req.EventID); err != nil {
+		return c.JSON(joinEventErrorStatus(err), map[string]string{"error": err.Error()})
 	}
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
-		"status":  status,
+		"status":  "left",
 		"eventId": req.EventID,
 	})
 }
 
+// joinEventErrorStatus maps errors of joining or leaving an event to an HTTP status.
+func joinEventErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "not eligible"),
+		strings.Contains(err.Error(), "not enough points"):
+		return http.StatusForbidden
+	case strings.Contains(err.Error(), "join window"),
+		strings.Contains(err.Error(), "has ended"),
+		strings.Contains(err.Error(), "already started"),
+		strings.Contains(err.Error(), "cannot leave"):
+		return http.StatusConflict
+	case strings.Contains(err.Error(), "required"),
+		strings.Contains(err.Error(), "not found"),
+		strings.Contains(err.Error(), "not taking part"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 func (h *HTTPHandler) UpdateUserEventPrizeStatus(c echo.Context) error {
 	if h.eventService == nil {
 		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for events"})
@@ -2204,6 +2417,37 @@ func (h *HTTPHandler) validateJWTToken(c echo.Context) (string, error) {
 	return userID, nil
 }
 
+// requireAdminToken checks the X-ADMIN-TOKEN header against ADMIN_TOKEN
+func requireAdminToken(c echo.Context) error {
+	adminToken := strings.TrimSpace(c.Request().Header.Get("X-ADMIN-TOKEN"))
+	if adminToken == "" {
+		return fmt.Errorf("X-ADMIN-TOKEN header is required")
+	}
+	expectedToken := strings.TrimSpace(os.Getenv("ADMIN_TOKEN"))
+	if expectedToken == "" || adminToken != expectedToken {
+		return fmt.Errorf("invalid admin token")
+	}
+	return nil
+}
+
+// parsePagination reads limit/offset query parameters (default limit 50, offset 0)
+func parsePagination(c echo.Context) (int, int) {
+	limit := 50 // Default limit
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	offset := 0 // Default offset
+	if offsetStr := c.QueryParam("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			offset = parsedOffset
+		}
+	}
+	return limit, offset
+}
+
 // GetRouletteConfig returns roulette config by id
 func (h *HTTPHandler) GetRouletteConfig(c echo.Context) error {
 	if h.rouletteService == nil {
@@ -0,0 +1,67 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// UserInventory lists the caller's usable boosts and consumables
+func (h *HTTPHandler) UserInventory(c echo.Context) error {
+	if h.inventoryService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for inventory"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	inventory, err := h.inventoryService.GetInventory(c.Request().Context(), userUUID)
+	if err != nil {
+		return c.JSON(inventoryErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, inventory)
+}
+
+// ActivateInventoryItem activates one of the caller's items
+func (h *HTTPHandler) ActivateInventoryItem(c echo.Context) error {
+	if h.inventoryService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for inventory"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	itemID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || itemID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid item id"})
+	}
+
+	result, err := h.inventoryService.ActivateItem(c.Request().Context(), userUUID, itemID)
+	if err != nil {
+		return c.JSON(inventoryErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func inventoryErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "already"),
+		strings.Contains(err.Error(), "expired"),
+		strings.Contains(err.Error(), "used automatically"):
+		return http.StatusConflict
+	case strings.Contains(err.Error(), "required"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
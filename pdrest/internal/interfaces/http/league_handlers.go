@@ -0,0 +1,54 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// UserLeague returns the caller's league tier and current weekly cohort leaderboard
+func (h *HTTPHandler) UserLeague(c echo.Context) error {
+	if h.leagueService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for leagues"})
+	}
+
+	// Get user UUID from context (set by JWT middleware)
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	ctx := context.Background()
+	response, err := h.leagueService.GetUserLeague(ctx, userUUID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// LeagueCohortLeaderboard returns the leaderboard of a league cohort
+func (h *HTTPHandler) LeagueCohortLeaderboard(c echo.Context) error {
+	if h.leagueService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for leagues"})
+	}
+
+	cohortID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || cohortID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid cohort id"})
+	}
+
+	ctx := context.Background()
+	response, err := h.leagueService.GetCohortLeaderboard(ctx, cohortID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,61 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// UserLoginStreak returns the caller's daily login streak and reward calendar
+func (h *HTTPHandler) UserLoginStreak(c echo.Context) error {
+	if h.loginStreakService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for login streaks"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	streak, err := h.loginStreakService.GetLoginStreak(c.Request().Context(), userUUID)
+	if err != nil {
+		return c.JSON(loginStreakErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, streak)
+}
+
+// ClaimLoginStreak checks the caller in for today and claims the reward of their streak day
+func (h *HTTPHandler) ClaimLoginStreak(c echo.Context) error {
+	if h.loginStreakService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for login streaks"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	result, err := h.loginStreakService.ClaimToday(c.Request().Context(), userUUID)
+	if err != nil {
+		return c.JSON(loginStreakErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func loginStreakErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "already claimed"),
+		strings.Contains(err.Error(), "not enough streak freezes"),
+		strings.Contains(err.Error(), "calendar is empty"):
+		return http.StatusConflict
+	case strings.Contains(err.Error(), "required"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
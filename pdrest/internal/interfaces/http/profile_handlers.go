@@ -0,0 +1,94 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"pdrest/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// UserProfileSettings returns the caller's display name and privacy settings
+func (h *HTTPHandler) UserProfileSettings(c echo.Context) error {
+	if h.profileService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for profile settings"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	settings, err := h.profileService.GetSettings(c.Request().Context(), userUUID)
+	if err != nil {
+		return c.JSON(profileErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, settings)
+}
+
+// UpdateUserProfileSettings changes the caller's display name and privacy settings
+func (h *HTTPHandler) UpdateUserProfileSettings(c echo.Context) error {
+	if h.profileService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for profile settings"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	var req domain.UpdateProfileSettingsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	settings, err := h.profileService.UpdateSettings(c.Request().Context(), userUUID, req)
+	if err != nil {
+		return c.JSON(profileErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, settings)
+}
+
+// SetProfileShowcase sets the claimed achievements shown on the caller's profile
+func (h *HTTPHandler) SetProfileShowcase(c echo.Context) error {
+	if h.profileService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for profile showcase"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	var req domain.SetShowcaseRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	showcase, err := h.profileService.SetShowcase(c.Request().Context(), userUUID, req.AchievementIDs)
+	if err != nil {
+		return c.JSON(profileErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"showcase": showcase})
+}
+
+func profileErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "already taken"):
+		return http.StatusConflict
+	case strings.Contains(err.Error(), "required"),
+		strings.Contains(err.Error(), "display name"),
+		strings.Contains(err.Error(), "showcase can hold"),
+		strings.Contains(err.Error(), "listed twice"),
+		strings.Contains(err.Error(), "is not claimed"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"pdrest/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// UserQuests lists the caller's daily and weekly quests
+func (h *HTTPHandler) UserQuests(c echo.Context) error {
+	if h.questService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for quests"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	quests, err := h.questService.GetQuests(c.Request().Context(), userUUID)
+	if err != nil {
+		return c.JSON(questErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, quests)
+}
+
+// ClaimQuest claims the reward of one of the caller's completed quests
+func (h *HTTPHandler) ClaimQuest(c echo.Context) error {
+	if h.questService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for quests"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	questID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || questID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid quest id"})
+	}
+
+	result, err := h.questService.ClaimQuest(c.Request().Context(), userUUID, questID)
+	if err != nil {
+		return c.JSON(questErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// SetUserTimezone requests the time zone the caller's quests and login streak reset in
+func (h *HTTPHandler) SetUserTimezone(c echo.Context) error {
+	if h.questService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for quests"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	var req domain.SetTimezoneRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	result, err := h.questService.SetTimezone(c.Request().Context(), userUUID, req.Timezone)
+	if err != nil {
+		return c.JSON(questErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func questErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "already claimed"),
+		strings.Contains(err.Error(), "not completed"):
+		return http.StatusConflict
+	case strings.Contains(err.Error(), "required"),
+		strings.Contains(err.Error(), "invalid timezone"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
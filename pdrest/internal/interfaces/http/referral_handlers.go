@@ -0,0 +1,142 @@
+package http
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"pdrest/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// UserReferrals returns the caller's invite count, active invitees and referral earnings per level
+func (h *HTTPHandler) UserReferrals(c echo.Context) error {
+	if h.referralService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for referrals"})
+	}
+
+	// Get user UUID from context (set by JWT middleware)
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	stats, err := h.referralService.GetReferralStats(c.Request().Context(), userUUID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, stats)
+}
+
+// UserReferralCodes lists the caller's referral codes with signups and conversions per code
+func (h *HTTPHandler) UserReferralCodes(c echo.Context) error {
+	if h.referralService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for referrals"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	stats, err := h.referralService.GetReferralCodeStats(c.Request().Context(), userUUID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"codes": stats,
+	})
+}
+
+// CreateUserReferralCode creates a campaign referral code for the caller
+func (h *HTTPHandler) CreateUserReferralCode(c echo.Context) error {
+	if h.referralService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for referrals"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	var req domain.CreateReferralCodeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	code, err := h.referralService.CreateReferralCode(c.Request().Context(), userUUID, &req, false)
+	if err != nil {
+		return c.JSON(referralCodeErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, code)
+}
+
+// AdminCreateReferralCode creates a campaign referral code for any user (requires X-ADMIN-TOKEN)
+func (h *HTTPHandler) AdminCreateReferralCode(c echo.Context) error {
+	if h.referralService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for referrals"})
+	}
+
+	if err := requireAdminToken(c); err != nil {
+		log.Printf("admin/referral_codes: %v", err)
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+	}
+
+	var req domain.CreateReferralCodeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	code, err := h.referralService.CreateReferralCode(c.Request().Context(), strings.TrimSpace(req.OwnerUUID), &req, true)
+	if err != nil {
+		return c.JSON(referralCodeErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	log.Printf("admin/referral_codes: created code %s for user_uuid=%s", code.Code, code.OwnerUUID)
+	return c.JSON(http.StatusOK, code)
+}
+
+// AdminReferralCodeStats lists a user's referral codes with signups and conversions (requires X-ADMIN-TOKEN)
+func (h *HTTPHandler) AdminReferralCodeStats(c echo.Context) error {
+	if h.referralService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for referrals"})
+	}
+
+	if err := requireAdminToken(c); err != nil {
+		log.Printf("admin/referral_codes: %v", err)
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+	}
+
+	ownerUUID := strings.TrimSpace(c.QueryParam("owner"))
+	if ownerUUID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "owner is required"})
+	}
+
+	stats, err := h.referralService.GetReferralCodeStats(c.Request().Context(), ownerUUID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"codes": stats,
+	})
+}
+
+func referralCodeErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "already exists"):
+		return http.StatusConflict
+	case strings.Contains(err.Error(), "required"),
+		strings.Contains(err.Error(), "must be"),
+		strings.Contains(err.Error(), "limit"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"pdrest/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AdminSimulateRoulette simulates spins of a roulette configuration and returns the expected payout per spin
+// (requires X-ADMIN-TOKEN)
+func (h *HTTPHandler) AdminSimulateRoulette(c echo.Context) error {
+	if h.rouletteService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for roulette"})
+	}
+	if err := requireAdminToken(c); err != nil {
+		log.Printf("admin/roulette: %v", err)
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+	}
+
+	var req domain.RouletteSimulationRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	result, err := h.rouletteService.SimulateRoulette(c.Request().Context(), &req)
+	if err != nil {
+		return c.JSON(rouletteAdminErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// AdminCreateRouletteConfig creates a roulette config (requires X-ADMIN-TOKEN)
+func (h *HTTPHandler) AdminCreateRouletteConfig(c echo.Context) error {
+	if h.rouletteService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for roulette"})
+	}
+	if err := requireAdminToken(c); err != nil {
+		log.Printf("admin/roulette: %v", err)
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+	}
+
+	var req domain.RouletteConfigRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	config, err := h.rouletteService.CreateRouletteConfig(c.Request().Context(), &req)
+	if err != nil {
+		return c.JSON(rouletteAdminErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	log.Printf("admin/roulette: created roulette config %d", config.ID)
+	return c.JSON(http.StatusCreated, config)
+}
+
+// AdminUpdateRouletteConfig replaces a roulette config (requires X-ADMIN-TOKEN)
+func (h *HTTPHandler) AdminUpdateRouletteConfig(c echo.Context) error {
+	if h.rouletteService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for roulette"})
+	}
+	if err := requireAdminToken(c); err != nil {
+		log.Printf("admin/roulette: %v", err)
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid id"})
+	}
+	var req domain.RouletteConfigRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	config, err := h.rouletteService.UpdateRouletteConfig(c.Request().Context(), id, &req)
+	if err != nil {
+		return c.JSON(rouletteAdminErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	log.Printf("admin/roulette: updated roulette config %d", config.ID)
+	return c.JSON(http.StatusOK, config)
+}
+
+func rouletteAdminErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "required"),
+		strings.Contains(err.Error(), "invalid"),
+		strings.Contains(err.Error(), "no prize values"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
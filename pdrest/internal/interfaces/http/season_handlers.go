@@ -0,0 +1,135 @@
+package http
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CurrentSeason returns the active season with its live standings
+func (h *HTTPHandler) CurrentSeason(c echo.Context) error {
+	if h.seasonService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for seasons"})
+	}
+
+	limit, offset := parsePagination(c)
+
+	ctx := context.Background()
+	response, err := h.seasonService.GetCurrentSeason(ctx, limit, offset)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// CurrentSeasonRating returns only the current season standings (used by /api/globalrating?scope=season)
+func (h *HTTPHandler) CurrentSeasonRating(c echo.Context) error {
+	if h.seasonService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for seasons"})
+	}
+
+	limit, offset := parsePagination(c)
+
+	ctx := context.Background()
+	response, err := h.seasonService.GetCurrentSeason(ctx, limit, offset)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, response.Standings)
+}
+
+// FinishedSeasons lists past seasons, newest first
+func (h *HTTPHandler) FinishedSeasons(c echo.Context) error {
+	if h.seasonService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for seasons"})
+	}
+
+	limit, offset := parsePagination(c)
+
+	ctx := context.Background()
+	seasons, err := h.seasonService.GetFinishedSeasons(ctx, limit, offset)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"seasons": seasons,
+	})
+}
+
+// SeasonResults returns the archived final standings of a finished season
+func (h *HTTPHandler) SeasonResults(c echo.Context) error {
+	if h.seasonService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for seasons"})
+	}
+
+	seasonID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || seasonID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid season id"})
+	}
+
+	limit, offset := parsePagination(c)
+
+	ctx := context.Background()
+	response, err := h.seasonService.GetSeasonResults(ctx, seasonID, limit, offset)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		}
+		if strings.Contains(err.Error(), "required") || strings.Contains(err.Error(), "not finished") {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// AdminCreateSeason schedules a season with an explicit start/end (requires X-ADMIN-TOKEN)
+func (h *HTTPHandler) AdminCreateSeason(c echo.Context) error {
+	if h.seasonService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for seasons"})
+	}
+
+	if err := requireAdminToken(c); err != nil {
+		log.Printf("admin/seasons: %v", err)
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+	}
+
+	var req struct {
+		Title     string    `json:"title"`
+		StartTime time.Time `json:"startTime"`
+		EndTime   time.Time `json:"endTime"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	ctx := context.Background()
+	season, err := h.seasonService.CreateSeason(ctx, strings.TrimSpace(req.Title), req.StartTime, req.EndTime)
+	if err != nil {
+		if strings.Contains(err.Error(), "required") ||
+			strings.Contains(err.Error(), "must be") ||
+			strings.Contains(err.Error(), "overlaps") ||
+			strings.Contains(err.Error(), "already exists") {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	log.Printf("admin/seasons: created season %d (%s)", season.ID, season.Title)
+	return c.JSON(http.StatusOK, season)
+}
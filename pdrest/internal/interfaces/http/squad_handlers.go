@@ -0,0 +1,199 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"pdrest/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CreateSquad creates a squad in a squad event with the caller as captain
+func (h *HTTPHandler) CreateSquad(c echo.Context) error {
+	if h.squadService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for squads"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	var req domain.CreateSquadRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	squad, err := h.squadService.CreateSquad(c.Request().Context(), userUUID, &req)
+	if err != nil {
+		return c.JSON(squadErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, squad)
+}
+
+// JoinSquad adds the caller to the squad with the invite code
+func (h *HTTPHandler) JoinSquad(c echo.Context) error {
+	if h.squadService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for squads"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	var req domain.JoinSquadRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	squad, err := h.squadService.JoinSquad(c.Request().Context(), userUUID, req.InviteCode)
+	if err != nil {
+		return c.JSON(squadErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, squad)
+}
+
+// Squad returns a squad with its members and their points
+func (h *HTTPHandler) Squad(c echo.Context) error {
+	if h.squadService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for squads"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	squadID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || squadID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid squad id"})
+	}
+
+	squad, err := h.squadService.GetSquad(c.Request().Context(), userUUID, squadID)
+	if err != nil {
+		return c.JSON(squadErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, squad)
+}
+
+// LeaveSquad removes the caller from a squad
+func (h *HTTPHandler) LeaveSquad(c echo.Context) error {
+	if h.squadService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for squads"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	squadID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || squadID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid squad id"})
+	}
+
+	if err := h.squadService.LeaveSquad(c.Request().Context(), userUUID, squadID); err != nil {
+		return c.JSON(squadErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"status": "left"})
+}
+
+// RemoveSquadMember removes a member from the caller's squad (captain only)
+func (h *HTTPHandler) RemoveSquadMember(c echo.Context) error {
+	if h.squadService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for squads"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	squadID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || squadID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid squad id"})
+	}
+
+	var req domain.RemoveSquadMemberRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	if err := h.squadService.RemoveMember(c.Request().Context(), userUUID, squadID, req.UserUUID); err != nil {
+		return c.JSON(squadErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"status": "removed"})
+}
+
+// EventSquad returns the caller's squad in an event
+func (h *HTTPHandler) EventSquad(c echo.Context) error {
+	if h.squadService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for squads"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	squad, err := h.squadService.GetUserSquad(c.Request().Context(), userUUID, strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		return c.JSON(squadErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+	if squad == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "user is not in a squad of this event"})
+	}
+
+	return c.JSON(http.StatusOK, squad)
+}
+
+// EventSquadLeaderboard returns a page of an event's squad standings and the caller's squad
+func (h *HTTPHandler) EventSquadLeaderboard(c echo.Context) error {
+	if h.squadService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for squads"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	limit, offset := parsePagination(c)
+	response, err := h.squadService.GetSquadLeaderboard(c.Request().Context(), strings.TrimSpace(c.Param("id")), userUUID, limit, offset)
+	if err != nil {
+		return c.JSON(squadErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func squadErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "only the captain"),
+		strings.Contains(err.Error(), "not eligible"),
+		strings.Contains(err.Error(), "not enough points"):
+		return http.StatusForbidden
+	case strings.Contains(err.Error(), "already"),
+		strings.Contains(err.Error(), "is full"),
+		strings.Contains(err.Error(), "join window"):
+		return http.StatusConflict
+	case strings.Contains(err.Error(), "required"),
+		strings.Contains(err.Error(), "must"),
+		strings.Contains(err.Error(), "not a squad event"),
+		strings.Contains(err.Error(), "not a member"),
+		strings.Contains(err.Error(), "cannot"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
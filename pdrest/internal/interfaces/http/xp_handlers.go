@@ -0,0 +1,83 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Levels lists the level curve with the perks of each level
+func (h *HTTPHandler) Levels(c echo.Context) error {
+	if h.xpService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for levels"})
+	}
+
+	levels, err := h.xpService.GetLevels(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"levels": levels})
+}
+
+// UserLevel returns the caller's XP, level and perks
+func (h *HTTPHandler) UserLevel(c echo.Context) error {
+	if h.xpService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for levels"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	level, err := h.xpService.GetUserLevel(c.Request().Context(), userUUID)
+	if err != nil {
+		return c.JSON(xpErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, level)
+}
+
+// UserXPHistory returns the caller's XP grants, newest first
+func (h *HTTPHandler) UserXPHistory(c echo.Context) error {
+	if h.xpService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "database connection required for levels"})
+	}
+
+	userUUID, ok := c.Get("user_uuid").(string)
+	if !ok || userUUID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	}
+
+	limit := 50 // Default limit
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+	offset := 0 // Default offset
+	if offsetStr := c.QueryParam("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			offset = parsedOffset
+		}
+	}
+
+	history, err := h.xpService.GetXPHistory(c.Request().Context(), userUUID, limit, offset)
+	if err != nil {
+		return c.JSON(xpErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, history)
+}
+
+func xpErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "required"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
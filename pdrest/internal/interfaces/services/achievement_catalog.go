@@ -0,0 +1,467 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"pdrest/internal/domain"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Columns of the achievement sheet; "prize" is optional and not in the design team's original sheet.
+const (
+	catalogColumnID         = "id achieve"
+	catalogColumnImage      = "achieve pic (github)"
+	catalogColumnConditions = "conditionals for receiving"
+	catalogColumnTags       = "tags"
+	catalogColumnPrize      = "prize"
+)
+
+var catalogHeader = []string{catalogColumnID, catalogColumnImage, catalogColumnConditions, catalogColumnTags, catalogColumnPrize}
+
+var (
+	achievementIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,50}$`)
+	catalogPrizePattern  = regexp.MustCompile(`^(\d+)(?:\s+\S.*)?$`)
+	conditionWindow      = regexp.MustCompile(`(?i)\s+within\s+(\d+)\s+days?$`)
+	conditionPairWins    = regexp.MustCompile(`(?i)^(\d+)\s+successful\s+bets?\s+on\s+([A-Za-z0-9]+/[A-Za-z0-9]+)$`)
+	conditionWins        = regexp.MustCompile(`(?i)^(\d+)\s+successful\s+bets?$`)
+	conditionFirstWin    = regexp.MustCompile(`(?i)^first\s+successful\s+bet$`)
+	conditionWinStreak   = regexp.MustCompile(`(?i)^(\d+)\s+wins?\s+in\s+a\s+row$`)
+	conditionBetsPlaced  = regexp.MustCompile(`(?i)^(\d+)\s+bets?\s+placed$`)
+	conditionReferrals   = regexp.MustCompile(`(?i)^(\d+)\s+referrals?$`)
+	conditionLoginDays   = regexp.MustCompile(`(?i)^(\d+)\s+login\s+days?$`)
+	conditionPlacements  = regexp.MustCompile(`(?i)^(\d+)\s+event\s+placements?(?:\s+in\s+top\s+(\d+))?$`)
+)
+
+// parseAchievementCondition turns the sheet's "conditionals for receiving" into a rule:
+//
+//	10 successful bets | first successful bet | 5 successful bets on BTC/USDT | 3 wins in a row
+//	20 bets placed | 5 referrals | 7 login days | 3 event placements [in top 3]
+//
+// optionally followed by "within N days" and "; side=pump", "; timeframe=60", "; tag=competition".
+func parseAchievementCondition(text string) (domain.AchievementRule, error) {
+	var rule domain.AchievementRule
+	parts := strings.Split(text, ";")
+	phrase := strings.TrimSuffix(strings.TrimSpace(parts[0]), ".")
+
+	if m := conditionWindow.FindStringSubmatch(phrase); m != nil {
+		rule.WindowDays, _ = strconv.Atoi(m[1])
+		phrase = strings.TrimSpace(phrase[:len(phrase)-len(m[0])])
+	}
+
+	var threshold string
+	switch {
+	case conditionFirstWin.MatchString(phrase):
+		rule.Metric, threshold = domain.AchievementMetricWins, "1"
+	case conditionPairWins.MatchString(phrase):
+		m := conditionPairWins.FindStringSubmatch(phrase)
+		rule.Metric, threshold = domain.AchievementMetricPairWins, m[1]
+		rule.Filters.Pair = strings.ToUpper(m[2])
+	case conditionWins.MatchString(phrase):
+		rule.Metric, threshold = domain.AchievementMetricWins, conditionWins.FindStringSubmatch(phrase)[1]
+	case conditionWinStreak.MatchString(phrase):
+		rule.Metric, threshold = domain.AchievementMetricWinStreak, conditionWinStreak.FindStringSubmatch(phrase)[1]
+	case conditionBetsPlaced.MatchString(phrase):
+		rule.Metric, threshold = domain.AchievementMetricBetsPlaced, conditionBetsPlaced.FindStringSubmatch(phrase)[1]
+	case conditionReferrals.MatchString(phrase):
+		rule.Metric, threshold = domain.AchievementMetricReferrals, conditionReferrals.FindStringSubmatch(phrase)[1]
+	case conditionLoginDays.MatchString(phrase):
+		rule.Metric, threshold = domain.AchievementMetricLoginDays, conditionLoginDays.FindStringSubmatch(phrase)[1]
+	case conditionPlacements.MatchString(phrase):
+		m := conditionPlacements.FindStringSubmatch(phrase)
+		rule.Metric, threshold = domain.AchievementMetricEventPlacements, m[1]
+		if m[2] != "" {
+			rule.Filters.MaxRank, _ = strconv.Atoi(m[2])
+		}
+	default:
+		return rule, fmt.Errorf("unrecognized conditions %q", text)
+	}
+
+	var err error
+	if rule.Threshold, err = strconv.Atoi(threshold); err != nil || rule.Threshold <= 0 {
+		return rule, fmt.Errorf("conditions %q must require at least 1", text)
+	}
+
+	for _, option := range parts[1:] {
+		key, value, ok := strings.Cut(strings.TrimSpace(option), "=")
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		if !ok || value == "" {
+			return rule, fmt.Errorf("invalid condition option %q", option)
+		}
+		switch key {
+		case "side":
+			if value != "pump" && value != "dump" {
+				return rule, fmt.Errorf("condition side must be pump or dump")
+			}
+			rule.Filters.Side = value
+		case "timeframe":
+			if rule.Filters.Timeframe, err = strconv.Atoi(value); err != nil || rule.Filters.Timeframe <= 0 {
+				return rule, fmt.Errorf("condition timeframe must be a number of seconds")
+			}
+		case "tag":
+			rule.Filters.EventTag = value
+		default:
+			return rule, fmt.Errorf("unknown condition option %q", key)
+		}
+	}
+	return rule, nil
+}
+
+// formatAchievementCondition is the inverse of parseAchievementCondition.
+func formatAchievementCondition(rule domain.AchievementRule) string {
+	n := rule.Threshold
+	plural := func(singular, pluralForm string) string {
+		if n == 1 {
+			return singular
+		}
+		return pluralForm
+	}
+
+	var text string
+	switch rule.Metric {
+	case domain.AchievementMetricWins:
+		text = fmt.Sprintf("%d %s", n, plural("successful bet", "successful bets"))
+	case domain.AchievementMetricPairWins:
+		text = fmt.Sprintf("%d %s on %s", n, plural("successful bet", "successful bets"), rule.Filters.Pair)
+	case domain.AchievementMetricWinStreak:
+		text = fmt.Sprintf("%d %s in a row", n, plural("win", "wins"))
+	case domain.AchievementMetricBetsPlaced:
+		text = fmt.Sprintf("%d %s placed", n, plural("bet", "bets"))
+	case domain.AchievementMetricReferrals:
+		text = fmt.Sprintf("%d %s", n, plural("referral", "referrals"))
+	case domain.AchievementMetricLoginDays:
+		text = fmt.Sprintf("%d %s", n, plural("login day", "login days"))
+	case domain.AchievementMetricEventPlacements:
+		text = fmt.Sprintf("%d %s", n, plural("event placement", "event placements"))
+		if rule.Filters.MaxRank > 0 {
+			text += fmt.Sprintf(" in top %d", rule.Filters.MaxRank)
+		}
+	default:
+		return ""
+	}
+	if rule.WindowDays > 0 {
+		text += fmt.Sprintf(" within %d days", rule.WindowDays)
+	}
+	if rule.Filters.Side != "" {
+		text += "; side=" + rule.Filters.Side
+	}
+	if rule.Filters.Timeframe > 0 {
+		text += fmt.Sprintf("; timeframe=%d", rule.Filters.Timeframe)
+	}
+	if rule.Filters.EventTag != "" {
+		text += "; tag=" + rule.Filters.EventTag
+	}
+	return text
+}
+
+// catalogAchievementID names an achievement the sheet lists without an ID after its rule, e.g. wins_1000.
+func catalogAchievementID(rule domain.AchievementRule) string {
+	id := rule.Metric
+	if rule.Filters.Pair != "" {
+		id += "_" + strings.ToLower(strings.ReplaceAll(rule.Filters.Pair, "/", "_"))
+	}
+	id += "_" + strconv.Itoa(rule.Threshold)
+	if rule.WindowDays > 0 {
+		id += fmt.Sprintf("_%dd", rule.WindowDays)
+	}
+	return id
+}
+
+// parseCatalogPrize reads a prize such as "10 USDT"; a bare number is labelled in USDT (1 USDT = 1 point).
+func parseCatalogPrize(text string) (int64, string, error) {
+	m := catalogPrizePattern.FindStringSubmatch(text)
+	if m == nil {
+		return 0, "", fmt.Errorf("invalid prize %q: must start with the points paid, e.g. \"10 USDT\"", text)
+	}
+	value, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil || value <= 0 {
+		return 0, "", fmt.Errorf("invalid prize %q: points must be positive", text)
+	}
+	if text == m[1] {
+		text += " USDT"
+	}
+	return value, text, nil
+}
+
+// ParseAchievementCatalog reads the achievement sheet CSV. Columns are found by their header; rows that
+// fail validation are reported together, with their line numbers.
+func ParseAchievementCatalog(r io.Reader) ([]domain.AchievementCatalogEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid achievement catalog: failed to read header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if name != "" {
+			columns[name] = i
+		}
+	}
+	for _, required := range []string{catalogColumnID, catalogColumnImage, catalogColumnConditions} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("invalid achievement catalog: missing column %q", required)
+		}
+	}
+	field := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var entries []domain.AchievementCatalogEntry
+	var problems []string
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid achievement catalog: %w", err)
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		entry := domain.AchievementCatalogEntry{
+			Achievement: domain.Achievement{
+				ID:       field(record, catalogColumnID),
+				ImageURL: field(record, catalogColumnImage),
+				Tags:     field(record, catalogColumnTags),
+			},
+			Conditions: field(record, catalogColumnConditions),
+			Line:       line,
+		}
+		if err := validateCatalogEntry(&entry, field(record, catalogColumnPrize)); err != nil {
+			problems = append(problems, fmt.Sprintf("line %d: %v", line, err))
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(problems) > 0 {
+		return nil, errors.New("invalid achievement catalog:\n" + strings.Join(problems, "\n"))
+	}
+	return entries, nil
+}
+
+func validateCatalogEntry(entry *domain.AchievementCatalogEntry, prize string) error {
+	a := &entry.Achievement
+	if a.ID != "" && !achievementIDPattern.MatchString(a.ID) {
+		return fmt.Errorf("id must be at most 50 letters, digits, '_' or '-'")
+	}
+	if !strings.HasPrefix(a.ImageURL, "https://") && !strings.HasPrefix(a.ImageURL, "http://") {
+		return fmt.Errorf("picture must be an http(s) URL")
+	}
+	if entry.Conditions == "" {
+		return fmt.Errorf("conditions are required")
+	}
+	rule, err := parseAchievementCondition(entry.Conditions)
+	if err != nil {
+		return err
+	}
+	entry.Rule = rule
+
+	tags := make([]string, 0)
+	for _, tag := range strings.Split(a.Tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			if tag == "event" {
+				return fmt.Errorf("tag \"event\" is reserved for event achievements")
+			}
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) == 0 {
+		tags = append(tags, "global")
+	}
+	a.Tags = strings.Join(tags, ",")
+	a.Steps = rule.Threshold
+
+	if prize != "" {
+		if entry.PrizeValue, entry.Prize, err = parseCatalogPrize(prize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteAchievementCatalog writes entries in the achievement sheet's CSV format, with the prize column.
+func WriteAchievementCatalog(w io.Writer, entries []domain.AchievementCatalogEntry) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(catalogHeader); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		conditions := entry.Conditions
+		if conditions == "" {
+			conditions = entry.Achievement.StepDesc
+		}
+		record := []string{entry.Achievement.ID, entry.Achievement.ImageURL, conditions, entry.Achievement.Tags, entry.Prize}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ImportAchievementCatalog validates the catalog CSV, diffs it against the database and, unless dryRun,
+// creates and updates its achievements with their rules and prizes. Rows without an ID update the
+// achievement with the same rule, or create one named after the rule. Achievements missing from the file
+// are reported, never deleted.
+func (s *AchievementService) ImportAchievementCatalog(ctx context.Context, r io.Reader, dryRun bool) (*domain.AchievementCatalogImportResult, error) {
+	if s.repo == nil {
+		return nil, errors.New("achievement repository is not configured")
+	}
+
+	entries, err := ParseAchievementCatalog(r)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.repo.GetAchievementCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]domain.AchievementCatalogEntry, len(current))
+	byRule := make(map[string]string, len(current))
+	for _, entry := range current {
+		byID[entry.Achievement.ID] = entry
+		if entry.Rule.Metric != "" {
+			byRule[formatAchievementCondition(entry.Rule)] = entry.Achievement.ID
+		}
+	}
+
+	result := &domain.AchievementCatalogImportResult{DryRun: dryRun, Changes: make([]domain.AchievementCatalogChange, 0, len(entries))}
+	seen := make(map[string]int)
+	toSave := make([]domain.AchievementCatalogEntry, 0)
+	var problems []string
+	for _, entry := range entries {
+		conditions := formatAchievementCondition(entry.Rule)
+		if entry.Achievement.ID == "" {
+			if id, ok := byRule[conditions]; ok {
+				entry.Achievement.ID = id
+			} else {
+				entry.Achievement.ID = catalogAchievementID(entry.Rule)
+			}
+		}
+		id := entry.Achievement.ID
+		if line, ok := seen[id]; ok {
+			problems = append(problems, fmt.Sprintf("line %d: achievement %s is already listed on line %d", entry.Line, id, line))
+			continue
+		}
+		seen[id] = entry.Line
+		entry.Rule.AchievementID = id
+
+		change := domain.AchievementCatalogChange{AchievementID: id, Line: entry.Line}
+		existing, ok := byID[id]
+		if !ok {
+			if other, err := s.repo.GetAchievementByID(ctx, id); err == nil && other != nil {
+				problems = append(problems, fmt.Sprintf("line %d: achievement %s belongs to an event and cannot be imported", entry.Line, id))
+				continue
+			}
+			fillCatalogAchievementTexts(&entry.Achievement, conditions)
+			change.Action = domain.AchievementCatalogCreate
+			if entry.Prize == "" {
+				change.Warnings = append(change.Warnings, "no prize: the achievement can't be claimed until one is linked")
+			}
+		} else {
+			old := existing.Achievement
+			if old.ImageURL != entry.Achievement.ImageURL {
+				change.Changes = append(change.Changes, fmt.Sprintf("imageUrl: %s -> %s", old.ImageURL, entry.Achievement.ImageURL))
+			}
+			if old.Tags != entry.Achievement.Tags {
+				change.Changes = append(change.Changes, fmt.Sprintf("tags: %s -> %s", old.Tags, entry.Achievement.Tags))
+			}
+			if oldConditions := formatAchievementCondition(existing.Rule); oldConditions != conditions || old.Steps != entry.Achievement.Steps {
+				change.Changes = append(change.Changes, fmt.Sprintf("conditions: %s -> %s", oldConditions, conditions))
+			}
+			if entry.Prize != "" && (existing.Prize != entry.Prize || existing.PrizeValue != entry.PrizeValue) {
+				change.Changes = append(change.Changes, fmt.Sprintf("prize: %s -> %s", existing.Prize, entry.Prize))
+			}
+			if existing.Prize == "" && entry.Prize == "" {
+				change.Warnings = append(change.Warnings, "no prize: the achievement can't be claimed until one is linked")
+			}
+			// The sheet doesn't carry texts; keep the stored ones
+			entry.Achievement.Badge = old.Badge
+			entry.Achievement.Title = old.Title
+			entry.Achievement.Desc = old.Desc
+			entry.Achievement.StepDesc = old.StepDesc
+			change.Action = domain.AchievementCatalogUnchanged
+			if len(change.Changes) > 0 {
+				change.Action = domain.AchievementCatalogUpdate
+			}
+		}
+
+		switch change.Action {
+		case domain.AchievementCatalogCreate:
+			result.Created++
+			toSave = append(toSave, entry)
+		case domain.AchievementCatalogUpdate:
+			result.Updated++
+			toSave = append(toSave, entry)
+		default:
+			result.Unchanged++
+		}
+		result.Changes = append(result.Changes, change)
+	}
+	if len(problems) > 0 {
+		return nil, errors.New("invalid achievement catalog:\n" + strings.Join(problems, "\n"))
+	}
+
+	for _, entry := range current {
+		if _, ok := seen[entry.Achievement.ID]; !ok {
+			result.NotInFile = append(result.NotInFile, entry.Achievement.ID)
+		}
+	}
+	sort.Strings(result.NotInFile)
+
+	if !dryRun && len(toSave) > 0 {
+		if err := s.repo.SaveAchievementCatalog(ctx, toSave); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// fillCatalogAchievementTexts writes the texts of a new achievement from its conditions, the way the
+// hand-written migrations did ("10 Successful Bets", "Awarded for 10 successful bets.").
+func fillCatalogAchievementTexts(a *domain.Achievement, conditions string) {
+	phrase, _, _ := strings.Cut(conditions, ";")
+	phrase = strings.TrimSpace(phrase)
+	words := strings.Fields(phrase)
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	a.Title = strings.Join(words, " ")
+	a.Badge = a.Title
+	a.Desc = "Awarded for " + phrase + "."
+	a.StepDesc = strings.ToUpper(phrase[:1]) + phrase[1:]
+}
+
+// ExportAchievementCatalog writes the achievements managed by the catalog in the sheet's CSV format.
+func (s *AchievementService) ExportAchievementCatalog(ctx context.Context, w io.Writer) error {
+	if s.repo == nil {
+		return errors.New("achievement repository is not configured")
+	}
+	entries, err := s.repo.GetAchievementCatalog(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range entries {
+		if entries[i].Rule.Metric != "" {
+			entries[i].Conditions = formatAchievementCondition(entries[i].Rule)
+		}
+	}
+	return WriteAchievementCatalog(w, entries)
+}
@@ -15,17 +15,17 @@ type AchievementService struct {
 	repo           data.AchievementRepository
 	prizeRepo      data.PrizeRepository
 	prizeValueRepo data.PrizeValueRepository
-	ratingRepo     data.RatingRepository
-	betRepo        data.BetRepository
+	rewards        *RewardService
+	bus            *DomainEventBus
 }
 
-func NewAchievementService(r data.AchievementRepository, prizeRepo data.PrizeRepository, prizeValueRepo data.PrizeValueRepository, ratingRepo data.RatingRepository, betRepo data.BetRepository) *AchievementService {
+func NewAchievementService(r data.AchievementRepository, prizeRepo data.PrizeRepository, prizeValueRepo data.PrizeValueRepository, rewards *RewardService, bus *DomainEventBus) *AchievementService {
 	return &AchievementService{
 		repo:           r,
 		prizeRepo:      prizeRepo,
 		prizeValueRepo: prizeValueRepo,
-		ratingRepo:     ratingRepo,
-		betRepo:        betRepo,
+		rewards:        rewards,
+		bus:            bus,
 	}
 }
 
@@ -100,92 +100,243 @@ func (s *AchievementService) GetUserAchievementByID(ctx context.Context, userUUI
 	}, nil
 }
 
-// CheckBetAchievements verifies bet-related achievements and returns newly created ids.
-func (s *AchievementService) UpdateWinAchievementsOnBet(ctx context.Context, userUUID string) ([]string, error) {
-	if userUUID == "" {
-		return nil, errors.New("user uuid is required")
+// achievementMetricsByEvent are the rule metrics each domain event moves.
+var achievementMetricsByEvent = map[string][]string{
+	domain.DomainEventBetSettled: {
+		domain.AchievementMetricWins,
+		domain.AchievementMetricBetsPlaced,
+		domain.AchievementMetricWinStreak,
+		domain.AchievementMetricPairWins,
+	},
+	// A claim re-delivers the bet in case its settlement event was lost; the event log applies it once.
+	domain.DomainEventBetClaimed: {
+		domain.AchievementMetricWins,
+		domain.AchievementMetricBetsPlaced,
+		domain.AchievementMetricWinStreak,
+		domain.AchievementMetricPairWins,
+	},
+	domain.DomainEventEventJoined:       {domain.AchievementMetricEventPlacements},
+	domain.DomainEventReferralActivated: {domain.AchievementMetricReferrals},
+	domain.DomainEventUserLoggedIn:      {domain.AchievementMetricLoginDays, domain.AchievementMetricEventPlacements},
+}
+
+// OnDomainEvent advances the achievements whose rules count one of the event's metrics, and publishes
+// AchievementCompleted for each achievement the event completed.
+//
+// All-time counters move by the event's delta; each event is applied once per user. Rules without
+// stored progress yet are seeded by counting the metric once, and windowed rules and event placements
+// (whose counts also fall or come from finished events) are recounted.
+func (s *AchievementService) OnDomainEvent(ctx context.Context, event domain.DomainEvent) error {
+	metrics := achievementMetricsByEvent[event.Type]
+	if len(metrics) == 0 || event.UserUUID == "" {
+		return nil
 	}
-	if s.repo == nil || s.betRepo == nil {
-		return nil, errors.New("achievement service dependencies are not configured")
+	if s.repo == nil {
+		return errors.New("achievement service dependencies are not configured")
 	}
 
-	newAchievements := make([]string, 0)
-	wins, err := s.betRepo.GetWinningBetsByUser(ctx, userUUID)
+	rules, err := s.repo.GetAchievementRules(ctx)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	winsCount := len(wins)
 
-	achievementIDs := []string{
-		"first_bet_success",
-		"wins_10",
-		"wins_50",
-		"wins_100",
-		"wins_250",
-		"wins_500",
-	}
-
-	for _, achievementID := range achievementIDs {
-		status, err := s.repo.GetUserAchievementStatus(ctx, userUUID, achievementID)
-		if err != nil && !strings.Contains(err.Error(), "not found") {
-			return nil, err
+	var deltas []domain.AchievementProgressDelta
+	var recounted []string
+	for _, rule := range rules {
+		if !containsString(metrics, rule.Metric) {
+			continue
 		}
-
-		achievement, err := s.repo.GetAchievementByID(ctx, achievementID)
+		status, err := s.getUserAchievementStatus(ctx, event.UserUUID, rule.AchievementID)
 		if err != nil {
-			if strings.Contains(strings.ToLower(err.Error()), "not found") {
-				continue
-			}
-			return nil, err
+			return err
 		}
-		if achievement == nil {
+		if status != nil && (status.ClaimedStatus || (status.NeedSteps > 0 && status.StepsGot >= status.NeedSteps)) {
 			continue
 		}
 
-		needSteps := achievement.Steps
-		if needSteps <= 0 {
-			return nil, errors.New("achievement has invalid steps")
-		}
-		nextSteps := winsCount
-		if nextSteps > needSteps {
-			nextSteps = needSteps
-		}
-		justCompleted := winsCount >= needSteps
-
-		if status == nil {
-			if err := s.repo.UpsertUserAchievementProgress(ctx, userUUID, achievementID, nextSteps, needSteps, false); err != nil {
-				return nil, err
+		if status == nil || rule.WindowDays > 0 || rule.Metric == domain.AchievementMetricEventPlacements {
+			completed, err := s.syncAchievementProgress(ctx, event.UserUUID, rule, status)
+			if err != nil {
+				return err
 			}
-			if justCompleted {
-				newAchievements = append(newAchievements, achievementID)
+			if completed {
+				recounted = append(recounted, rule.AchievementID)
 			}
 			continue
 		}
 
-		if status.ClaimedStatus {
-			continue
+		if delta, ok := achievementDelta(rule, status.NeedSteps, event); ok {
+			deltas = append(deltas, delta)
 		}
+	}
 
-		currentNeed := status.NeedSteps
-		if currentNeed <= 0 {
-			currentNeed = needSteps
-		}
-		prevCompleted := status.StepsGot >= currentNeed
-		nextSteps = winsCount
-		if nextSteps > currentNeed {
-			nextSteps = currentNeed
+	completed, err := s.repo.ApplyAchievementProgress(ctx, event.UserUUID, achievementEventKey(event), deltas, recounted)
+	if err != nil {
+		return err
+	}
+	for _, achievementID := range completed {
+		s.bus.Publish(ctx, domain.DomainEvent{
+			Type:          domain.DomainEventAchievementCompleted,
+			UserUUID:      event.UserUUID,
+			AchievementID: achievementID,
+		})
+	}
+	return nil
+}
+
+// achievementEventKey identifies an event in the achievement event log; events with the same key
+// are applied once.
+func achievementEventKey(event domain.DomainEvent) string {
+	switch event.Type {
+	case domain.DomainEventBetSettled, domain.DomainEventBetClaimed:
+		if event.Bet != nil {
+			return betAchievementEventKey(event.Bet.ID)
 		}
-		if nextSteps != status.StepsGot || status.NeedSteps != currentNeed {
-			if err := s.repo.UpsertUserAchievementProgress(ctx, userUUID, achievementID, nextSteps, currentNeed, false); err != nil {
-				return nil, err
-			}
+	case domain.DomainEventEventJoined:
+		return "event:" + event.EventID
+	case domain.DomainEventReferralActivated:
+		return "referral:" + event.InviteeUUID
+	case domain.DomainEventUserLoggedIn:
+		// One login_days step per UTC day
+		return "login:" + time.UnixMilli(event.OccurredAt).UTC().Format("2006-01-02")
+	}
+	return event.Type + ":" + strconv.FormatInt(event.OccurredAt, 10)
+}
+
+func betAchievementEventKey(betID int) string {
+	return "bet:" + strconv.Itoa(betID)
+}
+
+// achievementDelta is how far the event moves an all-time rule's counter.
+func achievementDelta(rule domain.AchievementRule, needSteps int, event domain.DomainEvent) (domain.AchievementProgressDelta, bool) {
+	if needSteps <= 0 {
+		needSteps = rule.Threshold
+	}
+	delta := domain.AchievementProgressDelta{AchievementID: rule.AchievementID, NeedSteps: needSteps, Increment: 1}
+
+	switch rule.Metric {
+	case domain.AchievementMetricReferrals, domain.AchievementMetricLoginDays:
+		return delta, true
+	}
+
+	bet := event.Bet
+	if bet == nil || bet.ClosePrice == nil || !betMatchesRule(bet, rule.Filters) {
+		return delta, false
+	}
+	won := determinePrizeStatus(*bet) == "win"
+	switch rule.Metric {
+	case domain.AchievementMetricWins, domain.AchievementMetricPairWins:
+		return delta, won
+	case domain.AchievementMetricBetsPlaced:
+		return delta, true
+	case domain.AchievementMetricWinStreak:
+		delta.Streak = true
+		delta.ResetRun = !won
+		return delta, true
+	}
+	return delta, false
+}
+
+func betMatchesRule(bet *domain.Bet, filters domain.AchievementRuleFilters) bool {
+	if filters.Pair != "" && bet.Pair != filters.Pair {
+		return false
+	}
+	if filters.Side != "" && bet.Side != filters.Side {
+		return false
+	}
+	if filters.Timeframe != 0 && bet.Timeframe != filters.Timeframe {
+		return false
+	}
+	return true
+}
+
+// NotifyAchievementCompleted queues a notification for a completed achievement.
+func (s *AchievementService) NotifyAchievementCompleted(ctx context.Context, event domain.DomainEvent) error {
+	if s.repo == nil {
+		return errors.New("achievement service dependencies are not configured")
+	}
+	achievement, err := s.repo.GetAchievementByID(ctx, event.AchievementID)
+	if err != nil {
+		return err
+	}
+	return s.repo.QueueNotification(ctx, &domain.Notification{
+		UserUUID: event.UserUUID,
+		Producer: domain.NotificationProducerAchievements,
+		Message:  fmt.Sprintf("Achievement unlocked: %s! Claim your reward in the app.", achievement.Title),
+	})
+}
+
+// GetBetAchievements returns the achievements the user completed with the bet.
+func (s *AchievementService) GetBetAchievements(ctx context.Context, userUUID string, betID int) ([]string, error) {
+	if s.repo == nil {
+		return nil, errors.New("achievement service dependencies are not configured")
+	}
+	completed, err := s.repo.GetAchievementsCompletedByEvent(ctx, userUUID, betAchievementEventKey(betID))
+	if err != nil {
+		return nil, err
+	}
+	if completed == nil {
+		completed = []string{}
+	}
+	return completed, nil
+}
+
+// syncAchievementProgress stores the rule's current progress for the user and reports whether the
+// achievement became completed by it. Progress is capped at the steps needed and never moves backwards
+// once completed, so windowed metrics can't take an earned achievement away.
+func (s *AchievementService) syncAchievementProgress(ctx context.Context, userUUID string, rule domain.AchievementRule, status *domain.UserAchievementStatus) (bool, error) {
+	needSteps := rule.Threshold
+	if status != nil && status.NeedSteps > 0 {
+		needSteps = status.NeedSteps
+	}
+	if needSteps <= 0 {
+		return false, errors.New("achievement has invalid steps")
+	}
+
+	prevCompleted := status != nil && status.StepsGot >= needSteps
+	if prevCompleted {
+		return false, nil
+	}
+
+	var count, run int
+	var err error
+	if rule.Metric == domain.AchievementMetricWinStreak {
+		count, run, err = s.repo.CountWinStreaks(ctx, userUUID, rule)
+	} else {
+		count, err = s.repo.CountAchievementMetric(ctx, userUUID, rule)
+	}
+	if err != nil {
+		return false, err
+	}
+	nextSteps := count
+	if nextSteps > needSteps {
+		nextSteps = needSteps
+	}
+
+	if status == nil || nextSteps != status.StepsGot || status.NeedSteps != needSteps {
+		if err := s.repo.UpsertUserAchievementProgress(ctx, userUUID, rule.AchievementID, nextSteps, needSteps, false); err != nil {
+			return false, err
 		}
-		if !prevCompleted && nextSteps >= currentNeed {
-			newAchievements = append(newAchievements, achievementID)
+	}
+	// Streak counters continue from the run the user is on
+	if rule.Metric == domain.AchievementMetricWinStreak {
+		if err := s.repo.SetUserAchievementRunLength(ctx, userUUID, rule.AchievementID, run); err != nil {
+			return false, err
 		}
 	}
+	return nextSteps >= needSteps, nil
+}
 
-	return newAchievements, nil
+// getUserAchievementStatus returns nil when the user has no progress on the achievement yet.
+func (s *AchievementService) getUserAchievementStatus(ctx context.Context, userUUID string, achievementID string) (*domain.UserAchievementStatus, error) {
+	status, err := s.repo.GetUserAchievementStatus(ctx, userUUID, achievementID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return status, nil
 }
 
 func hasTag(tags string, target string) bool {
@@ -207,76 +358,45 @@ func (s *AchievementService) UpdateAchievementStatus(ctx context.Context, userUU
 	if achievementID == "" {
 		return "", errors.New("achievement_id is required")
 	}
-	if s.repo == nil || s.betRepo == nil {
+	if s.repo == nil {
 		return "", errors.New("achievement service dependencies are not configured")
 	}
 
-	switch achievementID {
-	case "first_bet_success":
-		hasWin, err := s.betRepo.HasWinningBet(ctx, userUUID)
-		if err != nil {
-			return "", err
-		}
-		if !hasWin {
-			return "not_completed", nil
-		}
-
-		achievement, err := s.repo.GetAchievementByID(ctx, achievementID)
-		if err != nil {
-			return "", err
-		}
-		needSteps := achievement.Steps
-		if needSteps <= 0 {
-			needSteps = 1
-		}
-
-		created, err := s.repo.AddUserAchievement(ctx, userUUID, achievementID, needSteps, needSteps)
-		if err != nil {
-			return "", err
-		}
-		if !created {
-			return "already_exists", nil
-		}
-		return "created", nil
-	case "wins_10", "wins_50", "wins_100", "wins_250", "wins_500", "wins_1000", "wins_5000", "wins_10000":
-		achievement, err := s.repo.GetAchievementByID(ctx, achievementID)
-		if err != nil {
-			return "", err
-		}
-		needSteps := achievement.Steps
-		if needSteps <= 0 {
-			return "", errors.New("achievement has invalid steps")
-		}
+	rule, err := s.repo.GetAchievementRule(ctx, achievementID)
+	if err != nil {
+		return "", err
+	}
+	if rule == nil {
+		return "", errors.New("unsupported achievement id")
+	}
 
-		wins, err := s.betRepo.GetWinningBetsByUser(ctx, userUUID)
-		if err != nil {
-			return "", err
-		}
-		if len(wins) < needSteps {
-			return "not_completed", nil
-		}
+	status, err := s.getUserAchievementStatus(ctx, userUUID, achievementID)
+	if err != nil {
+		return "", err
+	}
+	if status != nil && (status.ClaimedStatus || (status.NeedSteps > 0 && status.StepsGot >= status.NeedSteps)) {
+		return "already_exists", nil
+	}
 
-		created, err := s.repo.AddUserAchievement(ctx, userUUID, achievementID, needSteps, needSteps)
-		if err != nil {
-			return "", err
-		}
-		if !created {
-			return "already_exists", nil
-		}
-		return "created", nil
-	default:
-		return "", errors.New("unsupported achievement id")
+	completed, err := s.syncAchievementProgress(ctx, userUUID, *rule, status)
+	if err != nil {
+		return "", err
 	}
+	if !completed {
+		return "not_completed", nil
+	}
+	return "created", nil
 }
 
-func (s *AchievementService) ClaimAchievement(ctx context.Context, userUUID string, achievementID string) (*domain.Prize, error) {
+// ClaimAchievement records the achievement's prize and grants its reward (points, spins, a boost or a badge)
+func (s *AchievementService) ClaimAchievement(ctx context.Context, userUUID string, achievementID string) (*domain.AchievementClaimResult, error) {
 	if userUUID == "" {
 		return nil, errors.New("user uuid is required")
 	}
 	if achievementID == "" {
 		return nil, errors.New("achievement_id is required")
 	}
-	if s.repo == nil || s.prizeRepo == nil || s.prizeValueRepo == nil || s.ratingRepo == nil {
+	if s.repo == nil || s.prizeRepo == nil || s.prizeValueRepo == nil || s.rewards == nil {
 		return nil, errors.New("achievement service dependencies are not configured")
 	}
 
@@ -322,6 +442,10 @@ func (s *AchievementService) ClaimAchievement(ctx context.Context, userUUID stri
 		return nil, errors.New("prize value not found")
 	}
 
+	if err := s.rewards.Validate(ctx, prizeValue); err != nil {
+		return nil, err
+	}
+
 	prizeValueStr := prizeValue.Label
 	if prizeValueStr == "" {
 		prizeValueStr = strconv.FormatInt(prizeValue.Value, 10)
@@ -343,15 +467,19 @@ func (s *AchievementService) ClaimAchievement(ctx context.Context, userUUID stri
 		return nil, fmt.Errorf("failed to create prize record: %w", err)
 	}
 
-	points := prizeValue.Value
-	description := fmt.Sprintf("Achievement %s: %d points", achievement.ID, points)
-	if err := s.ratingRepo.AddPoints(ctx, userUUID, points, &prize.ID, nil, description); err != nil {
-		return nil, fmt.Errorf("failed to add achievement points: %w", err)
+	reward, err := s.rewards.Grant(ctx, RewardGrant{
+		UserUUID:   userUUID,
+		PrizeValue: prizeValue,
+		Prize:      prize,
+		Source:     "Achievement " + achievement.ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to grant achievement reward: %w", err)
 	}
 
 	if err := s.repo.UpdateUserAchievementClaimStatus(ctx, userUUID, achievementID, true); err != nil {
 		return nil, fmt.Errorf("failed to update achievement claim: %w", err)
 	}
 
-	return prize, nil
+	return &domain.AchievementClaimResult{AchievementID: achievementID, Prize: prize, Reward: reward}, nil
 }
@@ -9,11 +9,17 @@ import (
 	"time"
 )
 
+// BetSettledHook runs after a bet got its close price. Bets closed lazily by GetBetStatus run it too,
+// so hooks must be idempotent.
+type BetSettledHook func(ctx context.Context, betID int) error
+
 // BetScheduler manages async timers for bet closing
 // It schedules bet closing tasks that fetch prices from Pyth after the timeframe expires
 type BetScheduler struct {
 	repo          data.BetRepository
 	priceProvider *PriceProvider
+	skillService  *SkillService
+	settledHooks  []BetSettledHook
 	timers        map[int]*timerInfo
 	mu            sync.RWMutex
 	ctx           context.Context
@@ -29,17 +35,32 @@ type timerInfo struct {
 }
 
 // NewBetScheduler creates a new bet scheduler
-func NewBetScheduler(repo data.BetRepository, priceProvider *PriceProvider) *BetScheduler {
+func NewBetScheduler(repo data.BetRepository, priceProvider *PriceProvider, skillService *SkillService) *BetScheduler {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &BetScheduler{
 		repo:          repo,
 		priceProvider: priceProvider,
+		skillService:  skillService,
 		timers:        make(map[int]*timerInfo),
 		ctx:           ctx,
 		cancel:        cancel,
 	}
 }
 
+// OnSettled registers a hook run after bets are closed. Hooks run in registration order.
+func (s *BetScheduler) OnSettled(hook BetSettledHook) {
+	s.settledHooks = append(s.settledHooks, hook)
+}
+
+// runSettledHooks runs the settlement hooks for a closed bet, logging failures.
+func (s *BetScheduler) runSettledHooks(ctx context.Context, betID int) {
+	for _, hook := range s.settledHooks {
+		if err := hook(ctx, betID); err != nil {
+			log.Printf("Error running settlement hook for bet %d: %v", betID, err)
+		}
+	}
+}
+
 // ScheduleBetClosing schedules a bet to be closed after the specified timeframe
 // It fetches the current price from Binance when the bet is opened,
 // then schedules another fetch after the timeframe expires
@@ -127,6 +148,14 @@ func (s *BetScheduler) closeBet(betID int, pair string) error {
 	}
 
 	log.Printf("Successfully closed bet %d with price %.8f at %s", betID, closePrice, closeTime.Format(time.RFC3339))
+
+	// Settlement updates the owner's skill rating
+	if s.skillService != nil {
+		if err := s.skillService.ApplyBetSettlement(ctx, betID); err != nil {
+			log.Printf("Error updating skill rating for bet %d: %v", betID, err)
+		}
+	}
+	s.runSettledHooks(ctx, betID)
 	return nil
 }
 
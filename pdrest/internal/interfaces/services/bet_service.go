@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"math"
 	"pdrest/internal/data"
 	"pdrest/internal/domain"
@@ -11,21 +12,44 @@ import (
 )
 
 type BetService struct {
-	repo          data.BetRepository
-	priceProvider *PriceProvider
-	scheduler     *BetScheduler
-	ratingRepo    data.RatingRepository
+	repo            data.BetRepository
+	priceProvider   *PriceProvider
+	scheduler       *BetScheduler
+	ratingRepo      data.RatingRepository
+	inventoryRepo   data.InventoryRepository
+	xpRepo          data.XPRepository
+	skillService    *SkillService
+	referralService *ReferralService
+	bus             *DomainEventBus
 }
 
-func NewBetService(r data.BetRepository, priceProvider *PriceProvider, scheduler *BetScheduler, ratingRepo data.RatingRepository) *BetService {
+func NewBetService(r data.BetRepository, priceProvider *PriceProvider, scheduler *BetScheduler, ratingRepo data.RatingRepository, inventoryRepo data.InventoryRepository, xpRepo data.XPRepository, skillService *SkillService, referralService *ReferralService, bus *DomainEventBus) *BetService {
 	return &BetService{
-		repo:          r,
-		priceProvider: priceProvider,
-		scheduler:     scheduler,
-		ratingRepo:    ratingRepo,
+		repo:            r,
+		priceProvider:   priceProvider,
+		scheduler:       scheduler,
+		ratingRepo:      ratingRepo,
+		inventoryRepo:   inventoryRepo,
+		xpRepo:          xpRepo,
+		skillService:    skillService,
+		referralService: referralService,
+		bus:             bus,
 	}
 }
 
+// PublishBetSettled publishes BetSettled for a closed bet; it is registered as a scheduler settlement hook.
+func (s *BetService) PublishBetSettled(ctx context.Context, betID int) error {
+	bet, err := s.repo.GetBetByIDAnyUser(ctx, betID)
+	if err != nil {
+		return err
+	}
+	if bet == nil || bet.ClosePrice == nil {
+		return nil
+	}
+	s.bus.Publish(ctx, domain.DomainEvent{Type: domain.DomainEventBetSettled, UserUUID: bet.UserID, Bet: bet})
+	return nil
+}
+
 func (s *BetService) OpenBet(ctx context.Context, userUUID string, req *domain.OpenBetRequest) (*domain.OpenBetResponse, error) {
 	// Validate side
 	if req.Side != "pump" && req.Side != "dump" {
@@ -40,6 +64,17 @@ func (s *BetService) OpenBet(ctx context.Context, userUUID string, req *domain.O
 		return nil, errors.New("sum must be a whole number of USDT")
 	}
 
+	// The user's level caps the stake
+	if s.xpRepo != nil {
+		level, err := s.xpRepo.GetUserLevel(ctx, userUUID)
+		if err != nil {
+			return nil, err
+		}
+		if level.Perks.MaxStake != nil && req.Sum > *level.Perks.MaxStake {
+			return nil, fmt.Errorf("sum must be at most %g USDT at level %d", *level.Perks.MaxStake, level.Level)
+		}
+	}
+
 	// Validate pair
 	if req.Pair == "" {
 		return nil, errors.New("pair is required")
@@ -70,7 +105,14 @@ func (s *BetService) OpenBet(ctx context.Context, userUUID string, req *domain.O
 		OpenTime:  req.OpenTime.UTC(),
 	}
 
-	if err := s.repo.CreateBet(ctx, bet); err != nil {
+	// Active inventory items (payout boosts, loss shields) are applied in the transaction opening the bet
+	var err error
+	if s.inventoryRepo != nil {
+		err = s.inventoryRepo.CreateBetWithModifiers(ctx, bet, time.Now().UnixMilli())
+	} else {
+		err = s.repo.CreateBet(ctx, bet)
+	}
+	if err != nil {
 		return nil, fmt.Errorf("failed to create bet: %w", err)
 	}
 
@@ -106,8 +148,9 @@ func (s *BetService) GetBetStatus(ctx context.Context, betID int, userUUID strin
 	timeframeDuration := time.Duration(bet.Timeframe) * time.Second
 	expectedCloseTime := bet.OpenTime.Add(timeframeDuration)
 
-	// If timeframe has passed and closePrice is not set, fetch from provider
-	if now.After(expectedCloseTime) && bet.ClosePrice == nil {
+	// If timeframe has passed and closePrice is not set, fetch from provider.
+	// Duel positions are closed together at one price when the duel settles.
+	if now.After(expectedCloseTime) && bet.ClosePrice == nil && bet.DuelID == nil {
 		if s.priceProvider != nil {
 			closePrice, err := s.priceProvider.GetPrice(bet.Pair)
 			if err != nil {
@@ -123,6 +166,13 @@ func (s *BetService) GetBetStatus(ctx context.Context, betID int, userUUID strin
 					// Update local bet object
 					bet.ClosePrice = &closePrice
 					bet.CloseTime = &closeTime
+					if s.skillService != nil {
+						// Log error but don't fail - ClaimBet applies it again idempotently
+						_ = s.skillService.ApplyBetSettlement(ctx, betID)
+					}
+					if s.scheduler != nil {
+						s.scheduler.runSettledHooks(ctx, betID)
+					}
 				}
 			}
 		}
@@ -137,6 +187,7 @@ func (s *BetService) GetBetStatus(ctx context.Context, betID int, userUUID strin
 		ClosePrice: bet.ClosePrice,
 		OpenTime:   bet.OpenTime,
 		Claimed:    bet.Claimed,
+		Modifiers:  bet.Modifiers,
 	}, nil
 }
 
@@ -209,19 +260,60 @@ func (s *BetService) ClaimBet(ctx context.Context, betID int, userUUID string) (
 		return false, fmt.Errorf("failed to claim bet: %w", err)
 	}
 
+	// Skill rating is normally updated when the scheduler closes the bet; this is a no-op then.
+	if s.skillService != nil {
+		if err := s.skillService.ApplyBetSettlement(ctx, betID); err != nil {
+			log.Printf("Error updating skill rating for bet %d: %v", betID, err)
+		}
+	}
+
+	bet.Claimed = true
+	s.bus.Publish(ctx, domain.DomainEvent{Type: domain.DomainEventBetClaimed, UserUUID: userUUID, Bet: bet})
+
+	// A duel's pot was paid when the duel settled.
+	if bet.DuelID != nil {
+		return determinePrizeStatus(*bet) == "win", nil
+	}
+
 	if s.ratingRepo == nil {
 		return false, errors.New("rating repository is not configured")
 	}
 
 	points := betPoints(bet)
 	description := fmt.Sprintf("Bet %d %s: %d points", bet.ID, determinePrizeStatus(*bet), points)
-	if err := s.ratingRepo.AddPoints(ctx, userUUID, points, nil, &bet.ID, description); err != nil {
+	paid, applied := modifiedBetPoints(bet, points)
+	if applied != "" {
+		description = fmt.Sprintf("Bet %d %s: %d points (%s)", bet.ID, determinePrizeStatus(*bet), paid, applied)
+	}
+	if err := s.ratingRepo.AddPoints(ctx, userUUID, paid, nil, &bet.ID, description); err != nil {
 		return false, fmt.Errorf("failed to add bet points: %w", err)
 	}
 
+	// Referral payouts must not fail the claim; they are idempotent per bet.
+	if s.referralService != nil {
+		if err := s.referralService.OnBetClaimed(ctx, bet, points); err != nil {
+			log.Printf("Error paying referral rewards for bet %d: %v", betID, err)
+		}
+	}
+
 	return determinePrizeStatus(*bet) == "win", nil
 }
 
+// modifiedBetPoints applies the modifiers recorded when the bet was opened: a payout boost multiplies a win,
+// a loss shield cancels a loss. It returns the points paid and the applied modifier; referral payouts keep
+// using the unmodified points.
+func modifiedBetPoints(bet *domain.Bet, points int64) (int64, string) {
+	for _, modifier := range bet.Modifiers {
+		switch {
+		case modifier.Type == domain.InventoryItemPayoutBoost && points > 0 && modifier.Multiplier > 1:
+			return int64(math.Round(float64(points) * modifier.Multiplier)), fmt.Sprintf("%gx boost", modifier.Multiplier)
+		case modifier.Type == domain.InventoryItemLossShield && points < 0:
+			return 0, "loss shield"
+		}
+	}
+	return points, ""
+}
+
 func determinePrizeStatus(bet domain.Bet) string {
 	if bet.ClosePrice == nil {
 		return "pending"
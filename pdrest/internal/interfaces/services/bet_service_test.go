@@ -0,0 +1,42 @@
+package services
+
+import (
+	"pdrest/internal/domain"
+	"testing"
+)
+
+func TestModifiedBetPoints(t *testing.T) {
+	boost := func(multiplier float64) domain.BetModifier {
+		return domain.BetModifier{ItemID: 1, Type: domain.InventoryItemPayoutBoost, Multiplier: multiplier}
+	}
+	shield := domain.BetModifier{ItemID: 2, Type: domain.InventoryItemLossShield}
+
+	tests := []struct {
+		name      string
+		modifiers []domain.BetModifier
+		points    int64
+		want      int64
+		wantLabel string
+	}{
+		{name: "no modifiers win", points: 10, want: 10},
+		{name: "no modifiers loss", points: -10, want: -10},
+		{name: "boost multiplies a win", modifiers: []domain.BetModifier{boost(2)}, points: 10, want: 20, wantLabel: "2x boost"},
+		{name: "boost rounds the payout", modifiers: []domain.BetModifier{boost(1.5)}, points: 5, want: 8, wantLabel: "1.5x boost"},
+		{name: "boost does not touch a loss", modifiers: []domain.BetModifier{boost(2)}, points: -10, want: -10},
+		{name: "boost of 1x or less is ignored", modifiers: []domain.BetModifier{boost(1)}, points: 10, want: 10},
+		{name: "shield cancels a loss", modifiers: []domain.BetModifier{shield}, points: -10, want: 0, wantLabel: "loss shield"},
+		{name: "shield does not touch a win", modifiers: []domain.BetModifier{shield}, points: 10, want: 10},
+		{name: "boost and shield on a win", modifiers: []domain.BetModifier{boost(3), shield}, points: 10, want: 30, wantLabel: "3x boost"},
+		{name: "boost and shield on a loss", modifiers: []domain.BetModifier{boost(3), shield}, points: -10, want: 0, wantLabel: "loss shield"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bet := &domain.Bet{Modifiers: tt.modifiers}
+			got, label := modifiedBetPoints(bet, tt.points)
+			if got != tt.want || label != tt.wantLabel {
+				t.Fatalf("modifiedBetPoints(%d) = %d %q, want %d %q", tt.points, got, label, tt.want, tt.wantLabel)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"log"
+	"pdrest/internal/domain"
+	"sync"
+	"time"
+)
+
+// DomainEventHandler handles a published domain event. Events may be published more than once
+// (e.g. a bet settled by the scheduler and again lazily), so handlers must be idempotent.
+type DomainEventHandler func(ctx context.Context, event domain.DomainEvent) error
+
+// DomainEventBus delivers domain events to their subscribers synchronously, in subscription order.
+// A failing handler is logged and doesn't stop the others or the publisher.
+type DomainEventBus struct {
+	handlers map[string][]DomainEventHandler
+	mu       sync.RWMutex
+}
+
+// NewDomainEventBus creates an empty event bus
+func NewDomainEventBus() *DomainEventBus {
+	return &DomainEventBus{handlers: make(map[string][]DomainEventHandler)}
+}
+
+// Subscribe registers a handler for the event types
+func (b *DomainEventBus) Subscribe(handler DomainEventHandler, eventTypes ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, eventType := range eventTypes {
+		b.handlers[eventType] = append(b.handlers[eventType], handler)
+	}
+}
+
+// Publish delivers the event to its subscribers. Publishing on a nil bus is a no-op, so services
+// built without a bus need no checks.
+func (b *DomainEventBus) Publish(ctx context.Context, event domain.DomainEvent) {
+	if b == nil {
+		return
+	}
+	if event.OccurredAt == 0 {
+		event.OccurredAt = time.Now().UnixMilli()
+	}
+
+	b.mu.RLock()
+	handlers := b.handlers[event.Type]
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			log.Printf("Error handling %s event for user %s: %v", event.Type, event.UserUUID, err)
+		}
+	}
+}
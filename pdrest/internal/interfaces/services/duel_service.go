@@ -0,0 +1,432 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"pdrest/internal/data"
+	"pdrest/internal/domain"
+	"strings"
+	"time"
+)
+
+// duelSettleGrace is how long after its positions were due to close an active duel is left to the bet
+// scheduler before the duel job settles it itself.
+const duelSettleGrace = time.Minute
+
+// DuelService runs head-to-head duels. The challenger's stake is escrowed when the duel is proposed and the
+// opponent's when it is accepted; both positions then open at the same server price on opposite sides.
+// The challenger's position is the duel's lead bet: when the bet scheduler closes it, both positions are
+// closed at its price and the winner takes the pot.
+type DuelService struct {
+	repo          data.DuelRepository
+	betRepo       data.BetRepository
+	priceProvider *PriceProvider
+	scheduler     *BetScheduler
+	skillService  *SkillService
+	expiry        time.Duration
+}
+
+func NewDuelService(repo data.DuelRepository, betRepo data.BetRepository, priceProvider *PriceProvider, scheduler *BetScheduler, skillService *SkillService, expiry time.Duration) *DuelService {
+	if expiry <= 0 {
+		expiry = time.Hour
+	}
+	return &DuelService{
+		repo:          repo,
+		betRepo:       betRepo,
+		priceProvider: priceProvider,
+		scheduler:     scheduler,
+		skillService:  skillService,
+		expiry:        expiry,
+	}
+}
+
+// CreateDuel proposes a duel to a friend, or to whoever opens its invite link when no opponent is given.
+// The stake is debited from the challenger until the duel is accepted, declined, cancelled or expires.
+func (s *DuelService) CreateDuel(ctx context.Context, userUUID string, req *domain.CreateDuelRequest) (*domain.Duel, error) {
+	if userUUID == "" {
+		return nil, errors.New("user uuid is required")
+	}
+	if s.repo == nil || s.priceProvider == nil {
+		return nil, errors.New("duel service dependencies are not configured")
+	}
+
+	pair := strings.ToUpper(strings.TrimSpace(req.Pair))
+	opponentUUID := strings.TrimSpace(req.OpponentUUID)
+	switch {
+	case req.Side != "pump" && req.Side != "dump":
+		return nil, errors.New("side must be 'pump' or 'dump'")
+	case req.Stake <= 0:
+		return nil, errors.New("stake must be greater than 0")
+	case pair == "":
+		return nil, errors.New("pair is required")
+	case req.Timeframe <= 0:
+		return nil, errors.New("timeframe must be greater than 0")
+	case opponentUUID == userUUID:
+		return nil, errors.New("cannot challenge yourself")
+	}
+	if opponentUUID != "" {
+		friends, err := s.repo.AreFriends(ctx, userUUID, opponentUUID)
+		if err != nil {
+			return nil, err
+		}
+		if !friends {
+			return nil, errors.New("opponent must be a friend")
+		}
+	}
+	if _, err := s.priceProvider.GetPrice(pair); err != nil {
+		return nil, fmt.Errorf("pair is not supported: %w", err)
+	}
+
+	inviteCode, err := generateInviteCode()
+	if err != nil {
+		return nil, err
+	}
+	duel := &domain.Duel{
+		ChallengerUUID: userUUID,
+		OpponentUUID:   opponentUUID,
+		InviteCode:     inviteCode,
+		Pair:           pair,
+		Timeframe:      req.Timeframe,
+		Stake:          req.Stake,
+		ChallengerSide: req.Side,
+		ExpiresAt:      time.Now().UTC().Add(s.expiry).UnixMilli(),
+	}
+	var notification *domain.Notification
+	if opponentUUID != "" {
+		notification = &domain.Notification{
+			UserUUID: opponentUUID,
+			Producer: domain.NotificationProducerDuels,
+			Message:  fmt.Sprintf("You have been challenged to a %d-point duel on %s. Accept it in the app.", duel.Stake, duel.Pair),
+		}
+	}
+	if err := s.repo.CreateDuel(ctx, duel, notification); err != nil {
+		return nil, err
+	}
+
+	return s.GetDuel(ctx, userUUID, duel.ID)
+}
+
+// GetDuel returns one of the user's duels.
+func (s *DuelService) GetDuel(ctx context.Context, userUUID string, duelID int) (*domain.Duel, error) {
+	if s.repo == nil {
+		return nil, errors.New("duel service dependencies are not configured")
+	}
+	duel, err := s.repo.GetDuel(ctx, duelID)
+	if err != nil {
+		return nil, err
+	}
+	if duel == nil || (duel.ChallengerUUID != userUUID && duel.OpponentUUID != userUUID) {
+		return nil, errors.New("duel not found")
+	}
+	return presentDuel(duel, userUUID), nil
+}
+
+// GetDuelByInviteCode returns the duel behind an invite link, so its terms can be shown before accepting.
+func (s *DuelService) GetDuelByInviteCode(ctx context.Context, userUUID string, inviteCode string) (*domain.Duel, error) {
+	duel, err := s.duelByInviteCode(ctx, inviteCode)
+	if err != nil {
+		return nil, err
+	}
+	return presentDuel(duel, userUUID), nil
+}
+
+// GetUserDuels lists the duels the user proposed or was challenged to, newest first.
+func (s *DuelService) GetUserDuels(ctx context.Context, userUUID string, limit, offset int) ([]domain.Duel, error) {
+	if userUUID == "" {
+		return nil, errors.New("user uuid is required")
+	}
+	if s.repo == nil {
+		return nil, errors.New("duel service dependencies are not configured")
+	}
+
+	duels, err := s.repo.GetUserDuels(ctx, userUUID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if duels == nil {
+		duels = make([]domain.Duel, 0)
+	}
+	for i := range duels {
+		duels[i] = *presentDuel(&duels[i], userUUID)
+	}
+	return duels, nil
+}
+
+// AcceptDuel accepts a duel the user was challenged to.
+func (s *DuelService) AcceptDuel(ctx context.Context, userUUID string, duelID int) (*domain.Duel, error) {
+	if s.repo == nil {
+		return nil, errors.New("duel service dependencies are not configured")
+	}
+	duel, err := s.repo.GetDuel(ctx, duelID)
+	if err != nil {
+		return nil, err
+	}
+	if duel == nil || duel.OpponentUUID != userUUID {
+		return nil, errors.New("duel not found")
+	}
+	return s.accept(ctx, userUUID, duel)
+}
+
+// AcceptDuelByInviteCode accepts the duel behind an invite link.
+func (s *DuelService) AcceptDuelByInviteCode(ctx context.Context, userUUID string, inviteCode string) (*domain.Duel, error) {
+	duel, err := s.duelByInviteCode(ctx, inviteCode)
+	if err != nil {
+		return nil, err
+	}
+	if duel.OpponentUUID != "" && duel.OpponentUUID != userUUID {
+		return nil, errors.New("duel is meant for another user")
+	}
+	return s.accept(ctx, userUUID, duel)
+}
+
+// accept escrows the opponent's stake, opens both positions at the current server price and schedules
+// the lead bet's closing.
+func (s *DuelService) accept(ctx context.Context, userUUID string, duel *domain.Duel) (*domain.Duel, error) {
+	if userUUID == "" {
+		return nil, errors.New("user uuid is required")
+	}
+	if s.priceProvider == nil {
+		return nil, errors.New("duel service dependencies are not configured")
+	}
+	if duel.ChallengerUUID == userUUID {
+		return nil, errors.New("cannot accept your own duel")
+	}
+	if duel.Status != domain.DuelPending {
+		return nil, errors.New("duel is already " + duel.Status)
+	}
+	now := time.Now().UTC()
+	if now.UnixMilli() >= duel.ExpiresAt {
+		return nil, errors.New("duel has expired")
+	}
+
+	openPrice, err := s.priceProvider.GetPrice(duel.Pair)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open price: %w", err)
+	}
+	accepted, err := s.repo.AcceptDuel(ctx, duel.ID, userUUID, openPrice, now, now.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	if !accepted {
+		return nil, errors.New("duel is no longer pending")
+	}
+
+	duel, err = s.repo.GetDuel(ctx, duel.ID)
+	if err != nil {
+		return nil, err
+	}
+	if s.scheduler != nil && duel.ChallengerBetID != nil {
+		// The duel job settles the duel if this fails.
+		if err := s.scheduler.ScheduleBetClosing(*duel.ChallengerBetID, duel.Pair, now, duel.Timeframe); err != nil {
+			log.Printf("duels: failed to schedule closing of duel %d: %v", duel.ID, err)
+		}
+	}
+	return presentDuel(duel, userUUID), nil
+}
+
+// DeclineDuel turns down a duel the user was challenged to and refunds the challenger's stake.
+func (s *DuelService) DeclineDuel(ctx context.Context, userUUID string, duelID int) error {
+	duel, err := s.pendingDuel(ctx, duelID)
+	if err != nil {
+		return err
+	}
+	if duel.OpponentUUID != userUUID {
+		return errors.New("only the challenged user can decline a duel")
+	}
+
+	notification := &domain.Notification{
+		UserUUID: duel.ChallengerUUID,
+		Producer: domain.NotificationProducerDuels,
+		Message:  fmt.Sprintf("%s declined your duel on %s. Your %d-point stake was refunded.", duel.OpponentName, duel.Pair, duel.Stake),
+	}
+	return s.endPending(ctx, duel, domain.DuelDeclined, notification)
+}
+
+// CancelDuel withdraws a duel the user proposed and refunds the stake.
+func (s *DuelService) CancelDuel(ctx context.Context, userUUID string, duelID int) error {
+	duel, err := s.pendingDuel(ctx, duelID)
+	if err != nil {
+		return err
+	}
+	if duel.ChallengerUUID != userUUID {
+		return errors.New("only the challenger can cancel a duel")
+	}
+	return s.endPending(ctx, duel, domain.DuelCancelled, nil)
+}
+
+// OnBetSettled settles the duel whose lead bet was closed; it is a bet settlement hook.
+func (s *DuelService) OnBetSettled(ctx context.Context, betID int) error {
+	if s.repo == nil || s.betRepo == nil {
+		return nil
+	}
+	duel, err := s.repo.GetDuelByBetID(ctx, betID)
+	if err != nil || duel == nil || duel.Status != domain.DuelActive || duel.ChallengerBetID == nil || *duel.ChallengerBetID != betID {
+		return err
+	}
+	bet, err := s.betRepo.GetBetByIDAnyUser(ctx, betID)
+	if err != nil || bet == nil || bet.ClosePrice == nil || bet.CloseTime == nil {
+		return err
+	}
+	return s.settle(ctx, duel, *bet.ClosePrice, *bet.CloseTime)
+}
+
+// Resolve expires pending duels nobody accepted in time and settles active duels the bet scheduler missed
+// (e.g. after a restart); it is the duels' periodic job.
+func (s *DuelService) Resolve(ctx context.Context, now time.Time) error {
+	if s.repo == nil || s.betRepo == nil || s.priceProvider == nil {
+		return errors.New("duel service dependencies are not configured")
+	}
+
+	duels, err := s.repo.GetDuelsToResolve(ctx, now.UTC().UnixMilli(), now.UTC().Add(-duelSettleGrace))
+	if err != nil {
+		return err
+	}
+	for i := range duels {
+		duel := &duels[i]
+		if duel.Status == domain.DuelPending {
+			notification := &domain.Notification{
+				UserUUID: duel.ChallengerUUID,
+				Producer: domain.NotificationProducerDuels,
+				Message:  fmt.Sprintf("Nobody accepted your duel on %s in time. Your %d-point stake was refunded.", duel.Pair, duel.Stake),
+			}
+			if err := s.endPending(ctx, duel, domain.DuelExpired, notification); err != nil {
+				log.Printf("duels: failed to expire duel %d: %v", duel.ID, err)
+			}
+			continue
+		}
+		if err := s.settleOverdue(ctx, duel); err != nil {
+			log.Printf("duels: failed to settle duel %d: %v", duel.ID, err)
+		}
+	}
+	return nil
+}
+
+// settleOverdue closes an active duel's lead bet at the current price if it is still open, then settles the duel.
+func (s *DuelService) settleOverdue(ctx context.Context, duel *domain.Duel) error {
+	if duel.ChallengerBetID == nil {
+		return errors.New("duel has no lead bet")
+	}
+	bet, err := s.betRepo.GetBetByIDAnyUser(ctx, *duel.ChallengerBetID)
+	if err != nil {
+		return err
+	}
+	if bet == nil {
+		return errors.New("duel lead bet not found")
+	}
+
+	if bet.ClosePrice == nil {
+		closePrice, err := s.priceProvider.GetPrice(bet.Pair)
+		if err != nil {
+			return fmt.Errorf("failed to get close price: %w", err)
+		}
+		closeTime := time.Now().UTC()
+		if err := s.betRepo.UpdateBetClosePrice(ctx, bet.ID, closePrice, closeTime); err != nil {
+			return err
+		}
+		bet.ClosePrice = &closePrice
+		bet.CloseTime = &closeTime
+	}
+	return s.settle(ctx, duel, *bet.ClosePrice, *bet.CloseTime)
+}
+
+// settle closes both positions at the lead bet's close price and pays the pot. A price that did not move
+// is a draw and refunds both stakes.
+func (s *DuelService) settle(ctx context.Context, duel *domain.Duel, closePrice float64, closeTime time.Time) error {
+	if duel.OpenPrice == nil {
+		return errors.New("duel has no open price")
+	}
+
+	winnerUUID, loserUUID := "", ""
+	switch {
+	case closePrice > *duel.OpenPrice && duel.ChallengerSide == "pump",
+		closePrice < *duel.OpenPrice && duel.ChallengerSide == "dump":
+		winnerUUID, loserUUID = duel.ChallengerUUID, duel.OpponentUUID
+	case closePrice != *duel.OpenPrice:
+		winnerUUID, loserUUID = duel.OpponentUUID, duel.ChallengerUUID
+	}
+
+	var notifications []domain.Notification
+	if winnerUUID != "" {
+		notifications = []domain.Notification{
+			{UserUUID: winnerUUID, Producer: domain.NotificationProducerDuels, Message: fmt.Sprintf("You won your duel on %s and take the %d-point pot!", duel.Pair, 2*duel.Stake)},
+			{UserUUID: loserUUID, Producer: domain.NotificationProducerDuels, Message: fmt.Sprintf("You lost your duel on %s.", duel.Pair)},
+		}
+	} else {
+		for _, userUUID := range []string{duel.ChallengerUUID, duel.OpponentUUID} {
+			notifications = append(notifications, domain.Notification{
+				UserUUID: userUUID,
+				Producer: domain.NotificationProducerDuels,
+				Message:  fmt.Sprintf("Your duel on %s ended in a draw. Your %d-point stake was refunded.", duel.Pair, duel.Stake),
+			})
+		}
+	}
+
+	settled, err := s.repo.SettleDuel(ctx, duel.ID, closePrice, closeTime, winnerUUID, notifications, time.Now().UTC().UnixMilli())
+	if err != nil || !settled {
+		return err
+	}
+
+	// The lead bet's skill update ran when it was closed; the opponent's position was closed with the duel.
+	if s.skillService != nil && duel.OpponentBetID != nil {
+		if err := s.skillService.ApplyBetSettlement(ctx, *duel.OpponentBetID); err != nil {
+			log.Printf("duels: failed to update skill rating for bet %d: %v", *duel.OpponentBetID, err)
+		}
+	}
+	return nil
+}
+
+func (s *DuelService) pendingDuel(ctx context.Context, duelID int) (*domain.Duel, error) {
+	if s.repo == nil {
+		return nil, errors.New("duel service dependencies are not configured")
+	}
+	duel, err := s.repo.GetDuel(ctx, duelID)
+	if err != nil {
+		return nil, err
+	}
+	if duel == nil {
+		return nil, errors.New("duel not found")
+	}
+	if duel.Status != domain.DuelPending {
+		return nil, errors.New("duel is already " + duel.Status)
+	}
+	return duel, nil
+}
+
+func (s *DuelService) endPending(ctx context.Context, duel *domain.Duel, status string, notification *domain.Notification) error {
+	ended, err := s.repo.EndPendingDuel(ctx, duel.ID, status, notification)
+	if err != nil {
+		return err
+	}
+	if !ended {
+		return errors.New("duel is no longer pending")
+	}
+	return nil
+}
+
+func (s *DuelService) duelByInviteCode(ctx context.Context, inviteCode string) (*domain.Duel, error) {
+	if s.repo == nil {
+		return nil, errors.New("duel service dependencies are not configured")
+	}
+	inviteCode = strings.ToUpper(strings.TrimSpace(inviteCode))
+	if inviteCode == "" {
+		return nil, errors.New("inviteCode is required")
+	}
+	duel, err := s.repo.GetDuelByInviteCode(ctx, inviteCode)
+	if err != nil {
+		return nil, err
+	}
+	if duel == nil {
+		return nil, errors.New("duel not found")
+	}
+	return duel, nil
+}
+
+// presentDuel hides the invite code from everyone but the challenger.
+func presentDuel(duel *domain.Duel, userUUID string) *domain.Duel {
+	if duel.ChallengerUUID != userUUID {
+		duel.InviteCode = ""
+	}
+	return duel
+}
@@ -0,0 +1,600 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"pdrest/internal/data"
+	"pdrest/internal/domain"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var eventIDPattern = regexp.MustCompile(`^[a-z0-9_]{1,50}$`)
+
+// EventAdminService manages events with their prize values and achievements for the admin API.
+type EventAdminService struct {
+	repo      data.EventRepository
+	lifecycle *EventLifecycleService
+}
+
+func NewEventAdminService(repo data.EventRepository, lifecycle *EventLifecycleService) *EventAdminService {
+	return &EventAdminService{repo: repo, lifecycle: lifecycle}
+}
+
+func (s *EventAdminService) GetEvents(ctx context.Context, includeArchived bool) ([]domain.AdminEvent, error) {
+	if s.repo == nil {
+		return nil, errors.New("event repository is not configured")
+	}
+
+	events, err := s.repo.GetAdminEvents(ctx, includeArchived)
+	if err != nil {
+		return nil, err
+	}
+	if events == nil {
+		events = make([]domain.AdminEvent, 0)
+	}
+	return events, nil
+}
+
+func (s *EventAdminService) GetEvent(ctx context.Context, eventID string) (*domain.AdminEvent, error) {
+	if eventID == "" {
+		return nil, errors.New("event_id is required")
+	}
+	if s.repo == nil {
+		return nil, errors.New("event repository is not configured")
+	}
+
+	event, err := s.repo.GetAdminEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, errors.New("event not found")
+	}
+
+	event.Transitions, err = s.repo.GetEventStateTransitions(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func (s *EventAdminService) CreateEvent(ctx context.Context, event *domain.AdminEvent) (*domain.AdminEvent, error) {
+	if s.repo == nil {
+		return nil, errors.New("event repository is not configured")
+	}
+
+	event.ID = strings.TrimSpace(event.ID)
+	if !eventIDPattern.MatchString(event.ID) {
+		return nil, errors.New("id must be 1-50 characters of lowercase letters, digits or '_'")
+	}
+	normalizeAdminEvent(event)
+	if err := validateAdminEvent(event); err != nil {
+		return nil, err
+	}
+	if event.State == "" {
+		event.State = domain.EventStateScheduled
+	}
+	if event.State != domain.EventStateDraft && event.State != domain.EventStateScheduled {
+		return nil, errors.New("state must be draft or scheduled for a new event")
+	}
+
+	if err := s.repo.CreateAdminEvent(ctx, event); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAdminEvent(ctx, event.ID)
+}
+
+// UpdateEvent replaces the event fields. Prize values are replaced only when update.PrizeValues
+// is set, and only before the event starts so that awarded prizes keep their prize values.
+// The schedule can only be edited while the event is a draft or scheduled; ScheduleEvent moves
+// the deadline of an active event.
+func (s *EventAdminService) UpdateEvent(ctx context.Context, eventID string, update *domain.AdminEvent) (*domain.AdminEvent, error) {
+	current, err := s.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	update.ID = current.ID
+	replacePrizes := update.PrizeValues != nil
+	if !replacePrizes {
+		update.PrizeValues = current.PrizeValues
+	} else if !time.Now().UTC().Before(current.StartTime) {
+		return nil, errors.New("prize values cannot be changed after the event has started")
+	}
+	if current.PrizePool > 0 && eventEligibility(&update.Event).EntryFee != eventEligibility(&current.Event).EntryFee {
+		return nil, errors.New("entryFee cannot be changed after participants have paid it")
+	}
+	normalizeAdminEvent(update)
+	if !update.StartTime.Equal(current.StartTime) || !update.Deadline.Equal(current.Deadline) {
+		if current.State != domain.EventStateDraft && current.State != domain.EventStateScheduled {
+			return nil, fmt.Errorf("startTime and deadline cannot be changed once the event is %s", current.State)
+		}
+		if err := checkReschedule(current, update.StartTime, time.Now().UTC()); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateAdminEvent(update); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateAdminEvent(ctx, update, replacePrizes); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAdminEvent(ctx, eventID)
+}
+
+// ScheduleEvent moves the event's start and deadline. Finished events cannot be rescheduled.
+func (s *EventAdminService) ScheduleEvent(ctx context.Context, eventID string, req *domain.ScheduleEventRequest) (*domain.AdminEvent, error) {
+	event, err := s.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkReschedule(event, req.StartTime, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+
+	if req.StartTime.IsZero() || req.Deadline.IsZero() {
+		return nil, errors.New("startTime and deadline are required")
+	}
+	if !req.StartTime.Before(req.Deadline) {
+		return nil, errors.New("startTime must be before deadline")
+	}
+	event.StartTime = req.StartTime.UTC()
+	event.Deadline = req.Deadline.UTC()
+
+	if err := s.repo.UpdateAdminEvent(ctx, event, false); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAdminEvent(ctx, eventID)
+}
+
+// checkReschedule rejects moving the schedule of an event whose results are frozen or being frozen,
+// and moving the start of an event that has already started.
+func checkReschedule(event *domain.AdminEvent, startTime time.Time, now time.Time) error {
+	if event.State == domain.EventStateArchived {
+		return errors.New("archived event cannot be rescheduled")
+	}
+	if event.State == domain.EventStateFinalizing || event.State == domain.EventStateFinished {
+		return errors.New("finished event cannot be rescheduled")
+	}
+	if !now.Before(event.Deadline) {
+		return errors.New("finished event cannot be rescheduled")
+	}
+	if !now.Before(event.StartTime) && !startTime.Equal(event.StartTime) {
+		return errors.New("startTime cannot be changed after the event has started")
+	}
+	return nil
+}
+
+// ArchiveEvent hides the event from all lists. Only drafts, scheduled and finished events can be archived.
+// Returns false if it was already archived.
+func (s *EventAdminService) ArchiveEvent(ctx context.Context, eventID string) (bool, error) {
+	return s.SetEventState(ctx, eventID, domain.EventStateArchived)
+}
+
+// SetEventState publishes (scheduled), unpublishes (draft) or archives the event through its lifecycle.
+// Returns false if the event already was in that state.
+func (s *EventAdminService) SetEventState(ctx context.Context, eventID string, state domain.EventState) (bool, error) {
+	if _, err := s.GetEvent(ctx, eventID); err != nil {
+		return false, err
+	}
+	if s.lifecycle == nil {
+		return false, errors.New("event lifecycle is not configured")
+	}
+	return s.lifecycle.Transition(ctx, eventID, state)
+}
+
+// CloneEvent copies an event with its prize values and achievements under a new ID and schedule.
+// Achievement IDs prefixed with the source event ID are re-prefixed with the new ID.
+func (s *EventAdminService) CloneEvent(ctx context.Context, sourceID string, req *domain.CloneEventRequest) (*domain.AdminEvent, error) {
+	source, err := s.GetEvent(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	clone := *source
+	clone.ID = strings.TrimSpace(req.ID)
+	clone.ArchivedAt = nil
+	clone.State = ""
+	clone.StateChangedAt = nil
+	clone.Transitions = nil
+	clone.PrizePool = 0
+	clone.Eligibility = cloneEventEligibility(source.Eligibility)
+	clone.StartTime = req.StartTime
+	clone.Deadline = req.Deadline
+	if strings.TrimSpace(req.Title) != "" {
+		clone.Title = req.Title
+	}
+	clone.Reward = append([]domain.Reward(nil), source.Reward...)
+	for i := range clone.Reward {
+		clone.Reward[i].AchievementID = ""
+	}
+	clone.PrizeValues = make([]domain.AdminEventPrize, 0, len(source.PrizeValues))
+	for i, prize := range source.PrizeValues {
+		prize.ID = 0
+		if prize.Achievement != nil {
+			achievement := *prize.Achievement
+			achievement.PrizeID = nil
+			if strings.HasPrefix(achievement.ID, source.ID) {
+				achievement.ID = clone.ID + strings.TrimPrefix(achievement.ID, source.ID)
+			} else {
+				achievement.ID = fmt.Sprintf("%s_place_%d", clone.ID, i+1)
+			}
+			prize.Achievement = &achievement
+		}
+		clone.PrizeValues = append(clone.PrizeValues, prize)
+	}
+
+	return s.CreateEvent(ctx, &clone)
+}
+
+// normalizeAdminEvent trims input, fills reward tiers in rank order and fills achievement defaults for
+// event prizes.
+func normalizeAdminEvent(event *domain.AdminEvent) {
+	event.Title = strings.TrimSpace(event.Title)
+	event.Tags = strings.TrimSpace(event.Tags)
+	event.StartTime = event.StartTime.UTC()
+	event.Deadline = event.Deadline.UTC()
+	if event.Scoring != nil {
+		for i, pair := range event.Scoring.Pairs {
+			event.Scoring.Pairs[i] = strings.ToUpper(strings.TrimSpace(pair))
+		}
+	}
+	if event.Eligibility != nil {
+		normalizeEventEligibility(event.Eligibility)
+	}
+	for i := range event.Reward {
+		normalizeRewardTier(&event.Reward[i])
+	}
+	// Tiers in rank order; "any" rewards last
+	sort.SliceStable(event.Reward, func(i, j int) bool {
+		fromI, fromJ := event.Reward[i].RankFrom, event.Reward[j].RankFrom
+		if fromI == 0 || fromJ == 0 {
+			return fromJ == 0 && fromI != 0
+		}
+		return fromI < fromJ
+	})
+	for i := range event.PrizeValues {
+		prize := &event.PrizeValues[i]
+		prize.Label = strings.TrimSpace(prize.Label)
+		if prize.Label == "" {
+			prize.Label = fmt.Sprintf("%d USDT", prize.Value)
+		}
+		achievement := prize.Achievement
+		if achievement == nil {
+			continue
+		}
+		achievement.ID = strings.TrimSpace(achievement.ID)
+		if achievement.ID == "" {
+			achievement.ID = fmt.Sprintf("%s_place_%d", event.ID, i+1)
+		}
+		if achievement.Badge == "" {
+			achievement.Badge = event.Title
+		}
+		if achievement.Tags == "" {
+			achievement.Tags = "event"
+		}
+		if achievement.Steps <= 0 {
+			achievement.Steps = 1
+		}
+		if achievement.StepDesc == "" {
+			achievement.StepDesc = "Claim event prize"
+		}
+	}
+	// Competition and squad tiers are paid by the prize value at the same position; link its achievement.
+	if strings.Contains(strings.ToLower(event.Tags), "competition") || isSquadEvent(&event.Event) {
+		for i := range event.Reward {
+			if i < len(event.PrizeValues) && event.Reward[i].AchievementID == "" && event.PrizeValues[i].Achievement != nil {
+				event.Reward[i].AchievementID = event.PrizeValues[i].Achievement.ID
+			}
+		}
+	}
+}
+
+// normalizeEventEligibility trims the rules' achievement IDs and auth providers.
+func normalizeEventEligibility(rules *domain.EventEligibility) {
+	for i, achievementID := range rules.RequiredAchievements {
+		rules.RequiredAchievements[i] = strings.TrimSpace(achievementID)
+	}
+	for i, provider := range rules.AuthProviders {
+		rules.AuthProviders[i] = strings.ToLower(strings.TrimSpace(provider))
+	}
+	if rules.JoinOpensAt != nil {
+		opens := rules.JoinOpensAt.UTC()
+		rules.JoinOpensAt = &opens
+	}
+	if rules.JoinClosesAt != nil {
+		closes := rules.JoinClosesAt.UTC()
+		rules.JoinClosesAt = &closes
+	}
+}
+
+// cloneEventEligibility copies eligibility rules for another event. The join window is dropped since it is
+// set in absolute times of the source event.
+func cloneEventEligibility(rules *domain.EventEligibility) *domain.EventEligibility {
+	if rules == nil {
+		return nil
+	}
+	clone := *rules
+	clone.JoinOpensAt = nil
+	clone.JoinClosesAt = nil
+	clone.RequiredAchievements = append([]string(nil), rules.RequiredAchievements...)
+	clone.AuthProviders = append([]string(nil), rules.AuthProviders...)
+	clone.PoolShares = append([]float64(nil), rules.PoolShares...)
+	return &clone
+}
+
+// normalizeRewardTier fills the tier's rank range from its place (or the place from the range) and its
+// amount and currency from its value label (or the label from the amount).
+func normalizeRewardTier(reward *domain.Reward) {
+	reward.Place = strings.TrimSpace(reward.Place)
+	reward.Value = strings.TrimSpace(reward.Value)
+	reward.Currency = strings.TrimSpace(reward.Currency)
+	reward.AchievementID = strings.TrimSpace(reward.AchievementID)
+
+	if reward.RankFrom > 0 {
+		if reward.RankTo == 0 {
+			reward.RankTo = reward.RankFrom
+		}
+		reward.Place = strconv.Itoa(reward.RankFrom)
+		if reward.RankTo != reward.RankFrom {
+			reward.Place = fmt.Sprintf("%d-%d", reward.RankFrom, reward.RankTo)
+		}
+	} else if placeRange, err := parseRewardPlace(reward.Place); err == nil {
+		reward.RankFrom = placeRange.from
+		reward.RankTo = placeRange.to
+		if placeRange.from == 0 {
+			reward.Place = "any"
+		}
+	}
+
+	if reward.Amount == 0 && reward.Value != "" {
+		reward.Amount, reward.Currency = parseRewardAmount(reward.Value, reward.Currency)
+	}
+	if reward.Value == "" && reward.Amount > 0 {
+		reward.Value = formatRewardAmount(reward.Amount, reward.Currency)
+	}
+}
+
+// parseRewardAmount reads "50 USDT" as 50 and "USDT"; an explicit currency wins over the label's.
+func parseRewardAmount(label string, currency string) (float64, string) {
+	amountStr, rest, _ := strings.Cut(strings.TrimSpace(label), " ")
+	amount, err := strconv.ParseFloat(amountStr, 64)
+	if err != nil || amount < 0 {
+		return 0, currency
+	}
+	if currency == "" {
+		currency = strings.TrimSpace(rest)
+	}
+	return amount, currency
+}
+
+// formatRewardAmount formats an amount with at most 2 decimals, e.g. "12.5 USDT".
+func formatRewardAmount(amount float64, currency string) string {
+	label := strconv.FormatFloat(math.Round(amount*100)/100, 'f', -1, 64)
+	if currency != "" {
+		label += " " + currency
+	}
+	return label
+}
+
+func validateAdminEvent(event *domain.AdminEvent) error {
+	if event.Title == "" {
+		return errors.New("title is required")
+	}
+	if event.StartTime.IsZero() || event.Deadline.IsZero() {
+		return errors.New("startTime and deadline are required")
+	}
+	if !event.StartTime.Before(event.Deadline) {
+		return errors.New("startTime must be before deadline")
+	}
+
+	for _, prize := range event.PrizeValues {
+		if prize.Value <= 0 {
+			return errors.New("prize value must be greater than 0")
+		}
+		weight := 1
+		if prize.Weight != nil {
+			weight = *prize.Weight
+		}
+		if err := validateRoulettePrizeCaps(weight, prize.TotalBudget, prize.DailyBudget); err != nil {
+			return fmt.Errorf("prize value %q: %w", prize.Label, err)
+		}
+		if prize.Achievement != nil {
+			if prize.Achievement.Title == "" || prize.Achievement.ImageURL == "" {
+				return errors.New("achievement title and imageUrl are required")
+			}
+			if len(prize.Achievement.ID) > 50 {
+				return errors.New("achievement id must be at most 50 characters")
+			}
+		}
+	}
+
+	if err := validateScoringRules(event.Scoring); err != nil {
+		return err
+	}
+
+	if err := validateEventEligibility(&event.Event); err != nil {
+		return err
+	}
+
+	if err := validateRewardTiers(event.Reward); err != nil {
+		return err
+	}
+
+	if !strings.Contains(strings.ToLower(event.Tags), "competition") && !isSquadEvent(&event.Event) {
+		return nil
+	}
+	return validateCompetitionPrizes(event.Reward, event.PrizeValues)
+}
+
+func validateScoringRules(rules *domain.EventScoringRules) error {
+	if rules == nil {
+		return nil
+	}
+	switch rules.Metric {
+	case "", domain.ScoringNetPoints, domain.ScoringROI, domain.ScoringWinRate, domain.ScoringSkill:
+	default:
+		return errors.New("scoring metric must be 'net_points', 'roi', 'win_rate' or 'skill'")
+	}
+	switch rules.Ties {
+	case "", domain.RewardTiesEarliest, domain.RewardTiesShared, domain.RewardTiesSplit:
+	default:
+		return errors.New("scoring ties must be 'earliest', 'shared' or 'split'")
+	}
+	if rules.MinBets < 0 {
+		return errors.New("scoring minBets must not be negative")
+	}
+	for _, pair := range rules.Pairs {
+		if pair == "" {
+			return errors.New("scoring pairs must not be empty")
+		}
+	}
+	for _, timeframe := range rules.Timeframes {
+		if timeframe <= 0 {
+			return errors.New("scoring timeframes must be greater than 0")
+		}
+	}
+	return nil
+}
+
+func validateEventEligibility(event *domain.Event) error {
+	rules := event.Eligibility
+	if rules == nil {
+		return nil
+	}
+	if rules.MaxParticipants < 0 || rules.MinTotalPoints < 0 || rules.MinLevel < 0 || rules.EntryFee < 0 {
+		return errors.New("eligibility maxParticipants, minTotalPoints, minLevel and entryFee must not be negative")
+	}
+	if rules.JoinOpensAt != nil && rules.JoinClosesAt != nil && !rules.JoinOpensAt.Before(*rules.JoinClosesAt) {
+		return errors.New("eligibility joinOpensAt must be before joinClosesAt")
+	}
+	if rules.JoinClosesAt != nil && rules.JoinClosesAt.After(event.Deadline) {
+		return errors.New("eligibility joinClosesAt must not be after the deadline")
+	}
+	for _, achievementID := range rules.RequiredAchievements {
+		if achievementID == "" {
+			return errors.New("eligibility requiredAchievements must not be empty")
+		}
+	}
+	for _, provider := range rules.AuthProviders {
+		if !eligibilityAuthProviders[provider] {
+			return errors.New("eligibility authProviders must be 'google' or 'telegram'")
+		}
+	}
+
+	if rules.EntryFee > 0 && !strings.Contains(strings.ToLower(event.Tags), "competition") {
+		return errors.New("entryFee requires a competition event")
+	}
+	if len(rules.PoolShares) > 0 && rules.EntryFee <= 0 {
+		return errors.New("eligibility poolShares require an entryFee")
+	}
+	var totalShare float64
+	for _, share := range rules.PoolShares {
+		if share <= 0 {
+			return errors.New("eligibility poolShares must be greater than 0")
+		}
+		totalShare += share
+	}
+	if totalShare > 100 {
+		return errors.New("eligibility poolShares must add up to at most 100")
+	}
+	return nil
+}
+
+// rewardPlaceRange is an inclusive range of leaderboard places; "any" is represented by from = 0.
+type rewardPlaceRange struct {
+	from int
+	to   int
+}
+
+// parseRewardPlace parses "1", "4-10" or "any".
+func parseRewardPlace(place string) (rewardPlaceRange, error) {
+	place = strings.TrimSpace(place)
+	if strings.EqualFold(place, "any") {
+		return rewardPlaceRange{}, nil
+	}
+
+	fromStr, toStr, isRange := strings.Cut(place, "-")
+	from, err := strconv.Atoi(strings.TrimSpace(fromStr))
+	if err != nil || from <= 0 {
+		return rewardPlaceRange{}, fmt.Errorf("invalid reward place %q", place)
+	}
+	to := from
+	if isRange {
+		to, err = strconv.Atoi(strings.TrimSpace(toStr))
+		if err != nil || to < from {
+			return rewardPlaceRange{}, fmt.Errorf("invalid reward place %q", place)
+		}
+	}
+	return rewardPlaceRange{from: from, to: to}, nil
+}
+
+// validateRewardTiers checks that every tier has a valid place or rank range and that rank ranges don't overlap.
+func validateRewardTiers(rewards []domain.Reward) error {
+	ranked := make([]domain.Reward, 0, len(rewards))
+	for _, reward := range rewards {
+		if reward.RankFrom == 0 && !strings.EqualFold(reward.Place, "any") {
+			return fmt.Errorf("invalid reward place %q", reward.Place)
+		}
+		if reward.RankFrom < 0 || reward.RankTo < reward.RankFrom {
+			return fmt.Errorf("invalid reward rank range %d-%d", reward.RankFrom, reward.RankTo)
+		}
+		if reward.Amount < 0 {
+			return errors.New("reward amount must not be negative")
+		}
+		if reward.RankFrom > 0 {
+			ranked = append(ranked, reward)
+		}
+	}
+
+	sort.Slice(ranked, func(i, j int) bool { return ranked[i].RankFrom < ranked[j].RankFrom })
+	for i := 1; i < len(ranked); i++ {
+		if ranked[i].RankFrom <= ranked[i-1].RankTo {
+			return errors.New("reward places must not overlap")
+		}
+	}
+	return nil
+}
+
+// validateCompetitionPrizes checks that competition reward tiers cover ranks contiguously from 1st place
+// and that each tier, in rank order, has a prize value whose label matches the tier value.
+func validateCompetitionPrizes(rewards []domain.Reward, prizes []domain.AdminEventPrize) error {
+	if len(rewards) == 0 {
+		return errors.New("competition must have rewards")
+	}
+
+	nextRank := 1
+	for _, reward := range rewards {
+		if reward.RankFrom == 0 {
+			return errors.New("competition reward places must be ranks, not 'any'")
+		}
+		if reward.RankFrom != nextRank {
+			return errors.New("competition reward places must be contiguous from 1")
+		}
+		nextRank = reward.RankTo + 1
+	}
+	if len(prizes) != len(rewards) {
+		return fmt.Errorf("competition needs %d prize values for %d reward tiers, got %d", len(rewards), len(rewards), len(prizes))
+	}
+
+	for i, prize := range prizes {
+		reward := rewards[i]
+		if i > 0 && prize.Value > prizes[i-1].Value {
+			return errors.New("competition prize values must be listed from 1st place and must not increase")
+		}
+		if reward.Value != "" && !strings.EqualFold(reward.Value, prize.Label) {
+			return fmt.Errorf("reward for place %s is %q but prize value label is %q", reward.Place, reward.Value, prize.Label)
+		}
+		if reward.AchievementID != "" && (prize.Achievement == nil || prize.Achievement.ID != reward.AchievementID) {
+			return fmt.Errorf("reward for place %s links achievement %q, which is not the achievement of its prize value", reward.Place, reward.AchievementID)
+		}
+	}
+	return nil
+}
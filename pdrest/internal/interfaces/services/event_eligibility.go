@@ -0,0 +1,262 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"pdrest/internal/domain"
+	"strings"
+	"time"
+)
+
+// eligibilityAuthProviders are the auth providers an event can require.
+var eligibilityAuthProviders = map[string]bool{"google": true, "telegram": true}
+
+// eventEligibility returns the event's eligibility rules; events without rules can be joined by anyone.
+func eventEligibility(event *domain.Event) domain.EventEligibility {
+	if event.Eligibility == nil {
+		return domain.EventEligibility{}
+	}
+	return *event.Eligibility
+}
+
+// checkJoinWindow rejects joining outside the event's join window, which defaults to until the deadline.
+func checkJoinWindow(event *domain.Event, rules domain.EventEligibility, now time.Time) error {
+	if event.State != domain.EventStateScheduled && event.State != domain.EventStateActive {
+		return errors.New("event has ended")
+	}
+	if rules.JoinOpensAt != nil && now.Before(*rules.JoinOpensAt) {
+		return fmt.Errorf("event join window opens at %s", rules.JoinOpensAt.UTC().Format(time.RFC3339))
+	}
+	closes := event.Deadline
+	if rules.JoinClosesAt != nil {
+		closes = *rules.JoinClosesAt
+	}
+	if !now.Before(closes) {
+		return errors.New("event join window is closed")
+	}
+	return nil
+}
+
+// checkEligibility rejects users who don't meet the event's rules or cannot pay its entry fee.
+func (s *EventService) checkEligibility(ctx context.Context, userUUID string, rules domain.EventEligibility) error {
+	if rules.MinTotalPoints <= 0 && rules.MinLevel <= 0 && len(rules.RequiredAchievements) == 0 && len(rules.AuthProviders) == 0 && rules.EntryFee <= 0 {
+		return nil
+	}
+
+	user, err := s.repo.GetUserEligibility(ctx, userUUID, rules.RequiredAchievements)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+
+	if user.TotalPoints < rules.MinTotalPoints {
+		return fmt.Errorf("not eligible: at least %d points required", rules.MinTotalPoints)
+	}
+	if user.Level < rules.MinLevel {
+		return fmt.Errorf("not eligible: level %d required", rules.MinLevel)
+	}
+	for _, achievementID := range rules.RequiredAchievements {
+		if !containsString(user.CompletedAchievements, achievementID) {
+			return fmt.Errorf("not eligible: achievement %s required", achievementID)
+		}
+	}
+	if len(rules.AuthProviders) > 0 {
+		linked := false
+		for _, provider := range rules.AuthProviders {
+			linked = linked || containsString(user.AuthProviders, provider)
+		}
+		if !linked {
+			return fmt.Errorf("not eligible: link a %s account", strings.Join(rules.AuthProviders, " or "))
+		}
+	}
+	if user.TotalPoints < rules.EntryFee {
+		return errors.New("not enough points for the entry fee")
+	}
+	return nil
+}
+
+// LeaveEvent takes the user out of the event. Participants can leave until the event starts and get
+// their entry fee back; waitlisted users can leave until joining closes.
+func (s *EventService) LeaveEvent(ctx context.Context, userUUID string, eventID string) error {
+	if userUUID == "" {
+		return errors.New("user uuid is required")
+	}
+	if eventID == "" {
+		return errors.New("event_id is required")
+	}
+
+	event, err := s.repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if event == nil || event.State == domain.EventStateDraft || event.State == domain.EventStateArchived {
+		return errors.New("event not found")
+	}
+	status, err := s.repo.GetUserEventStatus(ctx, userUUID, event.ID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	switch status {
+	case domain.UserEventJoined:
+		if isSquadEvent(event) {
+			return errors.New("cannot leave a squad event")
+		}
+		if event.State != domain.EventStateScheduled || !now.Before(event.StartTime) {
+			return errors.New("event has already started")
+		}
+	case domain.UserEventWaitlisted:
+		if event.State != domain.EventStateScheduled && event.State != domain.EventStateActive {
+			return errors.New("event has ended")
+		}
+	default:
+		return errors.New("user is not taking part in this event")
+	}
+
+	left, err := s.repo.LeaveEvent(ctx, userUUID, event.ID, status)
+	if err != nil {
+		return err
+	}
+	if !left {
+		return errors.New("user is not taking part in this event")
+	}
+
+	rules := eventEligibility(event)
+	if status == domain.UserEventJoined && rules.MaxParticipants > 0 {
+		if _, err := s.repo.PromoteEventWaitlist(ctx, event.ID, rules.MaxParticipants, rules.EntryFee); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PromoteWaitlist fills the event's free places from its waitlist; it is the lifecycle hook run when an
+// event starts, picking up places freed by a raised participant cap.
+func (s *EventService) PromoteWaitlist(ctx context.Context, event *domain.Event) error {
+	if event.Eligibility == nil {
+		return nil
+	}
+	rules := eventEligibility(event)
+	promoted, err := s.repo.PromoteEventWaitlist(ctx, event.ID, rules.MaxParticipants, rules.EntryFee)
+	if err != nil {
+		return err
+	}
+	s.publishEventJoined(ctx, event.ID, promoted...)
+	return nil
+}
+
+// PayPrizePool pays the entry fees collected by the event to its final ranks by the pool shares; it is
+// the lifecycle hook run after FreezeResults. Users tied on a rank split the shares of the places they
+// occupy, rounding leftovers go to the top rank and an event nobody ranked in refunds its entry fees.
+func (s *EventService) PayPrizePool(ctx context.Context, event *domain.Event) error {
+	if event.PrizePool <= 0 {
+		return nil
+	}
+
+	payouts, err := s.prizePoolPayouts(ctx, event)
+	if err != nil {
+		return err
+	}
+	_, err = s.repo.PayEventPrizePool(ctx, event.ID, payouts, time.Now().UTC().UnixMilli())
+	return err
+}
+
+// prizePoolPayouts splits the event's prize pool among its final ranks.
+func (s *EventService) prizePoolPayouts(ctx context.Context, event *domain.Event) ([]domain.PrizePoolPayout, error) {
+	shares := eventEligibility(event).PoolShares
+	if len(shares) == 0 {
+		shares = []float64{100}
+	}
+
+	const pageSize = 100
+	var results []domain.EventResult
+	for offset := 0; ; offset += pageSize {
+		page, err := s.repo.GetEventResults(ctx, event.ID, pageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range page {
+			if result.Rank <= len(shares) {
+				results = append(results, result)
+			}
+		}
+		if len(page) < pageSize || page[len(page)-1].Rank > len(shares) {
+			break
+		}
+	}
+
+	if len(results) == 0 {
+		fees, err := s.repo.GetEventEntryFees(ctx, event.ID)
+		if err != nil {
+			return nil, err
+		}
+		payouts := make([]domain.PrizePoolPayout, 0, len(fees))
+		for userUUID, fee := range fees {
+			payouts = append(payouts, domain.PrizePoolPayout{
+				UserUUID:    userUUID,
+				Points:      fee,
+				Description: fmt.Sprintf("Entry fee refund: %s", event.Title),
+			})
+		}
+		return payouts, nil
+	}
+	return splitPrizePool(event, shares, results), nil
+}
+
+// splitPrizePool pays the pool shares to the ranked results (in rank order, only ranks that have a share).
+// Tied users split the shares of the places they occupy; the rounding leftover goes to the first result.
+func splitPrizePool(event *domain.Event, shares []float64, results []domain.EventResult) []domain.PrizePoolPayout {
+	var totalShare float64
+	for _, share := range shares {
+		totalShare += share
+	}
+	distributed := int64(math.Floor(float64(event.PrizePool) * totalShare / 100))
+
+	payouts := make([]domain.PrizePoolPayout, 0, len(results))
+	var paid int64
+	for first := 0; first < len(results); {
+		last := first + 1
+		for last < len(results) && results[last].Rank == results[first].Rank {
+			last++
+		}
+		// The tied users occupy places rank..rank+tied-1 and split their shares.
+		rank := results[first].Rank
+		var share float64
+		for place := rank; place < rank+last-first && place <= len(shares); place++ {
+			share += shares[place-1]
+		}
+		points := int64(math.Floor(float64(event.PrizePool) * share / 100 / float64(last-first)))
+		for _, result := range results[first:last] {
+			payouts = append(payouts, domain.PrizePoolPayout{
+				UserUUID:    result.UserUUID,
+				Points:      points,
+				Description: fmt.Sprintf("Prize pool: %s, rank #%d", event.Title, rank),
+			})
+			paid += points
+		}
+		first = last
+	}
+	payouts[0].Points += distributed - paid
+
+	nonZero := payouts[:0]
+	for _, payout := range payouts {
+		if payout.Points > 0 {
+			nonZero = append(nonZero, payout)
+		}
+	}
+	return nonZero
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
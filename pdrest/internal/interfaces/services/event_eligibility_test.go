@@ -0,0 +1,90 @@
+package services
+
+import (
+	"pdrest/internal/domain"
+	"reflect"
+	"testing"
+)
+
+func TestSplitPrizePool(t *testing.T) {
+	ranked := func(ranks ...int) []domain.EventResult {
+		results := make([]domain.EventResult, len(ranks))
+		for i, rank := range ranks {
+			results[i] = domain.EventResult{UserUUID: string(rune('a' + i)), Rank: rank}
+		}
+		return results
+	}
+
+	tests := []struct {
+		name    string
+		pool    int64
+		shares  []float64
+		results []domain.EventResult
+		want    map[string]int64
+	}{
+		{
+			name:    "one user per rank",
+			pool:    1000,
+			shares:  []float64{50, 30, 20},
+			results: ranked(1, 2, 3),
+			want:    map[string]int64{"a": 500, "b": 300, "c": 200},
+		},
+		{
+			name:    "tie splits the shares of the places it covers",
+			pool:    1000,
+			shares:  []float64{50, 30, 20},
+			results: ranked(1, 1, 3),
+			want:    map[string]int64{"a": 400, "b": 400, "c": 200},
+		},
+		{
+			name:    "tie past the last share",
+			pool:    1000,
+			shares:  []float64{50, 30, 20},
+			results: ranked(1, 2, 2, 2),
+			want:    map[string]int64{"a": 502, "b": 166, "c": 166, "d": 166},
+		},
+		{
+			name:    "rounding leftover goes to the first result",
+			pool:    1000,
+			shares:  []float64{50, 30, 20},
+			results: ranked(1, 1, 1),
+			want:    map[string]int64{"a": 334, "b": 333, "c": 333},
+		},
+		{
+			name:    "shares of unranked places go to the first result",
+			pool:    1000,
+			shares:  []float64{50, 30, 20},
+			results: ranked(1),
+			want:    map[string]int64{"a": 1000},
+		},
+		{
+			name:    "only the shared part of the pool is paid",
+			pool:    1000,
+			shares:  []float64{40, 20},
+			results: ranked(1, 1),
+			want:    map[string]int64{"a": 300, "b": 300},
+		},
+		{
+			name:    "zero payouts are dropped",
+			pool:    1,
+			shares:  []float64{50, 30, 20},
+			results: ranked(1, 2, 3),
+			want:    map[string]int64{"a": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &domain.Event{ID: "cup", Title: "Cup", PrizePool: tt.pool}
+			payouts := splitPrizePool(event, tt.shares, tt.results)
+
+			got := make(map[string]int64, len(payouts))
+			for _, payout := range payouts {
+				got[payout.UserUUID] += payout.Points
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("payouts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"pdrest/internal/data"
+	"pdrest/internal/domain"
+	"strings"
+	"time"
+)
+
+// EventStateHook runs before an event enters a state. An error aborts the transition, which the lifecycle
+// job retries on its next run, so hooks may run more than once (also on several replicas) and must be idempotent.
+type EventStateHook func(ctx context.Context, event *domain.Event) error
+
+// EventLifecycleService moves events through draft → scheduled → active → finalizing → finished → archived.
+// Scheduled events start and end by their start time and deadline; drafts are published and events are
+// archived by admins.
+type EventLifecycleService struct {
+	repo  data.EventRepository
+	hooks map[domain.EventState][]EventStateHook
+}
+
+func NewEventLifecycleService(repo data.EventRepository) *EventLifecycleService {
+	return &EventLifecycleService{
+		repo:  repo,
+		hooks: make(map[domain.EventState][]EventStateHook),
+	}
+}
+
+// OnEnter registers a hook run before events enter state. Hooks run in registration order.
+func (s *EventLifecycleService) OnEnter(state domain.EventState, hook EventStateHook) {
+	s.hooks[state] = append(s.hooks[state], hook)
+}
+
+// Advance moves every due event along its lifecycle, several steps at once if needed; it is the lifecycle's periodic job.
+func (s *EventLifecycleService) Advance(ctx context.Context, now time.Time) error {
+	if s.repo == nil {
+		return errors.New("event repository is not configured")
+	}
+
+	events, err := s.repo.GetEventsToAdvance(ctx, now.UTC().UnixMilli())
+	if err != nil {
+		return err
+	}
+
+	for i := range events {
+		event := &events[i]
+		for {
+			next, due := nextEventState(event, now)
+			if !due {
+				break
+			}
+			moved, err := s.transition(ctx, event, next, now)
+			if err != nil {
+				log.Printf("events: failed to move %s from %s to %s: %v", event.ID, event.State, next, err)
+				break
+			}
+			if !moved {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// Transition moves an event to state on an admin's request. Only publishing (scheduled), unpublishing (draft)
+// and archiving are manual; the other steps follow the event's start time and deadline.
+// Returns false if the event already is in that state.
+func (s *EventLifecycleService) Transition(ctx context.Context, eventID string, state domain.EventState) (bool, error) {
+	if s.repo == nil {
+		return false, errors.New("event repository is not configured")
+	}
+	if !state.IsValid() {
+		return false, fmt.Errorf("invalid event state %q", state)
+	}
+	if state != domain.EventStateDraft && state != domain.EventStateScheduled && state != domain.EventStateArchived {
+		return false, errors.New("state must be draft, scheduled or archived; other states follow the event schedule")
+	}
+
+	event, err := s.repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return false, err
+	}
+	if event == nil {
+		return false, errors.New("event not found")
+	}
+	if event.State == state {
+		return false, nil
+	}
+	if !event.State.CanTransitionTo(state) {
+		return false, fmt.Errorf("event cannot move from %s to %s", event.State, state)
+	}
+
+	moved, err := s.transition(ctx, event, state, time.Now().UTC())
+	if err != nil {
+		return false, err
+	}
+	if !moved {
+		return false, errors.New("event state changed concurrently")
+	}
+	return true, nil
+}
+
+// transition runs the hooks of the next state and moves the event there. Returns false if another
+// replica moved the event first.
+func (s *EventLifecycleService) transition(ctx context.Context, event *domain.Event, next domain.EventState, now time.Time) (bool, error) {
+	for _, hook := range s.hooks[next] {
+		if err := hook(ctx, event); err != nil {
+			return false, fmt.Errorf("%s hook failed: %w", next, err)
+		}
+	}
+
+	moved, err := s.repo.TransitionEventState(ctx, event.ID, event.State, next, now.UTC().UnixMilli())
+	if err != nil || !moved {
+		return false, err
+	}
+	log.Printf("events: %s moved from %s to %s", event.ID, event.State, next)
+	event.State = next
+	return true, nil
+}
+
+// nextEventState returns the state a scheduled, active or finalizing event is due to enter at now.
+func nextEventState(event *domain.Event, now time.Time) (domain.EventState, bool) {
+	switch event.State {
+	case domain.EventStateScheduled:
+		if !now.Before(event.StartTime) {
+			return domain.EventStateActive, true
+		}
+	case domain.EventStateActive:
+		if !now.Before(event.Deadline) {
+			return domain.EventStateFinalizing, true
+		}
+	case domain.EventStateFinalizing:
+		// Competitions and squad events wait until bets closing at the deadline are settled before the results are frozen.
+		ranked := strings.Contains(strings.ToLower(event.Tags), "competition") || isSquadEvent(event)
+		if ranked && now.Before(event.Deadline.Add(competitionSettleGrace)) {
+			return "", false
+		}
+		return domain.EventStateFinished, true
+	}
+	return "", false
+}
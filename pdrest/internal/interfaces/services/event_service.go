@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
 	"pdrest/internal/data"
 	"pdrest/internal/domain"
 	"sort"
@@ -11,53 +13,129 @@ import (
 	"time"
 )
 
+// winnerNotificationWindow limits winner notifications to competitions finalized soon after their deadline,
+// so old competitions frozen on first deploy don't notify their winners again.
+const winnerNotificationWindow = 24 * time.Hour
+
+// competitionSettleGrace is how long after the deadline standings stay live, so that bets closing
+// at the deadline are settled by the bet scheduler before the results are frozen.
+const competitionSettleGrace = 2 * time.Minute
+
 type EventService struct {
-	repo            data.EventRepository
-	prizeRepo       data.PrizeRepository
-	prizeValueRepo  data.PrizeValueRepository
-	achievementRepo data.AchievementRepository
-	ratingRepo      data.RatingRepository
+	repo             data.EventRepository
+	prizeRepo        data.PrizeRepository
+	prizeValueRepo   data.PrizeValueRepository
+	achievementRepo  data.AchievementRepository
+	snapshotInterval time.Duration
+	bus              *DomainEventBus
 }
 
-func NewEventService(r data.EventRepository, prizeRepo data.PrizeRepository, prizeValueRepo data.PrizeValueRepository, achievementRepo data.AchievementRepository, ratingRepo data.RatingRepository) *EventService {
+func NewEventService(r data.EventRepository, prizeRepo data.PrizeRepository, prizeValueRepo data.PrizeValueRepository, achievementRepo data.AchievementRepository, snapshotInterval time.Duration, bus *DomainEventBus) *EventService {
 	return &EventService{
-		repo:            r,
-		prizeRepo:       prizeRepo,
-		prizeValueRepo:  prizeValueRepo,
-		achievementRepo: achievementRepo,
-		ratingRepo:      ratingRepo,
+		repo:             r,
+		prizeRepo:        prizeRepo,
+		prizeValueRepo:   prizeValueRepo,
+		achievementRepo:  achievementRepo,
+		snapshotInterval: snapshotInterval,
+		bus:              bus,
+	}
+}
+
+// eventScoringRules returns the event's scoring rules. Events without rules count participants'
+// bets by net points, or by skill rating gain for events tagged "skill".
+func eventScoringRules(event *domain.Event) domain.EventScoringRules {
+	if event.Scoring != nil {
+		rules := *event.Scoring
+		if rules.Metric == "" {
+			rules.Metric = domain.ScoringNetPoints
+		}
+		return rules
+	}
+
+	rules := domain.EventScoringRules{
+		ParticipantsOnly: true,
+		Metric:           domain.ScoringNetPoints,
+	}
+	if strings.Contains(strings.ToLower(event.Tags), "skill") {
+		rules.Metric = domain.ScoringSkill
+	}
+	return rules
+}
+
+// competitionLeaderboard ranks a competition with its scoring rules. A limit <= 0 returns all ranked users.
+func (s *EventService) competitionLeaderboard(ctx context.Context, event *domain.Event, limit, offset int) ([]domain.BetPrizeLeaderboardEntry, error) {
+	return s.repo.GetEventStandings(ctx, event.ID, event.StartTime.UTC().UnixMilli(), event.Deadline.UTC().UnixMilli(), eventScoringRules(event), "", limit, offset)
+}
+
+// competitionUserScore returns the user's standing computed like competitionLeaderboard, ignoring the
+// minimum bet count. Returns nil if the user has no counted bets.
+func (s *EventService) competitionUserScore(ctx context.Context, event *domain.Event, userUUID string) (*domain.BetPrizeLeaderboardEntry, error) {
+	entries, err := s.repo.GetEventStandings(ctx, event.ID, event.StartTime.UTC().UnixMilli(), event.Deadline.UTC().UnixMilli(), eventScoringRules(event), userUUID, 1, 0)
+	if err != nil || len(entries) == 0 {
+		return nil, err
 	}
+	return &entries[0], nil
 }
 
 func (s *EventService) GetAvailableEvents(ctx context.Context, tag string) ([]domain.Event, error) {
 	return s.repo.GetAllEvents(ctx, tag)
 }
 
-func (s *EventService) TakePartOnEvent(ctx context.Context, userUUID string, eventID string) (string, error) {
+// TakePartOnEvent joins the user to the event if the join window is open and the user meets the event's
+// eligibility rules. A full event puts the user on its waitlist; joining pays the entry fee.
+func (s *EventService) TakePartOnEvent(ctx context.Context, userUUID string, eventID string) (*domain.JoinEventResult, error) {
 	if userUUID == "" {
-		return "", errors.New("user uuid is required")
+		return nil, errors.New("user uuid is required")
 	}
 	if eventID == "" {
-		return "", errors.New("event_id is required")
+		return nil, errors.New("event_id is required")
 	}
 
 	event, err := s.repo.GetEventByID(ctx, eventID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	if event == nil {
-		return "", errors.New("event not found")
+	if event == nil || event.State == domain.EventStateDraft || event.State == domain.EventStateArchived {
+		return nil, errors.New("event not found")
 	}
+	rules := eventEligibility(event)
 
-	created, err := s.repo.AddUserEvent(ctx, userUUID, eventID, "joined")
+	status, err := s.repo.GetUserEventStatus(ctx, userUUID, event.ID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	if !created {
-		return "already_exists", nil
+	if status == "" || status == domain.UserEventDropped {
+		if err := checkJoinWindow(event, rules, time.Now().UTC()); err != nil {
+			return nil, err
+		}
+		if err := s.checkEligibility(ctx, userUUID, rules); err != nil {
+			return nil, err
+		}
+		if rules.MaxParticipants > 0 {
+			// Fill places freed since the last join first, so newcomers don't skip the waitlist.
+			promoted, err := s.repo.PromoteEventWaitlist(ctx, event.ID, rules.MaxParticipants, rules.EntryFee)
+			if err != nil {
+				return nil, err
+			}
+			s.publishEventJoined(ctx, event.ID, promoted...)
+		}
 	}
 
-	return "created", nil
+	result, err := s.repo.JoinEvent(ctx, userUUID, event.ID, rules.MaxParticipants, rules.EntryFee)
+	if err != nil {
+		return nil, err
+	}
+	if result.Status == "created" && result.UserStatus == domain.UserEventJoined {
+		s.publishEventJoined(ctx, event.ID, userUUID)
+	}
+	return result, nil
+}
+
+// publishEventJoined publishes EventJoined for users who took a place in the event.
+func (s *EventService) publishEventJoined(ctx context.Context, eventID string, userUUIDs ...string) {
+	for _, userUUID := range userUUIDs {
+		s.bus.Publish(ctx, domain.DomainEvent{Type: domain.DomainEventEventJoined, UserUUID: userUUID, EventID: eventID})
+	}
 }
 
 func (s *EventService) GetUserEvents(ctx context.Context, userUUID string) (*domain.UserEventsResponse, error) {
@@ -86,7 +164,7 @@ func (s *EventService) UpdateUserEventPrizeStatus(ctx context.Context, userUUID
 	if eventID == "" {
 		return "", errors.New("event_id is required")
 	}
-	if s.repo == nil || s.ratingRepo == nil || s.prizeValueRepo == nil {
+	if s.repo == nil || s.prizeValueRepo == nil {
 		return "", errors.New("event service dependencies are not configured")
 	}
 
@@ -97,10 +175,9 @@ func (s *EventService) UpdateUserEventPrizeStatus(ctx context.Context, userUUID
 	if event == nil {
 		return "", errors.New("event not found")
 	}
-	if time.Now().UTC().Before(event.Deadline) {
+	if time.Now().UTC().Before(event.Deadline.Add(competitionSettleGrace)) {
 		return "", errors.New("event is not finished yet")
 	}
-	startMs := event.StartTime.UTC().UnixMilli()
 
 	hasPriseStatus, _, _, err := s.repo.GetUserEventPrizeStatus(ctx, userUUID, eventID)
 	if err != nil {
@@ -110,38 +187,19 @@ func (s *EventService) UpdateUserEventPrizeStatus(ctx context.Context, userUUID
 		return "already_defined", nil
 	}
 
-	leaderboard, err := s.ratingRepo.GetBetPointsLeaderboard(ctx, startMs, event.Deadline.UTC().UnixMilli(), 3)
+	if err := s.ensureEventFinalized(ctx, event); err != nil {
+		return "", err
+	}
+	result, err := s.repo.GetUserEventResult(ctx, eventID, userUUID)
 	if err != nil {
 		return "", err
 	}
 
-	hasPrise := false
 	var prizeValueID *int
-
-	for idx, entry := range leaderboard {
-		if entry.UserUUID != userUUID {
-			continue
-		}
-		hasPrise = true
-
-		prizeValue, err := s.prizeValueRepo.GetPrizeValuesByEventID(ctx, eventID)
-		if err != nil {
-			return "", err
-		}
-		if len(prizeValue) == 0 {
-			return "", errors.New("prize values not found for event")
-		}
-
-		// Sort by value descending and pick index (0-based) for place.
-		sort.Slice(prizeValue, func(i, j int) bool {
-			return prizeValue[i].Value > prizeValue[j].Value
-		})
-		if idx >= len(prizeValue) {
-			break
-		}
-		prizeValueID = &prizeValue[idx].ID
-		break
+	if result != nil {
+		prizeValueID = result.PrizeValueID
 	}
+	hasPrise := prizeValueID != nil
 
 	updated, err := s.repo.UpdateUserEventPrizeStatusIfUnknown(ctx, userUUID, eventID, &hasPrise, prizeValueID)
 	if err != nil {
@@ -168,6 +226,23 @@ func (s *EventService) TakeEventPrize(ctx context.Context, userUUID string, even
 	if err != nil {
 		return nil, "", err
 	}
+	result, err := s.repo.GetUserEventResult(ctx, eventID, userUUID)
+	if err != nil {
+		return nil, "", err
+	}
+	if prizeValueID == nil {
+		// Prize status was not requested yet; take the prize straight from the frozen results.
+		if result != nil && result.PrizeValueID != nil {
+			hasPrise := true
+			updated, err := s.repo.UpdateUserEventPrizeStatusIfUnknown(ctx, userUUID, eventID, &hasPrise, result.PrizeValueID)
+			if err != nil {
+				return nil, "", err
+			}
+			if updated {
+				prizeValueID = result.PrizeValueID
+			}
+		}
+	}
 	if prizeValueID == nil {
 		return nil, "", errors.New("prize_value_id is not set for this event")
 	}
@@ -183,9 +258,9 @@ func (s *EventService) TakeEventPrize(ctx context.Context, userUUID string, even
 		return nil, "", errors.New("prize value not found")
 	}
 
-	prizeValueStr := prizeValue.Label
-	if prizeValueStr == "" {
-		prizeValueStr = strconv.FormatInt(prizeValue.Value, 10)
+	prizeValueStr := prizeValueLabel(prizeValue)
+	if result != nil && result.PrizeLabel != "" && result.PrizeValueID != nil && *result.PrizeValueID == *prizeValueID {
+		prizeValueStr = result.PrizeLabel
 	}
 
 	now := time.Now().UTC().UnixMilli()
@@ -211,21 +286,27 @@ func (s *EventService) TakeEventPrize(ctx context.Context, userUUID string, even
 		return nil, "", errors.New("prize already taken")
 	}
 
-	var achievementImageURL string
-	achievement, err := s.achievementRepo.GetAchievementByPrizeID(ctx, *prizeValueID)
+	achievementImageURL, err := s.grantPrizeAchievement(ctx, userUUID, *prizeValueID)
 	if err != nil {
 		return nil, "", err
 	}
-	if achievement != nil {
-		if err := s.achievementRepo.UpsertUserAchievementProgress(ctx, userUUID, achievement.ID, 1, 1, true); err != nil {
-			return nil, "", err
-		}
-		achievementImageURL = achievement.ImageURL
-	}
 
 	return prize, achievementImageURL, nil
 }
 
+// grantPrizeAchievement completes the achievement linked to the prize value for the user, if there is one,
+// and returns its image URL.
+func (s *EventService) grantPrizeAchievement(ctx context.Context, userUUID string, prizeValueID int) (string, error) {
+	achievement, err := s.achievementRepo.GetAchievementByPrizeID(ctx, prizeValueID)
+	if err != nil || achievement == nil {
+		return "", err
+	}
+	if err := s.achievementRepo.UpsertUserAchievementProgress(ctx, userUUID, achievement.ID, 1, 1, true); err != nil {
+		return "", err
+	}
+	return achievement.ImageURL, nil
+}
+
 func (s *EventService) GetUserEventProgress(ctx context.Context, userUUID string, eventID string) (*domain.EventProgressResponse, error) {
 	if userUUID == "" {
 		return nil, errors.New("user uuid is required")
@@ -233,7 +314,7 @@ func (s *EventService) GetUserEventProgress(ctx context.Context, userUUID string
 	if eventID == "" {
 		return nil, errors.New("event_id is required")
 	}
-	if s.repo == nil || s.ratingRepo == nil {
+	if s.repo == nil {
 		return nil, errors.New("event service dependencies are not configured")
 	}
 
@@ -249,7 +330,6 @@ func (s *EventService) GetUserEventProgress(ctx context.Context, userUUID string
 	}
 
 	startMs := event.StartTime.UTC().UnixMilli()
-	endMs := event.Deadline.UTC().UnixMilli()
 
 	nowMs := time.Now().UTC().UnixMilli()
 	if nowMs < startMs {
@@ -268,23 +348,31 @@ func (s *EventService) GetUserEventProgress(ctx context.Context, userUUID string
 		}, nil
 	}
 
-	points, err := s.ratingRepo.GetUserBetPointsInRange(ctx, userUUID, startMs, endMs)
+	entry, err := s.competitionUserScore(ctx, event, userUUID)
 	if err != nil {
 		return nil, err
 	}
 
-	return &domain.EventProgressResponse{
-		EventID:         eventID,
-		Participating:   true,
-		CollectedPoints: points,
-	}, nil
+	rules := eventScoringRules(event)
+	progress := &domain.EventProgressResponse{
+		EventID:       eventID,
+		Participating: true,
+		Metric:        rules.Metric,
+		MinBets:       rules.MinBets,
+	}
+	if entry != nil {
+		progress.CollectedPoints = entry.NetPoints
+		progress.Score = entry.Score
+		progress.BetCount = entry.BetCount
+	}
+	return progress, nil
 }
 
 func (s *EventService) GetBestInEvent(ctx context.Context, eventID string) (*domain.EventLeaderResponse, error) {
 	if eventID == "" {
 		return nil, errors.New("event_id is required")
 	}
-	if s.repo == nil || s.ratingRepo == nil || s.prizeValueRepo == nil || s.achievementRepo == nil {
+	if s.repo == nil || s.prizeValueRepo == nil || s.achievementRepo == nil {
 		return nil, errors.New("event service dependencies are not configured")
 	}
 
@@ -299,47 +387,473 @@ func (s *EventService) GetBestInEvent(ctx context.Context, eventID string) (*dom
 		return nil, errors.New("event is not a competition")
 	}
 
-	startMs := event.StartTime.UTC().UnixMilli()
-	endMs := event.Deadline.UTC().UnixMilli()
-
-	nowMs := time.Now().UTC().UnixMilli()
-	if nowMs < startMs || nowMs >= endMs {
+	now := time.Now().UTC()
+	if now.Before(event.StartTime) {
 		return nil, errors.New("event is not active")
 	}
 
-	leaders, err := s.ratingRepo.GetBetPointsLeaderboard(ctx, startMs, endMs, 1)
+	var leaderPoints int64
+	var leaderPrizeValueID *int
+	if !now.Before(event.Deadline.Add(competitionSettleGrace)) {
+		// Finished: the winner comes from the frozen results.
+		if err := s.ensureEventFinalized(ctx, event); err != nil {
+			return nil, err
+		}
+		results, err := s.repo.GetEventResults(ctx, eventID, 1, 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(results) == 0 {
+			return &domain.EventLeaderResponse{
+				LeaderImage: "",
+				Points:      0,
+			}, nil
+		}
+		leaderPoints = results[0].Points
+		leaderPrizeValueID = results[0].PrizeValueID
+	} else {
+		leaders, err := s.competitionLeaderboard(ctx, event, 1, 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(leaders) == 0 {
+			return &domain.EventLeaderResponse{
+				LeaderImage: "",
+				Points:      0,
+			}, nil
+		}
+
+		tiers, err := s.rewardTierPrizes(ctx, event)
+		if err != nil {
+			return nil, err
+		}
+		leaderTier := rewardTierForRank(tiers, 1)
+		if leaderTier == nil || leaderTier.prizeValue == nil {
+			return nil, errors.New("prize values not found for event")
+		}
+		leaderPoints = leaders[0].NetPoints
+		leaderPrizeValueID = &leaderTier.prizeValue.ID
+	}
+
+	var leaderImage string
+	if leaderPrizeValueID != nil {
+		achievement, err := s.achievementRepo.GetAchievementByPrizeID(ctx, *leaderPrizeValueID)
+		if err != nil {
+			return nil, err
+		}
+		if achievement != nil {
+			leaderImage = achievement.ImageURL
+		}
+	}
+
+	return &domain.EventLeaderResponse{
+		LeaderImage: leaderImage,
+		Points:      leaderPoints,
+	}, nil
+}
+
+// GetEventLeaderboard returns a page of a competition's standings, live while it runs and frozen once it
+// is finished, with each entry's rank change since the last snapshot. The caller's own entry is returned
+// in Me whether or not it is on the page.
+func (s *EventService) GetEventLeaderboard(ctx context.Context, eventID string, userUUID string, limit, offset int) (*domain.EventLeaderboardResponse, error) {
+	if eventID == "" {
+		return nil, errors.New("event id is required")
+	}
+	if s.repo == nil {
+		return nil, errors.New("event service dependencies are not configured")
+	}
+
+	event, err := s.repo.GetEventByID(ctx, eventID)
 	if err != nil {
 		return nil, err
 	}
-	if len(leaders) == 0 {
-		return &domain.EventLeaderResponse{
-			LeaderImage: "",
-			Points:      0,
-		}, nil
+	if event == nil {
+		return nil, errors.New("event not found")
+	}
+	if !strings.Contains(strings.ToLower(event.Tags), "competition") {
+		return nil, errors.New("event is not a competition")
+	}
+
+	now := time.Now().UTC()
+	if now.Before(event.StartTime) {
+		return nil, errors.New("event is not started yet")
+	}
+
+	response := &domain.EventLeaderboardResponse{
+		EventID: eventID,
+		Metric:  eventScoringRules(event).Metric,
+		Entries: []domain.EventLeaderboardEntry{},
 	}
 
-	prizeValues, err := s.prizeValueRepo.GetPrizeValuesByEventID(ctx, eventID)
+	if !now.Before(event.Deadline.Add(competitionSettleGrace)) {
+		// Finished: the standings come from the frozen results.
+		if err := s.ensureEventFinalized(ctx, event); err != nil {
+			return nil, err
+		}
+		response.Final = true
+
+		results, err := s.repo.GetEventResults(ctx, eventID, limit, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range results {
+			response.Entries = append(response.Entries, eventResultLeaderboardEntry(&result))
+		}
+		if userUUID != "" {
+			result, err := s.repo.GetUserEventResult(ctx, eventID, userUUID)
+			if err != nil {
+				return nil, err
+			}
+			if result != nil {
+				me := eventResultLeaderboardEntry(result)
+				response.Me = &me
+			}
+		}
+	} else {
+		rules := eventScoringRules(event)
+		startMs := event.StartTime.UTC().UnixMilli()
+		endMs := event.Deadline.UTC().UnixMilli()
+
+		standings, err := s.repo.GetEventStandings(ctx, eventID, startMs, endMs, rules, "", limit, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range standings {
+			response.Entries = append(response.Entries, standingLeaderboardEntry(&entry))
+		}
+		if userUUID != "" {
+			entry, err := s.repo.GetUserEventStanding(ctx, eventID, startMs, endMs, rules, userUUID)
+			if err != nil {
+				return nil, err
+			}
+			if entry != nil {
+				me := standingLeaderboardEntry(entry)
+				response.Me = &me
+			}
+		}
+	}
+
+	userUUIDs := make([]string, 0, len(response.Entries)+1)
+	for _, entry := range response.Entries {
+		userUUIDs = append(userUUIDs, entry.UserUUID)
+	}
+	if response.Me != nil {
+		userUUIDs = append(userUUIDs, response.Me.UserUUID)
+	}
+	previousRanks, snapshotAt, err := s.repo.GetEventRankSnapshot(ctx, eventID, userUUIDs)
 	if err != nil {
 		return nil, err
 	}
-	if len(prizeValues) == 0 {
-		return nil, errors.New("prize values not found for event")
+	response.SnapshotAt = snapshotAt
+	for i := range response.Entries {
+		setRankChange(&response.Entries[i], previousRanks)
+	}
+	if response.Me != nil {
+		setRankChange(response.Me, previousRanks)
 	}
-	sort.Slice(prizeValues, func(i, j int) bool {
-		return prizeValues[i].Value > prizeValues[j].Value
-	})
 
-	var leaderImage string
-	achievement, err := s.achievementRepo.GetAchievementByPrizeID(ctx, prizeValues[0].ID)
+	return response, nil
+}
+
+// SnapshotLeaderboards records the current ranks of every running competition; it is the periodic job
+// behind the leaderboard's rank change. Every replica runs it, but a competition is snapshotted at most
+// once per interval.
+func (s *EventService) SnapshotLeaderboards(ctx context.Context, now time.Time) error {
+	if s.repo == nil {
+		return errors.New("event repository is not configured")
+	}
+
+	events, err := s.repo.GetRunningCompetitions(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Allow some scheduling jitter so a replica doesn't skip a round it is due for.
+	minAge := s.snapshotInterval - s.snapshotInterval/10
+	for i := range events {
+		standings, err := s.competitionLeaderboard(ctx, &events[i], 0, 0)
+		if err != nil {
+			log.Printf("events: failed to rank %s for snapshot: %v", events[i].ID, err)
+			continue
+		}
+		if _, err := s.repo.SaveEventRankSnapshot(ctx, events[i].ID, standings, now.UTC().UnixMilli(), minAge.Milliseconds()); err != nil {
+			log.Printf("events: failed to snapshot %s: %v", events[i].ID, err)
+		}
+	}
+	return nil
+}
+
+func standingLeaderboardEntry(entry *domain.BetPrizeLeaderboardEntry) domain.EventLeaderboardEntry {
+	return domain.EventLeaderboardEntry{
+		Rank:      entry.Rank,
+		UserUUID:  entry.UserUUID,
+		UserName:  entry.UserName,
+		NetPoints: entry.NetPoints,
+		Score:     entry.Score,
+		WinCount:  entry.WinCount,
+		LossCount: entry.LossCount,
+	}
+}
+
+func eventResultLeaderboardEntry(result *domain.EventResult) domain.EventLeaderboardEntry {
+	return domain.EventLeaderboardEntry{
+		Rank:      result.Rank,
+		UserUUID:  result.UserUUID,
+		UserName:  result.UserName,
+		NetPoints: result.Points,
+		Score:     result.Score,
+		WinCount:  result.WinCount,
+		LossCount: result.LossCount,
+	}
+}
+
+// setRankChange sets the places gained since the snapshot (negative if dropped); users not in the
+// snapshot keep a nil change.
+func setRankChange(entry *domain.EventLeaderboardEntry, previousRanks map[string]int) {
+	if previous, ok := previousRanks[entry.UserUUID]; ok {
+		change := previous - entry.Rank
+		entry.RankChange = &change
+	}
+}
+
+// FreezeResults freezes a competition's final standings; it is the lifecycle hook run before an event is finished.
+func (s *EventService) FreezeResults(ctx context.Context, event *domain.Event) error {
+	if !strings.Contains(strings.ToLower(event.Tags), "competition") {
+		return nil
+	}
+	_, err := s.finalizeEvent(ctx, event, time.Now().UTC())
+	return err
+}
+
+// ReleasePrizes sets every participant's prize status from the frozen results, so winners see their prize
+// without asking for it; it is the lifecycle hook run after FreezeResults.
+func (s *EventService) ReleasePrizes(ctx context.Context, event *domain.Event) error {
+	if !strings.Contains(strings.ToLower(event.Tags), "competition") {
+		return nil
+	}
+	_, err := s.repo.ReleaseEventPrizes(ctx, event.ID)
+	return err
+}
+
+// ensureEventFinalized freezes an ended event's standings on demand when the finalizer job has not run yet.
+func (s *EventService) ensureEventFinalized(ctx context.Context, event *domain.Event) error {
+	finalized, err := s.repo.IsEventFinalized(ctx, event.ID)
+	if err != nil || finalized {
+		return err
+	}
+	_, err = s.finalizeEvent(ctx, event, time.Now().UTC())
+	return err
+}
+
+// finalizeEvent freezes the final standings of an ended event into event_results, assigns prize values
+// to the top places and queues winner notifications. Returns false if the event was already finalized.
+func (s *EventService) finalizeEvent(ctx context.Context, event *domain.Event, now time.Time) (bool, error) {
+	if s.prizeValueRepo == nil {
+		return false, errors.New("event service dependencies are not configured")
+	}
+	if now.Before(event.Deadline.Add(competitionSettleGrace)) {
+		return false, errors.New("event is not finished yet")
+	}
+
+	standings, err := s.competitionLeaderboard(ctx, event, 0, 0)
+	if err != nil {
+		return false, err
+	}
+	tiers, err := s.rewardTierPrizes(ctx, event)
+	if err != nil {
+		return false, err
+	}
+	if isSquadEvent(event) {
+		// Squad events pay their tiers to squads (see SquadService.FreezeResults), not to individual ranks.
+		tiers = nil
+	}
+	ties := eventScoringRules(event).Ties
+
+	results := make([]domain.EventResult, 0, len(standings))
+	var notifications []domain.Notification
+	for first := 0; first < len(standings); {
+		// Users tied on score occupy positions first+1..last together unless ties go to the earliest.
+		last := first + 1
+		if ties == domain.RewardTiesShared || ties == domain.RewardTiesSplit {
+			for last < len(standings) && standings[last].Score == standings[first].Score {
+				last++
+			}
+		}
+		prizeValue, prizeLabel := tiedPlacesAward(tiers, ties, first+1, last)
+		prizeValue, err = s.resolveTiedPlacesAward(ctx, prizeValue)
+		if err != nil {
+			return false, err
+		}
+
+		for _, entry := range standings[first:last] {
+			result := domain.EventResult{
+				EventID:      event.ID,
+				UserUUID:     entry.UserUUID,
+				Rank:         entry.Rank,
+				Points:       entry.NetPoints,
+				Score:        entry.Score,
+				WinCount:     entry.WinCount,
+				LossCount:    entry.LossCount,
+				LastScoredAt: entry.LastScoredAt,
+				PrizeLabel:   prizeLabel,
+			}
+			if prizeValue != nil {
+				result.PrizeValueID = &prizeValue.ID
+			}
+			if result.PrizeValueID != nil && now.Sub(event.Deadline) <= winnerNotificationWindow {
+				notifications = append(notifications, domain.Notification{
+					UserUUID: entry.UserUUID,
+					Producer: domain.NotificationProducerEventFinalizer,
+					Message:  fmt.Sprintf("You finished #%d in %s and won %s! Claim your prize in the app.", result.Rank, event.Title, prizeLabel),
+				})
+			}
+			results = append(results, result)
+		}
+		first = last
+	}
+
+	return s.repo.SaveEventResults(ctx, event.ID, results, notifications, now.UTC().UnixMilli())
+}
+
+// rewardTierPrize is a competition reward tier with the prize value that pays it.
+type rewardTierPrize struct {
+	tier       domain.Reward
+	prizeValue *domain.PrizeValue
+}
+
+// rewardTierPrizes returns the competition's rank reward tiers in rank order. A tier is paid by the prize
+// value of its linked achievement; tiers without a link (events created before reward tiers) are paid by
+// the prize value at the same position when prize values are ordered by value.
+func (s *EventService) rewardTierPrizes(ctx context.Context, event *domain.Event) ([]rewardTierPrize, error) {
+	prizeValues, err := s.prizeValueRepo.GetPrizeValuesByEventID(ctx, event.ID)
 	if err != nil {
 		return nil, err
 	}
-	if achievement != nil {
-		leaderImage = achievement.ImageURL
+	// Shares of split ties are paid by tiers, they don't pay any themselves
+	tierValues := prizeValues[:0]
+	for _, pv := range prizeValues {
+		if pv.SegmentID == nil || *pv.SegmentID != domain.PrizeSegmentTieSplit {
+			tierValues = append(tierValues, pv)
+		}
 	}
+	prizeValues = tierValues
+	sort.SliceStable(prizeValues, func(i, j int) bool {
+		return prizeValues[i].Value > prizeValues[j].Value
+	})
 
-	return &domain.EventLeaderResponse{
-		LeaderImage: leaderImage,
-		Points:      leaders[0].NetPoints,
-	}, nil
+	var tiers []domain.Reward
+	for _, reward := range event.Reward {
+		normalizeRewardTier(&reward)
+		if reward.RankFrom > 0 {
+			tiers = append(tiers, reward)
+		}
+	}
+	sort.SliceStable(tiers, func(i, j int) bool { return tiers[i].RankFrom < tiers[j].RankFrom })
+
+	tierPrizes := make([]rewardTierPrize, 0, len(tiers))
+	for i, tier := range tiers {
+		tierPrize := rewardTierPrize{tier: tier}
+		if tier.AchievementID != "" && s.achievementRepo != nil {
+			achievement, err := s.achievementRepo.GetAchievementByID(ctx, tier.AchievementID)
+			if err != nil {
+				return nil, err
+			}
+			if achievement != nil && achievement.PrizeID != nil {
+				for j := range prizeValues {
+					if prizeValues[j].ID == *achievement.PrizeID {
+						tierPrize.prizeValue = &prizeValues[j]
+						break
+					}
+				}
+			}
+		}
+		if tierPrize.prizeValue == nil && i < len(prizeValues) {
+			tierPrize.prizeValue = &prizeValues[i]
+		}
+		tierPrizes = append(tierPrizes, tierPrize)
+	}
+	return tierPrizes, nil
+}
+
+// rewardTierForRank returns the tier rewarding the rank, or nil if the rank wins nothing.
+func rewardTierForRank(tiers []rewardTierPrize, rank int) *rewardTierPrize {
+	for i := range tiers {
+		if tiers[i].tier.Covers(rank) {
+			return &tiers[i]
+		}
+	}
+	return nil
+}
+
+// tiedPlacesAward returns what each user tied on positions first..last wins: the tier of the best position.
+// When ties are split, the points prizes of every covered position are pooled and divided equally in whole
+// units (the remainder is not paid); the share is then an unsaved tie split prize value (ID 0), see
+// resolveTiedPlacesAward.
+func tiedPlacesAward(tiers []rewardTierPrize, ties domain.RewardTieMode, first, last int) (*domain.PrizeValue, string) {
+	best := rewardTierForRank(tiers, first)
+	if best == nil || best.prizeValue == nil {
+		return nil, ""
+	}
+
+	label := best.tier.Value
+	if label == "" {
+		label = prizeValueLabel(best.prizeValue)
+	}
+	if ties != domain.RewardTiesSplit || last <= first {
+		return best.prizeValue, label
+	}
+
+	var total int64
+	for position := first; position <= last; position++ {
+		tier := rewardTierForRank(tiers, position)
+		if tier == nil || tier.prizeValue == nil {
+			continue
+		}
+		if !isPointsPrizeValue(tier.prizeValue) {
+			// Only points can be divided; non-points prizes go to every tied user as when ties are shared
+			return best.prizeValue, label
+		}
+		total += tier.prizeValue.Value
+	}
+	share := total / int64(last-first+1)
+	if share == best.prizeValue.Value {
+		return best.prizeValue, label
+	}
+	if share <= 0 {
+		return nil, ""
+	}
+
+	currency := best.tier.Currency
+	if currency == "" {
+		_, currency = parseRewardAmount(label, "")
+	}
+	segmentID := domain.PrizeSegmentTieSplit
+	splitValue := &domain.PrizeValue{
+		EventID:    best.prizeValue.EventID,
+		Value:      share,
+		Label:      formatRewardAmount(float64(share), currency),
+		SegmentID:  &segmentID,
+		RewardType: domain.RewardTypePoints,
+	}
+	return splitValue, splitValue.Label
+}
+
+// resolveTiedPlacesAward saves the tie split prize value returned by tiedPlacesAward, so that the share and
+// not the best tier's prize is paid.
+func (s *EventService) resolveTiedPlacesAward(ctx context.Context, prizeValue *domain.PrizeValue) (*domain.PrizeValue, error) {
+	if prizeValue == nil || prizeValue.ID != 0 {
+		return prizeValue, nil
+	}
+	return s.prizeValueRepo.GetOrCreateTieSplitPrizeValue(ctx, prizeValue.EventID, prizeValue.Value, prizeValue.Label)
+}
+
+func isPointsPrizeValue(prizeValue *domain.PrizeValue) bool {
+	return prizeValue.RewardType == "" || prizeValue.RewardType == domain.RewardTypePoints
+}
+
+func prizeValueLabel(prizeValue *domain.PrizeValue) string {
+	if prizeValue.Label != "" {
+		return prizeValue.Label
+	}
+	return strconv.FormatInt(prizeValue.Value, 10)
 }
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"pdrest/internal/domain"
+	"testing"
+)
+
+func testRewardTiers() []rewardTierPrize {
+	return []rewardTierPrize{
+		{
+			tier:       domain.Reward{Value: "1000 USDT", RankFrom: 1, RankTo: 1, Amount: 1000, Currency: "USDT"},
+			prizeValue: &domain.PrizeValue{ID: 1, EventID: "cup", Value: 1000, Label: "1000 USDT", RewardType: domain.RewardTypePoints},
+		},
+		{
+			tier:       domain.Reward{Value: "500 USDT", RankFrom: 2, RankTo: 2, Amount: 500, Currency: "USDT"},
+			prizeValue: &domain.PrizeValue{ID: 2, EventID: "cup", Value: 500, Label: "500 USDT", RewardType: domain.RewardTypePoints},
+		},
+		{
+			tier:       domain.Reward{Value: "100 USDT", RankFrom: 3, RankTo: 5, Amount: 100, Currency: "USDT"},
+			prizeValue: &domain.PrizeValue{ID: 3, EventID: "cup", Value: 100, Label: "100 USDT", RewardType: domain.RewardTypePoints},
+		},
+	}
+}
+
+func TestTiedPlacesAward(t *testing.T) {
+	tests := []struct {
+		name        string
+		ties        domain.RewardTieMode
+		first, last int
+		wantID      int // 0 for a new tie split prize value
+		wantValue   int64
+		wantLabel   string
+		wantNoPrize bool
+	}{
+		{name: "single rank", ties: domain.RewardTiesSplit, first: 1, last: 1, wantID: 1, wantValue: 1000, wantLabel: "1000 USDT"},
+		{name: "earliest pays the best tier", ties: domain.RewardTiesEarliest, first: 2, last: 2, wantID: 2, wantValue: 500, wantLabel: "500 USDT"},
+		{name: "shared pays the best tier to everyone", ties: domain.RewardTiesShared, first: 1, last: 2, wantID: 1, wantValue: 1000, wantLabel: "1000 USDT"},
+		{name: "split across two tiers", ties: domain.RewardTiesSplit, first: 1, last: 2, wantID: 0, wantValue: 750, wantLabel: "750 USDT"},
+		{name: "split across three tiers", ties: domain.RewardTiesSplit, first: 2, last: 4, wantID: 0, wantValue: 233, wantLabel: "233 USDT"},
+		{name: "split within one tier keeps its prize", ties: domain.RewardTiesSplit, first: 3, last: 5, wantID: 3, wantValue: 100, wantLabel: "100 USDT"},
+		{name: "split past the last tier", ties: domain.RewardTiesSplit, first: 5, last: 8, wantID: 0, wantValue: 25, wantLabel: "25 USDT"},
+		{name: "unrewarded rank", ties: domain.RewardTiesSplit, first: 6, last: 7, wantNoPrize: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prizeValue, label := tiedPlacesAward(testRewardTiers(), tt.ties, tt.first, tt.last)
+			if tt.wantNoPrize {
+				if prizeValue != nil || label != "" {
+					t.Fatalf("got %+v %q, want no prize", prizeValue, label)
+				}
+				return
+			}
+			if prizeValue == nil {
+				t.Fatalf("got no prize, want %d", tt.wantValue)
+			}
+			if prizeValue.ID != tt.wantID || prizeValue.Value != tt.wantValue || label != tt.wantLabel {
+				t.Fatalf("got id %d value %d label %q, want id %d value %d label %q",
+					prizeValue.ID, prizeValue.Value, label, tt.wantID, tt.wantValue, tt.wantLabel)
+			}
+			if prizeValue.ID == 0 {
+				if prizeValue.SegmentID == nil || *prizeValue.SegmentID != domain.PrizeSegmentTieSplit {
+					t.Fatalf("split prize value segment = %v, want %q", prizeValue.SegmentID, domain.PrizeSegmentTieSplit)
+				}
+				if prizeValue.EventID != "cup" {
+					t.Fatalf("split prize value event = %q, want %q", prizeValue.EventID, "cup")
+				}
+			}
+		})
+	}
+}
+
+func TestTiedPlacesAwardSplitNonPointsPrize(t *testing.T) {
+	tiers := testRewardTiers()
+	tiers[1].prizeValue = &domain.PrizeValue{ID: 2, EventID: "cup", Label: "Silver badge", RewardType: domain.RewardTypeBadge}
+	tiers[1].tier.Value = "Silver badge"
+
+	prizeValue, label := tiedPlacesAward(tiers, domain.RewardTiesSplit, 1, 2)
+	if prizeValue == nil || prizeValue.ID != 1 || label != "1000 USDT" {
+		t.Fatalf("got %+v %q, want the best tier's prize value", prizeValue, label)
+	}
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"pdrest/internal/data"
+	"pdrest/internal/domain"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var eventTemplateIDPattern = regexp.MustCompile(`^[a-z0-9_]{1,30}$`)
+
+// EventTemplateService manages recurring event templates and materializes their instances.
+type EventTemplateService struct {
+	repo              data.EventTemplateRepository
+	eventAdminService *EventAdminService
+}
+
+func NewEventTemplateService(repo data.EventTemplateRepository, eventAdminService *EventAdminService) *EventTemplateService {
+	return &EventTemplateService{
+		repo:              repo,
+		eventAdminService: eventAdminService,
+	}
+}
+
+func (s *EventTemplateService) GetTemplates(ctx context.Context) ([]domain.EventTemplate, error) {
+	if s.repo == nil {
+		return nil, errors.New("event template repository is not configured")
+	}
+
+	templates, err := s.repo.GetEventTemplates(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	if templates == nil {
+		templates = make([]domain.EventTemplate, 0)
+	}
+	return templates, nil
+}
+
+// SaveTemplate creates or replaces a template. The template is validated by building its next instance,
+// so a template is accepted only if its instances would pass event validation.
+func (s *EventTemplateService) SaveTemplate(ctx context.Context, templateID string, template *domain.EventTemplate) (*domain.EventTemplate, error) {
+	if s.repo == nil {
+		return nil, errors.New("event template repository is not configured")
+	}
+
+	template.ID = strings.TrimSpace(templateID)
+	if !eventTemplateIDPattern.MatchString(template.ID) {
+		return nil, errors.New("id must be 1-30 characters of lowercase letters, digits or '_'")
+	}
+	template.Title = strings.TrimSpace(template.Title)
+	template.Tags = strings.TrimSpace(template.Tags)
+	switch template.Frequency {
+	case domain.EventTemplateDaily, domain.EventTemplateWeekly:
+	default:
+		return nil, errors.New("frequency must be 'daily' or 'weekly'")
+	}
+	if template.Weekday < 0 || template.Weekday > 6 {
+		return nil, errors.New("weekday must be between 0 (Sunday) and 6 (Saturday)")
+	}
+	if template.StartMinute < 0 || template.StartMinute >= 24*60 {
+		return nil, errors.New("startMinute must be between 0 and 1439")
+	}
+	if template.DurationMinutes <= 0 {
+		return nil, errors.New("durationMinutes must be greater than 0")
+	}
+	if template.LeadMinutes < 0 {
+		return nil, errors.New("leadMinutes must not be negative")
+	}
+
+	sample := templateInstance(template, nextTemplateStart(template, time.Now().UTC()))
+	normalizeAdminEvent(sample)
+	if err := validateAdminEvent(sample); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpsertEventTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+	return s.repo.GetEventTemplate(ctx, template.ID)
+}
+
+// Run materializes instances of all active templates; it is the template scheduler's periodic job.
+func (s *EventTemplateService) Run(ctx context.Context, now time.Time) error {
+	_, err := s.Materialize(ctx, now)
+	return err
+}
+
+// Materialize creates every instance of an active template that is running or starts within the
+// template's lead time. Instance IDs are deterministic and creation is conditional, so replicas
+// running this concurrently cannot create duplicates. Returns the IDs of created events.
+func (s *EventTemplateService) Materialize(ctx context.Context, now time.Time) ([]string, error) {
+	if s.repo == nil || s.eventAdminService == nil {
+		return nil, errors.New("event template service dependencies are not configured")
+	}
+
+	templates, err := s.repo.GetEventTemplates(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	created := make([]string, 0)
+	for i := range templates {
+		template := &templates[i]
+		for _, start := range templateOccurrences(template, now) {
+			// Instances up to the last recorded start were created before (and may have been archived since).
+			if template.LastInstanceStart != nil && !start.After(*template.LastInstanceStart) {
+				continue
+			}
+
+			instance := templateInstance(template, start)
+			_, err := s.eventAdminService.CreateEvent(ctx, instance)
+			if err != nil && !strings.Contains(err.Error(), fmt.Sprintf("event with id %s already exists", instance.ID)) {
+				log.Printf("event templates: failed to create %s from template %s: %v", instance.ID, template.ID, err)
+				break
+			}
+			if err == nil {
+				log.Printf("event templates: created %s from template %s", instance.ID, template.ID)
+				created = append(created, instance.ID)
+			}
+			if err := s.repo.SetLastInstanceStart(ctx, template.ID, start.UnixMilli()); err != nil {
+				return created, err
+			}
+		}
+	}
+	return created, nil
+}
+
+// templateOccurrences returns the starts (ascending) of a template's instances that have not
+// ended at now and start no later than now plus the template's lead time.
+func templateOccurrences(template *domain.EventTemplate, now time.Time) []time.Time {
+	now = now.UTC()
+	duration := time.Duration(template.DurationMinutes) * time.Minute
+	horizon := now.Add(time.Duration(template.LeadMinutes) * time.Minute)
+
+	first := now.Add(-duration).AddDate(0, 0, -1)
+	day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, time.UTC)
+	var starts []time.Time
+	for ; !day.After(horizon); day = day.AddDate(0, 0, 1) {
+		if template.Frequency == domain.EventTemplateWeekly && day.Weekday() != time.Weekday(template.Weekday) {
+			continue
+		}
+		start := day.Add(time.Duration(template.StartMinute) * time.Minute)
+		if start.Add(duration).After(now) && !start.After(horizon) {
+			starts = append(starts, start)
+		}
+	}
+	return starts
+}
+
+// nextTemplateStart returns the first instance start at or after now.
+func nextTemplateStart(template *domain.EventTemplate, now time.Time) time.Time {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	for {
+		start := day.Add(time.Duration(template.StartMinute) * time.Minute)
+		if !start.Before(now) && (template.Frequency != domain.EventTemplateWeekly || day.Weekday() == time.Weekday(template.Weekday)) {
+			return start
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+}
+
+// templateInstance builds the event instance of a template starting at start. Achievement IDs are
+// derived from the instance ID so every instance gets its own achievements.
+func templateInstance(template *domain.EventTemplate, start time.Time) *domain.AdminEvent {
+	instance := &domain.AdminEvent{
+		Event: domain.Event{
+			ID:        fmt.Sprintf("%s_%s", template.ID, start.Format("02_01_2006")),
+			Badge:     template.Badge,
+			Title:     template.Title,
+			Desc:      template.Desc,
+			StartTime: start,
+			Deadline:  start.Add(time.Duration(template.DurationMinutes) * time.Minute),
+			Tags:      template.Tags,
+			Reward:    append([]domain.Reward(nil), template.Reward...),
+			Info:      template.Info,
+		},
+	}
+	// Tiers are linked to the instance's own achievements when the instance is normalized.
+	for i := range instance.Reward {
+		instance.Reward[i].AchievementID = ""
+	}
+	if instance.Info == "" {
+		instance.Info = "Start: " + start.Format("2006-01-02T15:04:05Z")
+	}
+
+	if template.Scoring != nil {
+		scoring := *template.Scoring
+		scoring.Pairs = append([]string(nil), template.Scoring.Pairs...)
+		scoring.Timeframes = append([]int(nil), template.Scoring.Timeframes...)
+		instance.Scoring = &scoring
+	}
+	instance.Eligibility = cloneEventEligibility(template.Eligibility)
+
+	instance.PrizeValues = make([]domain.AdminEventPrize, 0, len(template.PrizeValues))
+	for i, prize := range template.PrizeValues {
+		prize.ID = 0
+		if prize.Achievement != nil {
+			achievement := *prize.Achievement
+			achievement.ID = fmt.Sprintf("%s_place_%d", instance.ID, i+1)
+			achievement.PrizeID = nil
+			prize.Achievement = &achievement
+		}
+		instance.PrizeValues = append(instance.PrizeValues, prize)
+	}
+	return instance
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pdrest/internal/data"
+	"pdrest/internal/domain"
+	"time"
+)
+
+type InventoryService struct {
+	repo data.InventoryRepository
+}
+
+func NewInventoryService(r data.InventoryRepository) *InventoryService {
+	return &InventoryService{repo: r}
+}
+
+// GetInventory returns the user's items that can still be used
+func (s *InventoryService) GetInventory(ctx context.Context, userUUID string) (*domain.InventoryResponse, error) {
+	if userUUID == "" {
+		return nil, errors.New("user uuid is required")
+	}
+	items, err := s.repo.GetUserInventory(ctx, userUUID, time.Now().UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	return &domain.InventoryResponse{Items: items}, nil
+}
+
+// ActivateItem activates one of the user's items: boosts and shields apply to the bets opened from now on,
+// extra spins are added to their roulette
+func (s *InventoryService) ActivateItem(ctx context.Context, userUUID string, itemID int) (*domain.ActivateInventoryItemResponse, error) {
+	if userUUID == "" {
+		return nil, errors.New("user uuid is required")
+	}
+	if itemID <= 0 {
+		return nil, errors.New("item id is required")
+	}
+	return s.repo.ActivateInventoryItem(ctx, userUUID, itemID, time.Now().UnixMilli())
+}
+
+// describeInventoryItem describes an item for reward descriptions, e.g. "2x payout on the next 3 bets"
+func describeInventoryItem(item *domain.InventoryItem) string {
+	var what string
+	switch item.ItemType {
+	case domain.InventoryItemPayoutBoost:
+		what = fmt.Sprintf("%gx payout", item.Metadata.Multiplier)
+	case domain.InventoryItemLossShield:
+		what = "Loss shield"
+	case domain.InventoryItemExtraSpin:
+		if item.Quantity != nil {
+			return fmt.Sprintf("%d extra spins", *item.Quantity)
+		}
+		return "Extra spins"
+	case domain.InventoryItemStreakFreeze:
+		if item.Quantity != nil && *item.Quantity > 1 {
+			return fmt.Sprintf("%d streak freezes", *item.Quantity)
+		}
+		return "Streak freeze"
+	default:
+		what = item.ItemType
+	}
+
+	switch {
+	case item.Quantity != nil && item.Metadata.DurationHours > 0:
+		return fmt.Sprintf("%s on the next %d bets within %d hours of activation", what, *item.Quantity, item.Metadata.DurationHours)
+	case item.Quantity != nil:
+		return fmt.Sprintf("%s on the next %d bets", what, *item.Quantity)
+	case item.Metadata.DurationHours > 0:
+		return fmt.Sprintf("%s for %d hours after activation", what, item.Metadata.DurationHours)
+	default:
+		return what
+	}
+}
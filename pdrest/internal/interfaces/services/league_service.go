@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"pdrest/internal/data"
+	"pdrest/internal/domain"
+	"strconv"
+	"time"
+)
+
+type LeagueService struct {
+	repo           data.LeagueRepository
+	prizeValueRepo data.PrizeValueRepository
+	cohortSize     int
+	promoteCount   int
+	relegateCount  int
+}
+
+func NewLeagueService(repo data.LeagueRepository, prizeValueRepo data.PrizeValueRepository, cohortSize, promoteCount, relegateCount int) *LeagueService {
+	if cohortSize <= 0 {
+		cohortSize = 50
+	}
+	return &LeagueService{
+		repo:           repo,
+		prizeValueRepo: prizeValueRepo,
+		cohortSize:     cohortSize,
+		promoteCount:   promoteCount,
+		relegateCount:  relegateCount,
+	}
+}
+
+// leagueWeekStart returns Monday 00:00 UTC of the week containing t.
+func leagueWeekStart(t time.Time) time.Time {
+	t = t.UTC()
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, time.UTC)
+}
+
+// AssignUser places the user into a cohort of their tier for the current week (no-op if already assigned).
+func (s *LeagueService) AssignUser(ctx context.Context, userUUID string) (int, error) {
+	if userUUID == "" {
+		return 0, errors.New("user uuid is required")
+	}
+	if s.repo == nil {
+		return 0, errors.New("league repository is not configured")
+	}
+
+	weekStart := leagueWeekStart(time.Now())
+	weekStartMs := weekStart.UnixMilli()
+	if err := s.repo.EnsureWeek(ctx, weekStartMs, weekStart.AddDate(0, 0, 7).UnixMilli()); err != nil {
+		return 0, err
+	}
+
+	cohortID, err := s.repo.GetUserCohortID(ctx, userUUID, weekStartMs)
+	if err != nil {
+		return 0, err
+	}
+	if cohortID != nil {
+		return *cohortID, nil
+	}
+
+	return s.repo.AssignUserToCohort(ctx, userUUID, weekStartMs, s.cohortSize)
+}
+
+// GetUserLeague returns the caller's tier and the current cohort leaderboard, assigning the user if needed.
+func (s *LeagueService) GetUserLeague(ctx context.Context, userUUID string) (*domain.UserLeagueResponse, error) {
+	cohortID, err := s.AssignUser(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	cohort, err := s.GetCohortLeaderboard(ctx, cohortID)
+	if err != nil {
+		return nil, err
+	}
+
+	tier, err := s.repo.GetUserTier(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &domain.UserLeagueResponse{
+		Tier:    tier,
+		Cohort:  cohort.Cohort,
+		Entries: cohort.Entries,
+	}
+	for _, entry := range cohort.Entries {
+		if entry.UserUUID == userUUID {
+			response.Rank = entry.Rank
+			response.Points = entry.Points
+			break
+		}
+	}
+	return response, nil
+}
+
+func (s *LeagueService) GetCohortLeaderboard(ctx context.Context, cohortID int) (*domain.LeagueCohortResponse, error) {
+	if cohortID <= 0 {
+		return nil, errors.New("cohort id is required")
+	}
+	if s.repo == nil {
+		return nil, errors.New("league repository is not configured")
+	}
+
+	cohort, err := s.repo.GetCohort(ctx, cohortID)
+	if err != nil {
+		return nil, err
+	}
+	if cohort == nil {
+		return nil, errors.New("league cohort not found")
+	}
+
+	entries, err := s.repo.GetCohortStandings(ctx, cohortID)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = make([]domain.LeagueCohortEntry, 0)
+	}
+
+	return &domain.LeagueCohortResponse{
+		Cohort:  *cohort,
+		Entries: entries,
+	}, nil
+}
+
+// Rollover opens the current week, finishes every ended week by freezing its ranks and outcomes, and applies
+// the tier moves and podium prizes still owed. Safe to run on every replica: each week is finished once and
+// every result is applied at most once.
+func (s *LeagueService) Rollover(ctx context.Context, now time.Time) error {
+	if s.repo == nil {
+		return errors.New("league repository is not configured")
+	}
+
+	weekStart := leagueWeekStart(now)
+	if err := s.repo.EnsureWeek(ctx, weekStart.UnixMilli(), weekStart.AddDate(0, 0, 7).UnixMilli()); err != nil {
+		return err
+	}
+
+	weeks, err := s.repo.GetEndedActiveWeeks(ctx, now.UTC().UnixMilli())
+	if err != nil {
+		return err
+	}
+
+	for _, week := range weeks {
+		finished, err := s.repo.FinishWeek(ctx, week.WeekStart.UnixMilli(), now.UTC().UnixMilli(), s.promoteCount, s.relegateCount)
+		if err != nil {
+			return err
+		}
+		if finished {
+			log.Printf("league: finished week %s", week.WeekStart.Format("2006-01-02"))
+		}
+	}
+
+	// Results are applied one by one, so a run that failed partway is resumed by the next one
+	return s.applyWeekResults(ctx)
+}
+
+func (s *LeagueService) applyWeekResults(ctx context.Context) error {
+	if s.prizeValueRepo == nil {
+		return errors.New("league service dependencies are not configured")
+	}
+
+	results, err := s.repo.GetUnappliedLeagueResults(ctx)
+	if err != nil {
+		return err
+	}
+
+	eventID := domain.LeagueEventID
+	prizeValues := make(map[int]*domain.PrizeValue)
+	for _, result := range results {
+		newTier := result.Tier
+		switch result.Outcome {
+		case domain.LeagueOutcomePromoted:
+			newTier = result.Tier.Promote()
+		case domain.LeagueOutcomeRelegated:
+			newTier = result.Tier.Relegate()
+		}
+
+		var prize *domain.Prize
+		var points int64
+		var description string
+		if result.PrizeValueID != nil {
+			prizeValue, ok := prizeValues[*result.PrizeValueID]
+			if !ok {
+				prizeValue, err = s.prizeValueRepo.GetPrizeValueByID(ctx, *result.PrizeValueID)
+				if err != nil {
+					return err
+				}
+				if prizeValue == nil {
+					return fmt.Errorf("prize value %d not found", *result.PrizeValueID)
+				}
+				prizeValues[*result.PrizeValueID] = prizeValue
+			}
+			prizeValueStr := prizeValue.Label
+			if prizeValueStr == "" {
+				prizeValueStr = strconv.FormatInt(prizeValue.Value, 10)
+			}
+
+			userUUID := result.UserUUID
+			now := time.Now().UTC().UnixMilli()
+			prize = &domain.Prize{
+				EventID:      &eventID,
+				UserID:       &userUUID,
+				PrizeValueID: &prizeValue.ID,
+				PrizeValue:   prizeValueStr,
+				PrizeType:    domain.PrizeTypeLeagueReward,
+				AwardedAt:    now,
+				CreatedAt:    now,
+			}
+			points = prizeValue.Value
+			description = fmt.Sprintf("%s league week %s place %d reward", result.Tier, result.WeekStart.Format("2006-01-02"), result.Rank)
+		}
+
+		if _, err := s.repo.ApplyLeagueResult(ctx, result, newTier, prize, points, description); err != nil {
+			return fmt.Errorf("failed to apply league week %s result of %s: %w", result.WeekStart.Format("2006-01-02"), result.UserUUID, err)
+		}
+	}
+
+	return nil
+}
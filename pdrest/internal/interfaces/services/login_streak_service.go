@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"pdrest/internal/data"
+	"pdrest/internal/domain"
+	"strconv"
+	"time"
+)
+
+const loginStreakDayLayout = "2006-01-02"
+
+type LoginStreakService struct {
+	repo           data.LoginStreakRepository
+	questRepo      data.QuestRepository
+	prizeRepo      data.PrizeRepository
+	prizeValueRepo data.PrizeValueRepository
+	rewards        *RewardService
+	maxFreezeDays  int
+}
+
+// loginStreakState is a user's streak as of today and what their next check-in would be
+type loginStreakState struct {
+	response    domain.LoginStreakResponse
+	previousDay string
+}
+
+func NewLoginStreakService(r data.LoginStreakRepository, questRepo data.QuestRepository, prizeRepo data.PrizeRepository, prizeValueRepo data.PrizeValueRepository, rewards *RewardService, maxFreezeDays int) *LoginStreakService {
+	if maxFreezeDays < 0 {
+		maxFreezeDays = 0
+	}
+	return &LoginStreakService{
+		repo:           r,
+		questRepo:      questRepo,
+		prizeRepo:      prizeRepo,
+		prizeValueRepo: prizeValueRepo,
+		rewards:        rewards,
+		maxFreezeDays:  maxFreezeDays,
+	}
+}
+
+// GetLoginStreak returns the user's streak, the reward of their next check-in and the reward calendar
+func (s *LoginStreakService) GetLoginStreak(ctx context.Context, userUUID string) (*domain.LoginStreakResponse, error) {
+	if userUUID == "" {
+		return nil, errors.New("user uuid is required")
+	}
+	state, err := s.currentState(ctx, userUUID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &state.response, nil
+}
+
+// ClaimToday checks the user in for today and pays the calendar reward of their streak day. Missed days since
+// the last check-in use up streak freezes when the user has enough of them, otherwise the streak starts over.
+// The check-in is recorded together with its prize, and the reward is granted once per check-in: a grant that
+// failed is retried with the same prize by the next claim of the day.
+func (s *LoginStreakService) ClaimToday(ctx context.Context, userUUID string) (*domain.LoginStreakClaimResult, error) {
+	if userUUID == "" {
+		return nil, errors.New("user uuid is required")
+	}
+	if s.repo == nil || s.prizeRepo == nil || s.prizeValueRepo == nil || s.rewards == nil {
+		return nil, errors.New("login streak service dependencies are not configured")
+	}
+
+	state, err := s.currentState(ctx, userUUID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	today := state.response.Today
+
+	var checkIn *domain.LoginCheckIn
+	if state.response.ClaimedToday {
+		checkIn, err = s.repo.GetLoginCheckIn(ctx, userUUID, today)
+		if err != nil {
+			return nil, err
+		}
+		if checkIn == nil || checkIn.RewardedAt != nil {
+			return nil, errors.New("today's login reward is already claimed")
+		}
+	}
+
+	var prizeValue *domain.PrizeValue
+	var prize *domain.Prize
+	if checkIn == nil {
+		reward := state.response.NextReward
+		if reward == nil {
+			return nil, errors.New("login reward calendar is empty")
+		}
+		prizeValue, err = s.loadPrizeValue(ctx, reward.PrizeValueID)
+		if err != nil {
+			return nil, err
+		}
+		checkIn = &domain.LoginCheckIn{
+			UserUUID:     userUUID,
+			Day:          today,
+			StreakDay:    state.response.NextStreakDay,
+			CalendarDay:  reward.Day,
+			PrizeValueID: reward.PrizeValueID,
+		}
+		if state.response.CurrentStreak > 0 {
+			checkIn.FreezesUsed = state.response.MissedDays
+		}
+
+		now := time.Now().UnixMilli()
+		prizeValueStr := prizeValue.Label
+		if prizeValueStr == "" {
+			prizeValueStr = strconv.FormatInt(prizeValue.Value, 10)
+		}
+		eventID := prizeValue.EventID
+		prize = &domain.Prize{
+			EventID:      &eventID,
+			UserID:       &userUUID,
+			PrizeValueID: &prizeValue.ID,
+			PrizeValue:   prizeValueStr,
+			PrizeType:    domain.PrizeTypeLoginReward,
+			AwardedAt:    now,
+			CreatedAt:    now,
+		}
+		checkedIn, err := s.repo.CheckIn(ctx, checkIn, prize, state.previousDay, now)
+		if err != nil {
+			return nil, err
+		}
+		if !checkedIn {
+			return nil, errors.New("today's login reward is already claimed")
+		}
+	} else {
+		prizeValue, err = s.loadPrizeValue(ctx, checkIn.PrizeValueID)
+		if err != nil {
+			return nil, err
+		}
+		if checkIn.GotPrizeID == nil {
+			return nil, errors.New("login reward prize not found")
+		}
+		prize, err = s.prizeRepo.GetPrizeByID(ctx, *checkIn.GotPrizeID)
+		if err != nil {
+			return nil, err
+		}
+		if prize == nil {
+			return nil, errors.New("login reward prize not found")
+		}
+	}
+
+	// The claim is keyed on the check-in (user, day): only one request grants its reward
+	rewardedAt := time.Now().UnixMilli()
+	claimed, err := s.repo.ClaimLoginCheckInReward(ctx, userUUID, checkIn.Day, rewardedAt)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, errors.New("today's login reward is already claimed")
+	}
+	reward, err := s.rewards.Grant(ctx, RewardGrant{
+		UserUUID:   userUUID,
+		PrizeValue: prizeValue,
+		Prize:      prize,
+		Source:     fmt.Sprintf("Login streak day %d", checkIn.StreakDay),
+	})
+	if err != nil {
+		if releaseErr := s.repo.ReleaseLoginCheckInReward(ctx, userUUID, checkIn.Day); releaseErr != nil {
+			log.Printf("Error releasing login reward claim of %s on %s: %v", userUUID, checkIn.Day, releaseErr)
+		}
+		return nil, fmt.Errorf("failed to grant login reward: %w", err)
+	}
+	checkIn.RewardedAt = &rewardedAt
+
+	best := state.response.BestStreak
+	if checkIn.StreakDay > best {
+		best = checkIn.StreakDay
+	}
+	return &domain.LoginStreakClaimResult{
+		CheckIn:       *checkIn,
+		CurrentStreak: checkIn.StreakDay,
+		BestStreak:    best,
+		Prize:         prize,
+		Reward:        reward,
+	}, nil
+}
+
+func (s *LoginStreakService) loadPrizeValue(ctx context.Context, prizeValueID int) (*domain.PrizeValue, error) {
+	prizeValue, err := s.prizeValueRepo.GetPrizeValueByID(ctx, prizeValueID)
+	if err != nil {
+		return nil, err
+	}
+	if prizeValue == nil {
+		return nil, errors.New("prize value not found")
+	}
+	if err := s.rewards.Validate(ctx, prizeValue); err != nil {
+		return nil, err
+	}
+	return prizeValue, nil
+}
+
+// currentState works out the user's streak on the current day of their time zone
+func (s *LoginStreakService) currentState(ctx context.Context, userUUID string, now time.Time) (*loginStreakState, error) {
+	if s.repo == nil || s.questRepo == nil {
+		return nil, errors.New("login streak service dependencies are not configured")
+	}
+	timezone, loc, err := userTimezone(ctx, s.questRepo, userUUID, now)
+	if err != nil {
+		return nil, err
+	}
+	todayStart, tomorrowStart := questPeriod(domain.QuestPeriodDaily, now, loc)
+	today := todayStart.Format(loginStreakDayLayout)
+
+	streak, err := s.repo.GetLoginStreak(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	freezes, err := s.repo.CountStreakFreezes(ctx, userUUID, now.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	calendar, err := s.repo.GetLoginRewardCalendar(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &loginStreakState{
+		response: domain.LoginStreakResponse{
+			Timezone:         timezone.Timezone,
+			Today:            today,
+			NextStreakDay:    1,
+			FreezesAvailable: freezes,
+			ResetsAt:         tomorrowStart.UnixMilli(),
+			Calendar:         calendar,
+		},
+	}
+	if streak != nil {
+		state.previousDay = streak.LastCheckinDay
+		state.response.BestStreak = streak.BestStreak
+	}
+	if streak != nil && streak.LastCheckinDay != "" {
+		gap, err := loginStreakDaysBetween(streak.LastCheckinDay, today)
+		if err != nil {
+			return nil, err
+		}
+		state.response.ClaimedToday = gap <= 0
+		state.response.CurrentStreak, state.response.MissedDays = continuedLoginStreak(streak.CurrentStreak, gap, freezes, s.maxFreezeDays)
+		if state.response.CurrentStreak > 0 {
+			state.response.NextStreakDay = state.response.CurrentStreak + 1
+		}
+	}
+	if len(calendar) > 0 {
+		reward := calendar[(state.response.NextStreakDay-1)%len(calendar)]
+		state.response.NextReward = &reward
+	}
+	return state, nil
+}
+
+// continuedLoginStreak returns the streak a check-in gap days after the last one continues, 0 when it starts
+// over, and the missed days it uses freezes for. Up to maxFreezeDays missed days in a row are covered when the
+// user has a freeze for each of them.
+func continuedLoginStreak(currentStreak, gap, freezes, maxFreezeDays int) (int, int) {
+	switch {
+	case gap <= 1:
+		return currentStreak, 0
+	case gap-1 <= maxFreezeDays && gap-1 <= freezes:
+		return currentStreak, gap - 1
+	}
+	return 0, 0
+}
+
+// loginStreakDaysBetween returns the number of calendar days from one YYYY-MM-DD day to another
+func loginStreakDaysBetween(from, to string) (int, error) {
+	fromDay, err := time.Parse(loginStreakDayLayout, from)
+	if err != nil {
+		return 0, fmt.Errorf("invalid check-in day %q", from)
+	}
+	toDay, err := time.Parse(loginStreakDayLayout, to)
+	if err != nil {
+		return 0, fmt.Errorf("invalid check-in day %q", to)
+	}
+	return int(toDay.Sub(fromDay).Hours() / 24), nil
+}
@@ -0,0 +1,72 @@
+package services
+
+import "testing"
+
+func TestLoginStreakDaysBetween(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		want    int
+		wantErr bool
+	}{
+		{name: "same day", from: "2026-04-06", to: "2026-04-06", want: 0},
+		{name: "next day", from: "2026-04-06", to: "2026-04-07", want: 1},
+		{name: "across a month", from: "2026-01-30", to: "2026-02-02", want: 3},
+		{name: "across a leap day", from: "2028-02-28", to: "2028-03-01", want: 2},
+		{name: "across a year", from: "2026-12-31", to: "2027-01-01", want: 1},
+		{name: "across daylight saving time", from: "2026-03-28", to: "2026-03-30", want: 2},
+		{name: "backwards", from: "2026-04-07", to: "2026-04-06", want: -1},
+		{name: "invalid from", from: "2026-4-6", to: "2026-04-06", wantErr: true},
+		{name: "invalid to", from: "2026-04-06", to: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loginStreakDaysBetween(tt.from, tt.to)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("loginStreakDaysBetween(%q, %q) = %d, want an error", tt.from, tt.to, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loginStreakDaysBetween(%q, %q) failed: %v", tt.from, tt.to, err)
+			}
+			if got != tt.want {
+				t.Fatalf("loginStreakDaysBetween(%q, %q) = %d, want %d", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContinuedLoginStreak(t *testing.T) {
+	tests := []struct {
+		name          string
+		streak        int
+		gap           int
+		freezes       int
+		maxFreezeDays int
+		wantStreak    int
+		wantMissed    int
+	}{
+		{name: "checked in today", streak: 4, gap: 0, freezes: 0, maxFreezeDays: 2, wantStreak: 4},
+		{name: "checked in yesterday", streak: 4, gap: 1, freezes: 0, maxFreezeDays: 2, wantStreak: 4},
+		{name: "one missed day without freezes", streak: 4, gap: 2, freezes: 0, maxFreezeDays: 2, wantStreak: 0},
+		{name: "one missed day with a freeze", streak: 4, gap: 2, freezes: 1, maxFreezeDays: 2, wantStreak: 4, wantMissed: 1},
+		{name: "two missed days with one freeze", streak: 4, gap: 3, freezes: 1, maxFreezeDays: 2, wantStreak: 0},
+		{name: "two missed days with two freezes", streak: 4, gap: 3, freezes: 2, maxFreezeDays: 2, wantStreak: 4, wantMissed: 2},
+		{name: "more missed days than freezes cover in a row", streak: 4, gap: 4, freezes: 5, maxFreezeDays: 2, wantStreak: 0},
+		{name: "freezes disabled", streak: 4, gap: 2, freezes: 3, maxFreezeDays: 0, wantStreak: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			streak, missed := continuedLoginStreak(tt.streak, tt.gap, tt.freezes, tt.maxFreezeDays)
+			if streak != tt.wantStreak || missed != tt.wantMissed {
+				t.Fatalf("continuedLoginStreak(%d, %d, %d, %d) = %d, %d, want %d, %d",
+					tt.streak, tt.gap, tt.freezes, tt.maxFreezeDays, streak, missed, tt.wantStreak, tt.wantMissed)
+			}
+		})
+	}
+}
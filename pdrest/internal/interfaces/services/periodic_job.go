@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// PeriodicJob runs a background task immediately and then on every interval
+// (season rollover, league rollover, ...). Jobs must be safe to run on every replica.
+type PeriodicJob struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context, now time.Time) error
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewPeriodicJob creates a new periodic job
+func NewPeriodicJob(name string, interval time.Duration, run func(ctx context.Context, now time.Time) error) *PeriodicJob {
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &PeriodicJob{
+		name:     name,
+		interval: interval,
+		run:      run,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Start launches the job loop
+func (j *PeriodicJob) Start() {
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			j.runOnce()
+			select {
+			case <-ticker.C:
+			case <-j.ctx.Done():
+				return
+			}
+		}
+	}()
+	log.Printf("Started %s job (interval %v)", j.name, j.interval)
+}
+
+func (j *PeriodicJob) runOnce() {
+	ctx, cancel := context.WithTimeout(j.ctx, time.Minute)
+	defer cancel()
+
+	if err := j.run(ctx, time.Now().UTC()); err != nil {
+		log.Printf("Error during %s job: %v", j.name, err)
+	}
+}
+
+// Shutdown stops the job and waits for a running iteration to complete
+func (j *PeriodicJob) Shutdown() {
+	log.Printf("Shutting down %s job...", j.name)
+	j.cancel()
+	j.wg.Wait()
+	log.Printf("%s job shut down complete", j.name)
+}
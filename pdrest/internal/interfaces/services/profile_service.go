@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pdrest/internal/data"
+	"pdrest/internal/domain"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type ProfileService struct {
+	repo            data.ProfileRepository
+	userRepo        data.UserRepository
+	rewardRepo      data.RewardRepository
+	achievementRepo data.AchievementRepository
+	leagueRepo      data.LeagueRepository
+	xpRepo          data.XPRepository
+	skillService    *SkillService
+}
+
+func NewProfileService(r data.ProfileRepository, userRepo data.UserRepository, rewardRepo data.RewardRepository, achievementRepo data.AchievementRepository, leagueRepo data.LeagueRepository, xpRepo data.XPRepository, skillService *SkillService) *ProfileService {
+	return &ProfileService{
+		repo:            r,
+		userRepo:        userRepo,
+		rewardRepo:      rewardRepo,
+		achievementRepo: achievementRepo,
+		leagueRepo:      leagueRepo,
+		xpRepo:          xpRepo,
+		skillService:    skillService,
+	}
+}
+
+// GetPublicProfile returns userUUID's profile as viewerUUID sees it: other users don't see a hidden name or
+// hidden stats, rank, league and level. The owner sees everything and their settings.
+func (s *ProfileService) GetPublicProfile(ctx context.Context, viewerUUID, userUUID string) (*domain.PublicProfile, error) {
+	if userUUID == "" {
+		return nil, errors.New("user uuid is required")
+	}
+	user, err := s.userRepo.GetProfile(userUUID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+	settings, err := s.repo.GetProfileSettings(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		return nil, errors.New("user not found")
+	}
+	owner := viewerUUID == userUUID
+
+	profile := &domain.PublicProfile{
+		UserID:   user.UserID,
+		Username: user.Username,
+	}
+	if owner {
+		profile.Settings = settings
+	} else if settings.HideName {
+		name := domain.ProfileAnonymousName
+		profile.Username = &name
+		profile.NameHidden = true
+	}
+
+	if profile.Showcase, err = s.repo.GetShowcase(ctx, userUUID); err != nil {
+		return nil, err
+	}
+	badges, err := s.rewardRepo.GetUserBadges(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	profile.Badges = badges
+	if profile.Badges == nil {
+		profile.Badges = []domain.UserBadge{}
+	}
+
+	if settings.HideStats && !owner {
+		profile.StatsHidden = true
+		return profile, nil
+	}
+
+	if profile.Stats, err = s.repo.GetProfileStats(ctx, userUUID); err != nil {
+		return nil, err
+	}
+	if profile.Stats.BestWinStreak, _, err = s.achievementRepo.CountWinStreaks(ctx, userUUID, domain.AchievementRule{}); err != nil {
+		return nil, err
+	}
+	if profile.Rank, err = s.repo.GetGlobalRank(ctx, userUUID); err != nil {
+		return nil, err
+	}
+	if profile.League, err = s.leagueRepo.GetUserTier(ctx, userUUID); err != nil {
+		return nil, err
+	}
+	level, err := s.xpRepo.GetUserLevel(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	profile.Level = level.Level
+	profile.XP = level.XP
+	if s.skillService != nil {
+		if profile.SkillRating, err = s.skillService.GetUserSkillRating(ctx, userUUID); err != nil {
+			return nil, err
+		}
+	}
+	return profile, nil
+}
+
+// GetSettings returns the user's profile settings
+func (s *ProfileService) GetSettings(ctx context.Context, userUUID string) (*domain.ProfileSettings, error) {
+	if userUUID == "" {
+		return nil, errors.New("user uuid is required")
+	}
+	settings, err := s.repo.GetProfileSettings(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		return nil, errors.New("user not found")
+	}
+	return settings, nil
+}
+
+// UpdateSettings applies the fields set in req and returns the resulting settings
+func (s *ProfileService) UpdateSettings(ctx context.Context, userUUID string, req domain.UpdateProfileSettingsRequest) (*domain.ProfileSettings, error) {
+	settings, err := s.GetSettings(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	if req.DisplayName != nil {
+		name := strings.TrimSpace(*req.DisplayName)
+		if name == "" {
+			settings.DisplayName = nil
+		} else {
+			if err := validateDisplayName(name); err != nil {
+				return nil, err
+			}
+			settings.DisplayName = &name
+		}
+	}
+	if req.HideName != nil {
+		settings.HideName = *req.HideName
+	}
+	if req.HideStats != nil {
+		settings.HideStats = *req.HideStats
+	}
+	if err := s.repo.UpdateProfileSettings(ctx, userUUID, *settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// SetShowcase replaces the user's showcase with up to ProfileShowcaseSize claimed achievements
+func (s *ProfileService) SetShowcase(ctx context.Context, userUUID string, achievementIDs []string) ([]domain.ShowcaseBadge, error) {
+	if userUUID == "" {
+		return nil, errors.New("user uuid is required")
+	}
+	if len(achievementIDs) > domain.ProfileShowcaseSize {
+		return nil, fmt.Errorf("showcase can hold at most %d achievements", domain.ProfileShowcaseSize)
+	}
+	seen := make(map[string]bool, len(achievementIDs))
+	for _, id := range achievementIDs {
+		if id == "" {
+			return nil, errors.New("achievement id is required")
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("achievement %s is listed twice", id)
+		}
+		seen[id] = true
+	}
+	if err := s.repo.SetShowcase(ctx, userUUID, achievementIDs); err != nil {
+		return nil, err
+	}
+	return s.repo.GetShowcase(ctx, userUUID)
+}
+
+// validateDisplayName allows letters, digits, spaces and _-. within the length limits; "Anonymous" is reserved
+// for hidden names.
+func validateDisplayName(name string) error {
+	length := utf8.RuneCountInString(name)
+	if length < domain.ProfileDisplayNameMinLen || length > domain.ProfileDisplayNameMaxLen {
+		return fmt.Errorf("display name must be %d to %d characters long", domain.ProfileDisplayNameMinLen, domain.ProfileDisplayNameMaxLen)
+	}
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == ' ' || r == '_' || r == '-' || r == '.' {
+			continue
+		}
+		return errors.New("display name may only contain letters, digits, spaces and _-.")
+	}
+	if strings.EqualFold(name, domain.ProfileAnonymousName) {
+		return errors.New("display name is reserved")
+	}
+	return nil
+}
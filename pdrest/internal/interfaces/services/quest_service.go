@@ -0,0 +1,336 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"pdrest/internal/data"
+	"pdrest/internal/domain"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// questMetricsByEvent are the quest metrics each domain event moves; like achievements, a claim re-delivers
+// the bet in case its settlement event was lost.
+var questMetricsByEvent = map[string][]string{
+	domain.DomainEventBetSettled: {
+		domain.AchievementMetricBetsPlaced,
+		domain.AchievementMetricWins,
+		domain.AchievementMetricWinStreak,
+	},
+	domain.DomainEventBetClaimed: {
+		domain.AchievementMetricBetsPlaced,
+		domain.AchievementMetricWins,
+		domain.AchievementMetricWinStreak,
+	},
+	domain.DomainEventReferralActivated: {domain.AchievementMetricReferrals},
+}
+
+type QuestService struct {
+	repo           data.QuestRepository
+	prizeRepo      data.PrizeRepository
+	prizeValueRepo data.PrizeValueRepository
+	rewards        *RewardService
+	dailyCount     int
+	weeklyCount    int
+}
+
+func NewQuestService(r data.QuestRepository, prizeRepo data.PrizeRepository, prizeValueRepo data.PrizeValueRepository, rewards *RewardService, dailyCount, weeklyCount int) *QuestService {
+	if dailyCount < 0 {
+		dailyCount = 0
+	}
+	if weeklyCount < 0 {
+		weeklyCount = 0
+	}
+	return &QuestService{
+		repo:           r,
+		prizeRepo:      prizeRepo,
+		prizeValueRepo: prizeValueRepo,
+		rewards:        rewards,
+		dailyCount:     dailyCount,
+		weeklyCount:    weeklyCount,
+	}
+}
+
+// GetQuests returns the user's quests of the current day and week, giving them new ones when a period starts
+func (s *QuestService) GetQuests(ctx context.Context, userUUID string) (*domain.QuestsResponse, error) {
+	if userUUID == "" {
+		return nil, errors.New("user uuid is required")
+	}
+	timezone, quests, err := s.currentQuests(ctx, userUUID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &domain.QuestsResponse{UserTimezone: *timezone, Quests: quests}, nil
+}
+
+// SetTimezone sets the IANA time zone the user's quests and login streak reset in. The change applies from the
+// next daily reset of the current zone, so switching zones never starts a new day early; until then a later
+// change replaces it.
+func (s *QuestService) SetTimezone(ctx context.Context, userUUID string, timezone string) (*domain.UserTimezone, error) {
+	if userUUID == "" {
+		return nil, errors.New("user uuid is required")
+	}
+	if timezone == "" {
+		return nil, errors.New("timezone is required")
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return nil, fmt.Errorf("invalid timezone %q", timezone)
+	}
+	if s.repo == nil {
+		return nil, errors.New("quest service dependencies are not configured")
+	}
+
+	now := time.Now()
+	current, loc, err := userTimezone(ctx, s.repo, userUUID, now)
+	if err != nil {
+		return nil, err
+	}
+	fromMs := timezoneChangeFrom(current.Timezone, loc, timezone, now).UnixMilli()
+	if err := s.repo.SetUserTimezone(ctx, userUUID, timezone, fromMs, now.UnixMilli()); err != nil {
+		return nil, err
+	}
+	if fromMs <= now.UnixMilli() {
+		return &domain.UserTimezone{Timezone: timezone}, nil
+	}
+	return &domain.UserTimezone{Timezone: current.Timezone, PendingTimezone: timezone, PendingFrom: fromMs}, nil
+}
+
+// OnDomainEvent moves the user's running quests by a settled bet or an activated referral.
+func (s *QuestService) OnDomainEvent(ctx context.Context, event domain.DomainEvent) error {
+	metrics := questMetricsByEvent[event.Type]
+	if len(metrics) == 0 || event.UserUUID == "" {
+		return nil
+	}
+
+	_, quests, err := s.currentQuests(ctx, event.UserUUID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var deltas []domain.QuestProgressDelta
+	for _, quest := range quests {
+		if quest.Status != domain.QuestStatusActive || !containsString(metrics, quest.Metric) {
+			continue
+		}
+		rule := domain.AchievementRule{Metric: quest.Metric, Threshold: quest.NeedSteps, Filters: quest.Filters}
+		if delta, ok := achievementDelta(rule, quest.NeedSteps, event); ok {
+			deltas = append(deltas, domain.QuestProgressDelta{
+				UserQuestID: quest.ID,
+				Increment:   delta.Increment,
+				Streak:      delta.Streak,
+				ResetRun:    delta.ResetRun,
+			})
+		}
+	}
+	if len(deltas) == 0 {
+		return nil
+	}
+	_, err = s.repo.ApplyQuestProgress(ctx, achievementEventKey(event), deltas, time.Now().UnixMilli())
+	return err
+}
+
+// ClaimQuest pays a completed quest's prize value through the reward pipeline
+func (s *QuestService) ClaimQuest(ctx context.Context, userUUID string, questID int) (*domain.QuestClaimResult, error) {
+	if userUUID == "" {
+		return nil, errors.New("user uuid is required")
+	}
+	if questID <= 0 {
+		return nil, errors.New("quest id is required")
+	}
+	if s.repo == nil || s.prizeRepo == nil || s.prizeValueRepo == nil || s.rewards == nil {
+		return nil, errors.New("quest service dependencies are not configured")
+	}
+
+	quest, err := s.repo.GetUserQuestByID(ctx, userUUID, questID)
+	if err != nil {
+		return nil, err
+	}
+	if quest == nil {
+		return nil, errors.New("quest not found")
+	}
+	switch quest.Status {
+	case domain.QuestStatusClaimed:
+		return nil, errors.New("quest already claimed")
+	case domain.QuestStatusActive:
+		return nil, errors.New("quest is not completed yet")
+	}
+
+	prizeValue, err := s.prizeValueRepo.GetPrizeValueByID(ctx, quest.PrizeValueID)
+	if err != nil {
+		return nil, err
+	}
+	if prizeValue == nil {
+		return nil, errors.New("prize value not found")
+	}
+	if err := s.rewards.Validate(ctx, prizeValue); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	claimed, err := s.repo.ClaimUserQuest(ctx, userUUID, questID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, errors.New("quest already claimed")
+	}
+
+	prizeValueStr := prizeValue.Label
+	if prizeValueStr == "" {
+		prizeValueStr = strconv.FormatInt(prizeValue.Value, 10)
+	}
+	eventID := prizeValue.EventID
+	prize := &domain.Prize{
+		EventID:      &eventID,
+		UserID:       &userUUID,
+		PrizeValueID: &prizeValue.ID,
+		PrizeValue:   prizeValueStr,
+		PrizeType:    domain.PrizeTypeQuestReward,
+		AwardedAt:    now,
+		CreatedAt:    now,
+	}
+	if err := s.prizeRepo.CreatePrize(ctx, prize); err != nil {
+		_ = s.repo.UnclaimUserQuest(ctx, questID)
+		return nil, fmt.Errorf("failed to create prize record: %w", err)
+	}
+	reward, err := s.rewards.Grant(ctx, RewardGrant{
+		UserUUID:   userUUID,
+		PrizeValue: prizeValue,
+		Prize:      prize,
+		Source:     "Quest " + quest.TemplateID,
+	})
+	if err != nil {
+		_ = s.repo.UnclaimUserQuest(ctx, questID)
+		return nil, fmt.Errorf("failed to grant quest reward: %w", err)
+	}
+	if err := s.repo.SetUserQuestPrize(ctx, questID, prize.ID); err != nil {
+		return nil, err
+	}
+
+	quest.Status = domain.QuestStatusClaimed
+	quest.ClaimedAt = &now
+	return &domain.QuestClaimResult{Quest: *quest, Prize: prize, Reward: reward}, nil
+}
+
+// currentQuests returns the user's time zone and quests of the periods running at now. A period the user has
+// no quests for yet gets its quests picked from the active templates.
+func (s *QuestService) currentQuests(ctx context.Context, userUUID string, now time.Time) (*domain.UserTimezone, []domain.UserQuest, error) {
+	if s.repo == nil {
+		return nil, nil, errors.New("quest service dependencies are not configured")
+	}
+	timezone, loc, err := userTimezone(ctx, s.repo, userUUID, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	quests, err := s.repo.GetUserQuests(ctx, userUUID, now.UnixMilli())
+	if err != nil {
+		return nil, nil, err
+	}
+	assigned := map[string]bool{}
+	for _, quest := range quests {
+		assigned[quest.Period] = true
+	}
+	if assigned[domain.QuestPeriodDaily] && assigned[domain.QuestPeriodWeekly] {
+		return timezone, quests, nil
+	}
+
+	templates, err := s.repo.GetActiveQuestTemplates(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	var picked []domain.UserQuest
+	counts := map[string]int{domain.QuestPeriodDaily: s.dailyCount, domain.QuestPeriodWeekly: s.weeklyCount}
+	for _, period := range []string{domain.QuestPeriodDaily, domain.QuestPeriodWeekly} {
+		count := counts[period]
+		if assigned[period] || count == 0 {
+			continue
+		}
+		start, end := questPeriod(period, now, loc)
+		for _, template := range pickQuestTemplates(templates, period, userUUID, start, count) {
+			picked = append(picked, domain.UserQuest{
+				UserUUID:    userUUID,
+				TemplateID:  template.ID,
+				NeedSteps:   template.Threshold,
+				PeriodStart: start.UnixMilli(),
+				ResetsAt:    end.UnixMilli(),
+			})
+		}
+	}
+	if len(picked) == 0 {
+		return timezone, quests, nil
+	}
+	if err := s.repo.AssignUserQuests(ctx, picked); err != nil {
+		return nil, nil, err
+	}
+	quests, err = s.repo.GetUserQuests(ctx, userUUID, now.UnixMilli())
+	if err != nil {
+		return nil, nil, err
+	}
+	return timezone, quests, nil
+}
+
+// questPeriod returns the bounds of the daily or weekly period containing now in the time zone; weeks start
+// on Monday.
+func questPeriod(period string, now time.Time, loc *time.Location) (time.Time, time.Time) {
+	local := now.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	if period == domain.QuestPeriodWeekly {
+		start = start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+		return start, start.AddDate(0, 0, 7)
+	}
+	return start, start.AddDate(0, 0, 1)
+}
+
+// userTimezone loads the user's time zone at now, shared by quests and login streaks; an unknown zone falls back
+// to UTC.
+func userTimezone(ctx context.Context, repo data.QuestRepository, userUUID string, now time.Time) (*domain.UserTimezone, *time.Location, error) {
+	timezone, err := repo.GetUserTimezone(ctx, userUUID, now.UnixMilli())
+	if err != nil {
+		return nil, nil, err
+	}
+	if timezone == nil {
+		return nil, nil, errors.New("user not found")
+	}
+	loc, err := time.LoadLocation(timezone.Timezone)
+	if err != nil {
+		timezone.Timezone, loc = "UTC", time.UTC
+	}
+	return timezone, loc, nil
+}
+
+// timezoneChangeFrom returns when a change from the current zone to timezone applies: the next daily reset of the
+// current zone, or now when going back to it (which cancels a pending change).
+func timezoneChangeFrom(current string, loc *time.Location, timezone string, now time.Time) time.Time {
+	if timezone == current {
+		return now
+	}
+	_, tomorrowStart := questPeriod(domain.QuestPeriodDaily, now, loc)
+	return tomorrowStart
+}
+
+// pickQuestTemplates picks count templates of the period for the user. The pick is a stable shuffle seeded by
+// the user and the period start, so concurrent requests pick the same quests.
+func pickQuestTemplates(templates []domain.QuestTemplate, period, userUUID string, start time.Time, count int) []domain.QuestTemplate {
+	var candidates []domain.QuestTemplate
+	for _, template := range templates {
+		if template.Period == period {
+			candidates = append(candidates, template)
+		}
+	}
+	key := func(templateID string) uint64 {
+		h := fnv.New64a()
+		_, _ = fmt.Fprintf(h, "%s|%d|%s", userUUID, start.UnixMilli(), templateID)
+		return h.Sum64()
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return key(candidates[i].ID) < key(candidates[j].ID)
+	})
+	if len(candidates) > count {
+		candidates = candidates[:count]
+	}
+	return candidates
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimezoneChangeFrom(t *testing.T) {
+	westmost := time.FixedZone("UTC-12", -12*60*60)
+	now := time.Date(2026, 4, 6, 22, 0, 0, 0, time.UTC) // 10:00 on April 6 at UTC-12
+
+	tests := []struct {
+		name     string
+		current  string
+		loc      *time.Location
+		timezone string
+		want     time.Time
+	}{
+		{name: "east waits for the current day to end", current: "Etc/GMT+12", loc: westmost, timezone: "Pacific/Kiritimati", want: time.Date(2026, 4, 7, 12, 0, 0, 0, time.UTC)},
+		{name: "west waits for the current day to end", current: "UTC", loc: time.UTC, timezone: "Etc/GMT+12", want: time.Date(2026, 4, 7, 0, 0, 0, 0, time.UTC)},
+		{name: "same zone applies at once", current: "UTC", loc: time.UTC, timezone: "UTC", want: now},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := timezoneChangeFrom(tt.current, tt.loc, tt.timezone, now)
+			if !got.Equal(tt.want) {
+				t.Fatalf("timezoneChangeFrom(%q, %q) = %v, want %v", tt.current, tt.timezone, got.UTC(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math"
+	"pdrest/internal/data"
+	"pdrest/internal/domain"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// maxUserReferralCodes caps self-service campaign codes per user (admins are not capped).
+	maxUserReferralCodes = 20
+	referralCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+var referralCodePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{4,32}$`)
+
+type ReferralService struct {
+	repo          data.ReferralRepository
+	ratingRepo    data.RatingRepository
+	signupBonus   int64
+	levelPercents []float64 // share of invitee winnings per level, index 0 = direct referrer
+	activeWindow  time.Duration
+}
+
+func NewReferralService(repo data.ReferralRepository, ratingRepo data.RatingRepository, signupBonus int64, levelPercents []float64, activeDays int) *ReferralService {
+	if activeDays <= 0 {
+		activeDays = 30
+	}
+	return &ReferralService{
+		repo:          repo,
+		ratingRepo:    ratingRepo,
+		signupBonus:   signupBonus,
+		levelPercents: levelPercents,
+		activeWindow:  time.Duration(activeDays) * 24 * time.Hour,
+	}
+}
+
+// depth is the number of referrer levels that can earn rewards (at least 1 for the signup bonus).
+func (s *ReferralService) depth() int {
+	if len(s.levelPercents) > 0 {
+		return len(s.levelPercents)
+	}
+	return 1
+}
+
+// OnBetClaimed pays referral rewards for a claimed bet: the signup bonus to the direct referrer
+// on the invitee's first completed bet, and for wins a share of the winnings to each referrer level.
+// Payouts are idempotent per invitee/bet/level, so repeated calls are safe.
+func (s *ReferralService) OnBetClaimed(ctx context.Context, bet *domain.Bet, points int64) error {
+	if s.repo == nil || s.ratingRepo == nil {
+		return errors.New("referral service dependencies are not configured")
+	}
+
+	chain, err := s.repo.GetReferrerChain(ctx, bet.UserID, s.depth())
+	if err != nil {
+		return err
+	}
+
+	for i, beneficiaryUUID := range chain {
+		if beneficiaryUUID == bet.UserID {
+			break // referral cycle
+		}
+		level := i + 1
+
+		if level == 1 && s.signupBonus > 0 {
+			if _, err := s.ratingRepo.AddReferralPoints(ctx, &domain.ReferralReward{
+				BeneficiaryUUID: beneficiaryUUID,
+				InviteeUUID:     bet.UserID,
+				Level:           level,
+				RewardType:      domain.ReferralRewardSignup,
+				Points:          s.signupBonus,
+				Description:     "Referral signup bonus: invitee completed first bet",
+			}); err != nil {
+				return err
+			}
+		}
+
+		if points <= 0 || i >= len(s.levelPercents) {
+			continue
+		}
+		share := int64(math.Floor(float64(points) * s.levelPercents[i] / 100))
+		if share <= 0 {
+			continue
+		}
+		betID := bet.ID
+		if _, err := s.ratingRepo.AddReferralPoints(ctx, &domain.ReferralReward{
+			BeneficiaryUUID: beneficiaryUUID,
+			InviteeUUID:     bet.UserID,
+			Level:           level,
+			RewardType:      domain.ReferralRewardWinnings,
+			BetID:           &betID,
+			Points:          share,
+			Description:     fmt.Sprintf("Referral level %d: %.4g%% of bet %d winnings", level, s.levelPercents[i], bet.ID),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *ReferralService) GetReferralStats(ctx context.Context, userUUID string) (*domain.ReferralStatsResponse, error) {
+	if userUUID == "" {
+		return nil, errors.New("user uuid is required")
+	}
+	if s.repo == nil {
+		return nil, errors.New("referral repository is not configured")
+	}
+
+	return s.repo.GetReferralStats(ctx, userUUID, s.depth(), time.Now().UTC().Add(-s.activeWindow))
+}
+
+// CreateReferralCode creates a campaign code owned by ownerUUID. Admin-created codes are not
+// subject to the per-user limit.
+func (s *ReferralService) CreateReferralCode(ctx context.Context, ownerUUID string, req *domain.CreateReferralCodeRequest, byAdmin bool) (*domain.ReferralCode, error) {
+	if ownerUUID == "" {
+		return nil, errors.New("owner uuid is required")
+	}
+	if s.repo == nil {
+		return nil, errors.New("referral repository is not configured")
+	}
+
+	code := strings.TrimSpace(req.Code)
+	if code == "" {
+		generated, err := generateReferralCode()
+		if err != nil {
+			return nil, err
+		}
+		code = generated
+	}
+	if !referralCodePattern.MatchString(code) {
+		return nil, errors.New("code must be 4-32 characters of letters, digits, '_' or '-'")
+	}
+	if req.ExpiresAt != nil && *req.ExpiresAt <= time.Now().UTC().UnixMilli() {
+		return nil, errors.New("expiresAt must be in the future")
+	}
+	if req.MaxUses != nil && *req.MaxUses <= 0 {
+		return nil, errors.New("maxUses must be greater than 0")
+	}
+
+	if !byAdmin {
+		count, err := s.repo.CountReferralCodes(ctx, ownerUUID)
+		if err != nil {
+			return nil, err
+		}
+		if count >= maxUserReferralCodes {
+			return nil, fmt.Errorf("referral code limit of %d reached", maxUserReferralCodes)
+		}
+	}
+
+	referralCode := &domain.ReferralCode{
+		Code:           code,
+		OwnerUUID:      ownerUUID,
+		Name:           strings.TrimSpace(req.Name),
+		ExpiresAt:      req.ExpiresAt,
+		MaxUses:        req.MaxUses,
+		CreatedByAdmin: byAdmin,
+	}
+	if err := s.repo.CreateReferralCode(ctx, referralCode); err != nil {
+		return nil, err
+	}
+	return referralCode, nil
+}
+
+// GetReferralCodeStats lists the owner's main code and campaign codes with signups and conversions.
+func (s *ReferralService) GetReferralCodeStats(ctx context.Context, ownerUUID string) ([]domain.ReferralCodeStats, error) {
+	if ownerUUID == "" {
+		return nil, errors.New("owner uuid is required")
+	}
+	if s.repo == nil {
+		return nil, errors.New("referral repository is not configured")
+	}
+
+	stats, err := s.repo.GetReferralCodeStats(ctx, ownerUUID)
+	if err != nil {
+		return nil, err
+	}
+	if stats == nil {
+		stats = make([]domain.ReferralCodeStats, 0)
+	}
+	return stats, nil
+}
+
+func generateReferralCode() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate referral code: %w", err)
+	}
+	for i := range buf {
+		buf[i] = referralCodeAlphabet[int(buf[i])%len(referralCodeAlphabet)]
+	}
+	return string(buf), nil
+}
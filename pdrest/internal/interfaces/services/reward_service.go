@@ -0,0 +1,305 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pdrest/internal/data"
+	"pdrest/internal/domain"
+)
+
+// RewardGrant is a prize value being paid to a user. Prize is the got_prizes row recording it; Source names
+// what paid it, e.g. "Achievement wins_10".
+type RewardGrant struct {
+	UserUUID   string
+	PrizeValue *domain.PrizeValue
+	Prize      *domain.Prize
+	Source     string
+}
+
+// RewardHandler grants one reward type. Validate checks the prize value's reward params before anything is
+// recorded, so a misconfigured reward fails the claim cleanly.
+type RewardHandler interface {
+	Type() string
+	Validate(ctx context.Context, prizeValue *domain.PrizeValue) error
+	Grant(ctx context.Context, grant RewardGrant) (*domain.GrantedReward, error)
+}
+
+// RewardService pays prize values through the handler of their reward type
+type RewardService struct {
+	handlers map[string]RewardHandler
+}
+
+// NewRewardService creates a reward service with the handlers; a later handler replaces an earlier one of the same type
+func NewRewardService(handlers ...RewardHandler) *RewardService {
+	s := &RewardService{handlers: make(map[string]RewardHandler)}
+	for _, handler := range handlers {
+		s.handlers[handler.Type()] = handler
+	}
+	return s
+}
+
+// Validate checks that the prize value's reward can be granted
+func (s *RewardService) Validate(ctx context.Context, prizeValue *domain.PrizeValue) error {
+	handler, err := s.handler(prizeValue)
+	if err != nil {
+		return err
+	}
+	return handler.Validate(ctx, prizeValue)
+}
+
+// Grant pays the prize value to the user
+func (s *RewardService) Grant(ctx context.Context, grant RewardGrant) (*domain.GrantedReward, error) {
+	handler, err := s.handler(grant.PrizeValue)
+	if err != nil {
+		return nil, err
+	}
+	if err := handler.Validate(ctx, grant.PrizeValue); err != nil {
+		return nil, err
+	}
+	return handler.Grant(ctx, grant)
+}
+
+// handler returns the handler of the prize value's reward type; prize values without a type pay points
+func (s *RewardService) handler(prizeValue *domain.PrizeValue) (RewardHandler, error) {
+	if prizeValue == nil {
+		return nil, errors.New("prize value is required")
+	}
+	rewardType := prizeValue.RewardType
+	if rewardType == "" {
+		rewardType = domain.RewardTypePoints
+	}
+	handler, ok := s.handlers[rewardType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported reward type: %s", rewardType)
+	}
+	return handler, nil
+}
+
+// pointsRewardHandler adds the prize value's points to the user's rating
+type pointsRewardHandler struct {
+	ratingRepo data.RatingRepository
+}
+
+func NewPointsRewardHandler(ratingRepo data.RatingRepository) RewardHandler {
+	return &pointsRewardHandler{ratingRepo: ratingRepo}
+}
+
+func (h *pointsRewardHandler) Type() string { return domain.RewardTypePoints }
+
+func (h *pointsRewardHandler) Validate(ctx context.Context, prizeValue *domain.PrizeValue) error {
+	if h.ratingRepo == nil {
+		return errors.New("rating repository is not configured")
+	}
+	return nil
+}
+
+func (h *pointsRewardHandler) Grant(ctx context.Context, grant RewardGrant) (*domain.GrantedReward, error) {
+	points := grant.PrizeValue.Value
+	var prizeID *int
+	if grant.Prize != nil {
+		prizeID = &grant.Prize.ID
+	}
+	description := fmt.Sprintf("%s: %d points", grant.Source, points)
+	if err := h.ratingRepo.AddPoints(ctx, grant.UserUUID, points, prizeID, nil, description); err != nil {
+		return nil, fmt.Errorf("failed to add reward points: %w", err)
+	}
+	return &domain.GrantedReward{
+		Type:        domain.RewardTypePoints,
+		Description: fmt.Sprintf("%d points", points),
+		Points:      points,
+	}, nil
+}
+
+// spinsRewardHandler adds extra spins on a roulette
+type spinsRewardHandler struct {
+	rouletteRepo data.RouletteRepository
+	rewardRepo   data.RewardRepository
+}
+
+func NewSpinsRewardHandler(rouletteRepo data.RouletteRepository, rewardRepo data.RewardRepository) RewardHandler {
+	return &spinsRewardHandler{rouletteRepo: rouletteRepo, rewardRepo: rewardRepo}
+}
+
+func (h *spinsRewardHandler) Type() string { return domain.RewardTypeSpins }
+
+func (h *spinsRewardHandler) Validate(ctx context.Context, prizeValue *domain.PrizeValue) error {
+	params := prizeValue.RewardParams
+	if params.Spins <= 0 || params.RouletteConfigID <= 0 {
+		return fmt.Errorf("invalid spins reward of prize value %d: rouletteConfigId and spins are required", prizeValue.ID)
+	}
+	config, err := h.rouletteRepo.GetRouletteConfigByID(ctx, params.RouletteConfigID)
+	if err != nil {
+		return fmt.Errorf("failed to get roulette config: %w", err)
+	}
+	if config == nil {
+		return fmt.Errorf("invalid spins reward of prize value %d: roulette config %d does not exist", prizeValue.ID, params.RouletteConfigID)
+	}
+	return nil
+}
+
+func (h *spinsRewardHandler) Grant(ctx context.Context, grant RewardGrant) (*domain.GrantedReward, error) {
+	params := grant.PrizeValue.RewardParams
+	if _, err := h.rewardRepo.AddRouletteSpins(ctx, grant.UserUUID, params.RouletteConfigID, params.Spins); err != nil {
+		return nil, err
+	}
+	return &domain.GrantedReward{
+		Type:             domain.RewardTypeSpins,
+		Description:      fmt.Sprintf("%d extra spins", params.Spins),
+		RouletteConfigID: params.RouletteConfigID,
+		Spins:            params.Spins,
+	}, nil
+}
+
+// boostRewardHandler puts a payout boost in the user's inventory
+type boostRewardHandler struct {
+	inventoryRepo data.InventoryRepository
+}
+
+func NewBoostRewardHandler(inventoryRepo data.InventoryRepository) RewardHandler {
+	return &boostRewardHandler{inventoryRepo: inventoryRepo}
+}
+
+func (h *boostRewardHandler) Type() string { return domain.RewardTypeBoost }
+
+func (h *boostRewardHandler) Validate(ctx context.Context, prizeValue *domain.PrizeValue) error {
+	params := prizeValue.RewardParams
+	if params.Multiplier <= 1 || (params.Bets <= 0 && params.DurationHours <= 0) {
+		return fmt.Errorf("invalid boost reward of prize value %d: multiplier above 1 and bets or durationHours are required", prizeValue.ID)
+	}
+	return nil
+}
+
+func (h *boostRewardHandler) Grant(ctx context.Context, grant RewardGrant) (*domain.GrantedReward, error) {
+	params := grant.PrizeValue.RewardParams
+	item := &domain.InventoryItem{
+		ItemType: domain.InventoryItemPayoutBoost,
+		Metadata: domain.InventoryItemMetadata{Multiplier: params.Multiplier, DurationHours: params.DurationHours},
+	}
+	if params.Bets > 0 {
+		item.Quantity = &params.Bets
+	}
+	return grantInventoryItem(ctx, h.inventoryRepo, grant, domain.RewardTypeBoost, item)
+}
+
+// itemRewardHandler puts any inventory item in the user's inventory
+type itemRewardHandler struct {
+	inventoryRepo data.InventoryRepository
+	rouletteRepo  data.RouletteRepository
+}
+
+func NewItemRewardHandler(inventoryRepo data.InventoryRepository, rouletteRepo data.RouletteRepository) RewardHandler {
+	return &itemRewardHandler{inventoryRepo: inventoryRepo, rouletteRepo: rouletteRepo}
+}
+
+func (h *itemRewardHandler) Type() string { return domain.RewardTypeItem }
+
+func (h *itemRewardHandler) Validate(ctx context.Context, prizeValue *domain.PrizeValue) error {
+	params := prizeValue.RewardParams
+	if params.Quantity <= 0 && params.DurationHours <= 0 {
+		return fmt.Errorf("invalid item reward of prize value %d: quantity or durationHours is required", prizeValue.ID)
+	}
+	switch params.ItemType {
+	case domain.InventoryItemPayoutBoost:
+		if params.Multiplier <= 1 {
+			return fmt.Errorf("invalid item reward of prize value %d: payout_boost requires a multiplier above 1", prizeValue.ID)
+		}
+	case domain.InventoryItemLossShield:
+	case domain.InventoryItemStreakFreeze:
+		if params.Quantity <= 0 {
+			return fmt.Errorf("invalid item reward of prize value %d: streak_freeze requires quantity", prizeValue.ID)
+		}
+	case domain.InventoryItemExtraSpin:
+		if params.Quantity <= 0 || params.RouletteConfigID <= 0 {
+			return fmt.Errorf("invalid item reward of prize value %d: extra_spin requires quantity and rouletteConfigId", prizeValue.ID)
+		}
+		config, err := h.rouletteRepo.GetRouletteConfigByID(ctx, params.RouletteConfigID)
+		if err != nil {
+			return fmt.Errorf("failed to get roulette config: %w", err)
+		}
+		if config == nil {
+			return fmt.Errorf("invalid item reward of prize value %d: roulette config %d does not exist", prizeValue.ID, params.RouletteConfigID)
+		}
+	default:
+		return fmt.Errorf("invalid item reward of prize value %d: unsupported item type %q", prizeValue.ID, params.ItemType)
+	}
+	return nil
+}
+
+func (h *itemRewardHandler) Grant(ctx context.Context, grant RewardGrant) (*domain.GrantedReward, error) {
+	params := grant.PrizeValue.RewardParams
+	item := &domain.InventoryItem{
+		ItemType: params.ItemType,
+		Metadata: domain.InventoryItemMetadata{
+			Multiplier:       params.Multiplier,
+			RouletteConfigID: params.RouletteConfigID,
+			DurationHours:    params.DurationHours,
+		},
+	}
+	if params.Quantity > 0 {
+		item.Quantity = &params.Quantity
+	}
+	return grantInventoryItem(ctx, h.inventoryRepo, grant, domain.RewardTypeItem, item)
+}
+
+// grantInventoryItem adds the item to the grant's user and describes it as the reward
+func grantInventoryItem(ctx context.Context, inventoryRepo data.InventoryRepository, grant RewardGrant, rewardType string, item *domain.InventoryItem) (*domain.GrantedReward, error) {
+	item.UserUUID = grant.UserUUID
+	if grant.Prize != nil {
+		item.GotPrizeID = &grant.Prize.ID
+	}
+	if err := inventoryRepo.AddInventoryItem(ctx, item); err != nil {
+		return nil, err
+	}
+	reward := &domain.GrantedReward{
+		Type:             rewardType,
+		Description:      describeInventoryItem(item),
+		ItemID:           item.ID,
+		ItemType:         item.ItemType,
+		Multiplier:       item.Metadata.Multiplier,
+		RouletteConfigID: item.Metadata.RouletteConfigID,
+	}
+	if item.Quantity != nil {
+		reward.Quantity = *item.Quantity
+	}
+	return reward, nil
+}
+
+// badgeRewardHandler adds a badge or title to the user's profile
+type badgeRewardHandler struct {
+	rewardRepo data.RewardRepository
+}
+
+func NewBadgeRewardHandler(rewardRepo data.RewardRepository) RewardHandler {
+	return &badgeRewardHandler{rewardRepo: rewardRepo}
+}
+
+func (h *badgeRewardHandler) Type() string { return domain.RewardTypeBadge }
+
+func (h *badgeRewardHandler) Validate(ctx context.Context, prizeValue *domain.PrizeValue) error {
+	params := prizeValue.RewardParams
+	if params.BadgeID == "" || params.Title == "" {
+		return fmt.Errorf("invalid badge reward of prize value %d: badgeId and title are required", prizeValue.ID)
+	}
+	return nil
+}
+
+func (h *badgeRewardHandler) Grant(ctx context.Context, grant RewardGrant) (*domain.GrantedReward, error) {
+	params := grant.PrizeValue.RewardParams
+	badge := &domain.UserBadge{BadgeID: params.BadgeID, Title: params.Title, ImageURL: params.ImageURL}
+	if grant.Prize != nil {
+		badge.GotPrizeID = &grant.Prize.ID
+	}
+	awarded, err := h.rewardRepo.AwardBadge(ctx, grant.UserUUID, badge)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.GrantedReward{
+		Type:         domain.RewardTypeBadge,
+		Description:  fmt.Sprintf("Badge: %s", params.Title),
+		BadgeID:      params.BadgeID,
+		Title:        params.Title,
+		ImageURL:     params.ImageURL,
+		AlreadyOwned: !awarded,
+	}, nil
+}
@@ -0,0 +1,365 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"pdrest/internal/domain"
+	"time"
+)
+
+const (
+	rouletteBudgetDayLayout  = "2006-01-02"
+	rouletteSimulationSpins  = 10000
+	rouletteSimulationMaxRun = 1000000
+)
+
+var errRoulettePrizesExhausted = errors.New("all roulette prizes are exhausted")
+
+// randomInt64 returns a uniformly random number in [0, n) from crypto/rand
+func randomInt64(n int64) (int64, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(n))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read random number: %w", err)
+	}
+	return v.Int64(), nil
+}
+
+// drawRoulettePrize draws one of the prizes with probability weight / sum of weights. When minValue is set and
+// a prize worth at least minValue is available, only those are drawn. A drawn prize that is not available falls
+// back to the cheapest available prize, so an exhausted budget never raises the payout.
+func drawRoulettePrize(prizes []domain.PrizeValue, minValue int64, available func(*domain.PrizeValue) bool) (*domain.PrizeValue, error) {
+	var candidates []*domain.PrizeValue
+	for i := range prizes {
+		candidates = append(candidates, &prizes[i])
+	}
+	if minValue > 0 {
+		var guaranteed []*domain.PrizeValue
+		for _, pv := range candidates {
+			if pv.Value >= minValue && available(pv) {
+				guaranteed = append(guaranteed, pv)
+			}
+		}
+		if len(guaranteed) > 0 {
+			candidates = guaranteed
+		}
+	}
+
+	var totalWeight int64
+	for _, pv := range candidates {
+		if pv.Weight > 0 {
+			totalWeight += int64(pv.Weight)
+		}
+	}
+	if totalWeight > 0 {
+		r, err := randomInt64(totalWeight)
+		if err != nil {
+			return nil, err
+		}
+		for _, pv := range candidates {
+			if pv.Weight <= 0 {
+				continue
+			}
+			if r < int64(pv.Weight) {
+				if available(pv) {
+					return pv, nil
+				}
+				break
+			}
+			r -= int64(pv.Weight)
+		}
+	}
+
+	var fallback *domain.PrizeValue
+	for _, pv := range candidates {
+		if available(pv) && (fallback == nil || pv.Value < fallback.Value) {
+			fallback = pv
+		}
+	}
+	if fallback == nil {
+		return nil, errRoulettePrizesExhausted
+	}
+	return fallback, nil
+}
+
+// guaranteedMinValue returns the minimum prize value of the roulette's spin number, 0 when no guarantee applies.
+// bestValue is the best prize of the roulette's earlier spins, if any.
+func guaranteedMinValue(guarantees []domain.RouletteGuarantee, spinNumber int, bestValue int64, hasBest bool) int64 {
+	var minValue int64
+	for _, g := range guarantees {
+		if g.Spin != spinNumber || g.MinValue <= minValue {
+			continue
+		}
+		if hasBest && bestValue >= g.MinValue {
+			continue
+		}
+		minValue = g.MinValue
+	}
+	return minValue
+}
+
+// prizeValueAvailable reports whether a budgeted prize value can still be won given its usage
+func prizeValueAvailable(pv *domain.PrizeValue, usage domain.PrizeValueUsage) bool {
+	if pv.TotalBudget != nil && usage.Total >= *pv.TotalBudget {
+		return false
+	}
+	if pv.DailyBudget != nil && usage.Today >= *pv.DailyBudget {
+		return false
+	}
+	return true
+}
+
+// validateRoulettePrizeCaps checks a prize value's roulette weight and budgets; nil budgets are unlimited
+func validateRoulettePrizeCaps(weight int, totalBudget, dailyBudget *int) error {
+	if weight < 0 {
+		return errors.New("invalid weight: must not be negative")
+	}
+	if totalBudget != nil && *totalBudget <= 0 {
+		return errors.New("invalid totalBudget: must be greater than 0")
+	}
+	if dailyBudget != nil && *dailyBudget <= 0 {
+		return errors.New("invalid dailyBudget: must be greater than 0")
+	}
+	return nil
+}
+
+// validateRouletteGuarantees checks that each guarantee sets a positive minimum for one of the roulette's spins
+func validateRouletteGuarantees(guarantees []domain.RouletteGuarantee, maxSpins int) error {
+	for _, g := range guarantees {
+		if g.Spin <= 0 || g.MinValue <= 0 {
+			return errors.New("invalid guarantee: spin and minValue must be positive")
+		}
+		if g.Spin > maxSpins {
+			return fmt.Errorf("invalid guarantee: spin %d is past the roulette's %d spins", g.Spin, maxSpins)
+		}
+	}
+	return nil
+}
+
+// spinResultInt64 reads a number stored in a roulette's spin_result, which is float64 once read back from JSONB
+func spinResultInt64(result map[string]interface{}, key string) (int64, bool) {
+	switch v := result[key].(type) {
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	case float64:
+		return int64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// spinReservation returns the budget reservation of the roulette's current prize, if it holds one
+func spinReservation(result map[string]interface{}) (int, string, bool) {
+	day, ok := result["budget_day"].(string)
+	if !ok || day == "" {
+		return 0, "", false
+	}
+	prizeValueID, ok := spinResultInt64(result, "prize_value_id")
+	if !ok {
+		return 0, "", false
+	}
+	return int(prizeValueID), day, true
+}
+
+// releaseSpinReservation gives back a budget reservation; a failure is logged and the win stays counted
+func (s *RouletteService) releaseSpinReservation(ctx context.Context, rouletteID int, prizeValueID int, day string) {
+	if err := s.prizeValueRepo.ReleasePrizeValue(ctx, prizeValueID, day); err != nil {
+		log.Printf("roulette: failed to release prize value %d of roulette %d: %v", prizeValueID, rouletteID, err)
+	}
+}
+
+// drawSpinPrize draws the prize of the roulette's current spin. A budgeted prize is reserved for this spin, and
+// one whose budget ran out concurrently is excluded and drawn again. The previous spin's reservation is kept:
+// the caller releases it once the new prize is saved, or releases the new reservation when saving fails.
+func (s *RouletteService) drawSpinPrize(ctx context.Context, config *domain.RouletteConfig, roulette *domain.Roulette, prizeValues []domain.PrizeValue) (*domain.PrizeValue, error) {
+	delete(roulette.SpinResult, "budget_day")
+
+	day := time.Now().UTC().Format(rouletteBudgetDayLayout)
+	var budgetedIDs []int
+	for _, pv := range prizeValues {
+		if pv.Budgeted() {
+			budgetedIDs = append(budgetedIDs, pv.ID)
+		}
+	}
+	usage, err := s.prizeValueRepo.GetPrizeValueUsage(ctx, budgetedIDs, day)
+	if err != nil {
+		return nil, err
+	}
+	exhausted := make(map[int]bool)
+	available := func(pv *domain.PrizeValue) bool {
+		return !exhausted[pv.ID] && prizeValueAvailable(pv, usage[pv.ID])
+	}
+
+	bestValue, hasBest := spinResultInt64(roulette.SpinResult, "best_value")
+	minValue := guaranteedMinValue(config.Guarantees, roulette.SpinNumber, bestValue, hasBest)
+
+	for range prizeValues {
+		pv, err := drawRoulettePrize(prizeValues, minValue, available)
+		if err != nil {
+			return nil, err
+		}
+		if !pv.Budgeted() {
+			return pv, nil
+		}
+		reserved, err := s.prizeValueRepo.ReservePrizeValue(ctx, pv.ID, day)
+		if err != nil {
+			return nil, err
+		}
+		if reserved {
+			roulette.SpinResult["budget_day"] = day
+			return pv, nil
+		}
+		exhausted[pv.ID] = true
+	}
+	return nil, errRoulettePrizesExhausted
+}
+
+// SimulateRoulette simulates spins of a roulette configuration and reports the payout per spin. Prizes and
+// guarantees default to the roulette config's; budgets start unused.
+func (s *RouletteService) SimulateRoulette(ctx context.Context, req *domain.RouletteSimulationRequest) (*domain.RouletteSimulationResult, error) {
+	prizes := req.Prizes
+	guarantees := req.Guarantees
+	maxSpins := req.MaxSpins
+	if len(prizes) == 0 {
+		if req.RouletteConfigID <= 0 {
+			return nil, errors.New("rouletteConfigId or prizes is required")
+		}
+		config, err := s.repo.GetRouletteConfigByID(ctx, req.RouletteConfigID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get roulette config: %w", err)
+		}
+		if config == nil {
+			return nil, errors.New("roulette config not found")
+		}
+		if s.prizeValueRepo == nil {
+			return nil, errors.New("prize value repository is not initialized")
+		}
+		prizeValues, err := s.prizeValueRepo.GetPrizeValuesByEventID(ctx, config.EventID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get prize values: %w", err)
+		}
+		for _, pv := range prizeValues {
+			prizes = append(prizes, domain.RouletteSimulationPrize{
+				PrizeValueID: pv.ID,
+				Label:        pv.Label,
+				Value:        pv.Value,
+				Weight:       pv.Weight,
+				TotalBudget:  pv.TotalBudget,
+				DailyBudget:  pv.DailyBudget,
+			})
+		}
+		if guarantees == nil {
+			guarantees = config.Guarantees
+		}
+		if maxSpins <= 0 {
+			maxSpins = config.MaxSpins
+		}
+	}
+	if len(prizes) == 0 {
+		return nil, errors.New("no prize values configured for the roulette")
+	}
+	if maxSpins <= 0 {
+		maxSpins = 1
+	}
+	spins := req.Spins
+	if spins <= 0 {
+		spins = rouletteSimulationSpins
+	}
+	if spins > rouletteSimulationMaxRun {
+		return nil, fmt.Errorf("invalid spins: at most %d spins can be simulated", rouletteSimulationMaxRun)
+	}
+	spinsPerDay := req.SpinsPerDay
+	if spinsPerDay <= 0 || spinsPerDay > spins {
+		spinsPerDay = spins
+	}
+	if err := validateRouletteGuarantees(guarantees, maxSpins); err != nil {
+		return nil, err
+	}
+
+	// Simulated prizes are indexed by position, so prizes without a prize value id can be simulated too
+	prizeValues := make([]domain.PrizeValue, len(prizes))
+	result := &domain.RouletteSimulationResult{
+		RouletteConfigID: req.RouletteConfigID,
+		Spins:            spins,
+		MaxSpins:         maxSpins,
+		SpinsPerDay:      spinsPerDay,
+		Guarantees:       guarantees,
+		Prizes:           make([]domain.RouletteSimulationPrizeResult, len(prizes)),
+	}
+	if result.Guarantees == nil {
+		result.Guarantees = []domain.RouletteGuarantee{}
+	}
+	var totalWeight int64
+	for i, prize := range prizes {
+		if err := validateRoulettePrizeCaps(prize.Weight, prize.TotalBudget, prize.DailyBudget); err != nil {
+			return nil, fmt.Errorf("prize %d: %w", i, err)
+		}
+		prizeValues[i] = domain.PrizeValue{
+			ID:          i,
+			Value:       prize.Value,
+			Label:       prize.Label,
+			Weight:      prize.Weight,
+			TotalBudget: prize.TotalBudget,
+			DailyBudget: prize.DailyBudget,
+		}
+		result.Prizes[i].RouletteSimulationPrize = prize
+		totalWeight += int64(prize.Weight)
+	}
+	if totalWeight > 0 {
+		var weighted float64
+		for i, prize := range prizes {
+			result.Prizes[i].Probability = float64(prize.Weight) / float64(totalWeight)
+			weighted += float64(prize.Weight) * float64(prize.Value)
+		}
+		result.ExpectedPayoutPerSpin = weighted / float64(totalWeight)
+	}
+
+	usage := make([]domain.PrizeValueUsage, len(prizes))
+	available := func(pv *domain.PrizeValue) bool {
+		return prizeValueAvailable(pv, usage[pv.ID])
+	}
+	var bestValue int64
+	for spin := 0; spin < spins; spin++ {
+		if spin%spinsPerDay == 0 {
+			for i := range usage {
+				usage[i].Today = 0
+			}
+		}
+		spinNumber := spin%maxSpins + 1
+		if spinNumber == 1 {
+			bestValue = 0
+		}
+		minValue := guaranteedMinValue(guarantees, spinNumber, bestValue, spinNumber > 1)
+
+		pv, err := drawRoulettePrize(prizeValues, minValue, available)
+		if errors.Is(err, errRoulettePrizesExhausted) {
+			result.NoPrizeSpins++
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		usage[pv.ID].Total++
+		usage[pv.ID].Today++
+		result.TotalPayout += pv.Value
+		if pv.Value > bestValue {
+			bestValue = pv.Value
+		}
+
+		prizeResult := &result.Prizes[pv.ID]
+		prizeResult.Hits++
+		if pv.TotalBudget != nil && usage[pv.ID].Total == *pv.TotalBudget {
+			prizeResult.ExhaustedAtSpin = spin + 1
+		}
+	}
+	result.SimulatedPayoutPerSpin = float64(result.TotalPayout) / float64(spins)
+	for i := range result.Prizes {
+		result.Prizes[i].HitRate = float64(result.Prizes[i].Hits) / float64(spins)
+	}
+	return result, nil
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"errors"
+	"pdrest/internal/domain"
+	"testing"
+)
+
+func TestDrawRoulettePrize(t *testing.T) {
+	prizes := func(weights ...int) []domain.PrizeValue {
+		values := []int64{1, 10, 100}
+		result := make([]domain.PrizeValue, len(weights))
+		for i, weight := range weights {
+			result[i] = domain.PrizeValue{ID: i + 1, Value: values[i], Weight: weight}
+		}
+		return result
+	}
+	allAvailable := func(*domain.PrizeValue) bool { return true }
+	unavailable := func(ids ...int) func(*domain.PrizeValue) bool {
+		return func(pv *domain.PrizeValue) bool {
+			for _, id := range ids {
+				if pv.ID == id {
+					return false
+				}
+			}
+			return true
+		}
+	}
+
+	tests := []struct {
+		name      string
+		prizes    []domain.PrizeValue
+		minValue  int64
+		available func(*domain.PrizeValue) bool
+		wantID    int
+		wantErr   error
+	}{
+		{name: "only weighted prize", prizes: prizes(0, 5, 0), available: allAvailable, wantID: 2},
+		{name: "no weights falls back to the cheapest", prizes: prizes(0, 0, 0), available: allAvailable, wantID: 1},
+		{name: "drawn prize exhausted falls back to the cheapest available", prizes: prizes(0, 0, 5), available: unavailable(3), wantID: 1},
+		{name: "fallback skips exhausted prizes", prizes: prizes(0, 0, 5), available: unavailable(1, 3), wantID: 2},
+		{name: "all exhausted", prizes: prizes(1, 1, 1), available: unavailable(1, 2, 3), wantErr: errRoulettePrizesExhausted},
+		{name: "minimum value filters cheaper prizes", prizes: prizes(5, 0, 1), minValue: 10, available: allAvailable, wantID: 3},
+		{name: "minimum value falls back within the guaranteed prizes", prizes: prizes(5, 0, 0), minValue: 10, available: allAvailable, wantID: 2},
+		{name: "minimum value without available prizes is dropped", prizes: prizes(5, 0, 0), minValue: 10, available: unavailable(2, 3), wantID: 1},
+		{name: "no prizes", available: allAvailable, wantErr: errRoulettePrizesExhausted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := drawRoulettePrize(tt.prizes, tt.minValue, tt.available)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("drawRoulettePrize() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("drawRoulettePrize() failed: %v", err)
+			}
+			if got.ID != tt.wantID {
+				t.Fatalf("drawRoulettePrize() = prize %d, want %d", got.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestDrawRoulettePrizeOnlyLandsWeightedPrizes(t *testing.T) {
+	prizes := []domain.PrizeValue{
+		{ID: 1, Value: 1, Weight: 3},
+		{ID: 2, Value: 10, Weight: 0},
+		{ID: 3, Value: 100, Weight: 1},
+	}
+	for i := 0; i < 200; i++ {
+		got, err := drawRoulettePrize(prizes, 0, func(*domain.PrizeValue) bool { return true })
+		if err != nil {
+			t.Fatalf("drawRoulettePrize() failed: %v", err)
+		}
+		if got.ID == 2 {
+			t.Fatalf("drawRoulettePrize() landed a prize of weight 0")
+		}
+	}
+}
+
+func TestGuaranteedMinValue(t *testing.T) {
+	guarantees := []domain.RouletteGuarantee{
+		{Spin: 2, MinValue: 10},
+		{Spin: 3, MinValue: 10},
+		{Spin: 3, MinValue: 50},
+	}
+
+	tests := []struct {
+		name       string
+		spinNumber int
+		bestValue  int64
+		hasBest    bool
+		want       int64
+	}{
+		{name: "spin without a guarantee", spinNumber: 1, want: 0},
+		{name: "guaranteed spin", spinNumber: 2, bestValue: 1, hasBest: true, want: 10},
+		{name: "guarantee already met by an earlier spin", spinNumber: 2, bestValue: 10, hasBest: true, want: 0},
+		{name: "highest guarantee of the spin", spinNumber: 3, bestValue: 1, hasBest: true, want: 50},
+		{name: "earlier spin meets only the lower guarantee", spinNumber: 3, bestValue: 20, hasBest: true, want: 50},
+		{name: "earlier spin meets every guarantee", spinNumber: 3, bestValue: 50, hasBest: true, want: 0},
+		{name: "no earlier spins", spinNumber: 3, want: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := guaranteedMinValue(guarantees, tt.spinNumber, tt.bestValue, tt.hasBest)
+			if got != tt.want {
+				t.Fatalf("guaranteedMinValue(spin %d, best %d) = %d, want %d", tt.spinNumber, tt.bestValue, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrizeValueAvailable(t *testing.T) {
+	budget := func(n int) *int { return &n }
+
+	tests := []struct {
+		name  string
+		pv    domain.PrizeValue
+		usage domain.PrizeValueUsage
+		want  bool
+	}{
+		{name: "unlimited", pv: domain.PrizeValue{}, usage: domain.PrizeValueUsage{Total: 1000, Today: 100}, want: true},
+		{name: "under the total budget", pv: domain.PrizeValue{TotalBudget: budget(5)}, usage: domain.PrizeValueUsage{Total: 4}, want: true},
+		{name: "total budget used up", pv: domain.PrizeValue{TotalBudget: budget(5)}, usage: domain.PrizeValueUsage{Total: 5}, want: false},
+		{name: "under the daily budget", pv: domain.PrizeValue{DailyBudget: budget(2)}, usage: domain.PrizeValueUsage{Total: 10, Today: 1}, want: true},
+		{name: "daily budget used up", pv: domain.PrizeValue{DailyBudget: budget(2)}, usage: domain.PrizeValueUsage{Total: 10, Today: 2}, want: false},
+		{name: "daily budget left but total used up", pv: domain.PrizeValue{TotalBudget: budget(10), DailyBudget: budget(2)}, usage: domain.PrizeValueUsage{Total: 10, Today: 0}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := prizeValueAvailable(&tt.pv, tt.usage); got != tt.want {
+				t.Fatalf("prizeValueAvailable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
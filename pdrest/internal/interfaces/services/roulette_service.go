@@ -2,12 +2,12 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"log"
-	"math/rand"
 	"pdrest/internal/data"
 	"pdrest/internal/domain"
 	"strconv"
@@ -22,6 +22,8 @@ type RouletteService struct {
 	prizeValueRepo data.PrizeValueRepository
 	eventRepo      data.EventRepository
 	ratingRepo     data.RatingRepository
+	rewardRepo     data.RewardRepository
+	xpRepo         data.XPRepository
 }
 
 type ContextKey string
@@ -32,7 +34,7 @@ const (
 	ContextKeyIPAddress  ContextKey = "ip_address"
 )
 
-func NewRouletteService(r data.RouletteRepository, userRepo data.UserRepository, prizeRepo data.PrizeRepository, prizeValueRepo data.PrizeValueRepository, eventRepo data.EventRepository, ratingRepo data.RatingRepository) *RouletteService {
+func NewRouletteService(r data.RouletteRepository, userRepo data.UserRepository, prizeRepo data.PrizeRepository, prizeValueRepo data.PrizeValueRepository, eventRepo data.EventRepository, ratingRepo data.RatingRepository, rewardRepo data.RewardRepository, xpRepo data.XPRepository) *RouletteService {
 	return &RouletteService{
 		repo:           r,
 		userRepo:       userRepo,
@@ -40,9 +42,48 @@ func NewRouletteService(r data.RouletteRepository, userRepo data.UserRepository,
 		prizeValueRepo: prizeValueRepo,
 		eventRepo:      eventRepo,
 		ratingRepo:     ratingRepo,
+		rewardRepo:     rewardRepo,
+		xpRepo:         xpRepo,
 	}
 }
 
+// spinLimit is the roulette's max spins plus the extra spins rewards granted the user and their level's extra spins
+func (s *RouletteService) spinLimit(ctx context.Context, config *domain.RouletteConfig, userUUID *string) int {
+	limit := config.MaxSpins
+	if userUUID == nil || *userUUID == "" {
+		return limit
+	}
+	if s.rewardRepo != nil {
+		extra, err := s.rewardRepo.GetRouletteExtraSpins(ctx, *userUUID, config.ID)
+		if err != nil {
+			log.Printf("roulette/service: failed to load extra spins user_uuid=%s config_id=%d: %v", *userUUID, config.ID, err)
+		} else {
+			limit += extra
+		}
+	}
+	if s.xpRepo != nil {
+		level, err := s.xpRepo.GetUserLevel(ctx, *userUUID)
+		if err != nil {
+			log.Printf("roulette/service: failed to load level user_uuid=%s: %v", *userUUID, err)
+		} else {
+			limit += level.Perks.ExtraSpins
+		}
+	}
+	return limit
+}
+
+// OpenEventRoulettes activates the event's during_event roulettes; it is the lifecycle hook run when an event starts.
+func (s *RouletteService) OpenEventRoulettes(ctx context.Context, event *domain.Event) error {
+	_, err := s.repo.SetEventRoulettesActive(ctx, event.ID, true)
+	return err
+}
+
+// CloseEventRoulettes deactivates the event's during_event roulettes once the event is finished or archived.
+func (s *RouletteService) CloseEventRoulettes(ctx context.Context, event *domain.Event) error {
+	_, err := s.repo.SetEventRoulettesActive(ctx, event.ID, false)
+	return err
+}
+
 // GetRouletteStatus gets the current status of roulette by preauth token
 // Allows used tokens (after spin) but checks expiration
 func (s *RouletteService) GetRouletteStatus(ctx context.Context, preauthToken string) (*domain.GetRouletteStatusResponse, error) {
@@ -85,6 +126,7 @@ func (s *RouletteService) GetRouletteStatus(ctx context.Context, preauthToken st
 		return nil, fmt.Errorf("failed to get roulette: %w", err)
 	}
 
+	maxSpins := s.spinLimit(ctx, config, token.UserUUID)
 	response := &domain.GetRouletteStatusResponse{
 		Config:     config,
 		CanSpin:    false,
@@ -93,7 +135,7 @@ func (s *RouletteService) GetRouletteStatus(ctx context.Context, preauthToken st
 
 	if roulette == nil {
 		// User hasn't started yet
-		response.RemainingSpins = config.MaxSpins
+		response.RemainingSpins = maxSpins
 		response.CanSpin = true
 		log.Printf("roulette/service: GetRouletteStatus success token_id=%d state=new remaining=%d", token.ID, response.RemainingSpins)
 		return response, nil
@@ -108,7 +150,7 @@ func (s *RouletteService) GetRouletteStatus(ctx context.Context, preauthToken st
 		response.CanSpin = false
 	} else {
 		// Calculate remaining spins
-		response.RemainingSpins = config.MaxSpins - roulette.SpinNumber
+		response.RemainingSpins = maxSpins - roulette.SpinNumber
 		response.CanSpin = response.RemainingSpins > 0
 	}
 	log.Printf("roulette/service: GetRouletteStatus success token_id=%d spin_number=%d prize_taken=%t remaining=%d", token.ID, roulette.SpinNumber, roulette.PrizeTaken, response.RemainingSpins)
@@ -163,6 +205,7 @@ func (s *RouletteService) GetRouletteStatusByUser(ctx context.Context, userUUID
 		return nil, fmt.Errorf("failed to get roulette: %w", err)
 	}
 
+	maxSpins := s.spinLimit(ctx, config, &userUUID)
 	response := &domain.GetRouletteStatusResponse{
 		Config:     config,
 		CanSpin:    false,
@@ -171,7 +214,7 @@ func (s *RouletteService) GetRouletteStatusByUser(ctx context.Context, userUUID
 
 	if roulette == nil {
 		// User hasn't started yet
-		response.RemainingSpins = config.MaxSpins
+		response.RemainingSpins = maxSpins
 		response.CanSpin = true
 		log.Printf("roulette/service: GetRouletteStatusByUser success user_uuid=%s state=new remaining=%d", userUUID, response.RemainingSpins)
 		return response, nil
@@ -186,7 +229,7 @@ func (s *RouletteService) GetRouletteStatusByUser(ctx context.Context, userUUID
 		response.CanSpin = false
 	} else {
 		// Calculate remaining spins
-		response.RemainingSpins = config.MaxSpins - roulette.SpinNumber
+		response.RemainingSpins = maxSpins - roulette.SpinNumber
 		response.CanSpin = response.RemainingSpins > 0
 	}
 	log.Printf("roulette/service: GetRouletteStatusByUser success user_uuid=%s spin_number=%d prize_taken=%t remaining=%d", userUUID, roulette.SpinNumber, roulette.PrizeTaken, response.RemainingSpins)
@@ -202,6 +245,65 @@ func (s *RouletteService) GetRouletteConfigByID(ctx context.Context, id int) (*d
 	return s.repo.GetRouletteConfigByID(ctx, id)
 }
 
+// CreateRouletteConfig creates a roulette config with its guarantees
+func (s *RouletteService) CreateRouletteConfig(ctx context.Context, req *domain.RouletteConfigRequest) (*domain.RouletteConfig, error) {
+	config := &domain.RouletteConfig{IsActive: true}
+	if err := applyRouletteConfigRequest(config, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateRouletteConfig(ctx, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// UpdateRouletteConfig replaces a roulette config's fields
+func (s *RouletteService) UpdateRouletteConfig(ctx context.Context, id int, req *domain.RouletteConfigRequest) (*domain.RouletteConfig, error) {
+	config, err := s.GetRouletteConfigByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return nil, errors.New("roulette config not found")
+	}
+	if err := applyRouletteConfigRequest(config, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateRouletteConfig(ctx, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// applyRouletteConfigRequest validates the request and copies it onto the config
+func applyRouletteConfigRequest(config *domain.RouletteConfig, req *domain.RouletteConfigRequest) error {
+	if req.Type != domain.RouletteTypeOnStart && req.Type != domain.RouletteTypeDuringEvent {
+		return errors.New("invalid type: must be on_start or during_event")
+	}
+	if req.EventID == "" {
+		return errors.New("eventId is required")
+	}
+	if req.MaxSpins <= 0 {
+		return errors.New("invalid maxSpins: must be greater than 0")
+	}
+	guarantees := config.Guarantees
+	if req.Guarantees != nil {
+		guarantees = req.Guarantees
+	}
+	if err := validateRouletteGuarantees(guarantees, req.MaxSpins); err != nil {
+		return err
+	}
+
+	config.Type = req.Type
+	config.EventID = req.EventID
+	config.MaxSpins = req.MaxSpins
+	config.Guarantees = guarantees
+	if req.IsActive != nil {
+		config.IsActive = *req.IsActive
+	}
+	return nil
+}
+
 // Spin performs a spin using preauth token
 func (s *RouletteService) Spin(ctx context.Context, preauthTokenStr string, req *domain.SpinRequest) (*domain.SpinResponse, error) {
 	var preauthToken *domain.RoulettePreauthToken
@@ -306,8 +408,9 @@ func (s *RouletteService) Spin(ctx context.Context, preauthTokenStr string, req
 	}
 
 	// Check if user has remaining spins
+	maxSpins := s.spinLimit(ctx, config, preauthToken.UserUUID)
 	if roulette != nil {
-		if roulette.SpinNumber >= config.MaxSpins {
+		if roulette.SpinNumber >= maxSpins {
 			return nil, errors.New("maximum spins reached")
 		}
 	}
@@ -355,14 +458,20 @@ func (s *RouletteService) Spin(ctx context.Context, preauthTokenStr string, req
 		return nil, fmt.Errorf("no prize values configured for event: %s", eventID)
 	}
 
-	// Randomly select one prize value
-	rand.Seed(time.Now().UnixNano())
-	selectedPrizeValue := &prizeValues[rand.Intn(len(prizeValues))]
-
-	// Store selected prize in spin_result
+	// Draw a prize value by weight, within budgets and guarantees
 	if roulette.SpinResult == nil {
 		roulette.SpinResult = make(map[string]interface{})
 	}
+	previousID, previousDay, hadReservation := spinReservation(roulette.SpinResult)
+	selectedPrizeValue, err := s.drawSpinPrize(ctx, config, roulette, prizeValues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to draw prize: %w", err)
+	}
+
+	// Store selected prize in spin_result
+	if best, ok := spinResultInt64(roulette.SpinResult, "best_value"); !ok || selectedPrizeValue.Value > best {
+		roulette.SpinResult["best_value"] = selectedPrizeValue.Value
+	}
 	roulette.SpinResult["prize_value_id"] = selectedPrizeValue.ID
 	roulette.SpinResult["prize_value"] = selectedPrizeValue.Value // Now int64 (points)
 	roulette.SpinResult["prize_label"] = selectedPrizeValue.Label
@@ -375,13 +484,20 @@ func (s *RouletteService) Spin(ctx context.Context, preauthTokenStr string, req
 	prizeValueStr := fmt.Sprintf("%d", selectedPrizeValue.Value)
 	roulette.Prize = &prizeValueStr
 
-	// Update roulette with selected prize
+	// Update roulette with selected prize; the budget reservation moves from the previous prize to the new one
+	// only once it is saved
 	if err := s.repo.UpdateRoulette(ctx, roulette); err != nil {
+		if day, ok := roulette.SpinResult["budget_day"].(string); ok {
+			s.releaseSpinReservation(ctx, roulette.ID, selectedPrizeValue.ID, day)
+		}
 		return nil, fmt.Errorf("failed to update roulette with prize: %w", err)
 	}
+	if hadReservation {
+		s.releaseSpinReservation(ctx, roulette.ID, previousID, previousDay)
+	}
 
 	// Calculate remaining spins
-	remainingSpins := config.MaxSpins - roulette.SpinNumber
+	remainingSpins := maxSpins - roulette.SpinNumber
 	if remainingSpins < 0 {
 		remainingSpins = 0
 	}
@@ -947,7 +1063,9 @@ func generateTokenFromSessionAndIP(sessionID, ipAddress string) string {
 
 // generateTokenFromUserUUID generates a random-ish token tied to a user and roulette config.
 func generateTokenFromUserUUID(userUUID string, rouletteConfigID int) string {
-	data := fmt.Sprintf("%s:%d:%d:%d", userUUID, rouletteConfigID, time.Now().UnixNano(), rand.Int63())
+	nonce := make([]byte, 16)
+	_, _ = rand.Read(nonce)
+	data := fmt.Sprintf("%s:%d:%d:%x", userUUID, rouletteConfigID, time.Now().UnixNano(), nonce)
 	hash := sha256.Sum256([]byte(data))
 	return hex.EncodeToString(hash[:])
 }
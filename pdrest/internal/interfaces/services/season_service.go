@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"pdrest/internal/data"
+	"pdrest/internal/domain"
+	"strconv"
+	"time"
+)
+
+type SeasonService struct {
+	repo           data.SeasonRepository
+	prizeValueRepo data.PrizeValueRepository
+	firstStart     time.Time
+	length         time.Duration
+}
+
+// NewSeasonService creates a season service. firstStart anchors the first season
+// when none exists yet; every following season starts where the previous one ended.
+func NewSeasonService(repo data.SeasonRepository, prizeValueRepo data.PrizeValueRepository, firstStart time.Time, length time.Duration) *SeasonService {
+	return &SeasonService{
+		repo:           repo,
+		prizeValueRepo: prizeValueRepo,
+		firstStart:     firstStart.UTC(),
+		length:         length,
+	}
+}
+
+func (s *SeasonService) GetCurrentSeason(ctx context.Context, limit, offset int) (*domain.CurrentSeasonResponse, error) {
+	if s.repo == nil {
+		return nil, errors.New("season repository is not configured")
+	}
+
+	limit, offset = normalizeSeasonPage(limit, offset)
+
+	season, err := s.repo.GetActiveSeason(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if season == nil {
+		return nil, errors.New("active season not found")
+	}
+
+	standings, err := s.repo.GetSeasonStandings(ctx, season.StartTime.UnixMilli(), season.EndTime.UnixMilli(), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if standings == nil {
+		standings = make([]domain.SeasonStandingEntry, 0)
+	}
+
+	return &domain.CurrentSeasonResponse{
+		Season:    *season,
+		Standings: standings,
+	}, nil
+}
+
+func (s *SeasonService) GetFinishedSeasons(ctx context.Context, limit, offset int) ([]domain.Season, error) {
+	if s.repo == nil {
+		return nil, errors.New("season repository is not configured")
+	}
+
+	limit, offset = normalizeSeasonPage(limit, offset)
+
+	seasons, err := s.repo.GetFinishedSeasons(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if seasons == nil {
+		seasons = make([]domain.Season, 0)
+	}
+	return seasons, nil
+}
+
+func (s *SeasonService) GetSeasonResults(ctx context.Context, seasonID int, limit, offset int) (*domain.SeasonResultsResponse, error) {
+	if seasonID <= 0 {
+		return nil, errors.New("season id is required")
+	}
+	if s.repo == nil {
+		return nil, errors.New("season repository is not configured")
+	}
+
+	limit, offset = normalizeSeasonPage(limit, offset)
+
+	season, err := s.repo.GetSeasonByID(ctx, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	if season == nil {
+		return nil, errors.New("season not found")
+	}
+	if season.Status != domain.SeasonStatusFinished {
+		return nil, errors.New("season is not finished yet")
+	}
+
+	results, err := s.repo.GetSeasonResults(ctx, seasonID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = make([]domain.SeasonStandingEntry, 0)
+	}
+
+	return &domain.SeasonResultsResponse{
+		Season:  *season,
+		Results: results,
+	}, nil
+}
+
+// CreateSeason schedules a season with an explicit window (admin).
+// The new season must not overlap the currently active one.
+func (s *SeasonService) CreateSeason(ctx context.Context, title string, start, end time.Time) (*domain.Season, error) {
+	if s.repo == nil {
+		return nil, errors.New("season repository is not configured")
+	}
+	if start.IsZero() || end.IsZero() {
+		return nil, errors.New("season start and end are required")
+	}
+	if !start.Before(end) {
+		return nil, errors.New("season start must be before end")
+	}
+
+	active, err := s.repo.GetActiveSeason(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil && start.Before(active.EndTime) {
+		return nil, errors.New("season overlaps the active season")
+	}
+
+	if title == "" {
+		title = seasonTitle(start)
+	}
+	season := &domain.Season{
+		Title:     title,
+		StartTime: start.UTC(),
+		EndTime:   end.UTC(),
+	}
+	created, err := s.repo.CreateSeason(ctx, season)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, errors.New("season already exists")
+	}
+	return season, nil
+}
+
+// Rollover finishes the active season once it has ended, archives its standings,
+// opens the next season and pays the season-end prizes still owed. Safe to run on every
+// replica: season starts are unique and every prize is paid at most once per result.
+func (s *SeasonService) Rollover(ctx context.Context, now time.Time) error {
+	if s.repo == nil {
+		return errors.New("season repository is not configured")
+	}
+	if s.length <= 0 {
+		return errors.New("season length must be greater than 0")
+	}
+	if err := s.rolloverSeason(ctx, now.UTC()); err != nil {
+		return err
+	}
+	// Prizes are paid per result, so a run that failed partway is resumed by the next one
+	return s.awardSeasonPrizes(ctx)
+}
+
+func (s *SeasonService) rolloverSeason(ctx context.Context, now time.Time) error {
+	active, err := s.repo.GetActiveSeason(ctx)
+	if err != nil {
+		return err
+	}
+	if active == nil {
+		return s.openSeason(ctx, s.firstStart, now)
+	}
+	if now.Before(active.EndTime) {
+		return nil
+	}
+
+	finished, err := s.repo.FinishSeason(ctx, active.ID, now.UnixMilli())
+	if err != nil {
+		return err
+	}
+	if finished {
+		log.Printf("season: finished season %d (%s)", active.ID, active.Title)
+	}
+
+	return s.openSeason(ctx, active.EndTime, now)
+}
+
+// openSeason creates the season window containing now, stepping from anchor by the season length.
+func (s *SeasonService) openSeason(ctx context.Context, anchor time.Time, now time.Time) error {
+	start := anchor
+	for !now.Before(start.Add(s.length)) {
+		start = start.Add(s.length)
+	}
+	season := &domain.Season{
+		Title:     seasonTitle(start),
+		StartTime: start,
+		EndTime:   start.Add(s.length),
+	}
+	created, err := s.repo.CreateSeason(ctx, season)
+	if err != nil {
+		return err
+	}
+	if created {
+		log.Printf("season: opened season %d (%s - %s)", season.ID, season.StartTime.Format(time.RFC3339), season.EndTime.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// awardSeasonPrizes pays every finished season's results that are due a prize and don't have one yet.
+func (s *SeasonService) awardSeasonPrizes(ctx context.Context) error {
+	if s.prizeValueRepo == nil {
+		return errors.New("season service dependencies are not configured")
+	}
+
+	unpaid, err := s.repo.GetUnpaidSeasonResults(ctx)
+	if err != nil {
+		return err
+	}
+
+	eventID := domain.SeasonEventID
+	prizeValues := make(map[int]*domain.PrizeValue)
+	for _, result := range unpaid {
+		prizeValue, ok := prizeValues[result.PrizeValueID]
+		if !ok {
+			prizeValue, err = s.prizeValueRepo.GetPrizeValueByID(ctx, result.PrizeValueID)
+			if err != nil {
+				return err
+			}
+			if prizeValue == nil {
+				return fmt.Errorf("prize value %d not found", result.PrizeValueID)
+			}
+			prizeValues[result.PrizeValueID] = prizeValue
+		}
+		prizeValueStr := prizeValue.Label
+		if prizeValueStr == "" {
+			prizeValueStr = strconv.FormatInt(prizeValue.Value, 10)
+		}
+
+		userUUID := result.UserUUID
+		now := time.Now().UTC().UnixMilli()
+		prize := &domain.Prize{
+			EventID:      &eventID,
+			UserID:       &userUUID,
+			PrizeValueID: &prizeValue.ID,
+			PrizeValue:   prizeValueStr,
+			PrizeType:    domain.PrizeTypeSeasonReward,
+			AwardedAt:    now,
+			CreatedAt:    now,
+		}
+		description := fmt.Sprintf("%s place %d reward", result.SeasonTitle, result.Rank)
+		awarded, err := s.repo.AwardSeasonResultPrize(ctx, result.SeasonID, prize, prizeValue.Value, description)
+		if err != nil {
+			return fmt.Errorf("failed to award season %d prize to %s: %w", result.SeasonID, userUUID, err)
+		}
+		if awarded {
+			log.Printf("season: awarded %s to place %d of season %d", prizeValueStr, result.Rank, result.SeasonID)
+		}
+	}
+
+	return nil
+}
+
+func seasonTitle(start time.Time) string {
+	return "Season " + start.UTC().Format("2006-01-02")
+}
+
+func normalizeSeasonPage(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = 50 // Default limit
+	}
+	if limit > 1000 {
+		limit = 1000 // Max limit to prevent abuse
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"pdrest/internal/data"
+	"pdrest/internal/domain"
+	"time"
+)
+
+// Glicko-style parameters. Every settled bet is a game against the market,
+// modelled as a fixed opponent with rating marketRating and deviation marketDeviation.
+const (
+	skillMarketRating    = 1500.0
+	skillMarketDeviation = 50.0
+	skillMinDeviation    = 50.0
+	// skillDeviationGrowthPerDay inflates deviation for inactive players (c^2 in Glicko).
+	skillDeviationGrowthPerDay = 100.0
+	// skillReferenceTimeframe is the timeframe (seconds) that gets weight 1.
+	skillReferenceTimeframe = 3600.0
+	// skillReferenceMoveBps is the price move (basis points) that gets weight 1.
+	skillReferenceMoveBps = 10.0
+	// skillUpdateRetries bounds retries when concurrent settlements race on one user.
+	skillUpdateRetries = 3
+	// skillLeaderboardMinGames hides provisional ratings from the leaderboard.
+	skillLeaderboardMinGames = 10
+)
+
+type SkillService struct {
+	repo    data.SkillRatingRepository
+	betRepo data.BetRepository
+}
+
+func NewSkillService(repo data.SkillRatingRepository, betRepo data.BetRepository) *SkillService {
+	return &SkillService{
+		repo:    repo,
+		betRepo: betRepo,
+	}
+}
+
+// ApplyBetSettlement updates the bet owner's skill rating for a closed bet.
+// It is idempotent: a bet is applied at most once.
+func (s *SkillService) ApplyBetSettlement(ctx context.Context, betID int) error {
+	if s.repo == nil || s.betRepo == nil {
+		return errors.New("skill service dependencies are not configured")
+	}
+
+	bet, err := s.betRepo.GetBetByIDAnyUser(ctx, betID)
+	if err != nil {
+		return err
+	}
+	if bet == nil {
+		return errors.New("bet not found")
+	}
+	if bet.ClosePrice == nil {
+		return errors.New("bet is not closed yet")
+	}
+
+	for attempt := 0; attempt < skillUpdateRetries; attempt++ {
+		current, err := s.GetUserSkillRating(ctx, bet.UserID)
+		if err != nil {
+			return err
+		}
+
+		update := computeSkillUpdate(current, bet, time.Now().UTC())
+		_, err = s.repo.ApplySkillUpdate(ctx, update)
+		if errors.Is(err, data.ErrSkillRatingConflict) {
+			continue
+		}
+		return err
+	}
+
+	return fmt.Errorf("failed to apply skill rating for bet %d: %w", betID, data.ErrSkillRatingConflict)
+}
+
+// GetUserSkillRating returns the user's rating, or the provisional default if they have no settled bets.
+func (s *SkillService) GetUserSkillRating(ctx context.Context, userUUID string) (*domain.SkillRating, error) {
+	if userUUID == "" {
+		return nil, errors.New("user uuid is required")
+	}
+	if s.repo == nil {
+		return nil, errors.New("skill rating repository is not configured")
+	}
+
+	rating, err := s.repo.GetSkillRating(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	if rating == nil {
+		rating = &domain.SkillRating{
+			UserUUID:  userUUID,
+			Rating:    domain.SkillRatingDefault,
+			Deviation: domain.SkillDeviationDefault,
+		}
+	}
+	return rating, nil
+}
+
+func (s *SkillService) GetSkillLeaderboard(ctx context.Context, limit, offset int) ([]domain.SkillLeaderboardEntry, error) {
+	if s.repo == nil {
+		return nil, errors.New("skill rating repository is not configured")
+	}
+
+	if limit <= 0 {
+		limit = 50 // Default limit
+	}
+	if limit > 1000 {
+		limit = 1000 // Max limit to prevent abuse
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	entries, err := s.repo.GetSkillLeaderboard(ctx, skillLeaderboardMinGames, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = make([]domain.SkillLeaderboardEntry, 0)
+	}
+	return entries, nil
+}
+
+// computeSkillUpdate applies a single Glicko-1 game against the market, scaling the
+// rating change by how informative the outcome was (timeframe and price move).
+func computeSkillUpdate(current *domain.SkillRating, bet *domain.Bet, now time.Time) *domain.SkillRatingUpdate {
+	deviation := current.Deviation
+	if current.UpdatedAt > 0 {
+		idleDays := now.Sub(time.UnixMilli(current.UpdatedAt)).Hours() / 24
+		if idleDays > 0 {
+			deviation = math.Min(math.Sqrt(deviation*deviation+skillDeviationGrowthPerDay*idleDays), domain.SkillDeviationDefault)
+		}
+	}
+
+	score := 0.0
+	if determinePrizeStatus(*bet) == "win" {
+		score = 1.0
+	}
+	weight := skillOutcomeWeight(bet)
+
+	q := math.Ln10 / 400
+	g := 1 / math.Sqrt(1+3*q*q*skillMarketDeviation*skillMarketDeviation/(math.Pi*math.Pi))
+	expected := 1 / (1 + math.Pow(10, -g*(current.Rating-skillMarketRating)/400))
+	dSquared := 1 / (q * q * g * g * expected * (1 - expected))
+	precision := 1/(deviation*deviation) + 1/dSquared
+
+	newRating := current.Rating + weight*(q/precision)*g*(score-expected)
+	newDeviation := math.Max(math.Sqrt(1/precision), skillMinDeviation)
+
+	return &domain.SkillRatingUpdate{
+		BetID:           bet.ID,
+		UserUUID:        bet.UserID,
+		GamesBefore:     current.Games,
+		RatingBefore:    current.Rating,
+		RatingAfter:     newRating,
+		DeviationBefore: current.Deviation,
+		DeviationAfter:  newDeviation,
+		Score:           score,
+		Weight:          weight,
+	}
+}
+
+// skillOutcomeWeight favours longer timeframes and clear price moves over short, noisy flips.
+func skillOutcomeWeight(bet *domain.Bet) float64 {
+	timeframeWeight := 0.25
+	if bet.Timeframe > 1 {
+		timeframeWeight = clampFloat(math.Log(float64(bet.Timeframe))/math.Log(skillReferenceTimeframe), 0.25, 1.5)
+	}
+
+	volatilityWeight := 0.25
+	if bet.ClosePrice != nil && bet.OpenPrice > 0 {
+		moveBps := math.Abs(*bet.ClosePrice-bet.OpenPrice) / bet.OpenPrice * 10000
+		volatilityWeight = clampFloat(moveBps/skillReferenceMoveBps, 0.25, 2.0)
+	}
+
+	return timeframeWeight * volatilityWeight
+}
+
+func clampFloat(value, min, max float64) float64 {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
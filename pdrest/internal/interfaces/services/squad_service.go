@@ -0,0 +1,443 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"pdrest/internal/data"
+	"pdrest/internal/domain"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// inviteCodeAlphabet leaves out look-alike characters so invite codes can be typed from a screenshot.
+const inviteCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// SquadService manages squads: teams that compete together in events tagged "squad". A squad's score is
+// the sum of its members' bet points in the event window; when the event finishes each winning squad's
+// reward tier is split equally among its members.
+type SquadService struct {
+	repo         data.SquadRepository
+	eventRepo    data.EventRepository
+	eventService *EventService
+	maxSize      int
+}
+
+func NewSquadService(repo data.SquadRepository, eventRepo data.EventRepository, eventService *EventService, maxSize int) *SquadService {
+	if maxSize <= 0 {
+		maxSize = 5
+	}
+	return &SquadService{
+		repo:         repo,
+		eventRepo:    eventRepo,
+		eventService: eventService,
+		maxSize:      maxSize,
+	}
+}
+
+func isSquadEvent(event *domain.Event) bool {
+	return strings.Contains(strings.ToLower(event.Tags), "squad")
+}
+
+// squadEvent returns the squad event. With open set, squads can only be changed until the event ends.
+func (s *SquadService) squadEvent(ctx context.Context, eventID string, open bool) (*domain.Event, error) {
+	if eventID == "" {
+		return nil, errors.New("event id is required")
+	}
+	event, err := s.eventRepo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil || event.State == domain.EventStateDraft || event.State == domain.EventStateArchived {
+		return nil, errors.New("event not found")
+	}
+	if !isSquadEvent(event) {
+		return nil, errors.New("event is not a squad event")
+	}
+	running := event.State == domain.EventStateScheduled || event.State == domain.EventStateActive
+	if open && (!running || !time.Now().UTC().Before(event.Deadline)) {
+		return nil, errors.New("squads cannot be changed after the event has ended")
+	}
+	return event, nil
+}
+
+// joinEvent makes a new squad member take part in the squad's event under the event's eligibility rules.
+// A member who cannot take part (not eligible, event full) is taken out of the squad again.
+func (s *SquadService) joinEvent(ctx context.Context, userUUID string, squad *domain.Squad) error {
+	result, err := s.eventService.TakePartOnEvent(ctx, userUUID, squad.EventID)
+	if err == nil && result.UserStatus != domain.UserEventJoined {
+		err = errors.New("event is full; you are on its waitlist")
+	}
+	if err != nil {
+		if _, removeErr := s.repo.RemoveSquadMember(ctx, squad.ID, userUUID); removeErr != nil {
+			log.Printf("squads: failed to remove %s from squad %d: %v", userUUID, squad.ID, removeErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// CreateSquad creates a squad in the event with the user as its captain and first member.
+// The user takes part in the event too.
+func (s *SquadService) CreateSquad(ctx context.Context, userUUID string, req *domain.CreateSquadRequest) (*domain.Squad, error) {
+	if userUUID == "" {
+		return nil, errors.New("user uuid is required")
+	}
+	if s.repo == nil || s.eventRepo == nil || s.eventService == nil {
+		return nil, errors.New("squad service dependencies are not configured")
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || utf8.RuneCountInString(name) > 50 {
+		return nil, errors.New("name must be 1-50 characters")
+	}
+	event, err := s.squadEvent(ctx, strings.TrimSpace(req.EventID), true)
+	if err != nil {
+		return nil, err
+	}
+
+	inviteCode, err := generateInviteCode()
+	if err != nil {
+		return nil, err
+	}
+	squad := &domain.Squad{
+		EventID:     event.ID,
+		Name:        name,
+		InviteCode:  inviteCode,
+		CaptainUUID: userUUID,
+		MaxSize:     s.maxSize,
+	}
+	if err := s.repo.CreateSquad(ctx, squad); err != nil {
+		return nil, err
+	}
+	if err := s.joinEvent(ctx, userUUID, squad); err != nil {
+		return nil, err
+	}
+
+	return s.GetSquad(ctx, userUUID, squad.ID)
+}
+
+// JoinSquad adds the user to the squad with the invite code. The user takes part in the event too.
+func (s *SquadService) JoinSquad(ctx context.Context, userUUID string, inviteCode string) (*domain.Squad, error) {
+	if userUUID == "" {
+		return nil, errors.New("user uuid is required")
+	}
+	if s.repo == nil || s.eventRepo == nil || s.eventService == nil {
+		return nil, errors.New("squad service dependencies are not configured")
+	}
+
+	inviteCode = strings.ToUpper(strings.TrimSpace(inviteCode))
+	if inviteCode == "" {
+		return nil, errors.New("inviteCode is required")
+	}
+	squad, err := s.repo.GetSquadByInviteCode(ctx, inviteCode)
+	if err != nil {
+		return nil, err
+	}
+	if squad == nil {
+		return nil, errors.New("squad not found")
+	}
+	if _, err := s.squadEvent(ctx, squad.EventID, true); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.AddSquadMember(ctx, squad.ID, userUUID, time.Now().UTC().UnixMilli()); err != nil {
+		return nil, err
+	}
+	if err := s.joinEvent(ctx, userUUID, squad); err != nil {
+		return nil, err
+	}
+
+	return s.GetSquad(ctx, userUUID, squad.ID)
+}
+
+// LeaveSquad removes the user from the squad; the user's bets stop counting for it. A leaving captain
+// hands over to the longest-standing member and the last member leaving deletes the squad.
+func (s *SquadService) LeaveSquad(ctx context.Context, userUUID string, squadID int) error {
+	if userUUID == "" {
+		return errors.New("user uuid is required")
+	}
+	squad, err := s.openSquad(ctx, squadID)
+	if err != nil {
+		return err
+	}
+
+	left, err := s.repo.RemoveSquadMember(ctx, squad.ID, userUUID)
+	if err != nil {
+		return err
+	}
+	if !left {
+		return errors.New("user is not a member of this squad")
+	}
+	return nil
+}
+
+// RemoveMember removes a member from the captain's squad.
+func (s *SquadService) RemoveMember(ctx context.Context, captainUUID string, squadID int, memberUUID string) error {
+	if captainUUID == "" {
+		return errors.New("user uuid is required")
+	}
+	memberUUID = strings.TrimSpace(memberUUID)
+	if memberUUID == "" {
+		return errors.New("userUuid is required")
+	}
+	squad, err := s.openSquad(ctx, squadID)
+	if err != nil {
+		return err
+	}
+	if squad.CaptainUUID != captainUUID {
+		return errors.New("only the captain can remove members")
+	}
+	if memberUUID == captainUUID {
+		return errors.New("captain cannot remove themselves, leave the squad instead")
+	}
+
+	removed, err := s.repo.RemoveSquadMember(ctx, squad.ID, memberUUID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return errors.New("user is not a member of this squad")
+	}
+	return nil
+}
+
+// openSquad returns the squad if its event still allows membership changes.
+func (s *SquadService) openSquad(ctx context.Context, squadID int) (*domain.Squad, error) {
+	if s.repo == nil || s.eventRepo == nil {
+		return nil, errors.New("squad service dependencies are not configured")
+	}
+	squad, err := s.repo.GetSquad(ctx, squadID)
+	if err != nil {
+		return nil, err
+	}
+	if squad == nil {
+		return nil, errors.New("squad not found")
+	}
+	if _, err := s.squadEvent(ctx, squad.EventID, true); err != nil {
+		return nil, err
+	}
+	return squad, nil
+}
+
+// GetSquad returns the squad with its members and their points. The invite code and member IDs are only
+// shown to members.
+func (s *SquadService) GetSquad(ctx context.Context, userUUID string, squadID int) (*domain.Squad, error) {
+	if s.repo == nil || s.eventRepo == nil {
+		return nil, errors.New("squad service dependencies are not configured")
+	}
+	squad, err := s.repo.GetSquad(ctx, squadID)
+	if err != nil {
+		return nil, err
+	}
+	if squad == nil {
+		return nil, errors.New("squad not found")
+	}
+	event, err := s.squadEvent(ctx, squad.EventID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.repo.GetSquadMembers(ctx, squad.ID, event.StartTime.UTC().UnixMilli(), event.Deadline.UTC().UnixMilli(), eventScoringRules(event))
+	if err != nil {
+		return nil, err
+	}
+	squad.Members = members
+
+	isMember := false
+	for _, member := range members {
+		if member.UserUUID == userUUID {
+			isMember = true
+			break
+		}
+	}
+	if !isMember {
+		squad.InviteCode = ""
+		for i := range squad.Members {
+			squad.Members[i].UserUUID = ""
+		}
+	}
+	return squad, nil
+}
+
+// GetUserSquad returns the user's squad in the event with its members, or nil if the user is in none.
+func (s *SquadService) GetUserSquad(ctx context.Context, userUUID string, eventID string) (*domain.Squad, error) {
+	if userUUID == "" {
+		return nil, errors.New("user uuid is required")
+	}
+	if s.repo == nil || s.eventRepo == nil {
+		return nil, errors.New("squad service dependencies are not configured")
+	}
+	if _, err := s.squadEvent(ctx, eventID, false); err != nil {
+		return nil, err
+	}
+
+	squad, err := s.repo.GetUserSquad(ctx, eventID, userUUID)
+	if err != nil || squad == nil {
+		return nil, err
+	}
+	return s.GetSquad(ctx, userUUID, squad.ID)
+}
+
+// GetSquadLeaderboard returns a page of the event's squad standings, live while it runs and frozen once it
+// is finished. The caller's squad is returned in Me whether or not it is on the page.
+func (s *SquadService) GetSquadLeaderboard(ctx context.Context, eventID string, userUUID string, limit, offset int) (*domain.SquadLeaderboardResponse, error) {
+	if s.repo == nil || s.eventRepo == nil {
+		return nil, errors.New("squad service dependencies are not configured")
+	}
+	event, err := s.squadEvent(ctx, eventID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &domain.SquadLeaderboardResponse{
+		EventID: eventID,
+		Entries: []domain.SquadStanding{},
+	}
+	finalized, err := s.repo.AreSquadsFinalized(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	response.Final = finalized
+
+	mySquadID := 0
+	if userUUID != "" {
+		squad, err := s.repo.GetUserSquad(ctx, eventID, userUUID)
+		if err != nil {
+			return nil, err
+		}
+		if squad != nil {
+			mySquadID = squad.ID
+		}
+	}
+
+	standings := func(squadID, limit, offset int) ([]domain.SquadStanding, error) {
+		if finalized {
+			return s.repo.GetSquadResults(ctx, eventID, squadID, limit, offset)
+		}
+		return s.repo.GetSquadStandings(ctx, eventID, event.StartTime.UTC().UnixMilli(), event.Deadline.UTC().UnixMilli(), eventScoringRules(event), squadID, limit, offset)
+	}
+
+	entries, err := standings(0, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	response.Entries = append(response.Entries, entries...)
+	if mySquadID > 0 {
+		mine, err := standings(mySquadID, 1, 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(mine) > 0 {
+			response.Me = &mine[0]
+		}
+	}
+	return response, nil
+}
+
+// FreezeResults freezes a squad event's squad standings and pays each winning squad's tier to its members,
+// split equally, through got_prizes; it is the lifecycle hook run before an event is finished. The prize of
+// a tier with an amount is split into equal shares; a tier without one (e.g. a badge) goes to every member.
+func (s *SquadService) FreezeResults(ctx context.Context, event *domain.Event) error {
+	if !isSquadEvent(event) {
+		return nil
+	}
+	if s.repo == nil || s.eventService == nil {
+		return errors.New("squad service dependencies are not configured")
+	}
+
+	rules := eventScoringRules(event)
+	standings, err := s.repo.GetSquadStandings(ctx, event.ID, event.StartTime.UTC().UnixMilli(), event.Deadline.UTC().UnixMilli(), rules, 0, 0, 0)
+	if err != nil {
+		return err
+	}
+	tiers, err := s.eventService.rewardTierPrizes(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	var prizes []domain.Prize
+	var notifications []domain.Notification
+	winners := make(map[string]int)
+	for first := 0; first < len(standings); {
+		// Squads tied on points occupy positions first+1..last together unless ties go to the earliest.
+		last := first + 1
+		if rules.Ties == domain.RewardTiesShared || rules.Ties == domain.RewardTiesSplit {
+			for last < len(standings) && standings[last].NetPoints == standings[first].NetPoints {
+				last++
+			}
+		}
+		prizeValue, prizeLabel := tiedPlacesAward(tiers, rules.Ties, first+1, last)
+		prizeValue, err = s.eventService.resolveTiedPlacesAward(ctx, prizeValue)
+		if err != nil {
+			return err
+		}
+
+		for i := first; i < last && prizeValue != nil; i++ {
+			standing := &standings[i]
+			members, err := s.repo.GetSquadMembers(ctx, standing.SquadID, event.StartTime.UTC().UnixMilli(), event.Deadline.UTC().UnixMilli(), rules)
+			if err != nil {
+				return err
+			}
+			if len(members) == 0 {
+				continue
+			}
+
+			standing.PrizeValueID = &prizeValue.ID
+			standing.PrizeLabel = prizeLabel
+			standing.MemberPrizeLabel = prizeLabel
+			if amount, currency := parseRewardAmount(prizeLabel, ""); amount > 0 {
+				standing.MemberPrizeLabel = formatRewardAmount(amount/float64(len(members)), currency)
+			}
+
+			for _, member := range members {
+				userUUID := member.UserUUID
+				prizes = append(prizes, domain.Prize{
+					EventID:      &event.ID,
+					UserID:       &userUUID,
+					PrizeValueID: &prizeValue.ID,
+					PrizeValue:   standing.MemberPrizeLabel,
+					PrizeType:    domain.PrizeTypeSquadReward,
+				})
+				winners[userUUID] = prizeValue.ID
+				if now.Sub(event.Deadline) <= winnerNotificationWindow {
+					notifications = append(notifications, domain.Notification{
+						UserUUID: userUUID,
+						Producer: domain.NotificationProducerSquadFinalizer,
+						Message:  fmt.Sprintf("Your squad %s finished #%d in %s and won %s! Your share: %s.", standing.Name, standing.Rank, event.Title, prizeLabel, standing.MemberPrizeLabel),
+					})
+				}
+			}
+		}
+		first = last
+	}
+
+	saved, err := s.repo.SaveSquadResults(ctx, event.ID, standings, prizes, notifications, now.UnixMilli())
+	if err != nil || !saved {
+		return err
+	}
+
+	// The prizes are paid; a failed achievement grant must not fail the transition, which would not pay again.
+	for userUUID, prizeValueID := range winners {
+		if _, err := s.eventService.grantPrizeAchievement(ctx, userUUID, prizeValueID); err != nil {
+			log.Printf("squads: failed to grant prize achievement to %s in %s: %v", userUUID, event.ID, err)
+		}
+	}
+	log.Printf("squads: froze %d squad standings of %s, paid %d member prizes", len(standings), event.ID, len(prizes))
+	return nil
+}
+
+func generateInviteCode() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate invite code: %w", err)
+	}
+	for i := range buf {
+		buf[i] = inviteCodeAlphabet[int(buf[i])%len(inviteCodeAlphabet)]
+	}
+	return string(buf), nil
+}
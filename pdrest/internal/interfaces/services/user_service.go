@@ -9,10 +9,31 @@ import (
 
 type UserService struct {
 	repo data.UserRepository
+	bus  *DomainEventBus
 }
 
-func NewUserService(r data.UserRepository) *UserService {
-	return &UserService{repo: r}
+func NewUserService(r data.UserRepository, bus *DomainEventBus) *UserService {
+	return &UserService{repo: r, bus: bus}
+}
+
+// publishLoggedIn publishes UserLoggedIn after a sign-in updated the user's last_login_at.
+func (s *UserService) publishLoggedIn(ctx context.Context, userUUID string) {
+	if userUUID == "" {
+		return
+	}
+	s.bus.Publish(ctx, domain.DomainEvent{Type: domain.DomainEventUserLoggedIn, UserUUID: userUUID})
+}
+
+// publishReferralActivated publishes ReferralActivated to the referrer the user was just attributed to.
+func (s *UserService) publishReferralActivated(ctx context.Context, userUUID string) {
+	if s.bus == nil {
+		return
+	}
+	referrerUUID, err := s.repo.GetReferrerUUID(ctx, userUUID)
+	if err != nil || referrerUUID == "" {
+		return
+	}
+	s.bus.Publish(ctx, domain.DomainEvent{Type: domain.DomainEventReferralActivated, UserUUID: referrerUUID, InviteeUUID: userUUID})
 }
 
 func (s *UserService) GetLastLogin(uuid string) (*domain.UserLastLogin, error) {
@@ -115,7 +136,16 @@ func (s *UserService) GetUserBySessionAndIP(ctx context.Context, sessionID strin
 }
 
 func (s *UserService) CreateOrUpdateUserBySession(sessionID string, ipAddress string) error {
-	return s.repo.CreateOrUpdateUserBySession(sessionID, ipAddress)
+	if err := s.repo.CreateOrUpdateUserBySession(sessionID, ipAddress); err != nil {
+		return err
+	}
+	if s.bus != nil {
+		ctx := context.Background()
+		if user, err := s.repo.GetUserBySessionAndIP(ctx, sessionID, ipAddress); err == nil && user != nil {
+			s.publishLoggedIn(ctx, user.UserID)
+		}
+	}
+	return nil
 }
 
 // RegisterUserWithGoogle registers or updates a user with Google OAuth information
@@ -126,7 +156,11 @@ func (s *UserService) RegisterUserWithGoogle(ctx context.Context, userUUID strin
 	if googleID == "" {
 		return errors.New("google_id is required")
 	}
-	return s.repo.CreateOrUpdateUserWithGoogleInfo(ctx, userUUID, googleID)
+	if err := s.repo.CreateOrUpdateUserWithGoogleInfo(ctx, userUUID, googleID); err != nil {
+		return err
+	}
+	s.publishLoggedIn(ctx, userUUID)
+	return nil
 }
 
 // RegisterUserWithGoogleByGoogleID creates or updates a user by google_id and returns user UUID
@@ -134,7 +168,12 @@ func (s *UserService) RegisterUserWithGoogleByGoogleID(ctx context.Context, goog
 	if googleID == "" {
 		return "", errors.New("google_id is required")
 	}
-	return s.repo.CreateOrUpdateUserWithGoogleInfoByGoogleID(ctx, googleID)
+	userUUID, err := s.repo.CreateOrUpdateUserWithGoogleInfoByGoogleID(ctx, googleID)
+	if err != nil {
+		return "", err
+	}
+	s.publishLoggedIn(ctx, userUUID)
+	return userUUID, nil
 }
 
 // RegisterUserWithTelegram registers or updates a user with Telegram OAuth information
@@ -145,7 +184,11 @@ func (s *UserService) RegisterUserWithTelegram(ctx context.Context, userUUID str
 	if telegramID == 0 {
 		return errors.New("telegram_id is required")
 	}
-	return s.repo.CreateOrUpdateUserWithTelegramInfo(ctx, userUUID, telegramID, telegramUsername, telegramFirstName, telegramLastName)
+	if err := s.repo.CreateOrUpdateUserWithTelegramInfo(ctx, userUUID, telegramID, telegramUsername, telegramFirstName, telegramLastName); err != nil {
+		return err
+	}
+	s.publishLoggedIn(ctx, userUUID)
+	return nil
 }
 
 // RegisterUserWithTelegramByTelegramID creates or updates a user by telegram_id and returns user UUID
@@ -153,7 +196,12 @@ func (s *UserService) RegisterUserWithTelegramByTelegramID(ctx context.Context,
 	if telegramID == 0 {
 		return "", errors.New("telegram_id is required")
 	}
-	return s.repo.CreateOrUpdateUserWithTelegramInfoByTelegramID(ctx, telegramID, telegramUsername, telegramFirstName, telegramLastName)
+	userUUID, err := s.repo.CreateOrUpdateUserWithTelegramInfoByTelegramID(ctx, telegramID, telegramUsername, telegramFirstName, telegramLastName)
+	if err != nil {
+		return "", err
+	}
+	s.publishLoggedIn(ctx, userUUID)
+	return userUUID, nil
 }
 
 func (s *UserService) UpdateMainRefIfEmpty(ctx context.Context, userUUID string, mainRef string) error {
@@ -161,17 +209,39 @@ func (s *UserService) UpdateMainRefIfEmpty(ctx context.Context, userUUID string,
 }
 
 func (s *UserService) ApplyReferralCode(ctx context.Context, userUUID string, referralCode string) error {
-	return s.repo.ApplyReferralCode(ctx, userUUID, referralCode)
+	if err := s.repo.ApplyReferralCode(ctx, userUUID, referralCode); err != nil {
+		return err
+	}
+	s.publishReferralActivated(ctx, userUUID)
+	return nil
 }
 
 func (s *UserService) SetReferrerByInviterTGID(ctx context.Context, userUUID string, inviterTGID int64) error {
-	return s.repo.SetReferrerByInviterTGID(ctx, userUUID, inviterTGID)
+	if err := s.repo.SetReferrerByInviterTGID(ctx, userUUID, inviterTGID); err != nil {
+		return err
+	}
+	s.publishReferralActivated(ctx, userUUID)
+	return nil
 }
 
 func (s *UserService) SetReferrerByInviterUUID(ctx context.Context, userUUID string, inviterUUID string) error {
-	return s.repo.SetReferrerByInviterUUID(ctx, userUUID, inviterUUID)
+	if err := s.repo.SetReferrerByInviterUUID(ctx, userUUID, inviterUUID); err != nil {
+		return err
+	}
+	s.publishReferralActivated(ctx, userUUID)
+	return nil
 }
 
 func (s *UserService) UpdateUserLanguage(ctx context.Context, userUUID string, language string) error {
 	return s.repo.UpdateUserLanguage(ctx, userUUID, language)
 }
+
+// RecordLogin updates last_login_at for sign-ins that reuse an existing account
+// (token refresh, Google token verification, returning Telegram users).
+func (s *UserService) RecordLogin(ctx context.Context, userUUID string) error {
+	if err := s.repo.UpdateLastLogin(ctx, userUUID); err != nil {
+		return err
+	}
+	s.publishLoggedIn(ctx, userUUID)
+	return nil
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pdrest/internal/data"
+	"pdrest/internal/domain"
+	"strconv"
+	"time"
+)
+
+type XPService struct {
+	repo    data.XPRepository
+	weights domain.XPWeights
+	bus     *DomainEventBus
+}
+
+func NewXPService(r data.XPRepository, weights domain.XPWeights, bus *DomainEventBus) *XPService {
+	return &XPService{repo: r, weights: weights, bus: bus}
+}
+
+// OnDomainEvent grants the XP the event is worth. Grants are keyed by their source, so replayed events grant once.
+func (s *XPService) OnDomainEvent(ctx context.Context, event domain.DomainEvent) error {
+	if event.UserUUID == "" {
+		return nil
+	}
+	for _, grant := range s.xpGrants(event) {
+		if err := s.Grant(ctx, grant); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// xpGrants are the grants an event is worth by the configured weights
+func (s *XPService) xpGrants(event domain.DomainEvent) []domain.XPGrant {
+	var grants []domain.XPGrant
+	add := func(source, sourceRef string, xp int) {
+		if xp > 0 {
+			grants = append(grants, domain.XPGrant{UserUUID: event.UserUUID, Source: source, SourceRef: sourceRef, XP: xp})
+		}
+	}
+
+	switch event.Type {
+	case domain.DomainEventBetSettled:
+		if event.Bet == nil || event.Bet.ClosePrice == nil {
+			break
+		}
+		betRef := strconv.Itoa(event.Bet.ID)
+		add(domain.XPSourceBetPlaced, betRef, s.weights.BetPlaced)
+		if determinePrizeStatus(*event.Bet) == "win" {
+			add(domain.XPSourceBetWon, betRef, s.weights.BetWon)
+		}
+	case domain.DomainEventUserLoggedIn:
+		add(domain.XPSourceDailyLogin, time.UnixMilli(event.OccurredAt).UTC().Format("2006-01-02"), s.weights.DailyLogin)
+	case domain.DomainEventEventJoined:
+		add(domain.XPSourceEventJoined, event.EventID, s.weights.EventJoined)
+	case domain.DomainEventAchievementCompleted:
+		add(domain.XPSourceAchievementCompleted, event.AchievementID, s.weights.AchievementCompleted)
+	}
+	return grants
+}
+
+// Grant grants XP to the user and publishes LevelUp when it takes them to a new level
+func (s *XPService) Grant(ctx context.Context, grant domain.XPGrant) error {
+	if s.repo == nil {
+		return errors.New("xp service dependencies are not configured")
+	}
+	result, err := s.repo.GrantXP(ctx, grant)
+	if err != nil {
+		return err
+	}
+	if result.Granted && result.NewLevel > result.OldLevel {
+		s.bus.Publish(ctx, domain.DomainEvent{Type: domain.DomainEventLevelUp, UserUUID: grant.UserUUID, Level: result.NewLevel})
+	}
+	return nil
+}
+
+// NotifyLevelUp queues a notification for a reached level.
+func (s *XPService) NotifyLevelUp(ctx context.Context, event domain.DomainEvent) error {
+	if s.repo == nil {
+		return errors.New("xp service dependencies are not configured")
+	}
+	return s.repo.QueueNotification(ctx, &domain.Notification{
+		UserUUID: event.UserUUID,
+		Producer: domain.NotificationProducerLevels,
+		Message:  fmt.Sprintf("Level up! You reached level %d and unlocked new perks.", event.Level),
+	})
+}
+
+// GetUserLevel returns the user's XP, level and perks
+func (s *XPService) GetUserLevel(ctx context.Context, userUUID string) (*domain.UserLevel, error) {
+	if userUUID == "" {
+		return nil, errors.New("user uuid is required")
+	}
+	return s.repo.GetUserLevel(ctx, userUUID)
+}
+
+// GetLevels returns the level curve with the perks of each level
+func (s *XPService) GetLevels(ctx context.Context) ([]domain.Level, error) {
+	return s.repo.GetLevels(ctx)
+}
+
+// GetXPHistory returns a page of the user's XP grants, newest first
+func (s *XPService) GetXPHistory(ctx context.Context, userUUID string, limit, offset int) (*domain.XPHistoryResponse, error) {
+	if userUUID == "" {
+		return nil, errors.New("user uuid is required")
+	}
+	if limit <= 0 {
+		limit = 50 // Default limit
+	}
+	if limit > 1000 {
+		limit = 1000 // Max limit to prevent abuse
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	entries, err := s.repo.GetXPHistory(ctx, userUUID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.XPHistoryResponse{Entries: entries}, nil
+}
@@ -0,0 +1,91 @@
+-- Seasons: periodic reset of the points race with archived standings
+-- Lifetime points stay in rating; season totals are computed from rating rows created inside the season window.
+
+CREATE TABLE IF NOT EXISTS seasons (
+    id SERIAL PRIMARY KEY,
+    title TEXT NOT NULL,
+    start_time BIGINT NOT NULL,              -- Unix milliseconds (UTC), inclusive
+    end_time BIGINT NOT NULL,                -- Unix milliseconds (UTC), exclusive
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    finalized_at BIGINT,
+    created_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+    updated_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    CONSTRAINT uq_seasons_start_time UNIQUE (start_time),
+    CONSTRAINT chk_seasons_window CHECK (start_time < end_time),
+    CONSTRAINT chk_seasons_status CHECK (status IN ('active', 'finished'))
+);
+
+COMMENT ON TABLE seasons IS 'Stores season windows for the points race';
+COMMENT ON COLUMN seasons.start_time IS 'Season start (Unix ms, inclusive)';
+COMMENT ON COLUMN seasons.end_time IS 'Season end (Unix ms, exclusive)';
+COMMENT ON COLUMN seasons.status IS 'active or finished';
+COMMENT ON COLUMN seasons.finalized_at IS 'When the standings were archived (Unix ms)';
+
+-- Final standings snapshot taken at rollover
+CREATE TABLE IF NOT EXISTS season_results (
+    season_id INTEGER NOT NULL,
+    user_uuid UUID NOT NULL,
+    rank INTEGER NOT NULL,
+    points BIGINT NOT NULL,
+    got_prize_id INTEGER,
+    created_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    PRIMARY KEY (season_id, user_uuid),
+    CONSTRAINT fk_season_results_season FOREIGN KEY (season_id) REFERENCES seasons(id) ON DELETE CASCADE,
+    CONSTRAINT fk_season_results_user FOREIGN KEY (user_uuid) REFERENCES users(user_uuid) ON DELETE CASCADE,
+    CONSTRAINT fk_season_results_got_prize FOREIGN KEY (got_prize_id) REFERENCES got_prizes(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_season_results_rank ON season_results(season_id, rank);
+
+COMMENT ON TABLE season_results IS 'Archived final standings per season';
+COMMENT ON COLUMN season_results.got_prize_id IS 'Season-end prize awarded for this place (got_prizes.id)';
+
+-- Season-end prize table: place range -> prize value
+CREATE TABLE IF NOT EXISTS season_rewards (
+    id SERIAL PRIMARY KEY,
+    place_from INTEGER NOT NULL,
+    place_to INTEGER NOT NULL,
+    prize_value_id INTEGER NOT NULL,
+
+    CONSTRAINT chk_season_rewards_places CHECK (place_from >= 1 AND place_from <= place_to),
+    CONSTRAINT fk_season_rewards_prize_value FOREIGN KEY (prize_value_id) REFERENCES prize_values(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE season_rewards IS 'Season-end prizes by final place range';
+
+-- Holder event for season prize values
+INSERT INTO all_events (id, badge, title, desc_text, start_time, deadline, tags, reward, info, created_at, updated_at)
+VALUES (
+    'season',
+    'Season prizes',
+    'Season Rating',
+    'Finish the season in the top 10 of the global rating to win USDT rewards.',
+    EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+    EXTRACT(EPOCH FROM (NOW() + INTERVAL '10 years'))::BIGINT * 1000,
+    'season',
+    '[
+        {"place": "1", "value": "1000 USDT"},
+        {"place": "2", "value": "500 USDT"},
+        {"place": "3", "value": "250 USDT"},
+        {"place": "4-10", "value": "100 USDT"}
+    ]'::jsonb,
+    'Season totals start from zero every season, lifetime points are kept.',
+    EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+    EXTRACT(EPOCH FROM NOW())::BIGINT * 1000
+)
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO prize_values (event_id, value, label, segment_id, created_at, updated_at)
+SELECT 'season', v.value, v.label, NULL, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000
+FROM (VALUES (1000, '1000 USDT'), (500, '500 USDT'), (250, '250 USDT'), (100, '100 USDT')) AS v(value, label)
+WHERE NOT EXISTS (
+    SELECT 1 FROM prize_values pv WHERE pv.event_id = 'season' AND pv.value = v.value
+);
+
+INSERT INTO season_rewards (place_from, place_to, prize_value_id)
+SELECT r.place_from, r.place_to, pv.id
+FROM (VALUES (1, 1, 1000), (2, 2, 500), (3, 3, 250), (4, 10, 100)) AS r(place_from, place_to, value)
+JOIN prize_values pv ON pv.event_id = 'season' AND pv.value = r.value
+WHERE NOT EXISTS (SELECT 1 FROM season_rewards);
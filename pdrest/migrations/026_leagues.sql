@@ -0,0 +1,110 @@
+-- Ranked leagues (bronze -> diamond) with weekly cohorts, promotion and relegation
+-- Weekly points are bet points from the rating ledger (bet_id IS NOT NULL AND got_prize_id IS NULL).
+
+-- Current league tier per user
+CREATE TABLE IF NOT EXISTS league_members (
+    user_uuid UUID PRIMARY KEY,
+    tier VARCHAR(20) NOT NULL DEFAULT 'bronze',
+    created_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+    updated_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    CONSTRAINT fk_league_members_user FOREIGN KEY (user_uuid) REFERENCES users(user_uuid) ON DELETE CASCADE,
+    CONSTRAINT chk_league_members_tier CHECK (tier IN ('bronze', 'silver', 'gold', 'platinum', 'diamond'))
+);
+
+-- League weeks (Monday 00:00 UTC -> next Monday 00:00 UTC)
+CREATE TABLE IF NOT EXISTS league_weeks (
+    week_start BIGINT PRIMARY KEY,           -- Unix milliseconds (UTC), inclusive
+    week_end BIGINT NOT NULL,                -- Unix milliseconds (UTC), exclusive
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    finalized_at BIGINT,
+    created_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    CONSTRAINT chk_league_weeks_status CHECK (status IN ('active', 'finished'))
+);
+
+-- Cohorts of ~50 users of the same tier competing in a week
+CREATE TABLE IF NOT EXISTS league_cohorts (
+    id SERIAL PRIMARY KEY,
+    week_start BIGINT NOT NULL,
+    tier VARCHAR(20) NOT NULL,
+    created_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    CONSTRAINT fk_league_cohorts_week FOREIGN KEY (week_start) REFERENCES league_weeks(week_start) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_league_cohorts_week_tier ON league_cohorts(week_start, tier);
+
+CREATE TABLE IF NOT EXISTS league_cohort_members (
+    week_start BIGINT NOT NULL,
+    user_uuid UUID NOT NULL,
+    cohort_id INTEGER NOT NULL,
+    tier VARCHAR(20) NOT NULL,
+    joined_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+    final_rank INTEGER,                      -- Set at weekly rollover
+    points BIGINT,                           -- Set at weekly rollover
+    outcome VARCHAR(20),                     -- promoted, relegated, stayed; set at weekly rollover
+    got_prize_id INTEGER,
+    applied_at BIGINT,                       -- Set once the tier move and prize of the result are applied
+
+    PRIMARY KEY (week_start, user_uuid),
+    CONSTRAINT fk_league_cohort_members_cohort FOREIGN KEY (cohort_id) REFERENCES league_cohorts(id) ON DELETE CASCADE,
+    CONSTRAINT fk_league_cohort_members_user FOREIGN KEY (user_uuid) REFERENCES users(user_uuid) ON DELETE CASCADE,
+    CONSTRAINT fk_league_cohort_members_got_prize FOREIGN KEY (got_prize_id) REFERENCES got_prizes(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_league_cohort_members_cohort ON league_cohort_members(cohort_id);
+
+-- Weekly prizes per tier and final cohort place
+CREATE TABLE IF NOT EXISTS league_rewards (
+    id SERIAL PRIMARY KEY,
+    tier VARCHAR(20) NOT NULL,
+    place_from INTEGER NOT NULL,
+    place_to INTEGER NOT NULL,
+    prize_value_id INTEGER NOT NULL,
+
+    CONSTRAINT chk_league_rewards_places CHECK (place_from >= 1 AND place_from <= place_to),
+    CONSTRAINT fk_league_rewards_prize_value FOREIGN KEY (prize_value_id) REFERENCES prize_values(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE league_members IS 'Current league tier per user';
+COMMENT ON TABLE league_weeks IS 'Weekly league rounds';
+COMMENT ON TABLE league_cohorts IS 'Groups of users of the same tier competing in a week';
+COMMENT ON TABLE league_cohort_members IS 'Cohort membership and final weekly results';
+COMMENT ON TABLE league_rewards IS 'Weekly league prizes by tier and cohort place';
+
+-- Holder event for league prize values
+INSERT INTO all_events (id, badge, title, desc_text, start_time, deadline, tags, reward, info, created_at, updated_at)
+VALUES (
+    'league',
+    'League prizes',
+    'Weekly Leagues',
+    'Finish the week at the top of your league cohort to get promoted and win USDT rewards.',
+    EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+    EXTRACT(EPOCH FROM (NOW() + INTERVAL '10 years'))::BIGINT * 1000,
+    'league',
+    '[
+        {"place": "1", "value": "50 USDT"},
+        {"place": "2", "value": "25 USDT"},
+        {"place": "3", "value": "10 USDT"}
+    ]'::jsonb,
+    'Bronze, Silver, Gold, Platinum, Diamond. Top of each cohort is promoted, bottom is relegated every Monday.',
+    EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+    EXTRACT(EPOCH FROM NOW())::BIGINT * 1000
+)
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO prize_values (event_id, value, label, segment_id, created_at, updated_at)
+SELECT 'league', v.value, v.label, NULL, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000, EXTRACT(EPOCH FROM NOW())::BIGINT * 1000
+FROM (VALUES (50, '50 USDT'), (25, '25 USDT'), (10, '10 USDT')) AS v(value, label)
+WHERE NOT EXISTS (
+    SELECT 1 FROM prize_values pv WHERE pv.event_id = 'league' AND pv.value = v.value
+);
+
+-- Same podium prizes for every tier
+INSERT INTO league_rewards (tier, place_from, place_to, prize_value_id)
+SELECT t.tier, r.place_from, r.place_to, pv.id
+FROM (VALUES ('bronze'), ('silver'), ('gold'), ('platinum'), ('diamond')) AS t(tier)
+CROSS JOIN (VALUES (1, 1, 50), (2, 2, 25), (3, 3, 10)) AS r(place_from, place_to, value)
+JOIN prize_values pv ON pv.event_id = 'league' AND pv.value = r.value
+WHERE NOT EXISTS (SELECT 1 FROM league_rewards);
@@ -0,0 +1,35 @@
+-- Skill rating (Glicko-style) per user, updated when bets are settled
+-- Each settled bet is a game against the market; outcomes are weighted by timeframe and price move.
+
+CREATE TABLE IF NOT EXISTS skill_ratings (
+    user_uuid UUID PRIMARY KEY,
+    rating DOUBLE PRECISION NOT NULL DEFAULT 1500,
+    deviation DOUBLE PRECISION NOT NULL DEFAULT 350,
+    games INTEGER NOT NULL DEFAULT 0,        -- Settled bets counted; also used as optimistic lock version
+    updated_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    CONSTRAINT fk_skill_ratings_user FOREIGN KEY (user_uuid) REFERENCES users(user_uuid) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_skill_ratings_rating ON skill_ratings(rating DESC);
+
+-- One row per settled bet; the primary key makes settlement idempotent
+CREATE TABLE IF NOT EXISTS skill_rating_updates (
+    bet_id INTEGER PRIMARY KEY,
+    user_uuid UUID NOT NULL,
+    rating_before DOUBLE PRECISION NOT NULL,
+    rating_after DOUBLE PRECISION NOT NULL,
+    deviation_before DOUBLE PRECISION NOT NULL,
+    deviation_after DOUBLE PRECISION NOT NULL,
+    score DOUBLE PRECISION NOT NULL,         -- 1 = win, 0 = loss
+    weight DOUBLE PRECISION NOT NULL,        -- Timeframe x volatility weight
+    created_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    CONSTRAINT fk_skill_rating_updates_bet FOREIGN KEY (bet_id) REFERENCES bets(id) ON DELETE CASCADE,
+    CONSTRAINT fk_skill_rating_updates_user FOREIGN KEY (user_uuid) REFERENCES users(user_uuid) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_skill_rating_updates_user_created ON skill_rating_updates(user_uuid, created_at);
+
+COMMENT ON TABLE skill_ratings IS 'Glicko-style skill rating per user';
+COMMENT ON TABLE skill_rating_updates IS 'Skill rating change per settled bet';
@@ -0,0 +1,29 @@
+-- Referral rewards: signup bonus for the first completed bet of an invitee and
+-- a percentage of invitees' winnings for several referrer levels.
+
+ALTER TABLE rating
+    ADD COLUMN IF NOT EXISTS source VARCHAR(32);
+
+CREATE TABLE IF NOT EXISTS referral_rewards (
+    id SERIAL PRIMARY KEY,
+    beneficiary_uuid UUID NOT NULL,
+    invitee_uuid UUID NOT NULL,
+    level INTEGER NOT NULL CHECK (level > 0),
+    reward_type VARCHAR(20) NOT NULL CHECK (reward_type IN ('signup', 'winnings')),
+    bet_id INTEGER,
+    points BIGINT NOT NULL,
+    created_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    CONSTRAINT fk_referral_rewards_beneficiary FOREIGN KEY (beneficiary_uuid) REFERENCES users(user_uuid) ON DELETE CASCADE,
+    CONSTRAINT fk_referral_rewards_invitee FOREIGN KEY (invitee_uuid) REFERENCES users(user_uuid) ON DELETE CASCADE
+);
+
+-- One signup bonus per invitee and one winnings share per bet and level.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_referral_rewards_once
+    ON referral_rewards(invitee_uuid, reward_type, level, (COALESCE(bet_id, 0)));
+
+CREATE INDEX IF NOT EXISTS idx_referral_rewards_beneficiary ON referral_rewards(beneficiary_uuid);
+CREATE INDEX IF NOT EXISTS idx_users_referrer_user_uuid ON users(referrer_user_uuid);
+
+COMMENT ON TABLE referral_rewards IS 'Ledger of referral payouts; each row has a matching rating row with source = referral';
+COMMENT ON COLUMN referral_rewards.level IS '1 = direct invitee, 2 = invitee of an invitee, ...';
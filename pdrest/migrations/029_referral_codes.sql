@@ -0,0 +1,24 @@
+-- Campaign referral codes. The codes a user owns are listed in users.add_refs
+-- (resolved alongside main_ref); referral_codes holds per-code metadata and caps.
+
+CREATE TABLE IF NOT EXISTS referral_codes (
+    code TEXT PRIMARY KEY,
+    owner_uuid UUID NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    expires_at BIGINT,                   -- NULL = never expires
+    max_uses INTEGER CHECK (max_uses IS NULL OR max_uses > 0),
+    uses INTEGER NOT NULL DEFAULT 0,
+    created_by_admin BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    CONSTRAINT fk_referral_codes_owner FOREIGN KEY (owner_uuid) REFERENCES users(user_uuid) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_referral_codes_owner_uuid ON referral_codes(owner_uuid);
+
+-- Code the user signed up with (main_ref or a campaign code), for per-code attribution.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS referral_code TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_users_referral_code ON users(referral_code);
+CREATE INDEX IF NOT EXISTS idx_users_add_refs ON users USING GIN (add_refs);
@@ -0,0 +1,8 @@
+-- Events managed through the admin API can be archived instead of deleted.
+-- Archived events are hidden from available event lists but keep their results and prizes.
+
+ALTER TABLE all_events
+    ADD COLUMN IF NOT EXISTS archived_at BIGINT;
+
+CREATE INDEX IF NOT EXISTS idx_prize_values_event_id ON prize_values(event_id);
+CREATE INDEX IF NOT EXISTS idx_achievements_prize_id ON achievements(prize_id);
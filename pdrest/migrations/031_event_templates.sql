@@ -0,0 +1,59 @@
+-- Recurring event templates: the template scheduler materializes upcoming instances into
+-- all_events/prize_values/achievements with deterministic IDs (<template id>_<DD_MM_YYYY>).
+
+CREATE TABLE IF NOT EXISTS event_templates (
+    id VARCHAR(30) PRIMARY KEY,              -- instance IDs append _DD_MM_YYYY, achievements _place_<n> (max 50 chars)
+    badge TEXT NOT NULL,
+    title TEXT NOT NULL,
+    desc_text TEXT NOT NULL,
+    tags VARCHAR(100),
+    reward JSONB NOT NULL DEFAULT '[]'::jsonb,
+    info TEXT,
+    prize_values JSONB NOT NULL DEFAULT '[]'::jsonb, -- [{value, label, achievement: {title, imageUrl, desc, ...}}] in place order
+    frequency VARCHAR(16) NOT NULL,
+    weekday INTEGER NOT NULL DEFAULT 1,      -- 0 = Sunday ... 6 = Saturday (weekly only)
+    start_minute INTEGER NOT NULL DEFAULT 0, -- minutes after 00:00 UTC
+    duration_minutes INTEGER NOT NULL,
+    lead_minutes INTEGER NOT NULL DEFAULT 1440,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    last_instance_start BIGINT,
+    created_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+    updated_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    CONSTRAINT chk_event_templates_frequency CHECK (frequency IN ('daily', 'weekly')),
+    CONSTRAINT chk_event_templates_weekday CHECK (weekday BETWEEN 0 AND 6),
+    CONSTRAINT chk_event_templates_start_minute CHECK (start_minute >= 0 AND start_minute < 1440),
+    CONSTRAINT chk_event_templates_duration CHECK (duration_minutes > 0),
+    CONSTRAINT chk_event_templates_lead CHECK (lead_minutes >= 0)
+);
+
+COMMENT ON TABLE event_templates IS 'Recurring event definitions materialized into all_events ahead of time';
+COMMENT ON COLUMN event_templates.lead_minutes IS 'How long before its start an instance is created';
+COMMENT ON COLUMN event_templates.last_instance_start IS 'Start of the latest materialized instance (Unix ms)';
+
+-- Best of the Week: every Monday 00:00 UTC for 7 days, created one day ahead
+INSERT INTO event_templates (id, badge, title, desc_text, tags, reward, info, prize_values, frequency, weekday, start_minute, duration_minutes, lead_minutes)
+VALUES (
+    'best_of_the_week',
+    'https://mrkriodev.github.io/mrkrio.github.io/data/events/event-weekly-3.png',
+    'Best of the Week',
+    'Weekly competition for the best betting results.',
+    'competition',
+    '[
+        {"place": "1", "value": "50 USDT"},
+        {"place": "2", "value": "30 USDT"},
+        {"place": "3", "value": "10 USDT"}
+    ]'::jsonb,
+    'Weekly competition, starts every Monday 00:00 UTC',
+    '[
+        {"value": 50, "label": "50 USDT", "achievement": {"badge": "Best of the Week", "title": "Best of the Week: 1st Place", "imageUrl": "https://mrkriodev.github.io/mrkrio.github.io/data/events/event-weekly-3.png", "desc": "Finish 1st place in Best of the Week competition."}},
+        {"value": 30, "label": "30 USDT", "achievement": {"badge": "Best of the Week", "title": "Best of the Week: 2nd Place", "imageUrl": "https://mrkriodev.github.io/mrkrio.github.io/data/events/event-weekly-1.png", "desc": "Finish 2nd place in Best of the Week competition."}},
+        {"value": 10, "label": "10 USDT", "achievement": {"badge": "Best of the Week", "title": "Best of the Week: 3rd Place", "imageUrl": "https://mrkriodev.github.io/mrkrio.github.io/data/events/event-weekly-1.png", "desc": "Finish 3rd place in Best of the Week competition."}}
+    ]'::jsonb,
+    'weekly',
+    1,
+    0,
+    10080,
+    1440
+)
+ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,29 @@
+-- Frozen final standings of finished competitions
+-- The competition finalizer snapshots the leaderboard once the deadline passes; prize status, prize
+-- claiming and best_in_event read these rows instead of recomputing the leaderboard.
+
+ALTER TABLE all_events ADD COLUMN IF NOT EXISTS finalized_at BIGINT;
+
+COMMENT ON COLUMN all_events.finalized_at IS 'When the final standings were frozen into event_results (Unix ms)';
+
+CREATE TABLE IF NOT EXISTS event_results (
+    event_id VARCHAR(50) NOT NULL,
+    user_uuid UUID NOT NULL,
+    rank INTEGER NOT NULL,
+    points BIGINT NOT NULL,
+    win_count INTEGER NOT NULL DEFAULT 0,
+    loss_count INTEGER NOT NULL DEFAULT 0,
+    last_scored_at BIGINT NOT NULL,          -- Tie-break: equal points rank by who reached them first
+    prize_value_id INTEGER,
+    created_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    PRIMARY KEY (event_id, user_uuid),
+    CONSTRAINT fk_event_results_event FOREIGN KEY (event_id) REFERENCES all_events(id) ON DELETE CASCADE,
+    CONSTRAINT fk_event_results_user FOREIGN KEY (user_uuid) REFERENCES users(user_uuid) ON DELETE CASCADE,
+    CONSTRAINT fk_event_results_prize_value FOREIGN KEY (prize_value_id) REFERENCES prize_values(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_results_rank ON event_results(event_id, rank);
+
+COMMENT ON TABLE event_results IS 'Final standings per finished competition';
+COMMENT ON COLUMN event_results.prize_value_id IS 'Prize value won for this place (prize_values.id)';
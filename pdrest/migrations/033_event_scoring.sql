@@ -0,0 +1,14 @@
+-- Per-event scoring rules for competitions
+-- NULL scoring = participants only, net points (skill gain for events tagged 'skill').
+-- Example: {"participantsOnly": true, "betsAfterJoin": true, "pairs": ["BTC/USDT"], "timeframes": [60], "minBets": 5, "metric": "roi"}
+
+ALTER TABLE all_events ADD COLUMN IF NOT EXISTS scoring JSONB;
+ALTER TABLE event_templates ADD COLUMN IF NOT EXISTS scoring JSONB;
+ALTER TABLE event_results ADD COLUMN IF NOT EXISTS score DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN all_events.scoring IS 'Competition scoring rules (which bets count, ranking metric)';
+COMMENT ON COLUMN event_results.score IS 'Value of the scoring metric the rank is based on';
+
+-- Standings scan settled bets by open time, optionally per user
+CREATE INDEX IF NOT EXISTS idx_bets_open_time ON bets(open_time) WHERE close_price IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_user_events_event_id ON user_events(event_id);
@@ -0,0 +1,19 @@
+-- Periodic leaderboard rank snapshots of running competitions
+-- The event leaderboard reports each entry's rank change against the latest snapshot.
+
+ALTER TABLE all_events ADD COLUMN IF NOT EXISTS rank_snapshot_at BIGINT;
+
+COMMENT ON COLUMN all_events.rank_snapshot_at IS 'When the latest leaderboard rank snapshot was taken (Unix ms)';
+
+CREATE TABLE IF NOT EXISTS event_rank_snapshots (
+    event_id VARCHAR(50) NOT NULL,
+    user_uuid UUID NOT NULL,
+    rank INTEGER NOT NULL,
+    created_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    PRIMARY KEY (event_id, user_uuid),
+    CONSTRAINT fk_event_rank_snapshots_event FOREIGN KEY (event_id) REFERENCES all_events(id) ON DELETE CASCADE,
+    CONSTRAINT fk_event_rank_snapshots_user FOREIGN KEY (user_uuid) REFERENCES users(user_uuid) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE event_rank_snapshots IS 'Latest leaderboard ranks per running competition (replaced on every snapshot)';
@@ -0,0 +1,50 @@
+-- Typed reward tiers
+-- Rewards become {place, value, rankFrom, rankTo, amount, currency, imageUrl, achievementId}. Legacy
+-- {place, prize, image_url} and {place, value} entries are converted; "any" places get no rank range.
+-- Tiers without achievementId are paid by the prize value at the same position (prize values by value desc).
+
+UPDATE all_events e
+SET reward = (
+    SELECT COALESCE(jsonb_agg(jsonb_strip_nulls(
+        (t.elem - 'prize' - 'image_url')
+        || jsonb_build_object(
+            'value', COALESCE(t.elem->>'value', t.elem->>'prize', ''),
+            'imageUrl', COALESCE(t.elem->>'imageUrl', t.elem->>'image_url'),
+            'rankFrom', CASE WHEN t.elem->>'place' ~ '^\s*\d+\s*(-\s*\d+\s*)?$'
+                THEN trim(split_part(t.elem->>'place', '-', 1))::INT END,
+            'rankTo', CASE WHEN t.elem->>'place' ~ '^\s*\d+\s*(-\s*\d+\s*)?$'
+                THEN COALESCE(NULLIF(trim(split_part(t.elem->>'place', '-', 2)), ''), trim(split_part(t.elem->>'place', '-', 1)))::INT END,
+            'amount', substring(COALESCE(t.elem->>'value', t.elem->>'prize', '') FROM '^\s*([0-9]+(?:\.[0-9]+)?)')::NUMERIC,
+            'currency', NULLIF(trim(substring(COALESCE(t.elem->>'value', t.elem->>'prize', '') FROM '^\s*[0-9]+(?:\.[0-9]+)?\s*(.*)$')), '')
+        )
+    ) ORDER BY t.ord), '[]'::jsonb)
+    FROM jsonb_array_elements(e.reward) WITH ORDINALITY AS t(elem, ord)
+)
+WHERE jsonb_typeof(e.reward) = 'array';
+
+UPDATE event_templates e
+SET reward = (
+    SELECT COALESCE(jsonb_agg(jsonb_strip_nulls(
+        (t.elem - 'prize' - 'image_url')
+        || jsonb_build_object(
+            'value', COALESCE(t.elem->>'value', t.elem->>'prize', ''),
+            'imageUrl', COALESCE(t.elem->>'imageUrl', t.elem->>'image_url'),
+            'rankFrom', CASE WHEN t.elem->>'place' ~ '^\s*\d+\s*(-\s*\d+\s*)?$'
+                THEN trim(split_part(t.elem->>'place', '-', 1))::INT END,
+            'rankTo', CASE WHEN t.elem->>'place' ~ '^\s*\d+\s*(-\s*\d+\s*)?$'
+                THEN COALESCE(NULLIF(trim(split_part(t.elem->>'place', '-', 2)), ''), trim(split_part(t.elem->>'place', '-', 1)))::INT END,
+            'amount', substring(COALESCE(t.elem->>'value', t.elem->>'prize', '') FROM '^\s*([0-9]+(?:\.[0-9]+)?)')::NUMERIC,
+            'currency', NULLIF(trim(substring(COALESCE(t.elem->>'value', t.elem->>'prize', '') FROM '^\s*[0-9]+(?:\.[0-9]+)?\s*(.*)$')), '')
+        )
+    ) ORDER BY t.ord), '[]'::jsonb)
+    FROM jsonb_array_elements(e.reward) WITH ORDINALITY AS t(elem, ord)
+)
+WHERE jsonb_typeof(e.reward) = 'array';
+
+-- Award text of a frozen result; differs from the prize value label when tied users split tiers
+ALTER TABLE event_results ADD COLUMN IF NOT EXISTS prize_label TEXT;
+
+COMMENT ON COLUMN event_results.prize_label IS 'What the rank won, e.g. "40 USDT" for a split tie';
+
+-- Prize values paying each user's share of a split tie, one per event and share
+CREATE UNIQUE INDEX IF NOT EXISTS uq_prize_values_tie_split ON prize_values(event_id, value) WHERE segment_id = 'tie_split';
@@ -0,0 +1,53 @@
+-- Explicit event lifecycle: draft -> scheduled -> active -> finalizing -> finished -> archived
+-- The lifecycle job moves events along by start time and deadline and runs the state hooks
+-- (open the event roulette, freeze results, release prizes); admins publish drafts and archive events.
+
+ALTER TABLE all_events ADD COLUMN IF NOT EXISTS state VARCHAR(20) NOT NULL DEFAULT 'scheduled';
+ALTER TABLE all_events ADD COLUMN IF NOT EXISTS state_changed_at BIGINT;
+
+ALTER TABLE all_events DROP CONSTRAINT IF EXISTS chk_all_events_state;
+ALTER TABLE all_events ADD CONSTRAINT chk_all_events_state
+    CHECK (state IN ('draft', 'scheduled', 'active', 'finalizing', 'finished', 'archived'));
+
+-- Events that ended before the lifecycle existed were settled by the lazy prize algorithm: users already hold
+-- prize statuses from it. Their standings count as frozen, so neither the job nor a read freezes them again
+-- under the current scoring, and they are backfilled as finished without running the hooks.
+UPDATE all_events
+SET finalized_at = EXTRACT(EPOCH FROM NOW())::BIGINT * 1000
+WHERE state_changed_at IS NULL
+  AND finalized_at IS NULL
+  AND deadline <= EXTRACT(EPOCH FROM NOW())::BIGINT * 1000
+  AND id NOT IN ('season', 'league');
+
+-- Backfill the rest from the timestamps. The season and league prize holder events can't be joined, so they
+-- stay hidden drafts.
+UPDATE all_events
+SET state = CASE
+        WHEN id IN ('season', 'league') THEN 'draft'
+        WHEN archived_at IS NOT NULL THEN 'archived'
+        WHEN finalized_at IS NOT NULL THEN 'finished'
+        WHEN start_time <= EXTRACT(EPOCH FROM NOW())::BIGINT * 1000 THEN 'active'
+        ELSE 'scheduled'
+    END,
+    state_changed_at = EXTRACT(EPOCH FROM NOW())::BIGINT * 1000
+WHERE state_changed_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_all_events_state ON all_events(state);
+
+COMMENT ON COLUMN all_events.state IS 'Lifecycle state: draft, scheduled, active, finalizing, finished, archived';
+COMMENT ON COLUMN all_events.state_changed_at IS 'When the event entered its current state (Unix ms)';
+
+-- Every state change, for auditing
+CREATE TABLE IF NOT EXISTS event_state_transitions (
+    id SERIAL PRIMARY KEY,
+    event_id VARCHAR(50) NOT NULL,
+    from_state VARCHAR(20) NOT NULL,
+    to_state VARCHAR(20) NOT NULL,
+    created_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    CONSTRAINT fk_event_state_transitions_event FOREIGN KEY (event_id) REFERENCES all_events(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_state_transitions_event ON event_state_transitions(event_id, created_at);
+
+COMMENT ON TABLE event_state_transitions IS 'Event lifecycle state changes';
@@ -0,0 +1,64 @@
+-- Squads: teams of users competing together in events tagged "squad"
+-- Members' bet points within the event window add up to the squad's score; when the event finishes the
+-- squad standings are frozen into squad_results and each winning squad's prize is split among its members.
+
+CREATE TABLE IF NOT EXISTS squads (
+    id SERIAL PRIMARY KEY,
+    event_id VARCHAR(50) NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    invite_code VARCHAR(16) NOT NULL UNIQUE,
+    captain_uuid UUID NOT NULL,
+    max_size INTEGER NOT NULL CHECK (max_size > 0),
+    created_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+    updated_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    CONSTRAINT fk_squads_event FOREIGN KEY (event_id) REFERENCES all_events(id) ON DELETE CASCADE,
+    CONSTRAINT fk_squads_captain FOREIGN KEY (captain_uuid) REFERENCES users(user_uuid) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_squads_event_name ON squads(event_id, LOWER(name));
+
+COMMENT ON TABLE squads IS 'Teams competing together in an event';
+COMMENT ON COLUMN squads.invite_code IS 'Code other users join the squad with';
+COMMENT ON COLUMN squads.max_size IS 'Member cap, set from SQUAD_MAX_SIZE when the squad is created';
+
+-- A user is in at most one squad per event
+CREATE TABLE IF NOT EXISTS squad_members (
+    squad_id INTEGER NOT NULL,
+    event_id VARCHAR(50) NOT NULL,
+    user_uuid UUID NOT NULL,
+    joined_at BIGINT NOT NULL,               -- Only bets opened after joining count for the squad
+
+    PRIMARY KEY (squad_id, user_uuid),
+    UNIQUE (event_id, user_uuid),
+    CONSTRAINT fk_squad_members_squad FOREIGN KEY (squad_id) REFERENCES squads(id) ON DELETE CASCADE,
+    CONSTRAINT fk_squad_members_user FOREIGN KEY (user_uuid) REFERENCES users(user_uuid) ON DELETE CASCADE
+);
+
+ALTER TABLE all_events ADD COLUMN IF NOT EXISTS squads_finalized_at BIGINT;
+
+COMMENT ON COLUMN all_events.squads_finalized_at IS 'When the squad standings were frozen into squad_results (Unix ms)';
+
+CREATE TABLE IF NOT EXISTS squad_results (
+    event_id VARCHAR(50) NOT NULL,
+    squad_id INTEGER NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    rank INTEGER NOT NULL,
+    points BIGINT NOT NULL,
+    win_count INTEGER NOT NULL DEFAULT 0,
+    loss_count INTEGER NOT NULL DEFAULT 0,
+    member_count INTEGER NOT NULL,
+    prize_value_id INTEGER,
+    prize_label TEXT,                        -- What the whole squad won, e.g. "50 USDT"
+    member_prize_label TEXT,                 -- Each member's share, e.g. "10 USDT"
+    created_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    PRIMARY KEY (event_id, squad_id),
+    CONSTRAINT fk_squad_results_event FOREIGN KEY (event_id) REFERENCES all_events(id) ON DELETE CASCADE,
+    CONSTRAINT fk_squad_results_squad FOREIGN KEY (squad_id) REFERENCES squads(id) ON DELETE CASCADE,
+    CONSTRAINT fk_squad_results_prize_value FOREIGN KEY (prize_value_id) REFERENCES prize_values(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_squad_results_rank ON squad_results(event_id, rank);
+
+COMMENT ON TABLE squad_results IS 'Final squad standings per finished squad event; member shares are paid into got_prizes';
@@ -0,0 +1,24 @@
+-- Event eligibility: who may join an event, how many may take part and what joining costs
+-- eligibility holds the join rules (window, participant cap, minimum points, required achievements and
+-- auth providers, entry fee). Entry fees are paid in rating points into the event's prize pool, which is
+-- paid out to the final ranks when the event finishes.
+
+ALTER TABLE all_events
+    ADD COLUMN IF NOT EXISTS eligibility JSONB,
+    ADD COLUMN IF NOT EXISTS prize_pool BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS pool_paid_at BIGINT;
+
+ALTER TABLE event_templates
+    ADD COLUMN IF NOT EXISTS eligibility JSONB;
+
+ALTER TABLE user_events
+    ADD COLUMN IF NOT EXISTS entry_fee_paid BIGINT NOT NULL DEFAULT 0;
+
+-- Participant counts and waitlist order per event
+CREATE INDEX IF NOT EXISTS idx_user_events_event_status ON user_events(event_id, status, created_at);
+
+COMMENT ON COLUMN all_events.eligibility IS 'Join rules: {joinOpensAt, joinClosesAt, maxParticipants, minTotalPoints, requiredAchievements, authProviders, entryFee, poolShares}';
+COMMENT ON COLUMN all_events.prize_pool IS 'Entry fees collected from participants (rating points)';
+COMMENT ON COLUMN all_events.pool_paid_at IS 'When the prize pool was paid out (Unix ms); NULL until the event finishes';
+COMMENT ON COLUMN user_events.status IS 'joined, waitlisted (event full, promoted in join order) or dropped (could not pay the entry fee on promotion)';
+COMMENT ON COLUMN user_events.entry_fee_paid IS 'Entry fee charged when the user joined (rating points)';
@@ -0,0 +1,44 @@
+-- Duels: head-to-head bets between two users
+-- The challenger proposes a pair, timeframe, stake and side to a friend (or to whoever opens the duel's link).
+-- Stakes are escrowed as negative rating rows; on acceptance both positions are opened as bets at the same
+-- server price on opposite sides, and when the bets settle the winner is paid both stakes.
+
+CREATE TABLE IF NOT EXISTS duels (
+    id SERIAL PRIMARY KEY,
+    challenger_uuid UUID NOT NULL,
+    opponent_uuid UUID,                      -- NULL for link duels until accepted
+    invite_code VARCHAR(16) NOT NULL UNIQUE,
+    pair VARCHAR(20) NOT NULL,
+    timeframe INTEGER NOT NULL CHECK (timeframe > 0),
+    stake BIGINT NOT NULL CHECK (stake > 0),
+    challenger_side VARCHAR(10) NOT NULL CHECK (challenger_side IN ('pump', 'dump')),
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    challenger_bet_id INTEGER,
+    opponent_bet_id INTEGER,
+    open_price NUMERIC(18, 8),
+    close_price NUMERIC(18, 8),
+    open_time TIMESTAMP,
+    winner_uuid UUID,                        -- NULL for a draw
+    expires_at BIGINT NOT NULL,
+    accepted_at BIGINT,
+    settled_at BIGINT,
+    created_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+    updated_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    CONSTRAINT chk_duels_status CHECK (status IN ('pending', 'active', 'settled', 'declined', 'cancelled', 'expired')),
+    CONSTRAINT fk_duels_challenger FOREIGN KEY (challenger_uuid) REFERENCES users(user_uuid) ON DELETE CASCADE,
+    CONSTRAINT fk_duels_opponent FOREIGN KEY (opponent_uuid) REFERENCES users(user_uuid) ON DELETE CASCADE,
+    CONSTRAINT fk_duels_challenger_bet FOREIGN KEY (challenger_bet_id) REFERENCES bets(id) ON DELETE SET NULL,
+    CONSTRAINT fk_duels_opponent_bet FOREIGN KEY (opponent_bet_id) REFERENCES bets(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_duels_challenger ON duels(challenger_uuid, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_duels_opponent ON duels(opponent_uuid, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_duels_open ON duels(status) WHERE status IN ('pending', 'active');
+
+ALTER TABLE bets
+    ADD COLUMN IF NOT EXISTS duel_id INTEGER REFERENCES duels(id) ON DELETE SET NULL;
+
+COMMENT ON TABLE duels IS 'Head-to-head bets; the winner takes both stakes';
+COMMENT ON COLUMN duels.challenger_bet_id IS 'The duel''s lead bet: its close settles both positions at the same price';
+COMMENT ON COLUMN bets.duel_id IS 'Duel the bet is a position of; duel bets are paid by the duel pot, not on claim';
@@ -0,0 +1,77 @@
+-- Declarative achievement rules
+-- An achievement with a rule is progressed by the generic evaluator: the rule names the metric counted
+-- for the user, the threshold that completes it, an optional rolling window and metric filters.
+-- Adding such an achievement means inserting an achievements row and its rule; no code change.
+
+CREATE TABLE IF NOT EXISTS achievement_rules (
+    achievement_id VARCHAR(50) PRIMARY KEY,
+    metric VARCHAR(30) NOT NULL,
+    threshold INTEGER NOT NULL CHECK (threshold > 0),
+    window_days INTEGER NOT NULL DEFAULT 0 CHECK (window_days >= 0), -- 0 = all time
+    filters JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+    updated_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    CONSTRAINT chk_achievement_rules_metric CHECK (metric IN (
+        'wins', 'bets_placed', 'win_streak', 'pair_wins', 'referrals', 'login_days', 'event_placements'
+    )),
+    CONSTRAINT chk_achievement_rules_pair CHECK (metric <> 'pair_wins' OR filters ? 'pair'),
+    CONSTRAINT fk_achievement_rules_achievement FOREIGN KEY (achievement_id) REFERENCES achievements(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_achievement_rules_metric ON achievement_rules(metric);
+
+COMMENT ON TABLE achievement_rules IS 'Metric, threshold, window and filters that complete an achievement';
+COMMENT ON COLUMN achievement_rules.filters IS 'Metric filters: pair, side, timeframe (bets); maxRank, eventTag (event placements)';
+
+-- Days a user logged in, recorded from users.last_login_at for the login_days metric
+CREATE TABLE IF NOT EXISTS user_login_days (
+    user_uuid UUID NOT NULL,
+    login_day DATE NOT NULL,
+
+    PRIMARY KEY (user_uuid, login_day),
+    CONSTRAINT fk_user_login_days_user FOREIGN KEY (user_uuid) REFERENCES users(user_uuid) ON DELETE CASCADE
+);
+
+CREATE OR REPLACE FUNCTION record_user_login_day()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.last_login_at IS NOT NULL THEN
+        INSERT INTO user_login_days (user_uuid, login_day)
+        VALUES (NEW.user_uuid, (to_timestamp(NEW.last_login_at / 1000.0) AT TIME ZONE 'UTC')::date)
+        ON CONFLICT DO NOTHING;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_record_user_login_day ON users;
+CREATE TRIGGER trigger_record_user_login_day
+    AFTER INSERT OR UPDATE OF last_login_at ON users
+    FOR EACH ROW
+    EXECUTE FUNCTION record_user_login_day();
+
+INSERT INTO user_login_days (user_uuid, login_day)
+SELECT user_uuid, (to_timestamp(last_login_at / 1000.0) AT TIME ZONE 'UTC')::date
+FROM users
+WHERE last_login_at IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+COMMENT ON TABLE user_login_days IS 'One row per UTC day a user logged in';
+
+-- Rules for the existing global win achievements
+INSERT INTO achievement_rules (achievement_id, metric, threshold)
+SELECT a.id, 'wins', r.threshold
+FROM (VALUES
+    ('first_bet_success', 1),
+    ('wins_10', 10),
+    ('wins_50', 50),
+    ('wins_100', 100),
+    ('wins_250', 250),
+    ('wins_500', 500),
+    ('wins_1000', 1000),
+    ('wins_5000', 5000),
+    ('wins_10000', 10000)
+) AS r(achievement_id, threshold)
+JOIN achievements a ON a.id = r.achievement_id
+ON CONFLICT (achievement_id) DO NOTHING;
@@ -0,0 +1,20 @@
+-- Event-driven achievement progress
+-- Domain events (bet settled/claimed, referral activated, user logged in, ...) advance the rule counters in
+-- user_achievements incrementally. Each event is applied once per user: achievement_event_log records the
+-- processed event keys together with the achievements the event completed.
+
+ALTER TABLE user_achievements ADD COLUMN IF NOT EXISTS run_length INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN user_achievements.run_length IS 'Current run for win_streak rules; steps_got holds the best run';
+
+CREATE TABLE IF NOT EXISTS achievement_event_log (
+    user_uuid UUID NOT NULL,
+    event_key VARCHAR(100) NOT NULL,         -- e.g. bet:42, referral:<invitee uuid>, login:2026-10-18
+    completed_achievements TEXT[] NOT NULL DEFAULT '{}',
+    created_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    PRIMARY KEY (user_uuid, event_key),
+    CONSTRAINT fk_achievement_event_log_user FOREIGN KEY (user_uuid) REFERENCES users(user_uuid) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE achievement_event_log IS 'Domain events already applied to a user''s achievement counters';
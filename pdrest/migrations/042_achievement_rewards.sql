@@ -0,0 +1,68 @@
+-- Typed rewards
+-- A prize value pays points by default; reward_type/reward_params make it grant extra roulette spins, a temporary
+-- bet-payout boost or a profile badge instead. Claiming the prize runs the reward type's handler.
+
+ALTER TABLE prize_values ADD COLUMN IF NOT EXISTS reward_type VARCHAR(20) NOT NULL DEFAULT 'points';
+ALTER TABLE prize_values ADD COLUMN IF NOT EXISTS reward_params JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+ALTER TABLE prize_values DROP CONSTRAINT IF EXISTS chk_prize_values_reward_type;
+ALTER TABLE prize_values ADD CONSTRAINT chk_prize_values_reward_type
+    CHECK (reward_type IN ('points', 'spins', 'boost', 'badge'));
+
+COMMENT ON COLUMN prize_values.reward_type IS 'points, spins, boost or badge';
+COMMENT ON COLUMN prize_values.reward_params IS 'spins: rouletteConfigId, spins; boost: multiplier, durationHours; badge: badgeId, title, imageUrl';
+
+-- Extra spins on a roulette on top of its max_spins
+CREATE TABLE IF NOT EXISTS user_roulette_spins (
+    user_uuid UUID NOT NULL,
+    roulette_config_id INTEGER NOT NULL,
+    extra_spins INTEGER NOT NULL DEFAULT 0 CHECK (extra_spins >= 0),
+    updated_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    PRIMARY KEY (user_uuid, roulette_config_id),
+    CONSTRAINT fk_user_roulette_spins_user FOREIGN KEY (user_uuid) REFERENCES users(user_uuid) ON DELETE CASCADE,
+    CONSTRAINT fk_user_roulette_spins_config FOREIGN KEY (roulette_config_id) REFERENCES roulette_config(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE user_roulette_spins IS 'Extra roulette spins granted to a user by rewards';
+
+-- User inventory: boost rewards are items owned by the user. A payout boost is active from activated_at until it
+-- expires; winning bets opened while it is active pay multiplier times their points.
+CREATE TABLE IF NOT EXISTS user_inventory (
+    id SERIAL PRIMARY KEY,
+    user_uuid UUID NOT NULL,
+    item_type VARCHAR(30) NOT NULL,
+    quantity INTEGER CHECK (quantity IS NULL OR quantity >= 0), -- uses left; NULL = unlimited until expires_at
+    expires_at BIGINT,                                           -- NULL = no expiry
+    activated_at BIGINT,                                         -- NULL = not activated yet
+    metadata JSONB NOT NULL DEFAULT '{}'::jsonb,
+    got_prize_id INTEGER,
+    created_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+    updated_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    CONSTRAINT chk_user_inventory_item_type CHECK (item_type IN ('payout_boost')),
+    CONSTRAINT chk_user_inventory_limited CHECK (quantity IS NOT NULL OR expires_at IS NOT NULL OR metadata ? 'durationHours'),
+    CONSTRAINT fk_user_inventory_user FOREIGN KEY (user_uuid) REFERENCES users(user_uuid) ON DELETE CASCADE,
+    CONSTRAINT fk_user_inventory_prize FOREIGN KEY (got_prize_id) REFERENCES got_prizes(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_inventory_user ON user_inventory(user_uuid, item_type);
+
+COMMENT ON TABLE user_inventory IS 'Boosts and consumables owned by users';
+COMMENT ON COLUMN user_inventory.metadata IS 'payout_boost: multiplier; any: durationHours active after activation';
+
+-- Profile badges and titles
+CREATE TABLE IF NOT EXISTS user_badges (
+    user_uuid UUID NOT NULL,
+    badge_id VARCHAR(50) NOT NULL,
+    title TEXT NOT NULL,
+    image_url TEXT,
+    got_prize_id INTEGER,
+    awarded_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    PRIMARY KEY (user_uuid, badge_id),
+    CONSTRAINT fk_user_badges_user FOREIGN KEY (user_uuid) REFERENCES users(user_uuid) ON DELETE CASCADE,
+    CONSTRAINT fk_user_badges_prize FOREIGN KEY (got_prize_id) REFERENCES got_prizes(id) ON DELETE SET NULL
+);
+
+COMMENT ON TABLE user_badges IS 'Badges and titles shown on user profiles';
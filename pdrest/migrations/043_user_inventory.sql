@@ -0,0 +1,24 @@
+-- User inventory
+-- Rewards beyond points are items in the user's inventory: payout boosts ("2x payout on the next 3 bets"),
+-- loss shields and extra roulette spins. A quantity-limited item is used up one bet at a time once activated;
+-- an item without a quantity works until it expires. Opening a bet consumes the user's active items and records
+-- the applied modifiers on the bet, and the claim pays out according to them.
+
+-- Inventory items beyond payout boosts
+ALTER TABLE user_inventory DROP CONSTRAINT IF EXISTS chk_user_inventory_item_type;
+ALTER TABLE user_inventory ADD CONSTRAINT chk_user_inventory_item_type
+    CHECK (item_type IN ('payout_boost', 'loss_shield', 'extra_spin'));
+
+COMMENT ON COLUMN user_inventory.metadata IS 'payout_boost: multiplier; extra_spin: rouletteConfigId; any: durationHours active after activation';
+
+-- Modifiers applied to a bet when it was opened, for audit and for the claim
+ALTER TABLE bets ADD COLUMN IF NOT EXISTS modifiers JSONB NOT NULL DEFAULT '[]'::jsonb;
+
+COMMENT ON COLUMN bets.modifiers IS 'Inventory items applied to the bet: [{"itemId", "type", "multiplier"}]';
+
+-- Rewards can grant any inventory item
+ALTER TABLE prize_values DROP CONSTRAINT IF EXISTS chk_prize_values_reward_type;
+ALTER TABLE prize_values ADD CONSTRAINT chk_prize_values_reward_type
+    CHECK (reward_type IN ('points', 'spins', 'boost', 'badge', 'item'));
+
+COMMENT ON COLUMN prize_values.reward_params IS 'spins: rouletteConfigId, spins; boost: multiplier, bets and/or durationHours; badge: badgeId, title, imageUrl; item: itemType, quantity, durationHours, multiplier, rouletteConfigId';
@@ -0,0 +1,26 @@
+-- Public profiles
+-- Users can pick a display name instead of their Google/Telegram name, hide their name from other users
+-- (profiles and ratings) or their stats, and showcase up to three claimed achievements on their profile.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS display_name VARCHAR(24);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS hide_name BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS hide_stats BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_users_display_name ON users(LOWER(display_name)) WHERE display_name IS NOT NULL;
+
+COMMENT ON COLUMN users.display_name IS 'Name chosen by the user, shown instead of the Google/Telegram name';
+COMMENT ON COLUMN users.hide_name IS 'Show the user as Anonymous to other users';
+COMMENT ON COLUMN users.hide_stats IS 'Hide lifetime stats, rank and league from other users';
+
+CREATE TABLE IF NOT EXISTS user_showcase_badges (
+    user_uuid UUID NOT NULL,
+    position SMALLINT NOT NULL CHECK (position BETWEEN 1 AND 3),
+    achievement_id VARCHAR(50) NOT NULL,
+
+    PRIMARY KEY (user_uuid, position),
+    CONSTRAINT uq_user_showcase_badges_achievement UNIQUE (user_uuid, achievement_id),
+    CONSTRAINT fk_user_showcase_badges_user FOREIGN KEY (user_uuid) REFERENCES users(user_uuid) ON DELETE CASCADE,
+    CONSTRAINT fk_user_showcase_badges_achievement FOREIGN KEY (achievement_id) REFERENCES achievements(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE user_showcase_badges IS 'Claimed achievements a user shows on their profile, in order';
@@ -0,0 +1,59 @@
+-- XP and levels
+-- XP is a non-spendable track next to rating points: placing and winning bets, daily logins, joining events and
+-- completing achievements grant XP (weights in XP_* settings). Every grant is a row of xp_ledger, keyed by its
+-- source so replayed domain events grant once; user_xp keeps the running total. The levels table is the level
+-- curve and the perks each level unlocks; events can require a level through their eligibility (minLevel).
+
+CREATE TABLE IF NOT EXISTS levels (
+    level INTEGER PRIMARY KEY CHECK (level >= 1),
+    xp_required BIGINT NOT NULL UNIQUE CHECK (xp_required >= 0),
+    title VARCHAR(50) NOT NULL,
+    max_stake NUMERIC(18, 2),                      -- max bet sum in USDT; NULL = no limit
+    extra_spins INTEGER NOT NULL DEFAULT 0 CHECK (extra_spins >= 0)
+);
+
+COMMENT ON TABLE levels IS 'Level curve: total XP needed for each level and the perks it unlocks';
+COMMENT ON COLUMN levels.extra_spins IS 'Spins added to every roulette''s limit';
+
+-- Level 1 is uncapped: XP is not backfilled, so every existing user starts at level 1 and must keep the stakes
+-- they could place before levels existed.
+INSERT INTO levels (level, xp_required, title, max_stake, extra_spins) VALUES
+    (1, 0, 'Rookie', NULL, 0),
+    (2, 100, 'Trader', 250, 0),
+    (3, 300, 'Analyst', 500, 1),
+    (4, 700, 'Strategist', 1000, 1),
+    (5, 1500, 'Expert', 2500, 2),
+    (6, 3000, 'Veteran', 5000, 2),
+    (7, 5500, 'Master', 10000, 3),
+    (8, 9000, 'Grandmaster', 25000, 3),
+    (9, 14000, 'Legend', 50000, 4),
+    (10, 21000, 'Whale', NULL, 5)
+ON CONFLICT (level) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS xp_ledger (
+    id BIGSERIAL PRIMARY KEY,
+    user_uuid UUID NOT NULL,
+    source VARCHAR(30) NOT NULL,      -- bet_placed, bet_won, daily_login, event_joined, achievement_completed
+    source_ref VARCHAR(100) NOT NULL, -- bet ID, UTC date, event ID or achievement ID
+    xp INTEGER NOT NULL CHECK (xp > 0),
+    created_at BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    CONSTRAINT uq_xp_ledger_source UNIQUE (user_uuid, source, source_ref),
+    CONSTRAINT fk_xp_ledger_user FOREIGN KEY (user_uuid) REFERENCES users(user_uuid) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_xp_ledger_user_created ON xp_ledger(user_uuid, created_at DESC);
+
+COMMENT ON TABLE xp_ledger IS 'XP granted to users, one row per source';
+
+CREATE TABLE IF NOT EXISTS user_xp (
+    user_uuid UUID PRIMARY KEY,
+    xp BIGINT NOT NULL DEFAULT 0,
+    updated_at BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    CONSTRAINT fk_user_xp_user FOREIGN KEY (user_uuid) REFERENCES users(user_uuid) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE user_xp IS 'Total XP per user (sum of xp_ledger)';
+
+COMMENT ON COLUMN all_events.eligibility IS 'Join rules: {joinOpensAt, joinClosesAt, maxParticipants, minTotalPoints, minLevel, requiredAchievements, authProviders, entryFee, poolShares}';
@@ -0,0 +1,117 @@
+-- Daily and weekly quests
+-- Quest templates describe short-term goals ("place 5 BTC bets", "win 3 in a row", "invite a friend"). Every user
+-- gets a few templates per day and per week, picked when their quests are first needed in the period; periods
+-- start at midnight (daily) or Monday midnight (weekly) in the user's time zone. Progress comes from settled bets
+-- and activated referrals, and a completed quest is claimed for its prize value through the reward pipeline.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone_pending VARCHAR(64);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone_pending_from BIGINT;
+
+COMMENT ON COLUMN users.timezone IS 'IANA time zone the user''s daily and weekly quests reset in';
+COMMENT ON COLUMN users.timezone_pending IS 'Requested time zone, replacing timezone from timezone_pending_from (the next daily reset)';
+
+CREATE TABLE IF NOT EXISTS quest_templates (
+    id VARCHAR(50) PRIMARY KEY,
+    period VARCHAR(10) NOT NULL CHECK (period IN ('daily', 'weekly')),
+    title TEXT NOT NULL,
+    desc_text TEXT NOT NULL DEFAULT '',
+    metric VARCHAR(30) NOT NULL CHECK (metric IN ('bets_placed', 'wins', 'win_streak', 'referrals')),
+    threshold INTEGER NOT NULL CHECK (threshold > 0),
+    filters JSONB NOT NULL DEFAULT '{}'::jsonb,
+    prize_value_id INTEGER NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+    updated_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    CONSTRAINT fk_quest_templates_prize_value FOREIGN KEY (prize_value_id) REFERENCES prize_values(id)
+);
+
+COMMENT ON TABLE quest_templates IS 'Daily and weekly quest definitions';
+COMMENT ON COLUMN quest_templates.filters IS 'Bet filters: pair, side, timeframe';
+
+CREATE TABLE IF NOT EXISTS user_quests (
+    id SERIAL PRIMARY KEY,
+    user_uuid UUID NOT NULL,
+    template_id VARCHAR(50) NOT NULL,
+    period_start BIGINT NOT NULL, -- Unix ms, midnight in the user's time zone
+    period_end BIGINT NOT NULL,
+    progress INTEGER NOT NULL DEFAULT 0,
+    current_run INTEGER NOT NULL DEFAULT 0, -- win_streak: the run of wins the user is on
+    need_steps INTEGER NOT NULL,
+    completed_at BIGINT,
+    claimed_at BIGINT,
+    got_prize_id INTEGER,
+    created_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+    updated_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    CONSTRAINT uq_user_quests_period UNIQUE (user_uuid, template_id, period_start),
+    CONSTRAINT fk_user_quests_user FOREIGN KEY (user_uuid) REFERENCES users(user_uuid) ON DELETE CASCADE,
+    CONSTRAINT fk_user_quests_template FOREIGN KEY (template_id) REFERENCES quest_templates(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_quests_prize FOREIGN KEY (got_prize_id) REFERENCES got_prizes(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_quests_user_period ON user_quests(user_uuid, period_end);
+
+COMMENT ON TABLE user_quests IS 'Quests assigned to users per period and their progress';
+
+-- Domain events applied to a quest, so replayed events count once
+CREATE TABLE IF NOT EXISTS user_quest_events (
+    user_quest_id INTEGER NOT NULL,
+    event_key VARCHAR(100) NOT NULL,
+    created_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    PRIMARY KEY (user_quest_id, event_key),
+    CONSTRAINT fk_user_quest_events_quest FOREIGN KEY (user_quest_id) REFERENCES user_quests(id) ON DELETE CASCADE
+);
+
+-- Quest prizes hang off a draft "quests" event, like achievement prizes hang off their achievements events
+INSERT INTO all_events (id, badge, title, desc_text, deadline, tags, reward, info, state, start_time, created_at, updated_at)
+VALUES (
+    'quests',
+    'Quests',
+    'Daily and Weekly Quests',
+    'Rewards of daily and weekly quests.',
+    EXTRACT(EPOCH FROM (NOW() + INTERVAL '3650 days'))::BIGINT * 1000,
+    'quests',
+    '[]'::jsonb,
+    'Quest prize event',
+    'draft',
+    EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+    EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+    EXTRACT(EPOCH FROM NOW())::BIGINT * 1000
+)
+ON CONFLICT (id) DO NOTHING;
+
+DO $$
+DECLARE
+    points_20 INTEGER;
+    points_30 INTEGER;
+    points_50 INTEGER;
+    points_150 INTEGER;
+    points_250 INTEGER;
+    shield INTEGER;
+BEGIN
+    IF EXISTS (SELECT 1 FROM quest_templates) THEN
+        RETURN;
+    END IF;
+
+    INSERT INTO prize_values (event_id, value, label) VALUES ('quests', 20, '20 points') RETURNING id INTO points_20;
+    INSERT INTO prize_values (event_id, value, label) VALUES ('quests', 30, '30 points') RETURNING id INTO points_30;
+    INSERT INTO prize_values (event_id, value, label) VALUES ('quests', 50, '50 points') RETURNING id INTO points_50;
+    INSERT INTO prize_values (event_id, value, label) VALUES ('quests', 150, '150 points') RETURNING id INTO points_150;
+    INSERT INTO prize_values (event_id, value, label) VALUES ('quests', 250, '250 points') RETURNING id INTO points_250;
+    INSERT INTO prize_values (event_id, value, label, reward_type, reward_params)
+    VALUES ('quests', 0, 'Loss shield', 'item', '{"itemType": "loss_shield", "quantity": 1}'::jsonb)
+    RETURNING id INTO shield;
+
+    INSERT INTO quest_templates (id, period, title, desc_text, metric, threshold, filters, prize_value_id) VALUES
+        ('daily_btc_bets', 'daily', 'Place 5 BTC bets', 'Place 5 bets on BTC/USDT today.', 'bets_placed', 5, '{"pair": "BTC/USDT"}', points_20),
+        ('daily_bets', 'daily', 'Place 10 bets', 'Place 10 bets on any pair today.', 'bets_placed', 10, '{}', points_30),
+        ('daily_wins', 'daily', 'Win 3 bets', 'Win 3 bets today.', 'wins', 3, '{}', points_30),
+        ('daily_win_streak', 'daily', 'Win 3 in a row', 'Win 3 bets in a row today.', 'win_streak', 3, '{}', points_50),
+        ('daily_pump_wins', 'daily', 'Pump it', 'Win 2 pump bets today.', 'wins', 2, '{"side": "pump"}', points_20),
+        ('weekly_invite', 'weekly', 'Invite a friend', 'Invite a friend who joins this week.', 'referrals', 1, '{}', shield),
+        ('weekly_bets', 'weekly', 'Place 50 bets', 'Place 50 bets this week.', 'bets_placed', 50, '{}', points_150),
+        ('weekly_win_streak', 'weekly', 'Win 5 in a row', 'Win 5 bets in a row this week.', 'win_streak', 5, '{}', points_250);
+END $$;
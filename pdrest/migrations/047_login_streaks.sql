@@ -0,0 +1,115 @@
+-- Daily login streaks
+-- Users check in once per day (midnight to midnight in their quest time zone) and claim the reward of their
+-- streak day from the login reward calendar; the calendar starts over after its last day. Missing a day breaks
+-- the streak unless the user owns streak freezes: each freeze covers one missed day and is used up automatically
+-- by the next check-in.
+
+ALTER TABLE user_inventory DROP CONSTRAINT IF EXISTS chk_user_inventory_item_type;
+ALTER TABLE user_inventory ADD CONSTRAINT chk_user_inventory_item_type
+    CHECK (item_type IN ('payout_boost', 'loss_shield', 'extra_spin', 'streak_freeze'));
+
+CREATE TABLE IF NOT EXISTS login_reward_calendar (
+    day INTEGER PRIMARY KEY CHECK (day >= 1),
+    prize_value_id INTEGER NOT NULL,
+    created_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+    updated_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    CONSTRAINT fk_login_reward_calendar_prize_value FOREIGN KEY (prize_value_id) REFERENCES prize_values(id)
+);
+
+COMMENT ON TABLE login_reward_calendar IS 'Reward of each streak day; streak day N pays the ((N - 1) mod days + 1)-th row';
+
+CREATE TABLE IF NOT EXISTS user_login_streaks (
+    user_uuid UUID PRIMARY KEY,
+    current_streak INTEGER NOT NULL DEFAULT 0,
+    best_streak INTEGER NOT NULL DEFAULT 0,
+    last_checkin_day DATE, -- in the user's time zone
+    freezes_used INTEGER NOT NULL DEFAULT 0,
+    updated_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    CONSTRAINT fk_user_login_streaks_user FOREIGN KEY (user_uuid) REFERENCES users(user_uuid) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE user_login_streaks IS 'Consecutive daily check-ins of users';
+
+CREATE TABLE IF NOT EXISTS login_checkins (
+    user_uuid UUID NOT NULL,
+    checkin_day DATE NOT NULL,
+    streak_day INTEGER NOT NULL CHECK (streak_day >= 1),
+    calendar_day INTEGER NOT NULL,
+    prize_value_id INTEGER NOT NULL,
+    freezes_used INTEGER NOT NULL DEFAULT 0,
+    got_prize_id INTEGER, -- prize of the reward, created with the check-in
+    rewarded_at BIGINT,   -- NULL until the reward was granted
+    created_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    PRIMARY KEY (user_uuid, checkin_day),
+    CONSTRAINT fk_login_checkins_user FOREIGN KEY (user_uuid) REFERENCES users(user_uuid) ON DELETE CASCADE,
+    CONSTRAINT fk_login_checkins_prize_value FOREIGN KEY (prize_value_id) REFERENCES prize_values(id),
+    CONSTRAINT fk_login_checkins_prize FOREIGN KEY (got_prize_id) REFERENCES got_prizes(id) ON DELETE SET NULL
+);
+
+COMMENT ON TABLE login_checkins IS 'Daily check-ins and the calendar reward claimed for them';
+
+-- Login reward prizes hang off a draft "login_rewards" event, like quest prizes
+INSERT INTO all_events (id, badge, title, desc_text, deadline, tags, reward, info, state, start_time, created_at, updated_at)
+VALUES (
+    'login_rewards',
+    'Daily Rewards',
+    'Daily Login Rewards',
+    'Rewards of daily login streaks.',
+    EXTRACT(EPOCH FROM (NOW() + INTERVAL '3650 days'))::BIGINT * 1000,
+    'login_rewards',
+    '[]'::jsonb,
+    'Login reward prize event',
+    'draft',
+    EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+    EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+    EXTRACT(EPOCH FROM NOW())::BIGINT * 1000
+)
+ON CONFLICT (id) DO NOTHING;
+
+DO $$
+DECLARE
+    points_10 INTEGER;
+    points_20 INTEGER;
+    spin_config INTEGER;
+    spin INTEGER;
+    points_30 INTEGER;
+    freeze INTEGER;
+    boost INTEGER;
+    points_100 INTEGER;
+BEGIN
+    IF EXISTS (SELECT 1 FROM login_reward_calendar) THEN
+        RETURN;
+    END IF;
+
+    INSERT INTO prize_values (event_id, value, label) VALUES ('login_rewards', 10, '10 points') RETURNING id INTO points_10;
+    INSERT INTO prize_values (event_id, value, label) VALUES ('login_rewards', 20, '20 points') RETURNING id INTO points_20;
+    -- Day 3 pays an extra spin on the on-start roulette, or points when there is no such roulette
+    SELECT id INTO spin_config FROM roulette_config WHERE roulette_type = 'on_start' AND is_active ORDER BY id LIMIT 1;
+    IF spin_config IS NOT NULL THEN
+        INSERT INTO prize_values (event_id, value, label, reward_type, reward_params)
+        VALUES ('login_rewards', 0, 'Extra roulette spin', 'spins', jsonb_build_object('rouletteConfigId', spin_config, 'spins', 1))
+        RETURNING id INTO spin;
+    ELSE
+        INSERT INTO prize_values (event_id, value, label) VALUES ('login_rewards', 25, '25 points') RETURNING id INTO spin;
+    END IF;
+    INSERT INTO prize_values (event_id, value, label) VALUES ('login_rewards', 30, '30 points') RETURNING id INTO points_30;
+    INSERT INTO prize_values (event_id, value, label, reward_type, reward_params)
+    VALUES ('login_rewards', 0, 'Streak freeze', 'item', '{"itemType": "streak_freeze", "quantity": 1}'::jsonb)
+    RETURNING id INTO freeze;
+    INSERT INTO prize_values (event_id, value, label, reward_type, reward_params)
+    VALUES ('login_rewards', 0, '1.5x payout on the next 3 bets', 'boost', '{"multiplier": 1.5, "bets": 3}'::jsonb)
+    RETURNING id INTO boost;
+    INSERT INTO prize_values (event_id, value, label) VALUES ('login_rewards', 100, '100 points') RETURNING id INTO points_100;
+
+    INSERT INTO login_reward_calendar (day, prize_value_id) VALUES
+        (1, points_10),
+        (2, points_20),
+        (3, spin),
+        (4, points_30),
+        (5, freeze),
+        (6, boost),
+        (7, points_100);
+END $$;
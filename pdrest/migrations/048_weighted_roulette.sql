@@ -0,0 +1,36 @@
+-- Weighted, budgeted roulette
+-- A spin draws a prize value of the roulette's event with probability weight / sum of weights. A prize value can
+-- be capped to a number of wins overall (total_budget) and per UTC day (daily_budget); when the drawn prize is
+-- exhausted the spin falls back to the cheapest prize that is still available. Roulette configs can guarantee a
+-- minimum prize: spin N of a roulette only lands prizes worth at least minValue unless an earlier spin did.
+
+ALTER TABLE prize_values ADD COLUMN IF NOT EXISTS weight INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE prize_values ADD COLUMN IF NOT EXISTS total_budget INTEGER;
+ALTER TABLE prize_values ADD COLUMN IF NOT EXISTS daily_budget INTEGER;
+
+ALTER TABLE prize_values DROP CONSTRAINT IF EXISTS chk_prize_values_weight;
+ALTER TABLE prize_values ADD CONSTRAINT chk_prize_values_weight CHECK (weight >= 0);
+ALTER TABLE prize_values DROP CONSTRAINT IF EXISTS chk_prize_values_budgets;
+ALTER TABLE prize_values ADD CONSTRAINT chk_prize_values_budgets
+    CHECK ((total_budget IS NULL OR total_budget > 0) AND (daily_budget IS NULL OR daily_budget > 0));
+
+COMMENT ON COLUMN prize_values.weight IS 'Relative roulette weight; 0 = only landed as a fallback';
+COMMENT ON COLUMN prize_values.total_budget IS 'Max roulette wins overall, NULL = unlimited';
+COMMENT ON COLUMN prize_values.daily_budget IS 'Max roulette wins per UTC day, NULL = unlimited';
+
+-- Roulette wins of capped prize values per UTC day. A spin reserves its prize; spinning again releases it.
+CREATE TABLE IF NOT EXISTS prize_value_usage (
+    prize_value_id INTEGER NOT NULL,
+    usage_day DATE NOT NULL,
+    used INTEGER NOT NULL DEFAULT 0 CHECK (used >= 0),
+    updated_at BIGINT DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT * 1000,
+
+    PRIMARY KEY (prize_value_id, usage_day),
+    CONSTRAINT fk_prize_value_usage_prize_value FOREIGN KEY (prize_value_id) REFERENCES prize_values(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE prize_value_usage IS 'Roulette wins of budgeted prize values per UTC day';
+
+ALTER TABLE roulette_config ADD COLUMN IF NOT EXISTS guarantees JSONB NOT NULL DEFAULT '[]'::jsonb;
+
+COMMENT ON COLUMN roulette_config.guarantees IS 'Guaranteed minimums: [{"spin": N, "minValue": V}]';